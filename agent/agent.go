@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"net/http"
 	"net/netip"
 	"os"
 	"os/exec"
@@ -57,11 +58,30 @@ var (
 	tailnetSSHPort             = 1
 	tailnetReconnectingPTYPort = 2
 	tailnetSpeedtestPort       = 3
+	tailnetHTTPAPIPort         = 4
 )
 
 type Options struct {
 	CoordinatorDialer CoordinatorDialer
 	FetchMetadata     FetchMetadata
+	ReportLifecycle   ReportLifecycle
+	PatchStartupLogs  PatchStartupLogs
+	AwaitShutdown     AwaitShutdown
+	PostMetadata      PostMetadata
+	PostAppHealth     PostAppHealth
+	PostHealth        PostHealth
+	UpdateAgent       UpdateAgent
+
+	// Version is the version of the running agent. The agent updates itself
+	// with UpdateAgent when coderd expects a different version.
+	Version string
+	// Restarted is true if the agent process was replaced by an updated
+	// binary. The startup script already ran, so it isn't run again.
+	Restarted bool
+	// UpdatedVersion is the version the agent was updated to before it
+	// restarted. The agent doesn't update to it again, since the binary
+	// served by coderd doesn't report that version.
+	UpdatedVersion string
 
 	StatsReporter          StatsReporter
 	ReconnectingPTYTimeout time.Duration
@@ -73,9 +93,40 @@ type Metadata struct {
 	DERPMap              *tailcfg.DERPMap  `json:"derpmap"`
 	EnvironmentVariables map[string]string `json:"environment_variables"`
 	StartupScript        string            `json:"startup_script"`
-	Directory            string            `json:"directory"`
+	StartupScriptTimeout time.Duration     `json:"startup_script_timeout"`
+	ShutdownScript       string            `json:"shutdown_script"`
+	// ShutdownScriptTimeout is zero if the script can run until the agent
+	// is killed.
+	ShutdownScriptTimeout time.Duration `json:"shutdown_script_timeout"`
+	Directory             string        `json:"directory"`
+	// Metadata describes scripts that collect values to display on the
+	// workspace page.
+	Metadata []MetadataDescription `json:"metadata"`
+	// AgentVersion is the version of the agent that coderd expects. Agents
+	// with a different version update themselves.
+	AgentVersion string `json:"agent_version"`
+	// AppHealthchecks are run by the agent to determine the health of
+	// workspace applications.
+	AppHealthchecks []AppHealthcheck `json:"app_healthchecks"`
+	// SessionLimits are applied to the processes of each session.
+	SessionLimits SessionLimits `json:"session_limits"`
 }
 
+// LifecycleState is the state of the agent as it starts up and shuts down.
+type LifecycleState string
+
+const (
+	LifecycleStateCreated         LifecycleState = "created"
+	LifecycleStateStarting        LifecycleState = "starting"
+	LifecycleStateStartTimeout    LifecycleState = "start_timeout"
+	LifecycleStateStartError      LifecycleState = "start_error"
+	LifecycleStateReady           LifecycleState = "ready"
+	LifecycleStateShuttingDown    LifecycleState = "shutting_down"
+	LifecycleStateShutdownTimeout LifecycleState = "shutdown_timeout"
+	LifecycleStateShutdownError   LifecycleState = "shutdown_error"
+	LifecycleStateOff             LifecycleState = "off"
+)
+
 // CoordinatorDialer is a function that constructs a new broker.
 // A dialer must be passed in to allow for reconnects.
 type CoordinatorDialer func(ctx context.Context) (net.Conn, error)
@@ -83,6 +134,19 @@ type CoordinatorDialer func(ctx context.Context) (net.Conn, error)
 // FetchMetadata is a function to obtain metadata for the agent.
 type FetchMetadata func(ctx context.Context) (Metadata, error)
 
+// ReportLifecycle is a function to report the lifecycle state of the agent.
+type ReportLifecycle func(ctx context.Context, state LifecycleState) error
+
+// PatchStartupLogs is a function to send output of the startup script.
+type PatchStartupLogs func(ctx context.Context, logs []StartupLog) error
+
+// AwaitShutdown is a function that blocks until coderd requests the agent
+// to shut down gracefully, e.g. before the workspace is stopped.
+type AwaitShutdown func(ctx context.Context) error
+
+// PostMetadata is a function to report the result of a metadata script.
+type PostMetadata func(ctx context.Context, key string, result MetadataResult) error
+
 func New(options Options) io.Closer {
 	if options.ReconnectingPTYTimeout == 0 {
 		options.ReconnectingPTYTimeout = 5 * time.Minute
@@ -96,6 +160,18 @@ func New(options Options) io.Closer {
 		envVars:                options.EnvironmentVariables,
 		coordinatorDialer:      options.CoordinatorDialer,
 		fetchMetadata:          options.FetchMetadata,
+		reportLifecycle:        options.ReportLifecycle,
+		patchStartupLogs:       options.PatchStartupLogs,
+		awaitShutdown:          options.AwaitShutdown,
+		postMetadata:           options.PostMetadata,
+		postAppHealth:          options.PostAppHealth,
+		postHealth:             options.PostHealth,
+		updateAgent:            options.UpdateAgent,
+		version:                options.Version,
+		restarted:              options.Restarted,
+		updatedVersion:         options.UpdatedVersion,
+		lifecycleUpdate:        make(chan struct{}, 1),
+		lifecycleState:         LifecycleStateCreated,
 		stats:                  &Stats{},
 		statsReporter:          options.StatsReporter,
 	}
@@ -120,6 +196,25 @@ type agent struct {
 	fetchMetadata FetchMetadata
 	sshServer     *ssh.Server
 
+	reportLifecycle  ReportLifecycle
+	patchStartupLogs PatchStartupLogs
+	lifecycleUpdate  chan struct{}
+	lifecycleMutex   sync.RWMutex
+	lifecycleState   LifecycleState
+	awaitShutdown    AwaitShutdown
+	shutdownOnce     sync.Once
+	shuttingDown     bool
+	postMetadata     PostMetadata
+	postAppHealth    PostAppHealth
+	postHealth       PostHealth
+	updateAgent      UpdateAgent
+	version          string
+	restarted        bool
+	updatedVersion   string
+	// sessionLimiter is set before the tailnet is started, so sessions
+	// can't be started without it.
+	sessionLimiter *sessionLimiter
+
 	network           *tailnet.Conn
 	coordinatorDialer CoordinatorDialer
 	stats             *Stats
@@ -154,17 +249,38 @@ func (a *agent) run(ctx context.Context) {
 	}
 	a.metadata.Store(metadata)
 
+	limiter, warnings := newSessionLimiter(a.logger.Named("session-limits"), metadata.SessionLimits)
+	a.sessionLimiter = limiter
+	go a.reportHealth(ctx, warnings)
+
 	// The startup script has not ran yet!
 	go func() {
-		err := a.runStartupScript(ctx, metadata.StartupScript)
-		if errors.Is(err, context.Canceled) {
+		if a.restarted {
+			// The startup script ran before the agent was updated.
+			a.setLifecycle(ctx, LifecycleStateReady)
 			return
 		}
-		if err != nil {
+		a.setLifecycle(ctx, LifecycleStateStarting)
+
+		err := a.runStartupScript(ctx, metadata.StartupScript, metadata.StartupScriptTimeout)
+		switch {
+		case errors.Is(err, context.DeadlineExceeded):
+			a.logger.Warn(ctx, "agent script timed out", slog.F("timeout", metadata.StartupScriptTimeout))
+			a.setLifecycle(ctx, LifecycleStateStartTimeout)
+		case errors.Is(err, context.Canceled):
+			return
+		case err != nil:
 			a.logger.Warn(ctx, "agent script failed", slog.Error(err))
+			a.setLifecycle(ctx, LifecycleStateStartError)
+		default:
+			a.setLifecycle(ctx, LifecycleStateReady)
 		}
 	}()
 
+	go a.reportMetadataLoop(ctx, metadata.Metadata)
+	go a.appHealthLoop(ctx, metadata.AppHealthchecks)
+	go a.selfUpdate(ctx, metadata.AgentVersion)
+
 	if metadata.DERPMap != nil {
 		go a.runTailnet(ctx, metadata.DERPMap)
 	}
@@ -195,7 +311,9 @@ func (a *agent) runTailnet(ctx context.Context, derpMap *tailcfg.DERPMap) {
 			// If a listener already exists, we would double-wrap the conn.
 			return conn
 		}
-		return a.stats.wrapConn(conn)
+		// Connections to ports the agent doesn't listen on are forwarded
+		// to the workspace.
+		return a.stats.wrapSessionConn(conn, SessionTypePortForward)
 	})
 	go a.runCoordinator(ctx)
 
@@ -268,6 +386,25 @@ func (a *agent) runTailnet(ctx context.Context, derpMap *tailcfg.DERPMap) {
 			}()
 		}
 	}()
+	apiListener, err := a.network.Listen("tcp", ":"+strconv.Itoa(tailnetHTTPAPIPort))
+	if err != nil {
+		a.logger.Critical(ctx, "listen for http api", slog.Error(err))
+		return
+	}
+	apiServer := &http.Server{
+		Handler:           a.apiHandler(),
+		ReadHeaderTimeout: 20 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		_ = apiServer.Close()
+	}()
+	go func() {
+		err := apiServer.Serve(apiListener)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			a.logger.Debug(ctx, "http api server failed", slog.Error(err))
+		}
+	}()
 }
 
 // runCoordinator listens for nodes and updates the self-node as it changes.
@@ -315,25 +452,55 @@ func (a *agent) runCoordinator(ctx context.Context) {
 	}
 }
 
-func (a *agent) runStartupScript(ctx context.Context, script string) error {
+// runStartupScript runs the script and sends its output to coderd. A timeout
+// of zero allows the script to run until the agent is closed.
+func (a *agent) runStartupScript(ctx context.Context, script string, timeout time.Duration) error {
+	if script == "" {
+		return nil
+	}
+	// Logs are sent with the agent context, so output is still flushed
+	// after the script times out.
+	logs := a.newStartupLogsSender(ctx)
+	defer logs.Close()
+	return a.runScript(ctx, "startup", script, timeout, logs)
+}
+
+// runShutdownScript runs the script before the agent stops. A timeout of
+// zero allows the script to run until it exits.
+func (a *agent) runShutdownScript(ctx context.Context, script string, timeout time.Duration) error {
 	if script == "" {
 		return nil
 	}
+	return a.runScript(ctx, "shutdown", script, timeout, nil)
+}
 
-	writer, err := os.OpenFile(filepath.Join(os.TempDir(), "coder-startup-script.log"), os.O_CREATE|os.O_RDWR, 0o600)
+// runScript runs a script, writing its output to a log file named after the
+// lifecycle it belongs to and to output, if provided.
+func (a *agent) runScript(ctx context.Context, lifecycle, script string, timeout time.Duration, output io.Writer) error {
+	a.logger.Info(ctx, "running script", slog.F("lifecycle", lifecycle))
+	writer, err := os.OpenFile(filepath.Join(os.TempDir(), fmt.Sprintf("coder-%s-script.log", lifecycle)), os.O_CREATE|os.O_RDWR, 0o600)
 	if err != nil {
-		return xerrors.Errorf("open startup script log file: %w", err)
+		return xerrors.Errorf("open %s script log file: %w", lifecycle, err)
 	}
 	defer func() {
 		_ = writer.Close()
 	}()
 
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
 	cmd, err := a.createCommand(ctx, script, nil)
 	if err != nil {
 		return xerrors.Errorf("create command: %w", err)
 	}
-	cmd.Stdout = writer
-	cmd.Stderr = writer
+	var cmdOutput io.Writer = writer
+	if output != nil {
+		cmdOutput = io.MultiWriter(writer, output)
+	}
+	cmd.Stdout = cmdOutput
+	cmd.Stderr = cmdOutput
 	err = cmd.Run()
 	if err != nil {
 		// cmd.Run does not return a context canceled error, it returns "signal: killed".
@@ -364,8 +531,13 @@ func (a *agent) init(ctx context.Context) {
 	forwardHandler := &ssh.ForwardedTCPHandler{}
 	a.sshServer = &ssh.Server{
 		ChannelHandlers: map[string]ssh.ChannelHandler{
-			"direct-tcpip": ssh.DirectTCPIPHandler,
-			"session":      ssh.DefaultSessionHandler,
+			"direct-tcpip": func(srv *ssh.Server, conn *gossh.ServerConn, newChan gossh.NewChannel, ctx ssh.Context) {
+				ssh.DirectTCPIPHandler(srv, conn, &sessionNewChannel{
+					NewChannel: newChan,
+					stats:      a.stats,
+				}, ctx)
+			},
+			"session": ssh.DefaultSessionHandler,
 		},
 		ConnectionFailedCallback: func(conn net.Conn, err error) {
 			sshLogger.Info(ctx, "ssh connection ended", slog.Error(err))
@@ -415,6 +587,7 @@ func (a *agent) init(ctx context.Context) {
 		},
 		SubsystemHandlers: map[string]ssh.SubsystemHandler{
 			"sftp": func(session ssh.Session) {
+				defer a.stats.startSession(sessionTypeFromEnv(session.Environ()))()
 				session.DisablePTYEmulation()
 
 				server, err := sftp.NewServer(session)
@@ -433,6 +606,8 @@ func (a *agent) init(ctx context.Context) {
 	}
 
 	go a.run(ctx)
+	go a.reportLifecycleLoop(ctx)
+	go a.awaitShutdownLoop(ctx)
 	if a.statsReporter != nil {
 		cl, err := a.statsReporter(ctx, a.logger, func() *Stats {
 			return a.stats.Copy()
@@ -537,10 +712,15 @@ func (a *agent) createCommand(ctx context.Context, rawCommand string, env []stri
 		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", envKey, value))
 	}
 
+	// Every command, including scripts, is killed before the agent when
+	// the workspace runs out of memory.
+	a.sessionLimiter.wrapCommand(cmd)
+
 	return cmd, nil
 }
 
 func (a *agent) handleSSHSession(session ssh.Session) (retErr error) {
+	defer a.stats.startSession(sessionTypeFromEnv(session.Environ()))()
 	ctx := session.Context()
 	cmd, err := a.createCommand(ctx, session.RawCommand(), session.Environ())
 	if err != nil {
@@ -573,6 +753,7 @@ func (a *agent) handleSSHSession(session ssh.Session) (retErr error) {
 		if err != nil {
 			return xerrors.Errorf("start command: %w", err)
 		}
+		defer a.sessionLimiter.limit(ctx, cmd)()
 		defer func() {
 			closeErr := ptty.Close()
 			if closeErr != nil {
@@ -622,11 +803,13 @@ func (a *agent) handleSSHSession(session ssh.Session) (retErr error) {
 	if err != nil {
 		return xerrors.Errorf("start: %w", err)
 	}
+	defer a.sessionLimiter.limit(ctx, cmd)()
 	return cmd.Wait()
 }
 
 func (a *agent) handleReconnectingPTY(ctx context.Context, msg reconnectingPTYInit, conn net.Conn) {
 	defer conn.Close()
+	defer a.stats.startSession(SessionTypeReconnectingPTY)()
 
 	var rpty *reconnectingPTY
 	rawRPTY, ok := a.reconnectingPTYs.Load(msg.ID)
@@ -657,6 +840,7 @@ func (a *agent) handleReconnectingPTY(ctx context.Context, msg reconnectingPTYIn
 			a.logger.Error(ctx, "start reconnecting pty command", slog.F("id", msg.ID))
 			return
 		}
+		releaseLimits := a.sessionLimiter.limit(ctx, cmd)
 
 		a.closeMutex.Lock()
 		a.connCloseWait.Add(1)
@@ -683,6 +867,7 @@ func (a *agent) handleReconnectingPTY(ctx context.Context, msg reconnectingPTYIn
 			// If the process dies randomly, we should
 			// close the pty.
 			_ = process.Wait()
+			releaseLimits()
 			rpty.Close()
 		}()
 		go func() {
@@ -809,6 +994,7 @@ func (a *agent) Close() error {
 	if a.isClosed() {
 		return nil
 	}
+	a.reportShutdown()
 	close(a.closed)
 	a.closeCancel()
 	if a.network != nil {
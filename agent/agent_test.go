@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"net/http"
 	"net/netip"
 	"os"
 	"os/exec"
@@ -16,6 +17,7 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"testing/iotest"
 	"time"
 
 	"golang.org/x/xerrors"
@@ -66,6 +68,32 @@ func TestAgent(t *testing.T) {
 			assert.Greater(t, (<-stats).TxBytes, int64(0))
 		})
 
+		t.Run("SessionType", func(t *testing.T) {
+			t.Parallel()
+			conn, stats := setupAgent(t, agent.Metadata{}, 0)
+
+			sshClient, err := conn.SSHClient()
+			require.NoError(t, err)
+			defer sshClient.Close()
+			session, err := sshClient.NewSession()
+			require.NoError(t, err)
+			defer session.Close()
+			err = session.Setenv(agent.MagicSessionTypeEnvironmentVariable, "vscode")
+			require.NoError(t, err)
+			err = session.Run("echo test")
+			require.NoError(t, err)
+
+			var s *agent.Stats
+			require.Eventuallyf(t, func() bool {
+				var ok bool
+				s, ok = (<-stats)
+				return ok && s.SessionCount[agent.SessionTypeVSCode] == 1 && s.SessionSeconds > 0
+			}, testutil.WaitLong, testutil.IntervalFast,
+				"never saw stats: %+v", s,
+			)
+			assert.Zero(t, s.SessionCount[agent.SessionTypeSSH])
+		})
+
 		t.Run("ReconnectingPTY", func(t *testing.T) {
 			t.Parallel()
 
@@ -93,6 +121,67 @@ func TestAgent(t *testing.T) {
 		})
 	})
 
+	t.Run("StartupLogs", func(t *testing.T) {
+		t.Parallel()
+		var (
+			mutex  sync.Mutex
+			states []agent.LifecycleState
+			output []string
+		)
+		setupAgentLifecycle(t, agent.Metadata{
+			StartupScript: "echo hello && echo world",
+		}, func(_ context.Context, state agent.LifecycleState) error {
+			mutex.Lock()
+			defer mutex.Unlock()
+			states = append(states, state)
+			return nil
+		}, func(_ context.Context, logs []agent.StartupLog) error {
+			mutex.Lock()
+			defer mutex.Unlock()
+			for _, log := range logs {
+				output = append(output, strings.TrimSpace(log.Output))
+			}
+			return nil
+		})
+
+		require.Eventually(t, func() bool {
+			mutex.Lock()
+			defer mutex.Unlock()
+			return len(states) > 0 && states[len(states)-1] == agent.LifecycleStateReady
+		}, testutil.WaitMedium, testutil.IntervalFast)
+		mutex.Lock()
+		defer mutex.Unlock()
+		require.Equal(t, []string{"hello", "world"}, output)
+	})
+
+	t.Run("StartupScriptTimeout", func(t *testing.T) {
+		t.Parallel()
+		if runtime.GOOS == "windows" {
+			t.Skip("sleep is not available on Windows")
+		}
+		states := make(chan agent.LifecycleState, 10)
+		setupAgentLifecycle(t, agent.Metadata{
+			StartupScript:        "sleep 10",
+			StartupScriptTimeout: 100 * time.Millisecond,
+		}, func(_ context.Context, state agent.LifecycleState) error {
+			states <- state
+			return nil
+		}, nil)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitMedium)
+		defer cancel()
+		for {
+			select {
+			case <-ctx.Done():
+				t.Fatal("timed out waiting for lifecycle state")
+			case state := <-states:
+				if state == agent.LifecycleStateStartTimeout {
+					return
+				}
+			}
+		}
+	})
+
 	t.Run("SessionExec", func(t *testing.T) {
 		t.Parallel()
 		session := setupSSHSession(t, agent.Metadata{})
@@ -493,6 +582,72 @@ func TestAgent(t *testing.T) {
 		}, testutil.WaitMedium, testutil.IntervalFast)
 	})
 
+	t.Run("ListeningPorts", func(t *testing.T) {
+		t.Parallel()
+		conn, _ := setupAgent(t, agent.Metadata{}, 0)
+
+		// The agent runs in the test process, so its listeners are
+		// excluded.
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer listener.Close()
+		ownPort := uint16(listener.Addr().(*net.TCPAddr).Port)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+		resp, err := conn.ListeningPorts(ctx)
+		require.NoError(t, err)
+		for _, port := range resp.Ports {
+			require.NotEqual(t, ownPort, port.Port)
+		}
+	})
+
+	t.Run("Files", func(t *testing.T) {
+		t.Parallel()
+		conn, _ := setupAgent(t, agent.Metadata{}, 0)
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		dir := t.TempDir()
+		path := filepath.Join(dir, "nested", "hello.txt")
+		info, err := conn.WriteFile(ctx, path, 0o600, strings.NewReader("hello"))
+		require.NoError(t, err)
+		require.Equal(t, "hello.txt", info.Name)
+		require.EqualValues(t, 5, info.Size)
+
+		info, err = conn.StatFile(ctx, filepath.Join(dir, "nested"))
+		require.NoError(t, err)
+		require.True(t, info.IsDir)
+
+		files, err := conn.ListFiles(ctx, filepath.Join(dir, "nested"))
+		require.NoError(t, err)
+		require.Len(t, files.Files, 1)
+		require.Equal(t, "hello.txt", files.Files[0].Name)
+
+		content, size, err := conn.ReadFile(ctx, path)
+		require.NoError(t, err)
+		defer content.Close()
+		data, err := io.ReadAll(content)
+		require.NoError(t, err)
+		require.Equal(t, "hello", string(data))
+		require.EqualValues(t, 5, size)
+
+		// A failed upload doesn't replace the existing file.
+		_, err = conn.WriteFile(ctx, path, 0o600, io.MultiReader(strings.NewReader("partial"), iotest.ErrReader(io.ErrUnexpectedEOF)))
+		require.Error(t, err)
+		content, _, err = conn.ReadFile(ctx, path)
+		require.NoError(t, err)
+		defer content.Close()
+		data, err = io.ReadAll(content)
+		require.NoError(t, err)
+		require.Equal(t, "hello", string(data))
+
+		_, err = conn.StatFile(ctx, filepath.Join(dir, "missing"))
+		var apiErr *agent.APIError
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	})
+
 	t.Run("Speedtest", func(t *testing.T) {
 		t.Parallel()
 		if testing.Short() {
@@ -653,6 +808,41 @@ func setupAgent(t *testing.T, metadata agent.Metadata, ptyTimeout time.Duration)
 	}, statsCh
 }
 
+// setupAgentLifecycle starts an agent without a client connection to
+// observe how it reports startup progress.
+func setupAgentLifecycle(t *testing.T, metadata agent.Metadata, reportLifecycle agent.ReportLifecycle, patchStartupLogs agent.PatchStartupLogs) {
+	if metadata.DERPMap == nil {
+		metadata.DERPMap = tailnettest.RunDERPAndSTUN(t)
+	}
+	coordinator := tailnet.NewCoordinator()
+	agentID := uuid.New()
+	closer := agent.New(agent.Options{
+		FetchMetadata: func(ctx context.Context) (agent.Metadata, error) {
+			return metadata, nil
+		},
+		CoordinatorDialer: func(ctx context.Context) (net.Conn, error) {
+			clientConn, serverConn := net.Pipe()
+			closed := make(chan struct{})
+			t.Cleanup(func() {
+				_ = serverConn.Close()
+				_ = clientConn.Close()
+				<-closed
+			})
+			go func() {
+				_ = coordinator.ServeAgent(serverConn, agentID)
+				close(closed)
+			}()
+			return clientConn, nil
+		},
+		ReportLifecycle:  reportLifecycle,
+		PatchStartupLogs: patchStartupLogs,
+		Logger:           slogtest.Make(t, nil).Leveled(slog.LevelDebug),
+	})
+	t.Cleanup(func() {
+		_ = closer.Close()
+	})
+}
+
 var dialTestPayload = []byte("dean-was-here123")
 
 func testDial(t *testing.T, c net.Conn) {
@@ -0,0 +1,162 @@
+package agent
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/retry"
+)
+
+// AppHealth is the health of an application, as determined by its
+// healthcheck.
+type AppHealth string
+
+const (
+	AppHealthInitializing AppHealth = "initializing"
+	AppHealthHealthy      AppHealth = "healthy"
+	AppHealthUnhealthy    AppHealth = "unhealthy"
+)
+
+// AppHealthcheck describes an HTTP endpoint that is requested periodically to
+// determine the health of an application.
+type AppHealthcheck struct {
+	// ID is the ID of the workspace application.
+	ID  uuid.UUID `json:"id"`
+	URL string    `json:"url"`
+	// Interval is the number of seconds between healthcheck requests.
+	Interval int32 `json:"interval"`
+	// Threshold is the number of consecutive failed healthchecks before the
+	// application is unhealthy.
+	Threshold int32 `json:"threshold"`
+}
+
+// PostAppHealth is a function to report the health of applications.
+type PostAppHealth func(ctx context.Context, healths map[uuid.UUID]AppHealth) error
+
+// appHealthLoop runs the healthcheck of each application on its interval and
+// reports the health to coderd whenever it changes, retrying until the
+// report is accepted.
+func (a *agent) appHealthLoop(ctx context.Context, healthchecks []AppHealthcheck) {
+	if a.postAppHealth == nil || len(healthchecks) == 0 {
+		return
+	}
+
+	var (
+		mu      sync.Mutex
+		healths = make(map[uuid.UUID]AppHealth, len(healthchecks))
+		// Updates are coalesced, since every report contains all healths.
+		update = make(chan struct{}, 1)
+	)
+	setHealth := func(id uuid.UUID, health AppHealth) {
+		mu.Lock()
+		defer mu.Unlock()
+		if healths[id] == health {
+			return
+		}
+		healths[id] = health
+		select {
+		case update <- struct{}{}:
+		default:
+		}
+	}
+	for _, hc := range healthchecks {
+		healths[hc.ID] = AppHealthInitializing
+		go a.runAppHealthcheck(ctx, hc, setHealth)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-update:
+		}
+
+		// Failed reports are retried with the latest healths, so coderd
+		// doesn't keep showing stale healths until the next change.
+		retrier := retry.New(time.Second, 15*time.Second)
+		for {
+			mu.Lock()
+			report := make(map[uuid.UUID]AppHealth, len(healths))
+			for id, health := range healths {
+				report[id] = health
+			}
+			mu.Unlock()
+
+			err := a.postAppHealth(ctx, report)
+			if err == nil {
+				break
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			a.logger.Warn(ctx, "report app health", slog.Error(err))
+			if !retrier.Wait(ctx) {
+				return
+			}
+		}
+	}
+}
+
+func (a *agent) runAppHealthcheck(ctx context.Context, hc AppHealthcheck, setHealth func(uuid.UUID, AppHealth)) {
+	interval := time.Duration(hc.Interval) * time.Second
+	if interval <= 0 {
+		interval = time.Second
+	}
+	threshold := hc.Threshold
+	if threshold <= 0 {
+		threshold = 1
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var failures int32
+	for {
+		err := checkAppHealth(ctx, hc.URL, interval)
+		switch {
+		case ctx.Err() != nil:
+			return
+		case err == nil:
+			failures = 0
+			setHealth(hc.ID, AppHealthHealthy)
+		default:
+			failures++
+			a.logger.Debug(ctx, "app healthcheck failed", slog.F("url", hc.URL), slog.F("failures", failures), slog.Error(err))
+			// Applications stay initializing until they've failed enough
+			// times to be considered unhealthy.
+			if failures >= threshold {
+				setHealth(hc.ID, AppHealthUnhealthy)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkAppHealth requests the URL provided. Server errors are considered a
+// failed healthcheck, any other response is healthy.
+func checkAppHealth(ctx context.Context, rawURL string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return err
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	_ = res.Body.Close()
+	if res.StatusCode >= http.StatusInternalServerError {
+		return xerrors.Errorf("unexpected status code %d", res.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package agent
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/testutil"
+)
+
+func TestAppHealthLoop(t *testing.T) {
+	t.Parallel()
+
+	t.Run("RetryReport", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+			rw.WriteHeader(http.StatusOK)
+		}))
+		defer srv.Close()
+
+		var attempts atomic.Int32
+		reports := make(chan map[uuid.UUID]AppHealth, 2)
+		a := &agent{
+			logger: slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}),
+			postAppHealth: func(ctx context.Context, healths map[uuid.UUID]AppHealth) error {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case reports <- healths:
+				}
+				// coderd is unavailable for the first report.
+				if attempts.Add(1) == 1 {
+					return xerrors.New("unavailable")
+				}
+				return nil
+			},
+		}
+		appID := uuid.New()
+		go a.appHealthLoop(ctx, []AppHealthcheck{{
+			ID:        appID,
+			URL:       srv.URL,
+			Interval:  1,
+			Threshold: 1,
+		}})
+
+		// The failed report is retried even though the health doesn't
+		// change again.
+		for i := 0; i < 2; i++ {
+			select {
+			case <-ctx.Done():
+				t.Fatal("timed out waiting for app health report")
+			case healths := <-reports:
+				require.Equal(t, AppHealthHealthy, healths[appID])
+			}
+		}
+	})
+}
@@ -4,9 +4,15 @@ import (
 	"context"
 	"encoding/binary"
 	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
 	"net"
+	"net/http"
 	"net/netip"
+	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"golang.org/x/crypto/ssh"
@@ -128,6 +134,123 @@ func (c *Conn) Speedtest(direction speedtest.Direction, duration time.Duration)
 	return results, err
 }
 
+// ListeningPorts returns the TCP ports that processes in the workspace
+// listen on.
+func (c *Conn) ListeningPorts(ctx context.Context) (ListeningPortsResponse, error) {
+	res, err := c.apiRequest(ctx, http.MethodGet, "/api/v0/listening-ports", nil)
+	if err != nil {
+		return ListeningPortsResponse{}, err
+	}
+	defer res.Body.Close()
+	var resp ListeningPortsResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// StatFile returns information about a file in the workspace. Relative paths
+// are relative to the home directory of the agent user.
+func (c *Conn) StatFile(ctx context.Context, path string) (FileInfo, error) {
+	res, err := c.apiRequest(ctx, http.MethodGet, "/api/v0/files/stat?"+url.Values{"path": {path}}.Encode(), nil)
+	if err != nil {
+		return FileInfo{}, err
+	}
+	defer res.Body.Close()
+	var info FileInfo
+	return info, json.NewDecoder(res.Body).Decode(&info)
+}
+
+// ListFiles returns the files in a directory in the workspace.
+func (c *Conn) ListFiles(ctx context.Context, path string) (ListFilesResponse, error) {
+	res, err := c.apiRequest(ctx, http.MethodGet, "/api/v0/files/list?"+url.Values{"path": {path}}.Encode(), nil)
+	if err != nil {
+		return ListFilesResponse{}, err
+	}
+	defer res.Body.Close()
+	var resp ListFilesResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// ReadFile returns the contents of a file in the workspace and its size. The
+// caller must close the reader.
+func (c *Conn) ReadFile(ctx context.Context, path string) (io.ReadCloser, int64, error) {
+	res, err := c.apiRequest(ctx, http.MethodGet, "/api/v0/files/read?"+url.Values{"path": {path}}.Encode(), nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	return res.Body, res.ContentLength, nil
+}
+
+// WriteFile creates or replaces a file in the workspace with the contents of
+// the reader. Parent directories are created if they don't exist.
+func (c *Conn) WriteFile(ctx context.Context, path string, mode fs.FileMode, content io.Reader) (FileInfo, error) {
+	query := url.Values{
+		"path": {path},
+		"mode": {strconv.FormatUint(uint64(mode.Perm()), 8)},
+	}
+	res, err := c.apiRequest(ctx, http.MethodPost, "/api/v0/files/write?"+query.Encode(), content)
+	if err != nil {
+		return FileInfo{}, err
+	}
+	defer res.Body.Close()
+	var info FileInfo
+	return info, json.NewDecoder(res.Body).Decode(&info)
+}
+
+// apiRequest makes a request to the HTTP API of the agent. Responses with a
+// status code other than 200 are returned as an error.
+func (c *Conn) apiRequest(ctx context.Context, method, path string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, "http://agent"+path, body)
+	if err != nil {
+		return nil, xerrors.Errorf("create request: %w", err)
+	}
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return c.DialContextTCP(ctx, netip.AddrPortFrom(tailnetIP, uint16(tailnetHTTPAPIPort)))
+		},
+	}
+	res, err := (&http.Client{Transport: transport}).Do(req)
+	if err != nil {
+		transport.CloseIdleConnections()
+		return nil, xerrors.Errorf("do request: %w", err)
+	}
+	if res.StatusCode != http.StatusOK {
+		defer transport.CloseIdleConnections()
+		defer res.Body.Close()
+		message, _ := io.ReadAll(io.LimitReader(res.Body, 4096))
+		return nil, &APIError{
+			StatusCode: res.StatusCode,
+			Message:    strings.TrimSpace(string(message)),
+		}
+	}
+	res.Body = &closeTransportBody{ReadCloser: res.Body, transport: transport}
+	return res, nil
+}
+
+// APIError is returned when the HTTP API of the agent responds with an error.
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("unexpected status code %d", e.StatusCode)
+	}
+	return fmt.Sprintf("unexpected status code %d: %s", e.StatusCode, e.Message)
+}
+
+// closeTransportBody closes the idle connections of the transport once the
+// response body is closed, since a transport is created for each request.
+type closeTransportBody struct {
+	io.ReadCloser
+	transport *http.Transport
+}
+
+func (b *closeTransportBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.transport.CloseIdleConnections()
+	return err
+}
+
 func (c *Conn) DialContext(ctx context.Context, network string, addr string) (net.Conn, error) {
 	if network == "unix" {
 		return nil, xerrors.New("network must be tcp or udp")
@@ -0,0 +1,236 @@
+package agent
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+)
+
+// FileInfo describes a file or directory in the workspace.
+type FileInfo struct {
+	Name    string      `json:"name"`
+	Size    int64       `json:"size"`
+	Mode    fs.FileMode `json:"mode"`
+	ModTime time.Time   `json:"mod_time"`
+	IsDir   bool        `json:"is_dir"`
+}
+
+// ListFilesResponse contains the files in a directory.
+type ListFilesResponse struct {
+	// Path is the absolute path of the directory.
+	Path  string     `json:"path"`
+	Files []FileInfo `json:"files"`
+}
+
+func convertFileInfo(info fs.FileInfo) FileInfo {
+	return FileInfo{
+		Name:    info.Name(),
+		Size:    info.Size(),
+		Mode:    info.Mode(),
+		ModTime: info.ModTime(),
+		IsDir:   info.IsDir(),
+	}
+}
+
+// resolveFilePath returns the absolute path of a file. Relative paths are
+// relative to the home directory of the agent user.
+func resolveFilePath(in string) (string, error) {
+	if in == "" {
+		return "", xerrors.New("path is required")
+	}
+	if !filepath.IsAbs(in) && in != "~" && !strings.HasPrefix(in, "~/") {
+		in = "~/" + in
+	}
+	return ExpandRelativeHomePath(in)
+}
+
+// writeFileError writes the error to the response with a status code that
+// matches the cause.
+func (a *agent) writeFileError(rw http.ResponseWriter, r *http.Request, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		status = http.StatusNotFound
+	case errors.Is(err, fs.ErrPermission):
+		status = http.StatusForbidden
+	default:
+		a.logger.Warn(r.Context(), "file request failed", slog.F("path", r.URL.Query().Get("path")), slog.Error(err))
+	}
+	http.Error(rw, err.Error(), status)
+}
+
+func (a *agent) handleStatFile(rw http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		rw.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	path, err := resolveFilePath(r.URL.Query().Get("path"))
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		a.writeFileError(rw, r, err)
+		return
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(rw).Encode(convertFileInfo(info))
+}
+
+func (a *agent) handleListFiles(rw http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		rw.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	path, err := resolveFilePath(r.URL.Query().Get("path"))
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		a.writeFileError(rw, r, err)
+		return
+	}
+	files := make([]FileInfo, 0, len(entries))
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			// The file may have been removed since the directory was read.
+			continue
+		}
+		files = append(files, convertFileInfo(info))
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Name < files[j].Name
+	})
+	rw.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(rw).Encode(ListFilesResponse{
+		Path:  path,
+		Files: files,
+	})
+}
+
+func (a *agent) handleReadFile(rw http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		rw.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	path, err := resolveFilePath(r.URL.Query().Get("path"))
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	// #nosec G304 -- The agent user can read any file they have access to.
+	file, err := os.Open(path)
+	if err != nil {
+		a.writeFileError(rw, r, err)
+		return
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		a.writeFileError(rw, r, err)
+		return
+	}
+	if info.IsDir() {
+		http.Error(rw, xerrors.Errorf("%q is a directory", path).Error(), http.StatusBadRequest)
+		return
+	}
+	rw.Header().Set("Content-Type", "application/octet-stream")
+	rw.Header().Set("Content-Length", strconv.FormatInt(info.Size(), 10))
+	rw.Header().Set("X-File-Mode", strconv.FormatUint(uint64(info.Mode().Perm()), 8))
+	rw.WriteHeader(http.StatusOK)
+	_, _ = io.Copy(rw, file)
+}
+
+// handleWriteFile creates or replaces a file with the request body. Parent
+// directories are created if they don't exist.
+func (a *agent) handleWriteFile(rw http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		rw.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	path, err := resolveFilePath(r.URL.Query().Get("path"))
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	mode := fs.FileMode(0o644)
+	rawMode := r.URL.Query().Get("mode")
+	if rawMode != "" {
+		parsed, err := strconv.ParseUint(rawMode, 8, 32)
+		if err != nil {
+			http.Error(rw, xerrors.Errorf("parse mode: %w", err).Error(), http.StatusBadRequest)
+			return
+		}
+		mode = fs.FileMode(parsed).Perm()
+	} else if existing, err := os.Stat(path); err == nil {
+		// Replacing a file keeps its mode unless one is provided.
+		mode = existing.Mode().Perm()
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		a.writeFileError(rw, r, err)
+		return
+	}
+	// The body is written to a temporary file that replaces the target once
+	// it's complete, so a failed upload doesn't leave a truncated file.
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		a.writeFileError(rw, r, err)
+		return
+	}
+	defer func() {
+		// This fails once the file has been renamed.
+		_ = os.Remove(file.Name())
+	}()
+	_, err = io.Copy(file, r.Body)
+	if err != nil {
+		_ = file.Close()
+		a.writeFileError(rw, r, xerrors.Errorf("write file: %w", err))
+		return
+	}
+	err = file.Sync()
+	if err != nil {
+		_ = file.Close()
+		a.writeFileError(rw, r, xerrors.Errorf("sync file: %w", err))
+		return
+	}
+	err = file.Close()
+	if err != nil {
+		a.writeFileError(rw, r, err)
+		return
+	}
+	err = os.Chmod(file.Name(), mode)
+	if err != nil {
+		a.writeFileError(rw, r, err)
+		return
+	}
+	err = os.Rename(file.Name(), path)
+	if err != nil {
+		a.writeFileError(rw, r, err)
+		return
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		a.writeFileError(rw, r, err)
+		return
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(rw).Encode(convertFileInfo(info))
+}
@@ -0,0 +1,257 @@
+package agent
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"cdr.dev/slog"
+	"github.com/coder/retry"
+)
+
+const (
+	// startupLogMaxLength is the maximum length of a single line of
+	// startup script output. Longer lines are truncated.
+	startupLogMaxLength = 1024
+	// startupLogBatchSize is the maximum number of lines sent at once.
+	startupLogBatchSize = 100
+	// startupLogFlushInterval is how often buffered output is sent.
+	startupLogFlushInterval = 250 * time.Millisecond
+	// startupLogMaxAttempts is how many times a batch is sent before
+	// giving up.
+	startupLogMaxAttempts = 5
+)
+
+// StartupLog is a line of output from the startup script.
+type StartupLog struct {
+	CreatedAt time.Time `json:"created_at"`
+	Output    string    `json:"output"`
+}
+
+func (a *agent) setLifecycle(ctx context.Context, state LifecycleState) {
+	a.lifecycleMutex.Lock()
+	if a.shuttingDown && !isShutdownState(state) {
+		// The startup script may finish after shutdown has begun.
+		a.lifecycleMutex.Unlock()
+		return
+	}
+	if isShutdownState(state) {
+		a.shuttingDown = true
+	}
+	a.lifecycleState = state
+	a.lifecycleMutex.Unlock()
+
+	a.logger.Debug(ctx, "set lifecycle state", slog.F("state", state))
+	select {
+	case a.lifecycleUpdate <- struct{}{}:
+	default:
+	}
+}
+
+func (a *agent) lifecycle() LifecycleState {
+	a.lifecycleMutex.RLock()
+	defer a.lifecycleMutex.RUnlock()
+	return a.lifecycleState
+}
+
+// reportLifecycleLoop reports the latest lifecycle state to coderd. States
+// that change while a report is in flight are coalesced.
+func (a *agent) reportLifecycleLoop(ctx context.Context) {
+	if a.reportLifecycle == nil {
+		return
+	}
+	var lastReported LifecycleState
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-a.lifecycleUpdate:
+		}
+
+		retrier := retry.New(time.Second, 15*time.Second)
+		for {
+			state := a.lifecycle()
+			if state == lastReported {
+				break
+			}
+			err := a.reportLifecycle(ctx, state)
+			if err == nil {
+				lastReported = state
+				continue
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			a.logger.Warn(ctx, "report lifecycle state", slog.F("state", state), slog.Error(err))
+			if !retrier.Wait(ctx) {
+				return
+			}
+		}
+	}
+}
+
+func isShutdownState(state LifecycleState) bool {
+	switch state {
+	case LifecycleStateShuttingDown, LifecycleStateShutdownTimeout, LifecycleStateShutdownError, LifecycleStateOff:
+		return true
+	default:
+		return false
+	}
+}
+
+// awaitShutdownLoop runs the shutdown script once coderd requests a
+// graceful shutdown.
+func (a *agent) awaitShutdownLoop(ctx context.Context) {
+	if a.awaitShutdown == nil {
+		return
+	}
+	retrier := retry.New(time.Second, 15*time.Second)
+	for {
+		err := a.awaitShutdown(ctx)
+		if err == nil {
+			a.logger.Info(ctx, "shutdown requested")
+			a.shutdown(ctx)
+			return
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		a.logger.Debug(ctx, "await shutdown", slog.Error(err))
+		if !retrier.Wait(ctx) {
+			return
+		}
+	}
+}
+
+// shutdown runs the shutdown script once, reporting its progress through the
+// lifecycle state.
+func (a *agent) shutdown(ctx context.Context) LifecycleState {
+	a.shutdownOnce.Do(func() {
+		a.setLifecycle(ctx, LifecycleStateShuttingDown)
+
+		var metadata Metadata
+		if rawMetadata, ok := a.metadata.Load().(Metadata); ok {
+			metadata = rawMetadata
+		}
+		err := a.runShutdownScript(ctx, metadata.ShutdownScript, metadata.ShutdownScriptTimeout)
+		switch {
+		case errors.Is(err, context.DeadlineExceeded):
+			a.logger.Warn(ctx, "shutdown script timed out", slog.F("timeout", metadata.ShutdownScriptTimeout))
+			a.setLifecycle(ctx, LifecycleStateShutdownTimeout)
+		case err != nil:
+			a.logger.Warn(ctx, "shutdown script failed", slog.Error(err))
+			a.setLifecycle(ctx, LifecycleStateShutdownError)
+		default:
+			a.setLifecycle(ctx, LifecycleStateOff)
+		}
+	})
+	return a.lifecycle()
+}
+
+// reportShutdown runs the shutdown script, if it hasn't run already, and
+// makes a best-effort attempt to report the outcome before the agent's
+// context is canceled.
+func (a *agent) reportShutdown() {
+	state := a.shutdown(context.Background())
+	if a.reportLifecycle == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := a.reportLifecycle(ctx, state)
+	if err != nil {
+		a.logger.Debug(ctx, "report shutdown", slog.Error(err))
+	}
+}
+
+// startupLogsSender is an io.Writer that splits startup script output into
+// lines and sends them to coderd in batches.
+type startupLogsSender struct {
+	*io.PipeWriter
+	done chan struct{}
+}
+
+// Close flushes any buffered output and waits for it to be sent.
+func (s *startupLogsSender) Close() error {
+	err := s.PipeWriter.Close()
+	<-s.done
+	return err
+}
+
+func (a *agent) newStartupLogsSender(ctx context.Context) io.WriteCloser {
+	reader, writer := io.Pipe()
+	sender := &startupLogsSender{
+		PipeWriter: writer,
+		done:       make(chan struct{}),
+	}
+
+	lines := make(chan StartupLog)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(reader)
+		for scanner.Scan() {
+			output := scanner.Text()
+			if len(output) > startupLogMaxLength {
+				output = output[:startupLogMaxLength]
+			}
+			select {
+			case lines <- StartupLog{CreatedAt: time.Now(), Output: output}:
+			case <-ctx.Done():
+			}
+		}
+		// Drain the pipe if the scanner failed, e.g. on a line that
+		// exceeds its buffer, so the script doesn't block on writes.
+		_, _ = io.Copy(io.Discard, reader)
+	}()
+
+	go func() {
+		defer close(sender.done)
+		ticker := time.NewTicker(startupLogFlushInterval)
+		defer ticker.Stop()
+
+		var pending []StartupLog
+		// Output is dropped once sending fails repeatedly, e.g. when
+		// coderd rejects it for exceeding the size limit, so the script
+		// never blocks on writes.
+		disabled := a.patchStartupLogs == nil
+		flush := func() {
+			defer func() {
+				pending = nil
+			}()
+			if len(pending) == 0 || disabled {
+				return
+			}
+			retrier := retry.New(250*time.Millisecond, 5*time.Second)
+			for attempt := 1; ; attempt++ {
+				err := a.patchStartupLogs(ctx, pending)
+				if err == nil || ctx.Err() != nil {
+					return
+				}
+				a.logger.Warn(ctx, "send startup logs", slog.F("attempt", attempt), slog.Error(err))
+				if attempt >= startupLogMaxAttempts || !retrier.Wait(ctx) {
+					break
+				}
+			}
+			a.logger.Error(ctx, "startup logs will no longer be sent")
+			disabled = true
+		}
+		for {
+			select {
+			case log, ok := <-lines:
+				if !ok {
+					flush()
+					return
+				}
+				pending = append(pending, log)
+				if len(pending) >= startupLogBatchSize {
+					flush()
+				}
+			case <-ticker.C:
+				flush()
+			}
+		}
+	}()
+	return sender
+}
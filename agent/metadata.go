@@ -0,0 +1,111 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"cdr.dev/slog"
+)
+
+const (
+	// metadataValueMaxLength is the maximum length of a metadata value or
+	// error. Longer output is truncated.
+	metadataValueMaxLength = 10 << 10
+	// metadataDefaultTimeout is used for scripts that don't set a timeout.
+	metadataDefaultTimeout = time.Minute
+)
+
+// MetadataDescription describes a script that is run periodically to collect
+// a metadata value, e.g. CPU usage or the current git branch.
+type MetadataDescription struct {
+	DisplayName string `json:"display_name"`
+	Key         string `json:"key"`
+	Script      string `json:"script"`
+	// Interval is the number of seconds between runs. If zero, the script
+	// only runs once.
+	Interval int64 `json:"interval"`
+	// Timeout is the number of seconds the script may run for.
+	Timeout int64 `json:"timeout"`
+}
+
+// MetadataResult is the outcome of running a metadata script.
+type MetadataResult struct {
+	CollectedAt time.Time `json:"collected_at"`
+	Value       string    `json:"value"`
+	Error       string    `json:"error"`
+}
+
+// reportMetadataLoop runs each metadata script on its interval and reports
+// the results to coderd.
+func (a *agent) reportMetadataLoop(ctx context.Context, descriptions []MetadataDescription) {
+	if a.postMetadata == nil {
+		return
+	}
+	for _, md := range descriptions {
+		go func(md MetadataDescription) {
+			if md.Interval <= 0 {
+				a.reportMetadata(ctx, md)
+				return
+			}
+			ticker := time.NewTicker(time.Duration(md.Interval) * time.Second)
+			defer ticker.Stop()
+			for {
+				a.reportMetadata(ctx, md)
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				}
+			}
+		}(md)
+	}
+}
+
+func (a *agent) reportMetadata(ctx context.Context, md MetadataDescription) {
+	result := a.collectMetadata(ctx, md)
+	if ctx.Err() != nil {
+		return
+	}
+	err := a.postMetadata(ctx, md.Key, result)
+	if err != nil && ctx.Err() == nil {
+		a.logger.Warn(ctx, "report metadata", slog.F("key", md.Key), slog.Error(err))
+	}
+}
+
+func (a *agent) collectMetadata(ctx context.Context, md MetadataDescription) MetadataResult {
+	timeout := time.Duration(md.Timeout) * time.Second
+	if timeout <= 0 {
+		timeout = metadataDefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	result := MetadataResult{}
+	var output bytes.Buffer
+	cmd, err := a.createCommand(ctx, md.Script, nil)
+	if err == nil {
+		cmd.Stdout = &output
+		cmd.Stderr = &output
+		err = cmd.Run()
+	}
+	result.CollectedAt = time.Now()
+	result.Value = truncateMetadata(strings.TrimSpace(output.String()))
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		result.Error = fmt.Sprintf("script timed out after %s", timeout)
+	case err != nil:
+		result.Error = truncateMetadata(err.Error())
+	}
+	return result
+}
+
+func truncateMetadata(value string) string {
+	if len(value) > metadataValueMaxLength {
+		return value[:metadataValueMaxLength]
+	}
+	return value
+}
@@ -0,0 +1,62 @@
+package agent
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	"cdr.dev/slog"
+)
+
+// ListeningPort is a TCP port that a process in the workspace listens on.
+type ListeningPort struct {
+	ProcessName string `json:"process_name"`
+	Network     string `json:"network"`
+	Port        uint16 `json:"port"`
+}
+
+type ListeningPortsResponse struct {
+	Ports []ListeningPort `json:"ports"`
+}
+
+// apiHandler serves the agent's HTTP API over the tailnet.
+func (a *agent) apiHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v0/listening-ports", func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			rw.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		ports, err := listeningPorts()
+		if err != nil {
+			a.logger.Warn(r.Context(), "list listening ports", slog.Error(err))
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		rw.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(rw).Encode(ListeningPortsResponse{
+			Ports: ports,
+		})
+	})
+	mux.HandleFunc("/api/v0/files/stat", a.handleStatFile)
+	mux.HandleFunc("/api/v0/files/list", a.handleListFiles)
+	mux.HandleFunc("/api/v0/files/read", a.handleReadFile)
+	mux.HandleFunc("/api/v0/files/write", a.handleWriteFile)
+	return mux
+}
+
+// sortListeningPorts sorts ports by number and removes duplicates, e.g. a
+// process listening on both IPv4 and IPv6.
+func sortListeningPorts(ports []ListeningPort) []ListeningPort {
+	sort.Slice(ports, func(i, j int) bool {
+		return ports[i].Port < ports[j].Port
+	})
+	deduped := make([]ListeningPort, 0, len(ports))
+	for i, port := range ports {
+		if i > 0 && ports[i-1].Port == port.Port {
+			continue
+		}
+		deduped = append(deduped, port)
+	}
+	return deduped
+}
@@ -0,0 +1,120 @@
+package agent
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// tcpStateListen is the state of listening sockets in /proc/net/tcp.
+const tcpStateListen = "0A"
+
+// listeningPorts returns the TCP ports processes listen on, excluding the
+// agent's own.
+func listeningPorts() ([]ListeningPort, error) {
+	var sockets []procNetSocket
+	for _, path := range []string{"/proc/net/tcp", "/proc/net/tcp6"} {
+		file, err := os.Open(path)
+		if os.IsNotExist(err) {
+			// IPv6 may be disabled.
+			continue
+		}
+		if err != nil {
+			return nil, xerrors.Errorf("open %s: %w", path, err)
+		}
+		parsed, err := parseProcNet(file)
+		_ = file.Close()
+		if err != nil {
+			return nil, xerrors.Errorf("parse %s: %w", path, err)
+		}
+		sockets = append(sockets, parsed...)
+	}
+
+	processes := socketProcesses()
+	self := os.Getpid()
+	ports := make([]ListeningPort, 0, len(sockets))
+	for _, socket := range sockets {
+		process, ok := processes[socket.inode]
+		if ok && process.pid == self {
+			continue
+		}
+		ports = append(ports, ListeningPort{
+			ProcessName: process.name,
+			Network:     "tcp",
+			Port:        socket.port,
+		})
+	}
+	return sortListeningPorts(ports), nil
+}
+
+type procNetSocket struct {
+	port  uint16
+	inode string
+}
+
+// parseProcNet returns the listening sockets in the format of
+// /proc/net/tcp, e.g.
+//
+//	sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+//	 0: 00000000:0016 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 1234 ...
+func parseProcNet(r io.Reader) ([]procNetSocket, error) {
+	var sockets []procNetSocket
+	scanner := bufio.NewScanner(r)
+	// Skip the header.
+	scanner.Scan()
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 || fields[3] != tcpStateListen {
+			continue
+		}
+		_, rawPort, ok := strings.Cut(fields[1], ":")
+		if !ok {
+			return nil, xerrors.Errorf("invalid local address %q", fields[1])
+		}
+		port, err := strconv.ParseUint(rawPort, 16, 16)
+		if err != nil {
+			return nil, xerrors.Errorf("parse port %q: %w", rawPort, err)
+		}
+		sockets = append(sockets, procNetSocket{
+			port:  uint16(port),
+			inode: fields[9],
+		})
+	}
+	return sockets, scanner.Err()
+}
+
+type socketProcess struct {
+	pid  int
+	name string
+}
+
+// socketProcesses maps socket inodes to the processes that own them. Only
+// processes the agent can inspect, usually those of the same user, are
+// included.
+func socketProcesses() map[string]socketProcess {
+	processes := make(map[string]socketProcess)
+	fds, _ := filepath.Glob("/proc/[0-9]*/fd/[0-9]*")
+	for _, fd := range fds {
+		link, err := os.Readlink(fd)
+		if err != nil || !strings.HasPrefix(link, "socket:[") {
+			continue
+		}
+		inode := strings.TrimSuffix(strings.TrimPrefix(link, "socket:["), "]")
+		pidDir := filepath.Dir(filepath.Dir(fd))
+		pid, err := strconv.Atoi(filepath.Base(pidDir))
+		if err != nil {
+			continue
+		}
+		name, _ := os.ReadFile(filepath.Join(pidDir, "comm"))
+		processes[inode] = socketProcess{
+			pid:  pid,
+			name: strings.TrimSpace(string(name)),
+		}
+	}
+	return processes
+}
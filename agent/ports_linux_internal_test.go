@@ -0,0 +1,37 @@
+package agent
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseProcNet(t *testing.T) {
+	t.Parallel()
+
+	sockets, err := parseProcNet(strings.NewReader(`  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000:1F90 00000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 4242 1 0000000000000000 100 0 0 10 0
+   1: 0100007F:0BB8 00000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 4343 1 0000000000000000 100 0 0 10 0
+   2: 0100007F:0BB8 0100007F:D431 01 00000000:00000000 00:00000000 00000000  1000        0 4444 1 0000000000000000 20 4 30 10 -1
+`))
+	require.NoError(t, err)
+	require.Equal(t, []procNetSocket{
+		{port: 8080, inode: "4242"},
+		{port: 3000, inode: "4343"},
+	}, sockets)
+}
+
+func TestSortListeningPorts(t *testing.T) {
+	t.Parallel()
+
+	ports := sortListeningPorts([]ListeningPort{
+		{ProcessName: "python3", Network: "tcp", Port: 8080},
+		{ProcessName: "node", Network: "tcp", Port: 3000},
+		{ProcessName: "python3", Network: "tcp", Port: 8080},
+	})
+	require.Equal(t, []ListeningPort{
+		{ProcessName: "node", Network: "tcp", Port: 3000},
+		{ProcessName: "python3", Network: "tcp", Port: 8080},
+	}, ports)
+}
@@ -0,0 +1,9 @@
+//go:build !linux
+// +build !linux
+
+package agent
+
+// listeningPorts is only supported on Linux.
+func listeningPorts() ([]ListeningPort, error) {
+	return []ListeningPort{}, nil
+}
@@ -4,6 +4,7 @@ package reaper
 
 import (
 	"os"
+	"os/signal"
 	"syscall"
 
 	"github.com/hashicorp/go-reap"
@@ -53,6 +54,22 @@ func ForkReap(opt ...Option) error {
 	//#nosec G204
 	pid, _ := syscall.ForkExec(opts.ExecArgs[0], opts.ExecArgs, pattrs)
 
+	if len(opts.CatchSignals) > 0 {
+		// Forward signals to the child so it can shut down gracefully.
+		sigs := make(chan os.Signal, 1)
+		signal.Notify(sigs, opts.CatchSignals...)
+		defer signal.Stop(sigs)
+		go func() {
+			for sig := range sigs {
+				sysSig, ok := sig.(syscall.Signal)
+				if !ok {
+					continue
+				}
+				_ = syscall.Kill(pid, sysSig)
+			}
+		}()
+	}
+
 	var wstatus syscall.WaitStatus
 	_, err = syscall.Wait4(pid, &wstatus, 0, nil)
 	for xerrors.Is(err, syscall.EINTR) {
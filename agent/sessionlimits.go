@@ -0,0 +1,54 @@
+package agent
+
+import (
+	"context"
+	"time"
+
+	"cdr.dev/slog"
+	"github.com/coder/retry"
+)
+
+// SessionLimits restrict the resources used by the processes of each
+// session, e.g. an SSH connection. Zero values are unlimited.
+type SessionLimits struct {
+	// CPUs is the number of CPUs the processes can use, e.g. 1.5.
+	CPUs     float64 `json:"cpus"`
+	MemoryMB int64   `json:"memory_mb"`
+	Pids     int64   `json:"pids"`
+}
+
+func (l SessionLimits) isZero() bool {
+	return l == SessionLimits{}
+}
+
+// PostHealth is a function to report problems the agent encountered, e.g.
+// session limits that couldn't be applied. Previous warnings are replaced.
+type PostHealth func(ctx context.Context, warnings []string) error
+
+// reportHealth reports the warnings from setting up the agent. Warnings are
+// always reported, so those of a previous agent process are cleared.
+func (a *agent) reportHealth(ctx context.Context, warnings []string) {
+	for _, warning := range warnings {
+		a.logger.Warn(ctx, "agent health warning", slog.F("warning", warning))
+	}
+	if a.postHealth == nil {
+		return
+	}
+	if warnings == nil {
+		warnings = []string{}
+	}
+	retrier := retry.New(time.Second, 15*time.Second)
+	for {
+		err := a.postHealth(ctx, warnings)
+		if err == nil {
+			return
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		a.logger.Warn(ctx, "failed to report health", slog.Error(err))
+		if !retrier.Wait(ctx) {
+			return
+		}
+	}
+}
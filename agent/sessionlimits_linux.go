@@ -0,0 +1,310 @@
+package agent
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+)
+
+const (
+	cgroupRoot = "/sys/fs/cgroup"
+	// agentCgroupName is the leaf cgroup the processes of the cgroup of the
+	// agent are moved into, since controllers can only be enabled for the
+	// children of cgroups without processes.
+	agentCgroupName = "coder-agent"
+	// cgroupCPUPeriod is the period, in microseconds, CPU quotas apply to.
+	cgroupCPUPeriod = 100000
+
+	// agentOOMScoreAdj makes the kernel prefer other processes over the agent
+	// when the workspace runs out of memory. Lowering the score requires
+	// CAP_SYS_RESOURCE, so it's only applied if the agent is privileged.
+	agentOOMScoreAdj = -500
+	// sessionOOMScoreAdjOffset is added to the score of the agent for the
+	// processes it starts, so they're killed before the agent is.
+	sessionOOMScoreAdjOffset = 500
+	maxOOMScoreAdj           = 1000
+	// oomScoreWrapperShell sets the OOM score of commands before they're
+	// executed.
+	oomScoreWrapperShell = "/bin/sh"
+)
+
+// sessionLimiter places the processes of each session in a cgroup with the
+// configured limits, and raises the OOM score of every command the agent
+// starts above the agent's.
+type sessionLimiter struct {
+	logger slog.Logger
+	limits SessionLimits
+	// cgroup is the directory session cgroups are created in. It's empty if
+	// the limits can't be applied.
+	cgroup string
+	// sessionOOMScoreAdj is nil if the score of the agent is unknown.
+	sessionOOMScoreAdj *int
+}
+
+func newSessionLimiter(logger slog.Logger, limits SessionLimits) (*sessionLimiter, []string) {
+	ctx := context.Background()
+	limiter := &sessionLimiter{
+		logger: logger,
+		limits: limits,
+	}
+	var warnings []string
+
+	agentScore, err := adjustAgentOOMScore()
+	if err == nil {
+		_, err = os.Stat(oomScoreWrapperShell)
+	}
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("The OOM score of commands isn't adjusted, so the agent may be killed before their processes when the workspace runs out of memory: %s", err))
+	} else {
+		sessionScore := agentScore + sessionOOMScoreAdjOffset
+		if sessionScore > maxOOMScoreAdj {
+			sessionScore = maxOOMScoreAdj
+		}
+		limiter.sessionOOMScoreAdj = &sessionScore
+		logger.Debug(ctx, "adjusted oom score", slog.F("agent", agentScore), slog.F("sessions", sessionScore))
+	}
+
+	if limits.isZero() {
+		return limiter, warnings
+	}
+	cgroup, err := setupSessionCgroups(limits)
+	if err != nil {
+		return limiter, append(warnings, fmt.Sprintf("Session limits aren't applied: %s", err))
+	}
+	limiter.cgroup = cgroup
+	logger.Info(ctx, "applying session limits", slog.F("cgroup", cgroup), slog.F("limits", limits))
+	return limiter, warnings
+}
+
+// wrapCommand makes a command raise its OOM score above the agent's before
+// it's executed. The score is inherited by the processes the command starts,
+// so it can't be written once the command is running, e.g. by the startup
+// script starting daemons in the background. The command is run through a
+// shell that writes the score and then replaces itself with the command.
+func (l *sessionLimiter) wrapCommand(cmd *exec.Cmd) {
+	if l == nil || l.sessionOOMScoreAdj == nil {
+		return
+	}
+	script := fmt.Sprintf(`{ echo %d > /proc/self/oom_score_adj; } 2>/dev/null; exec "$0" "$@"`, *l.sessionOOMScoreAdj)
+	cmd.Args = append([]string{oomScoreWrapperShell, "-c", script, cmd.Path}, cmd.Args[1:]...)
+	cmd.Path = oomScoreWrapperShell
+}
+
+// limit applies the limits to the process of a session. Processes started
+// before it's moved into the cgroup of the session aren't limited, so this
+// must be called right after the process starts. The returned function must
+// be called when the process exits.
+func (l *sessionLimiter) limit(ctx context.Context, cmd *exec.Cmd) func() {
+	if l == nil || cmd.Process == nil || l.cgroup == "" {
+		return func() {}
+	}
+	pid := cmd.Process.Pid
+
+	dir, err := os.MkdirTemp(l.cgroup, "session-")
+	if err != nil {
+		l.logger.Warn(ctx, "create session cgroup", slog.F("pid", pid), slog.Error(err))
+		return func() {}
+	}
+	err = applySessionCgroup(dir, l.limits, pid)
+	if err != nil {
+		l.logger.Warn(ctx, "apply session limits", slog.F("pid", pid), slog.F("cgroup", dir), slog.Error(err))
+		_ = os.Remove(dir)
+		return func() {}
+	}
+	return func() {
+		// The cgroup can only be removed once all of its processes exit, so
+		// it's left behind if the session started background processes.
+		err := os.Remove(dir)
+		if err != nil {
+			l.logger.Debug(ctx, "remove session cgroup", slog.F("cgroup", dir), slog.Error(err))
+		}
+	}
+}
+
+// adjustAgentOOMScore lowers the OOM score of the agent if it's privileged,
+// and returns the resulting score.
+func adjustAgentOOMScore() (int, error) {
+	err := writeProcOOMScoreAdj("self", agentOOMScoreAdj)
+	if err != nil && !errors.Is(err, fs.ErrPermission) {
+		return 0, err
+	}
+	raw, err := os.ReadFile("/proc/self/oom_score_adj")
+	if err != nil {
+		return 0, xerrors.Errorf("read oom_score_adj: %w", err)
+	}
+	score, err := strconv.Atoi(strings.TrimSpace(string(raw)))
+	if err != nil {
+		return 0, xerrors.Errorf("parse oom_score_adj: %w", err)
+	}
+	return score, nil
+}
+
+func writeProcOOMScoreAdj(pid string, score int) error {
+	return os.WriteFile(filepath.Join("/proc", pid, "oom_score_adj"), []byte(strconv.Itoa(score)), 0o600)
+}
+
+// setupSessionCgroups enables the controllers the limits require for the
+// children of the cgroup of the agent, and returns its directory.
+func setupSessionCgroups(limits SessionLimits) (string, error) {
+	_, err := os.Stat(filepath.Join(cgroupRoot, "cgroup.controllers"))
+	if err != nil {
+		return "", xerrors.Errorf("cgroup v2 isn't mounted at %s", cgroupRoot)
+	}
+	raw, err := os.ReadFile("/proc/self/cgroup")
+	if err != nil {
+		return "", xerrors.Errorf("read cgroup of the agent: %w", err)
+	}
+	current, err := parseProcCgroup(string(raw))
+	if err != nil {
+		return "", err
+	}
+	if path.Base(current) == agentCgroupName {
+		// The agent was restarted, e.g. after updating itself.
+		current = path.Dir(current)
+	}
+	if current == "/" {
+		return "", xerrors.New("the agent runs in the root cgroup, run it in a delegated cgroup instead, e.g. with Delegate=yes in a systemd unit")
+	}
+	parent := filepath.Join(cgroupRoot, current)
+
+	raw, err = os.ReadFile(filepath.Join(parent, "cgroup.controllers"))
+	if err != nil {
+		return "", xerrors.Errorf("read controllers: %w", err)
+	}
+	available := strings.Fields(string(raw))
+	var enable []string
+	for _, controller := range cgroupControllers(limits) {
+		found := false
+		for _, name := range available {
+			if name == controller {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return "", xerrors.Errorf("the %q controller isn't available in cgroup %s", controller, parent)
+		}
+		enable = append(enable, "+"+controller)
+	}
+
+	leaf := filepath.Join(parent, agentCgroupName)
+	err = os.Mkdir(leaf, 0o755)
+	if err != nil && !errors.Is(err, fs.ErrExist) {
+		return "", xerrors.Errorf("cgroup %s isn't writable by the agent: %w", parent, err)
+	}
+	err = moveCgroupProcesses(parent, leaf)
+	if err != nil {
+		return "", err
+	}
+	err = os.WriteFile(filepath.Join(parent, "cgroup.subtree_control"), []byte(strings.Join(enable, " ")), 0o600)
+	if err != nil {
+		return "", xerrors.Errorf("enable controllers: %w", err)
+	}
+	return parent, nil
+}
+
+// moveCgroupProcesses moves every process in one cgroup to another.
+func moveCgroupProcesses(from, to string) error {
+	file, err := os.Open(filepath.Join(from, "cgroup.procs"))
+	if err != nil {
+		return xerrors.Errorf("open processes: %w", err)
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		pid := strings.TrimSpace(scanner.Text())
+		if pid == "" {
+			continue
+		}
+		err = os.WriteFile(filepath.Join(to, "cgroup.procs"), []byte(pid), 0o600)
+		// The process may have exited since the list was read.
+		if err != nil && !errors.Is(err, syscall.ESRCH) {
+			return xerrors.Errorf("move process %s: %w", pid, err)
+		}
+	}
+	return scanner.Err()
+}
+
+// applySessionCgroup writes the limits to a new cgroup and moves the process
+// into it.
+func applySessionCgroup(dir string, limits SessionLimits, pid int) error {
+	files := cgroupLimitFiles(limits)
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		err := os.WriteFile(filepath.Join(dir, name), []byte(files[name]), 0o600)
+		if err != nil {
+			return xerrors.Errorf("write %s: %w", name, err)
+		}
+	}
+	err := os.WriteFile(filepath.Join(dir, "cgroup.procs"), []byte(strconv.Itoa(pid)), 0o600)
+	if err != nil {
+		return xerrors.Errorf("move process: %w", err)
+	}
+	return nil
+}
+
+// cgroupLimitFiles returns the contents of the cgroup interface files that
+// apply the limits.
+func cgroupLimitFiles(limits SessionLimits) map[string]string {
+	files := map[string]string{}
+	if limits.CPUs > 0 {
+		quota := int64(limits.CPUs * cgroupCPUPeriod)
+		files["cpu.max"] = fmt.Sprintf("%d %d", quota, cgroupCPUPeriod)
+	}
+	if limits.MemoryMB > 0 {
+		files["memory.max"] = strconv.FormatInt(limits.MemoryMB<<20, 10)
+	}
+	if limits.Pids > 0 {
+		files["pids.max"] = strconv.FormatInt(limits.Pids, 10)
+	}
+	return files
+}
+
+// cgroupControllers returns the controllers the limits require.
+func cgroupControllers(limits SessionLimits) []string {
+	var controllers []string
+	for name := range cgroupLimitFiles(limits) {
+		controller, _, _ := strings.Cut(name, ".")
+		found := false
+		for _, existing := range controllers {
+			if existing == controller {
+				found = true
+				break
+			}
+		}
+		if !found {
+			controllers = append(controllers, controller)
+		}
+	}
+	sort.Strings(controllers)
+	return controllers
+}
+
+// parseProcCgroup returns the cgroup v2 path from /proc/<pid>/cgroup.
+func parseProcCgroup(content string) (string, error) {
+	for _, line := range strings.Split(content, "\n") {
+		// The cgroup v2 hierarchy always has the ID 0 and no controllers.
+		if strings.HasPrefix(line, "0::") {
+			return strings.TrimSpace(strings.TrimPrefix(line, "0::")), nil
+		}
+	}
+	return "", xerrors.New("the agent isn't in a cgroup v2 hierarchy")
+}
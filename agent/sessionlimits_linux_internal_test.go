@@ -0,0 +1,65 @@
+package agent
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseProcCgroup(t *testing.T) {
+	t.Parallel()
+
+	cgroup, err := parseProcCgroup("0::/system.slice/coder-agent.service\n")
+	require.NoError(t, err)
+	require.Equal(t, "/system.slice/coder-agent.service", cgroup)
+
+	// Hybrid hierarchies list cgroup v1 controllers too.
+	cgroup, err = parseProcCgroup("12:pids:/docker/abc\n1:name=systemd:/docker/abc\n0::/docker/abc\n")
+	require.NoError(t, err)
+	require.Equal(t, "/docker/abc", cgroup)
+
+	_, err = parseProcCgroup("12:pids:/docker/abc\n1:name=systemd:/docker/abc\n")
+	require.Error(t, err)
+}
+
+func TestCgroupLimitFiles(t *testing.T) {
+	t.Parallel()
+
+	limits := SessionLimits{
+		CPUs:     1.5,
+		MemoryMB: 2048,
+		Pids:     1024,
+	}
+	require.Equal(t, map[string]string{
+		"cpu.max":    "150000 100000",
+		"memory.max": "2147483648",
+		"pids.max":   "1024",
+	}, cgroupLimitFiles(limits))
+	require.Equal(t, []string{"cpu", "memory", "pids"}, cgroupControllers(limits))
+
+	limits = SessionLimits{MemoryMB: 512}
+	require.Equal(t, map[string]string{
+		"memory.max": "536870912",
+	}, cgroupLimitFiles(limits))
+	require.Equal(t, []string{"memory"}, cgroupControllers(limits))
+	require.Empty(t, cgroupLimitFiles(SessionLimits{}))
+}
+
+func TestWrapCommand(t *testing.T) {
+	t.Parallel()
+
+	// Raising the score doesn't require privileges.
+	score := maxOOMScoreAdj
+	limiter := &sessionLimiter{sessionOOMScoreAdj: &score}
+	cmd := exec.Command("sh", "-c", `cat /proc/self/oom_score_adj; echo "$0 $1"`, "first", "second arg")
+	limiter.wrapCommand(cmd)
+	output, err := cmd.Output()
+	require.NoError(t, err)
+	require.Equal(t, "1000\nfirst second arg\n", string(output))
+
+	// Commands aren't changed if the score of the agent is unknown.
+	cmd = exec.Command("true")
+	(&sessionLimiter{}).wrapCommand(cmd)
+	require.Equal(t, []string{"true"}, cmd.Args)
+}
@@ -0,0 +1,31 @@
+//go:build !linux
+// +build !linux
+
+package agent
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"runtime"
+
+	"cdr.dev/slog"
+)
+
+// sessionLimiter is a no-op, as session limits rely on cgroups.
+type sessionLimiter struct{}
+
+func newSessionLimiter(_ slog.Logger, limits SessionLimits) (*sessionLimiter, []string) {
+	if limits.isZero() {
+		return &sessionLimiter{}, nil
+	}
+	return &sessionLimiter{}, []string{
+		fmt.Sprintf("Session limits are only supported on Linux, they aren't applied on %s.", runtime.GOOS),
+	}
+}
+
+func (*sessionLimiter) wrapCommand(*exec.Cmd) {}
+
+func (*sessionLimiter) limit(context.Context, *exec.Cmd) func() {
+	return func() {}
+}
@@ -4,7 +4,12 @@ import (
 	"context"
 	"io"
 	"net"
+	"strings"
+	"sync"
 	"sync/atomic"
+	"time"
+
+	gossh "golang.org/x/crypto/ssh"
 
 	"cdr.dev/slog"
 )
@@ -31,23 +36,112 @@ func (c *statsConn) Write(b []byte) (n int, err error) {
 
 var _ net.Conn = new(statsConn)
 
+// SessionType is the kind of client that opened a session.
+type SessionType string
+
+const (
+	SessionTypeSSH             SessionType = "ssh"
+	SessionTypeVSCode          SessionType = "vscode"
+	SessionTypeJetBrains       SessionType = "jetbrains"
+	SessionTypeReconnectingPTY SessionType = "reconnecting_pty"
+	SessionTypePortForward     SessionType = "port_forward"
+)
+
+// MagicSessionTypeEnvironmentVariable is set by clients that open SSH
+// sessions, e.g. the VS Code extension, to identify themselves.
+const MagicSessionTypeEnvironmentVariable = "CODER_SSH_SESSION_TYPE"
+
+// sessionTypeFromEnv returns the session type a client identified itself
+// as, defaulting to SSH.
+func sessionTypeFromEnv(env []string) SessionType {
+	for _, kv := range env {
+		key, value, ok := strings.Cut(kv, "=")
+		if !ok || key != MagicSessionTypeEnvironmentVariable {
+			continue
+		}
+		switch SessionType(strings.ToLower(value)) {
+		case SessionTypeVSCode:
+			return SessionTypeVSCode
+		case SessionTypeJetBrains:
+			return SessionTypeJetBrains
+		}
+	}
+	return SessionTypeSSH
+}
+
 // Stats records the Agent's network connection statistics for use in
 // user-facing metrics and debugging.
-// Each member value must be written and read with atomic.
+// NumConns, RxBytes, and TxBytes must be written and read with atomic.
+// All values are cumulative over the lifetime of the agent.
 type Stats struct {
 	NumConns int64 `json:"num_comms"`
 	RxBytes  int64 `json:"rx_bytes"`
 	TxBytes  int64 `json:"tx_bytes"`
+	// SessionCount is the number of sessions opened, by type.
+	SessionCount map[SessionType]int64 `json:"session_count"`
+	// SessionSeconds is the time spent in sessions, including sessions
+	// that are still open.
+	SessionSeconds float64 `json:"session_seconds"`
+
+	sessionMutex sync.Mutex
+	// closedSessionSeconds is the time spent in sessions that have ended.
+	closedSessionSeconds float64
+	activeSessions       map[*time.Time]struct{}
 }
 
 func (s *Stats) Copy() *Stats {
+	s.sessionMutex.Lock()
+	defer s.sessionMutex.Unlock()
+	sessionCount := make(map[SessionType]int64, len(s.SessionCount))
+	for sessionType, count := range s.SessionCount {
+		sessionCount[sessionType] = count
+	}
+	sessionSeconds := s.closedSessionSeconds
+	for start := range s.activeSessions {
+		sessionSeconds += time.Since(*start).Seconds()
+	}
 	return &Stats{
-		NumConns: atomic.LoadInt64(&s.NumConns),
-		RxBytes:  atomic.LoadInt64(&s.RxBytes),
-		TxBytes:  atomic.LoadInt64(&s.TxBytes),
+		NumConns:       atomic.LoadInt64(&s.NumConns),
+		RxBytes:        atomic.LoadInt64(&s.RxBytes),
+		TxBytes:        atomic.LoadInt64(&s.TxBytes),
+		SessionCount:   sessionCount,
+		SessionSeconds: sessionSeconds,
 	}
 }
 
+// startSession records the start of a session. The returned function must
+// be called when the session ends.
+func (s *Stats) startSession(sessionType SessionType) func() {
+	start := time.Now()
+	s.sessionMutex.Lock()
+	if s.SessionCount == nil {
+		s.SessionCount = make(map[SessionType]int64)
+	}
+	if s.activeSessions == nil {
+		s.activeSessions = make(map[*time.Time]struct{})
+	}
+	s.SessionCount[sessionType]++
+	s.activeSessions[&start] = struct{}{}
+	s.sessionMutex.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			s.sessionMutex.Lock()
+			defer s.sessionMutex.Unlock()
+			delete(s.activeSessions, &start)
+			s.closedSessionSeconds += time.Since(start).Seconds()
+		})
+	}
+}
+
+// activeSessionCount returns the number of sessions that haven't ended.
+func (s *Stats) activeSessionCount() int {
+	s.sessionMutex.Lock()
+	defer s.sessionMutex.Unlock()
+	return len(s.activeSessions)
+}
+
 // wrapConn returns a new connection that records statistics.
 func (s *Stats) wrapConn(conn net.Conn) net.Conn {
 	atomic.AddInt64(&s.NumConns, 1)
@@ -59,6 +153,54 @@ func (s *Stats) wrapConn(conn net.Conn) net.Conn {
 	return cs
 }
 
+// sessionConn ends a session when the connection is closed.
+type sessionConn struct {
+	net.Conn
+	end func()
+}
+
+func (c *sessionConn) Close() error {
+	c.end()
+	return c.Conn.Close()
+}
+
+// wrapSessionConn returns a new connection that records statistics and
+// is counted as a session until it's closed.
+func (s *Stats) wrapSessionConn(conn net.Conn, sessionType SessionType) net.Conn {
+	return &sessionConn{
+		Conn: s.wrapConn(conn),
+		end:  s.startSession(sessionType),
+	}
+}
+
+// sessionNewChannel counts an accepted SSH channel as a port forwarding
+// session until the channel is closed.
+type sessionNewChannel struct {
+	gossh.NewChannel
+	stats *Stats
+}
+
+func (c *sessionNewChannel) Accept() (gossh.Channel, <-chan *gossh.Request, error) {
+	channel, requests, err := c.NewChannel.Accept()
+	if err != nil {
+		return nil, nil, err
+	}
+	return &sessionChannel{
+		Channel: channel,
+		end:     c.stats.startSession(SessionTypePortForward),
+	}, requests, nil
+}
+
+type sessionChannel struct {
+	gossh.Channel
+	end func()
+}
+
+func (c *sessionChannel) Close() error {
+	c.end()
+	return c.Channel.Close()
+}
+
 // StatsReporter periodically accept and records agent stats.
 type StatsReporter func(
 	ctx context.Context,
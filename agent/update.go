@@ -0,0 +1,85 @@
+package agent
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/mod/semver"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/buildinfo"
+)
+
+// updateCheckInterval is how often the agent checks whether sessions have
+// ended before restarting into an updated binary.
+const updateCheckInterval = 10 * time.Second
+
+// UpdateAgent is a function that replaces the agent binary with the version
+// provided. The returned restart function replaces the running agent with
+// the new binary, and only returns if that fails.
+type UpdateAgent func(ctx context.Context, version string) (restart func() error, err error)
+
+// selfUpdate updates the agent if coderd expects a different version. The
+// agent is restarted once the startup script has finished and no sessions
+// are open, so updating doesn't interrupt reconnecting PTYs or SSH sessions.
+func (a *agent) selfUpdate(ctx context.Context, expectedVersion string) {
+	if a.updateAgent == nil || !semver.IsValid(expectedVersion) || !semver.IsValid(a.version) {
+		return
+	}
+	// Developer builds can't be matched to a published binary.
+	if buildinfo.IsDev(a.version) || buildinfo.IsDev(expectedVersion) {
+		return
+	}
+	if semver.Compare(a.version, expectedVersion) == 0 {
+		return
+	}
+	logger := a.logger.With(slog.F("version", a.version), slog.F("expected_version", expectedVersion))
+	// Updating again would restart the agent in a loop if the binary served
+	// by coderd is built with a different version.
+	if a.updatedVersion != "" && semver.Compare(a.updatedVersion, expectedVersion) == 0 {
+		logger.Warn(ctx, "agent was already updated to the expected version, but the binary reports a different version")
+		return
+	}
+	logger.Info(ctx, "updating agent")
+	restart, err := a.updateAgent(ctx, expectedVersion)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.Warn(ctx, "update agent", slog.Error(err))
+		}
+		return
+	}
+
+	ticker := time.NewTicker(updateCheckInterval)
+	defer ticker.Stop()
+	for a.isStarting() || a.activeSessionCount() > 0 {
+		logger.Debug(ctx, "waiting for startup and sessions to end before restarting")
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+
+	logger.Info(ctx, "restarting updated agent")
+	err = restart()
+	if err != nil {
+		logger.Error(ctx, "restart updated agent", slog.Error(err))
+	}
+}
+
+// isStarting returns true if the startup script hasn't finished.
+func (a *agent) isStarting() bool {
+	state := a.lifecycle()
+	return state == LifecycleStateCreated || state == LifecycleStateStarting
+}
+
+// activeSessionCount returns the number of open sessions, including
+// reconnecting PTYs that clients may reconnect to.
+func (a *agent) activeSessionCount() int {
+	count := a.stats.activeSessionCount()
+	a.reconnectingPTYs.Range(func(_, _ interface{}) bool {
+		count++
+		return true
+	})
+	return count
+}
@@ -0,0 +1,64 @@
+package agent
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+)
+
+func TestSelfUpdate(t *testing.T) {
+	t.Parallel()
+
+	run := func(t *testing.T, version, expectedVersion, updatedVersion string) (updatedTo string, restarted bool) {
+		t.Helper()
+		a := &agent{
+			logger:  slogtest.Make(t, nil),
+			version: version,
+			updateAgent: func(_ context.Context, version string) (func() error, error) {
+				updatedTo = version
+				return func() error {
+					restarted = true
+					return nil
+				}, nil
+			},
+			lifecycleState: LifecycleStateReady,
+			updatedVersion: updatedVersion,
+			stats:          &Stats{},
+		}
+		a.selfUpdate(context.Background(), expectedVersion)
+		return updatedTo, restarted
+	}
+
+	t.Run("Outdated", func(t *testing.T) {
+		t.Parallel()
+		updatedTo, restarted := run(t, "v0.9.1", "v0.9.2", "")
+		require.Equal(t, "v0.9.2", updatedTo)
+		require.True(t, restarted)
+	})
+
+	t.Run("Current", func(t *testing.T) {
+		t.Parallel()
+		updatedTo, restarted := run(t, "v0.9.2", "v0.9.2", "")
+		require.Empty(t, updatedTo)
+		require.False(t, restarted)
+	})
+
+	t.Run("AlreadyUpdated", func(t *testing.T) {
+		t.Parallel()
+		// The binary served for v0.9.2 was built as v0.9.1, so updating
+		// again would restart the agent in a loop.
+		updatedTo, restarted := run(t, "v0.9.1", "v0.9.2", "v0.9.2")
+		require.Empty(t, updatedTo)
+		require.False(t, restarted)
+	})
+
+	t.Run("Developer", func(t *testing.T) {
+		t.Parallel()
+		updatedTo, restarted := run(t, "v0.0.0-devel+abcdef1", "v0.9.2", "")
+		require.Empty(t, updatedTo)
+		require.False(t, restarted)
+	})
+}
@@ -7,6 +7,7 @@ import (
 	_ "net/http/pprof" //nolint: gosec
 	"net/url"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"runtime"
 	"time"
@@ -32,6 +33,8 @@ func workspaceAgent() *cobra.Command {
 		pprofEnabled bool
 		pprofAddress string
 		noReap       bool
+
+		disableSelfUpdate bool
 	)
 	cmd := &cobra.Command{
 		Use: "agent",
@@ -63,7 +66,10 @@ func workspaceAgent() *cobra.Command {
 				// Do not start a reaper on the child process. It's important
 				// to do this else we fork bomb ourselves.
 				args := append(os.Args, "--no-reap")
-				err := reaper.ForkReap(reaper.WithExecArgs(args...))
+				err := reaper.ForkReap(
+					reaper.WithExecArgs(args...),
+					reaper.WithCatchSignals(interruptSignals...),
+				)
 				if err != nil {
 					logger.Error(cmd.Context(), "failed to reap", slog.Error(err))
 					return xerrors.Errorf("fork reap: %w", err)
@@ -73,6 +79,11 @@ func workspaceAgent() *cobra.Command {
 				return nil
 			}
 
+			// Close the agent gracefully when the workspace is stopped, so
+			// the shutdown script can run.
+			ctx, stopNotify := signal.NotifyContext(cmd.Context(), interruptSignals...)
+			defer stopNotify()
+
 			version := buildinfo.Version()
 			logger.Info(cmd.Context(), "starting agent",
 				slog.F("url", coderURL),
@@ -181,6 +192,17 @@ func workspaceAgent() *cobra.Command {
 				logger.Error(cmd.Context(), "post agent version: %w", slog.Error(err), slog.F("version", version))
 			}
 
+			// The environment variable is removed so it isn't inherited by
+			// sessions, or by the next update.
+			updatedVersion, restarted := os.LookupEnv(agentRestartedEnv)
+			_ = os.Unsetenv(agentRestartedEnv)
+			var updateAgent agent.UpdateAgent
+			if !disableSelfUpdate {
+				updateAgent = func(ctx context.Context, version string) (func() error, error) {
+					return updateAgentBinary(ctx, client, version)
+				}
+			}
+
 			closer := agent.New(agent.Options{
 				FetchMetadata: client.WorkspaceAgentMetadata,
 				Logger:        logger,
@@ -191,8 +213,18 @@ func workspaceAgent() *cobra.Command {
 				},
 				CoordinatorDialer: client.ListenWorkspaceAgentTailnet,
 				StatsReporter:     client.AgentReportStats,
+				ReportLifecycle:   client.PostWorkspaceAgentLifecycle,
+				PatchStartupLogs:  client.PatchWorkspaceAgentStartupLogs,
+				AwaitShutdown:     client.WorkspaceAgentAwaitShutdown,
+				PostMetadata:      client.PostWorkspaceAgentMetadata,
+				PostAppHealth:     client.PostWorkspaceAgentAppHealth,
+				PostHealth:        client.PostWorkspaceAgentHealth,
+				UpdateAgent:       updateAgent,
+				Version:           version,
+				Restarted:         restarted,
+				UpdatedVersion:    updatedVersion,
 			})
-			<-cmd.Context().Done()
+			<-ctx.Done()
 			return closer.Close()
 		},
 	}
@@ -200,6 +232,7 @@ func workspaceAgent() *cobra.Command {
 	cliflag.StringVarP(cmd.Flags(), &auth, "auth", "", "CODER_AGENT_AUTH", "token", "Specify the authentication type to use for the agent")
 	cliflag.BoolVarP(cmd.Flags(), &pprofEnabled, "pprof-enable", "", "CODER_AGENT_PPROF_ENABLE", false, "Enable serving pprof metrics on the address defined by --pprof-address.")
 	cliflag.BoolVarP(cmd.Flags(), &noReap, "no-reap", "", "", false, "Do not start a process reaper.")
+	cliflag.BoolVarP(cmd.Flags(), &disableSelfUpdate, "disable-self-update", "", "CODER_AGENT_DISABLE_SELF_UPDATE", false, "Do not update the agent when coderd expects a different version.")
 	cliflag.StringVarP(cmd.Flags(), &pprofAddress, "pprof-address", "", "CODER_AGENT_PPROF_ADDRESS", "127.0.0.1:6060", "The address to serve pprof.")
 	return cmd
 }
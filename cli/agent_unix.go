@@ -36,3 +36,17 @@ func agentStartPPROFOnUSR1(ctx context.Context, logger slog.Logger, pprofAddress
 		<-usr1 // Wait until usr1 is closed, ensures srvClose was run.
 	}
 }
+
+// replaceAgentExecutable renames the updated binary over the running one.
+// The running process keeps the old binary open until it restarts.
+func replaceAgentExecutable(executablePath, updatedPath string) error {
+	return os.Rename(updatedPath, executablePath)
+}
+
+// restartAgent replaces the agent process with the executable, keeping the
+// same PID so the reaper and init system continue to track it.
+func restartAgent(executablePath, version string) error {
+	env := append(os.Environ(), agentRestartedEnv+"="+version)
+	//#nosec G204 -- The executable is the agent itself.
+	return syscall.Exec(executablePath, os.Args, env)
+}
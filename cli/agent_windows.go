@@ -2,6 +2,8 @@ package cli
 
 import (
 	"context"
+	"os"
+	"os/exec"
 
 	"cdr.dev/slog"
 )
@@ -10,3 +12,37 @@ import (
 func agentStartPPROFOnUSR1(ctx context.Context, logger slog.Logger, pprofAddress string) (srvClose func()) {
 	return func() {}
 }
+
+// replaceAgentExecutable replaces the running binary with the updated one.
+// Windows doesn't allow replacing a running executable, but it can be
+// renamed out of the way.
+func replaceAgentExecutable(executablePath, updatedPath string) error {
+	oldPath := executablePath + ".old"
+	_ = os.Remove(oldPath)
+	err := os.Rename(executablePath, oldPath)
+	if err != nil {
+		return err
+	}
+	err = os.Rename(updatedPath, executablePath)
+	if err != nil {
+		_ = os.Rename(oldPath, executablePath)
+		return err
+	}
+	return nil
+}
+
+// restartAgent starts the updated agent and exits, since Windows can't
+// replace the running process.
+func restartAgent(executablePath, version string) error {
+	//#nosec G204 -- The executable is the agent itself.
+	cmd := exec.Command(executablePath, os.Args[1:]...)
+	cmd.Env = append(os.Environ(), agentRestartedEnv+"="+version)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	err := cmd.Start()
+	if err != nil {
+		return err
+	}
+	os.Exit(0)
+	return nil
+}
@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/codersdk"
+)
+
+// agentRestartedEnv is set to the version the agent was updated to when it
+// restarts with an updated binary, so the startup script doesn't run again
+// and the agent doesn't update to the same version in a loop.
+const agentRestartedEnv = "CODER_AGENT_RESTARTED"
+
+// updateAgentBinary replaces the running binary with the one served by
+// coderd for the version provided, and returns a function that restarts the
+// agent with it.
+func updateAgentBinary(ctx context.Context, client *codersdk.Client, version string) (func() error, error) {
+	executablePath, err := os.Executable()
+	if err != nil {
+		return nil, xerrors.Errorf("get executable: %w", err)
+	}
+	executablePath, err = filepath.EvalSymlinks(executablePath)
+	if err != nil {
+		return nil, xerrors.Errorf("resolve executable: %w", err)
+	}
+
+	// The binary is downloaded next to the executable, so it can be renamed
+	// over it atomically.
+	file, err := os.CreateTemp(filepath.Dir(executablePath), ".coder-update-*")
+	if err != nil {
+		return nil, xerrors.Errorf("create temporary file: %w", err)
+	}
+	defer os.Remove(file.Name())
+	err = client.DownloadAgentBinary(ctx, runtime.GOOS, runtime.GOARCH, file)
+	if err != nil {
+		_ = file.Close()
+		return nil, xerrors.Errorf("download agent: %w", err)
+	}
+	err = file.Close()
+	if err != nil {
+		return nil, xerrors.Errorf("close temporary file: %w", err)
+	}
+	err = os.Chmod(file.Name(), 0o755)
+	if err != nil {
+		return nil, xerrors.Errorf("make agent executable: %w", err)
+	}
+	err = replaceAgentExecutable(executablePath, file.Name())
+	if err != nil {
+		return nil, xerrors.Errorf("replace agent: %w", err)
+	}
+	return func() error {
+		return restartAgent(executablePath, version)
+	}, nil
+}
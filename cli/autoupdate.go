@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/codersdk"
+)
+
+func autoupdate() *cobra.Command {
+	cmd := &cobra.Command{
+		Annotations: workspaceCommand,
+		Use:         "autoupdate <workspace> <always|never>",
+		Short:       "Toggle automatic updates of a workspace to the active template version when it's started",
+		Args:        cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			policy := codersdk.AutomaticUpdates(args[1])
+			switch policy {
+			case codersdk.AutomaticUpdatesAlways, codersdk.AutomaticUpdatesNever:
+			default:
+				return xerrors.Errorf("invalid option %q, must be %q or %q", args[1], codersdk.AutomaticUpdatesAlways, codersdk.AutomaticUpdatesNever)
+			}
+
+			client, err := CreateClient(cmd)
+			if err != nil {
+				return err
+			}
+			workspace, err := namedWorkspace(cmd, client, args[0])
+			if err != nil {
+				return xerrors.Errorf("get workspace: %w", err)
+			}
+
+			err = client.UpdateWorkspaceAutomaticUpdates(cmd.Context(), workspace.ID, codersdk.UpdateWorkspaceAutomaticUpdatesRequest{
+				AutomaticUpdates: policy,
+			})
+			if err != nil {
+				return err
+			}
+
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Updated workspace %s automatic updates to %s\n",
+				cliui.Styles.Keyword.Render(workspace.Name), cliui.Styles.Keyword.Render(string(policy)))
+			return nil
+		},
+	}
+	return cmd
+}
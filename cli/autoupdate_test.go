@@ -0,0 +1,49 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/cli/clitest"
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/codersdk"
+)
+
+func TestAutoUpdate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		require.Equal(t, codersdk.AutomaticUpdatesNever, workspace.AutomaticUpdates)
+
+		cmd, root := clitest.New(t, "autoupdate", workspace.Name, "always")
+		clitest.SetupConfig(t, client, root)
+		out := bytes.NewBuffer(nil)
+		cmd.SetOut(out)
+		require.NoError(t, cmd.Execute())
+		require.Contains(t, out.String(), "always")
+
+		workspace, err := client.Workspace(context.Background(), workspace.ID)
+		require.NoError(t, err)
+		require.Equal(t, codersdk.AutomaticUpdatesAlways, workspace.AutomaticUpdates)
+	})
+
+	t.Run("InvalidArgs", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		cmd, root := clitest.New(t, "autoupdate", "my-workspace", "sometimes")
+		clitest.SetupConfig(t, client, root)
+		require.ErrorContains(t, cmd.Execute(), "invalid option")
+	})
+}
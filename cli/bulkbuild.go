@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/codersdk"
+)
+
+type bulkBuildRow struct {
+	Workspace string `table:"workspace"`
+	Result    string `table:"result"`
+}
+
+// bulkWorkspaceBuild builds every workspace matching the request's query and
+// prints the result of each. Unless it's a dry run, the user is shown the
+// affected workspaces and asked to confirm first. Only the confirmed
+// workspaces are built, even if more match the query by then.
+func bulkWorkspaceBuild(cmd *cobra.Command, client *codersdk.Client, req codersdk.BulkWorkspaceBuildRequest) error {
+	dryRun := req.DryRun
+	req.DryRun = true
+	resp, err := client.BulkWorkspaceBuild(cmd.Context(), req)
+	if err != nil {
+		return err
+	}
+
+	matched := make([]uuid.UUID, 0, len(resp.Results))
+	for _, result := range resp.Results {
+		if result.Skipped == "" && result.Error == "" {
+			matched = append(matched, result.WorkspaceID)
+		}
+	}
+	if dryRun || len(matched) == 0 {
+		return displayBulkBuildResults(cmd, req.Transition, resp)
+	}
+
+	_, err = cliui.Prompt(cmd, cliui.PromptOptions{
+		Text:      fmt.Sprintf("Confirm %s %d workspace(s)?", req.Transition, len(matched)),
+		IsConfirm: true,
+		Default:   cliui.ConfirmNo,
+	})
+	if err != nil {
+		return err
+	}
+
+	req.DryRun = false
+	req.WorkspaceIDs = matched
+	resp, err = client.BulkWorkspaceBuild(cmd.Context(), req)
+	if err != nil {
+		return err
+	}
+	return displayBulkBuildResults(cmd, req.Transition, resp)
+}
+
+func displayBulkBuildResults(cmd *cobra.Command, transition codersdk.WorkspaceTransition, resp codersdk.BulkWorkspaceBuildResponse) error {
+	if len(resp.Results) == 0 {
+		_, _ = fmt.Fprintln(cmd.OutOrStdout(), "No workspaces matched the search query.")
+		return nil
+	}
+
+	var failed int
+	rows := make([]bulkBuildRow, 0, len(resp.Results))
+	for _, result := range resp.Results {
+		row := bulkBuildRow{
+			Workspace: result.OwnerName + "/" + result.WorkspaceName,
+		}
+		switch {
+		case result.Error != "":
+			failed++
+			row.Result = "Failed: " + result.Error
+		case result.Skipped != "":
+			row.Result = "Skipped: " + result.Skipped
+		case resp.DryRun:
+			row.Result = fmt.Sprintf("Would %s", transition)
+		default:
+			row.Result = fmt.Sprintf("Queued %s build %s", transition, result.BuildID)
+		}
+		rows = append(rows, row)
+	}
+
+	out, err := cliui.DisplayTable(rows, "workspace", nil)
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintln(cmd.OutOrStdout(), out)
+	if failed > 0 {
+		return xerrors.Errorf("%d of %d workspace build(s) failed", failed, len(resp.Results))
+	}
+	return nil
+}
@@ -33,6 +33,7 @@ type WorkspaceResourcesOptions struct {
 // ├────────────────────────────────────────────────────────────────────────────┤
 // │ kubernetes_pod.dev                                                         │
 // │ ├─ go (linux, amd64)         ⦿ connected           coder ssh dev.go        │
+// │ │  └─ CPU: 12%                                                             │
 // │ └─ postgres (linux, amd64)   ⦾ disconnected [4s]   coder ssh dev.postgres  │
 // └────────────────────────────────────────────────────────────────────────────┘
 func WorkspaceResources(writer io.Writer, resources []codersdk.WorkspaceResource, options WorkspaceResourcesOptions) error {
@@ -110,6 +111,31 @@ func WorkspaceResources(writer io.Writer, resources []codersdk.WorkspaceResource
 				row = append(row, sshCommand)
 			}
 			tableWriter.AppendRow(row)
+
+			if !options.HideAgentState {
+				// Display the latest metadata values and any health warnings
+				// beneath the agent.
+				indent := "│"
+				if index == len(resource.Agents)-1 {
+					indent = " "
+				}
+				details := make([]string, 0, len(agent.Metadata)+len(agent.HealthWarnings))
+				for _, md := range agent.Metadata {
+					details = append(details, fmt.Sprintf("%s: %s", md.Description.DisplayName, renderAgentMetadata(md)))
+				}
+				for _, warning := range agent.HealthWarnings {
+					details = append(details, Styles.Warn.Render("warning: "+warning))
+				}
+				for detailIndex, detail := range details {
+					detailPipe := "├"
+					if detailIndex == len(details)-1 {
+						detailPipe = "└"
+					}
+					tableWriter.AppendRow(table.Row{
+						fmt.Sprintf("%s  %s─ %s", indent, detailPipe, detail),
+					})
+				}
+			}
 		}
 		tableWriter.AppendSeparator()
 	}
@@ -134,6 +160,16 @@ func renderAgentStatus(agent codersdk.WorkspaceAgent) string {
 	}
 }
 
+func renderAgentMetadata(md codersdk.WorkspaceAgentMetadata) string {
+	if md.Result.Error != "" {
+		return Styles.Error.Render(md.Result.Error)
+	}
+	if md.Result.CollectedAt.IsZero() {
+		return Styles.Placeholder.Render("(pending)")
+	}
+	return md.Result.Value
+}
+
 func renderAgentVersion(agentVersion, serverVersion string) string {
 	if agentVersion == "" {
 		agentVersion = "(unknown)"
@@ -0,0 +1,320 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/agent"
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/codersdk"
+)
+
+func cp() *cobra.Command {
+	var (
+		recursive bool
+		quiet     bool
+	)
+	cmd := &cobra.Command{
+		Annotations: workspaceCommand,
+		Use:         "cp <source> <destination>",
+		Short:       "Copy files between your machine and a workspace",
+		Long: "Copy files between your machine and a workspace. Paths in a workspace are written as " +
+			"<workspace>[.<agent>]:<path>, and are relative to the home directory of the workspace user.",
+		Args: cobra.ExactArgs(2),
+		Example: formatExamples(
+			example{
+				Description: "Copy a file to the home directory of a workspace",
+				Command:     "coder cp ./notes.txt <workspace>:",
+			},
+			example{
+				Description: "Copy a directory from a workspace to your machine",
+				Command:     "coder cp --recursive <workspace>:project/dist ./dist",
+			},
+		),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithCancel(cmd.Context())
+			defer cancel()
+
+			source, destination := parseCopyPath(args[0]), parseCopyPath(args[1])
+			if (source.Workspace == "") == (destination.Workspace == "") {
+				return xerrors.New("exactly one of the source or destination must be in a workspace, e.g. <workspace>:<path>")
+			}
+			remote := source
+			if destination.Workspace != "" {
+				remote = destination
+			}
+
+			client, err := CreateClient(cmd)
+			if err != nil {
+				return err
+			}
+			workspace, workspaceAgent, err := getWorkspaceAndAgent(ctx, cmd, client, codersdk.Me, remote.Workspace, false)
+			if err != nil {
+				return err
+			}
+			err = cliui.Agent(ctx, cmd.ErrOrStderr(), cliui.AgentOptions{
+				WorkspaceName: workspace.Name,
+				Fetch: func(ctx context.Context) (codersdk.WorkspaceAgent, error) {
+					return client.WorkspaceAgent(ctx, workspaceAgent.ID)
+				},
+			})
+			if err != nil {
+				return xerrors.Errorf("await agent: %w", err)
+			}
+			conn, err := client.DialWorkspaceAgentTailnet(ctx, slog.Logger{}, workspaceAgent.ID)
+			if err != nil {
+				return err
+			}
+			defer conn.Close()
+
+			copier := &fileCopier{
+				conn:      conn,
+				recursive: recursive,
+				progress:  cmd.ErrOrStderr(),
+			}
+			if quiet {
+				copier.progress = io.Discard
+			}
+			if destination.Workspace != "" {
+				return copier.upload(ctx, source.Path, destination.Path)
+			}
+			return copier.download(ctx, source.Path, destination.Path)
+		},
+	}
+	cmd.Flags().BoolVarP(&recursive, "recursive", "r", false, "Copy directories recursively.")
+	cmd.Flags().BoolVarP(&quiet, "quiet", "q", false, "Don't show the progress of each file.")
+	return cmd
+}
+
+// copyPath is a path on the local machine, or in a workspace if Workspace is
+// set.
+type copyPath struct {
+	Workspace string
+	Path      string
+}
+
+func parseCopyPath(in string) copyPath {
+	workspace, remotePath, found := strings.Cut(in, ":")
+	// A single letter before the colon is a Windows drive, e.g. "C:\Users".
+	if !found || len(workspace) <= 1 || strings.ContainsAny(workspace, `/\`) {
+		return copyPath{Path: in}
+	}
+	if remotePath == "" {
+		remotePath = "~"
+	}
+	return copyPath{Workspace: workspace, Path: remotePath}
+}
+
+type fileCopier struct {
+	conn      *agent.Conn
+	recursive bool
+	progress  io.Writer
+}
+
+// upload copies a local file or directory into the workspace. If the
+// destination is an existing directory, the source is copied into it.
+func (c *fileCopier) upload(ctx context.Context, source, destination string) error {
+	info, err := os.Stat(source)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() && !c.recursive {
+		return xerrors.Errorf("%q is a directory, use --recursive to copy it", source)
+	}
+	destinationInfo, err := c.conn.StatFile(ctx, destination)
+	if err == nil && destinationInfo.IsDir {
+		destination = path.Join(destination, filepath.Base(source))
+	}
+
+	if !info.IsDir() {
+		return c.uploadFile(ctx, source, destination, info)
+	}
+	return filepath.WalkDir(source, func(localPath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			_, _ = fmt.Fprintf(c.progress, "Skipping %s, it isn't a regular file.\n", localPath)
+			return nil
+		}
+		rel, err := filepath.Rel(source, localPath)
+		if err != nil {
+			return err
+		}
+		return c.uploadFile(ctx, localPath, path.Join(destination, filepath.ToSlash(rel)), info)
+	})
+}
+
+func (c *fileCopier) uploadFile(ctx context.Context, source, destination string, info fs.FileInfo) error {
+	file, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	progress := newCopyProgress(c.progress, source, info.Size())
+	defer progress.done()
+	_, err = c.conn.WriteFile(ctx, destination, info.Mode(), io.TeeReader(file, progress))
+	if err != nil {
+		return xerrors.Errorf("write %q: %w", destination, err)
+	}
+	return nil
+}
+
+// download copies a file or directory from the workspace to the local
+// machine. If the destination is an existing directory, the source is copied
+// into it.
+func (c *fileCopier) download(ctx context.Context, source, destination string) error {
+	info, err := c.conn.StatFile(ctx, source)
+	if err != nil {
+		return xerrors.Errorf("stat %q: %w", source, err)
+	}
+	if info.IsDir && !c.recursive {
+		return xerrors.Errorf("%q is a directory, use --recursive to copy it", source)
+	}
+	destinationInfo, err := os.Stat(destination)
+	if err == nil && destinationInfo.IsDir() {
+		err = validateRemoteFileName(info.Name)
+		if err != nil {
+			return err
+		}
+		destination = filepath.Join(destination, info.Name)
+	}
+
+	if !info.IsDir {
+		return c.downloadFile(ctx, source, destination, info)
+	}
+	return c.downloadDir(ctx, source, destination)
+}
+
+func (c *fileCopier) downloadDir(ctx context.Context, source, destination string) error {
+	resp, err := c.conn.ListFiles(ctx, source)
+	if err != nil {
+		return xerrors.Errorf("list %q: %w", source, err)
+	}
+	err = os.MkdirAll(destination, 0o755)
+	if err != nil {
+		return err
+	}
+	for _, file := range resp.Files {
+		err = validateRemoteFileName(file.Name)
+		if err != nil {
+			return err
+		}
+		remotePath := path.Join(resp.Path, file.Name)
+		localPath := filepath.Join(destination, file.Name)
+		switch {
+		case file.IsDir:
+			err = c.downloadDir(ctx, remotePath, localPath)
+		case file.Mode.IsRegular():
+			err = c.downloadFile(ctx, remotePath, localPath, file)
+		default:
+			_, _ = fmt.Fprintf(c.progress, "Skipping %s, it isn't a regular file.\n", remotePath)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateRemoteFileName returns an error if a file name reported by the
+// agent isn't a single path element, so it can't be used to write outside
+// of the local destination.
+func validateRemoteFileName(name string) error {
+	if name == "" || name == "." || name == ".." ||
+		strings.ContainsAny(name, `/\`) || filepath.Base(name) != name {
+		return xerrors.Errorf("the workspace returned an invalid file name %q", name)
+	}
+	return nil
+}
+
+func (c *fileCopier) downloadFile(ctx context.Context, source, destination string, info agent.FileInfo) error {
+	content, size, err := c.conn.ReadFile(ctx, source)
+	if err != nil {
+		return xerrors.Errorf("read %q: %w", source, err)
+	}
+	defer content.Close()
+	file, err := os.OpenFile(destination, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, info.Mode.Perm())
+	if err != nil {
+		return err
+	}
+	progress := newCopyProgress(c.progress, source, size)
+	defer progress.done()
+	_, err = io.Copy(io.MultiWriter(file, progress), content)
+	if err != nil {
+		_ = file.Close()
+		return xerrors.Errorf("copy %q: %w", source, err)
+	}
+	return file.Close()
+}
+
+// copyProgress writes the progress of copying a file. Updates are
+// throttled, since a write happens for every chunk of data.
+type copyProgress struct {
+	w           io.Writer
+	name        string
+	total       int64
+	copied      int64
+	lastUpdated time.Time
+}
+
+func newCopyProgress(w io.Writer, name string, total int64) *copyProgress {
+	return &copyProgress{
+		w:     w,
+		name:  name,
+		total: total,
+	}
+}
+
+func (p *copyProgress) Write(b []byte) (int, error) {
+	p.copied += int64(len(b))
+	if time.Since(p.lastUpdated) >= 100*time.Millisecond {
+		p.lastUpdated = time.Now()
+		p.print()
+	}
+	return len(b), nil
+}
+
+func (p *copyProgress) print() {
+	percent := 100
+	if p.total > 0 {
+		percent = int(p.copied * 100 / p.total)
+	}
+	_, _ = fmt.Fprintf(p.w, "\r%s %s / %s (%d%%)", p.name, formatCopyBytes(p.copied), formatCopyBytes(p.total), percent)
+}
+
+func (p *copyProgress) done() {
+	p.print()
+	_, _ = fmt.Fprintln(p.w)
+}
+
+func formatCopyBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
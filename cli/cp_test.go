@@ -0,0 +1,68 @@
+package cli_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/agent"
+	"github.com/coder/coder/cli/clitest"
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/testutil"
+)
+
+func TestCp(t *testing.T) {
+	t.Parallel()
+	client, workspace, agentToken := setupWorkspaceForAgent(t)
+	agentClient := codersdk.New(client.URL)
+	agentClient.SessionToken = agentToken
+	agentCloser := agent.New(agent.Options{
+		FetchMetadata:     agentClient.WorkspaceAgentMetadata,
+		CoordinatorDialer: agentClient.ListenWorkspaceAgentTailnet,
+		Logger:            slogtest.Make(t, nil).Named("agent"),
+	})
+	defer agentCloser.Close()
+	coderdtest.AwaitWorkspaceAgents(t, client, workspace.LatestBuild.ID)
+
+	// The agent runs on this machine, so the workspace paths are local too.
+	localDir := t.TempDir()
+	remoteDir := t.TempDir()
+	err := os.MkdirAll(filepath.Join(localDir, "src", "nested"), 0o755)
+	require.NoError(t, err)
+	err = os.WriteFile(filepath.Join(localDir, "src", "hello.txt"), []byte("hello"), 0o600)
+	require.NoError(t, err)
+	err = os.WriteFile(filepath.Join(localDir, "src", "nested", "world.txt"), []byte("world"), 0o644)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	// Directories are only copied with --recursive.
+	cmd, root := clitest.New(t, "cp", filepath.Join(localDir, "src"), workspace.Name+":"+remoteDir)
+	clitest.SetupConfig(t, client, root)
+	require.ErrorContains(t, cmd.ExecuteContext(ctx), "--recursive")
+
+	cmd, root = clitest.New(t, "cp", "--recursive", filepath.Join(localDir, "src"), workspace.Name+":"+remoteDir)
+	clitest.SetupConfig(t, client, root)
+	require.NoError(t, cmd.ExecuteContext(ctx))
+
+	content, err := os.ReadFile(filepath.Join(remoteDir, "src", "nested", "world.txt"))
+	require.NoError(t, err)
+	require.Equal(t, "world", string(content))
+
+	cmd, root = clitest.New(t, "cp", "--recursive", workspace.Name+":"+filepath.Join(remoteDir, "src"), filepath.Join(localDir, "dst"))
+	clitest.SetupConfig(t, client, root)
+	require.NoError(t, cmd.ExecuteContext(ctx))
+
+	content, err = os.ReadFile(filepath.Join(localDir, "dst", "hello.txt"))
+	require.NoError(t, err)
+	require.Equal(t, "hello", string(content))
+	content, err = os.ReadFile(filepath.Join(localDir, "dst", "nested", "world.txt"))
+	require.NoError(t, err)
+	require.Equal(t, "world", string(content))
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
 
 	"github.com/coder/coder/cli/cliui"
 	"github.com/coder/coder/codersdk"
@@ -12,14 +13,45 @@ import (
 
 // nolint
 func deleteWorkspace() *cobra.Command {
-	var orphan bool
+	var (
+		orphan      bool
+		searchQuery string
+		dryRun      bool
+	)
 	cmd := &cobra.Command{
 		Annotations: workspaceCommand,
-		Use:         "delete <workspace>",
+		Use:         "delete [workspace]",
 		Short:       "Delete a workspace",
-		Aliases:     []string{"rm"},
-		Args:        cobra.ExactArgs(1),
+		Example: formatExamples(
+			example{
+				Description: "Delete all workspaces of a user",
+				Command:     "coder delete --search owner:bob",
+			},
+		),
+		Aliases: []string{"rm"},
+		Args:    cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if cmd.Flags().Changed("search") {
+				if len(args) > 0 {
+					return xerrors.New("a workspace can't be specified together with --search")
+				}
+				if orphan {
+					return xerrors.New("--orphan can't be used together with --search")
+				}
+				client, err := CreateClient(cmd)
+				if err != nil {
+					return err
+				}
+				return bulkWorkspaceBuild(cmd, client, codersdk.BulkWorkspaceBuildRequest{
+					Query:      searchQuery,
+					Transition: codersdk.WorkspaceTransitionDelete,
+					DryRun:     dryRun,
+				})
+			}
+			if len(args) == 0 {
+				return xerrors.New("specify a workspace to delete, or delete many with --search")
+			}
+
 			_, err := cliui.Prompt(cmd, cliui.PromptOptions{
 				Text:      "Confirm delete workspace?",
 				IsConfirm: true,
@@ -70,6 +102,8 @@ func deleteWorkspace() *cobra.Command {
 		`Delete a workspace without deleting its resources. This can delete a
 workspace in a broken state, but may also lead to unaccounted cloud resources.`,
 	)
+	cmd.Flags().StringVar(&searchQuery, "search", "", "Delete all workspaces matching a search query, e.g. \"owner:bob\".")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "List the workspaces --search would delete without deleting them.")
 	cliui.AllowSkipPrompt(cmd)
 	return cmd
 }
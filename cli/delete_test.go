@@ -1,6 +1,7 @@
 package cli_test
 
 import (
+	"bytes"
 	"context"
 	"io"
 	"testing"
@@ -12,6 +13,7 @@ import (
 	"github.com/coder/coder/coderd/coderdtest"
 	"github.com/coder/coder/codersdk"
 	"github.com/coder/coder/pty/ptytest"
+	"github.com/coder/coder/testutil"
 )
 
 func TestDelete(t *testing.T) {
@@ -122,4 +124,45 @@ func TestDelete(t *testing.T) {
 		}()
 		<-doneChan
 	})
+
+	t.Run("Search", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		workspaces := []codersdk.Workspace{
+			coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID),
+			coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID),
+		}
+		for _, workspace := range workspaces {
+			coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+		}
+
+		// A dry run only lists the workspaces.
+		cmd, root := clitest.New(t, "delete", "--search", "template:"+template.Name, "--dry-run")
+		clitest.SetupConfig(t, client, root)
+		out := bytes.NewBuffer(nil)
+		cmd.SetOut(out)
+		require.NoError(t, cmd.Execute())
+		for _, workspace := range workspaces {
+			require.Contains(t, out.String(), workspace.Name)
+		}
+		require.Contains(t, out.String(), "Would delete")
+
+		cmd, root = clitest.New(t, "delete", "--search", "template:"+template.Name, "-y")
+		clitest.SetupConfig(t, client, root)
+		out = bytes.NewBuffer(nil)
+		cmd.SetOut(out)
+		require.NoError(t, cmd.Execute())
+		require.Contains(t, out.String(), "Queued delete build")
+
+		require.Eventually(t, func() bool {
+			workspaces, err := client.Workspaces(context.Background(), codersdk.WorkspaceFilter{
+				FilterQuery: "template:" + template.Name,
+			})
+			return err == nil && len(workspaces) == 0
+		}, testutil.WaitLong, testutil.IntervalFast)
+	})
 }
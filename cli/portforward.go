@@ -26,6 +26,7 @@ func portForward() *cobra.Command {
 	var (
 		tcpForwards []string // <port>:<port>
 		udpForwards []string // <port>:<port>
+		listPorts   bool
 	)
 	cmd := &cobra.Command{
 		Use:     "port-forward <workspace>",
@@ -45,6 +46,10 @@ func portForward() *cobra.Command {
 				Description: "Port forward multiple TCP ports and a UDP port",
 				Command:     "coder port-forward <workspace> --tcp 8080:8080 --tcp 9000:3000 --udp 5353:53",
 			},
+			example{
+				Description: "List the ports that processes in the workspace listen on",
+				Command:     "coder port-forward <workspace> --list",
+			},
 		),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx, cancel := context.WithCancel(cmd.Context())
@@ -54,7 +59,7 @@ func portForward() *cobra.Command {
 			if err != nil {
 				return xerrors.Errorf("parse port-forward specs: %w", err)
 			}
-			if len(specs) == 0 {
+			if len(specs) == 0 && !listPorts {
 				err = cmd.Help()
 				if err != nil {
 					return xerrors.Errorf("generate help output: %w", err)
@@ -91,6 +96,23 @@ func portForward() *cobra.Command {
 				return xerrors.Errorf("await agent: %w", err)
 			}
 
+			if listPorts {
+				ports, err := client.WorkspaceAgentListeningPorts(ctx, workspaceAgent.ID)
+				if err != nil {
+					return xerrors.Errorf("list listening ports: %w", err)
+				}
+				if len(ports.Ports) == 0 {
+					_, _ = fmt.Fprintln(cmd.OutOrStdout(), "No listening ports found.")
+					return nil
+				}
+				out, err := displayListeningPorts(ports.Ports)
+				if err != nil {
+					return xerrors.Errorf("render table: %w", err)
+				}
+				_, err = fmt.Fprintln(cmd.OutOrStdout(), out)
+				return err
+			}
+
 			conn, err := client.DialWorkspaceAgentTailnet(ctx, slog.Logger{}, workspaceAgent.ID)
 			if err != nil {
 				return err
@@ -166,9 +188,28 @@ func portForward() *cobra.Command {
 
 	cmd.Flags().StringArrayVarP(&tcpForwards, "tcp", "p", []string{}, "Forward a TCP port from the workspace to the local machine")
 	cmd.Flags().StringArrayVar(&udpForwards, "udp", []string{}, "Forward a UDP port from the workspace to the local machine. The UDP connection has TCP-like semantics to support stateful UDP protocols")
+	cmd.Flags().BoolVarP(&listPorts, "list", "l", false, "List the TCP ports that processes in the workspace listen on")
 	return cmd
 }
 
+type listeningPortRow struct {
+	Port    uint16 `table:"port"`
+	Network string `table:"network"`
+	Process string `table:"process"`
+}
+
+func displayListeningPorts(ports []codersdk.WorkspaceAgentListeningPort) (string, error) {
+	rows := make([]listeningPortRow, 0, len(ports))
+	for _, port := range ports {
+		rows = append(rows, listeningPortRow{
+			Port:    port.Port,
+			Network: port.Network,
+			Process: port.ProcessName,
+		})
+	}
+	return cliui.DisplayTable(rows, "", nil)
+}
+
 func listenAndPortForward(ctx context.Context, cmd *cobra.Command, conn *agent.Conn, wg *sync.WaitGroup, spec portForwardSpec) (net.Listener, error) {
 	_, _ = fmt.Fprintf(cmd.OutOrStderr(), "Forwarding '%v://%v' locally to '%v://%v' in the workspace\n", spec.listenNetwork, spec.listenAddress, spec.dialNetwork, spec.dialAddress)
 
@@ -0,0 +1,205 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/cli/cliflag"
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/codersdk"
+)
+
+func port() *cobra.Command {
+	cmd := &cobra.Command{
+		Annotations: workspaceCommand,
+		Use:         "port",
+		Short:       "Share the ports of a workspace with other users",
+	}
+	cmd.AddCommand(
+		portShare(),
+		portUnshare(),
+		portList(),
+	)
+	return cmd
+}
+
+func portShare() *cobra.Command {
+	var shareLevel string
+	cmd := &cobra.Command{
+		Use:   "share <workspace>[.<agent>] <port>",
+		Short: "Share a port of a workspace through its application URL",
+		Args:  cobra.ExactArgs(2),
+		Example: formatExamples(
+			example{
+				Description: "Share port 3000 with every signed in user",
+				Command:     "coder port share <workspace> 3000",
+			},
+			example{
+				Description: "Share port 8080 of the \"main\" agent with anyone, without signing in",
+				Command:     "coder port share <workspace>.main 8080 --level public",
+			},
+		),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := CreateClient(cmd)
+			if err != nil {
+				return err
+			}
+			workspace, agentName, err := workspaceAndAgentName(cmd, client, args[0])
+			if err != nil {
+				return err
+			}
+			port, err := parseSharedPort(args[1])
+			if err != nil {
+				return err
+			}
+
+			share, err := client.UpsertWorkspacePortShare(cmd.Context(), workspace.ID, codersdk.UpsertWorkspacePortShareRequest{
+				AgentName:  agentName,
+				Port:       port,
+				ShareLevel: codersdk.WorkspacePortShareLevel(shareLevel),
+			})
+			if err != nil {
+				return xerrors.Errorf("share port: %w", err)
+			}
+
+			if share.URL == "" {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Port %d is shared with %s users\n",
+					share.Port, cliui.Styles.Keyword.Render(string(share.ShareLevel)))
+				return nil
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Port %d is shared with %s users at %s\n",
+				share.Port, cliui.Styles.Keyword.Render(string(share.ShareLevel)), share.URL)
+			return nil
+		},
+	}
+	cliflag.StringVarP(cmd.Flags(), &shareLevel, "level", "l", "CODER_PORT_SHARE_LEVEL", string(codersdk.WorkspacePortShareLevelAuthenticated),
+		`Specifies who can access the port: "owner", "authenticated", or "public".`)
+	return cmd
+}
+
+func portUnshare() *cobra.Command {
+	return &cobra.Command{
+		Use:   "unshare <workspace>[.<agent>] <port>",
+		Short: "Stop sharing a port of a workspace",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := CreateClient(cmd)
+			if err != nil {
+				return err
+			}
+			workspace, agentName, err := workspaceAndAgentName(cmd, client, args[0])
+			if err != nil {
+				return err
+			}
+			port, err := parseSharedPort(args[1])
+			if err != nil {
+				return err
+			}
+
+			err = client.DeleteWorkspacePortShare(cmd.Context(), workspace.ID, codersdk.DeleteWorkspacePortShareRequest{
+				AgentName: agentName,
+				Port:      port,
+			})
+			if err != nil {
+				return xerrors.Errorf("unshare port: %w", err)
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Port %d is only accessible by the workspace owner\n", port)
+			return nil
+		},
+	}
+}
+
+type portShareRow struct {
+	Agent      string `table:"agent"`
+	Port       int32  `table:"port"`
+	ShareLevel string `table:"share level"`
+}
+
+func portList() *cobra.Command {
+	var columns []string
+	cmd := &cobra.Command{
+		Use:     "list <workspace>",
+		Aliases: []string{"ls"},
+		Short:   "List the shared ports of a workspace",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := CreateClient(cmd)
+			if err != nil {
+				return err
+			}
+			workspace, err := namedWorkspace(cmd, client, args[0])
+			if err != nil {
+				return xerrors.Errorf("get workspace: %w", err)
+			}
+			shares, err := client.WorkspacePortShares(cmd.Context(), workspace.ID)
+			if err != nil {
+				return xerrors.Errorf("get port shares: %w", err)
+			}
+			if len(shares) == 0 {
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), "No shared ports found.")
+				return nil
+			}
+
+			rows := make([]portShareRow, 0, len(shares))
+			for _, share := range shares {
+				rows = append(rows, portShareRow{
+					Agent:      share.AgentName,
+					Port:       share.Port,
+					ShareLevel: string(share.ShareLevel),
+				})
+			}
+			out, err := cliui.DisplayTable(rows, "", columns)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(cmd.OutOrStdout(), out)
+			return err
+		},
+	}
+	cmd.Flags().StringArrayVarP(&columns, "column", "c", nil,
+		"Specify a column to filter in the table.")
+	return cmd
+}
+
+// workspaceAndAgentName returns the workspace and agent name identified by
+// "<workspace>[.<agent>]". The agent name can be omitted if the workspace
+// has a single agent.
+func workspaceAndAgentName(cmd *cobra.Command, client *codersdk.Client, in string) (codersdk.Workspace, string, error) {
+	workspaceName, agentName, _ := strings.Cut(in, ".")
+	workspace, err := namedWorkspace(cmd, client, workspaceName)
+	if err != nil {
+		return codersdk.Workspace{}, "", xerrors.Errorf("get workspace: %w", err)
+	}
+	if agentName != "" {
+		return workspace, agentName, nil
+	}
+
+	resources, err := client.WorkspaceResourcesByBuild(cmd.Context(), workspace.LatestBuild.ID)
+	if err != nil {
+		return codersdk.Workspace{}, "", xerrors.Errorf("fetch workspace resources: %w", err)
+	}
+	var agents []codersdk.WorkspaceAgent
+	for _, resource := range resources {
+		agents = append(agents, resource.Agents...)
+	}
+	switch len(agents) {
+	case 0:
+		return codersdk.Workspace{}, "", xerrors.Errorf("workspace %q has no agents", workspace.Name)
+	case 1:
+		return workspace, agents[0].Name, nil
+	default:
+		return codersdk.Workspace{}, "", xerrors.New("you must specify the name of an agent")
+	}
+}
+
+func parseSharedPort(raw string) (int32, error) {
+	port, err := strconv.ParseUint(raw, 10, 16)
+	if err != nil || port == 0 {
+		return 0, xerrors.Errorf("invalid port %q", raw)
+	}
+	return int32(port), nil
+}
@@ -0,0 +1,86 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/cli/clitest"
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/provisioner/echo"
+	"github.com/coder/coder/provisionersdk/proto"
+	"github.com/coder/coder/testutil"
+)
+
+func TestPortShare(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{
+		AppHostname:              "*.test.coder.com",
+		IncludeProvisionerDaemon: true,
+	})
+	user := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse:           echo.ParseComplete,
+		ProvisionDryRun: echo.ProvisionComplete,
+		Provision: []*proto.Provision_Response{{
+			Type: &proto.Provision_Response_Complete{
+				Complete: &proto.Provision_Complete{
+					Resources: []*proto.Resource{{
+						Name: "dev",
+						Type: "google_compute_instance",
+						Agents: []*proto.Agent{{
+							Id:   uuid.NewString(),
+							Name: "main",
+							Auth: &proto.Agent_Token{
+								Token: uuid.NewString(),
+							},
+						}},
+					}},
+				},
+			},
+		}},
+	})
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	cmd, root := clitest.New(t, "port", "share", workspace.Name, "8080", "--level", "public")
+	clitest.SetupConfig(t, client, root)
+	out := bytes.NewBuffer(nil)
+	cmd.SetOut(out)
+	require.NoError(t, cmd.ExecuteContext(ctx))
+	require.Contains(t, out.String(), fmt.Sprintf("8080--main--%s--%s.test.coder.com", workspace.Name, coderdtest.FirstUserParams.Username))
+
+	shares, err := client.WorkspacePortShares(ctx, workspace.ID)
+	require.NoError(t, err)
+	require.Len(t, shares, 1)
+	require.Equal(t, "main", shares[0].AgentName)
+	require.EqualValues(t, 8080, shares[0].Port)
+	require.Equal(t, codersdk.WorkspacePortShareLevelPublic, shares[0].ShareLevel)
+
+	cmd, root = clitest.New(t, "port", "list", workspace.Name)
+	clitest.SetupConfig(t, client, root)
+	out = bytes.NewBuffer(nil)
+	cmd.SetOut(out)
+	require.NoError(t, cmd.ExecuteContext(ctx))
+	require.Contains(t, out.String(), "8080")
+	require.Contains(t, out.String(), "public")
+
+	cmd, root = clitest.New(t, "port", "unshare", workspace.Name+".main", "8080")
+	clitest.SetupConfig(t, client, root)
+	require.NoError(t, cmd.ExecuteContext(ctx))
+
+	shares, err = client.WorkspacePortShares(ctx, workspace.ID)
+	require.NoError(t, err)
+	require.Empty(t, shares)
+}
@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/sloghuman"
+	"github.com/coder/coder/cli/cliflag"
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/provisionerd/proto"
+)
+
+func provisionerDaemons() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "provisionerd",
+		Short: "Manage provisioner daemons",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+	cmd.AddCommand(
+		provisionerDaemonStart(),
+	)
+
+	return cmd
+}
+
+func provisionerDaemonStart() *cobra.Command {
+	var (
+		cacheDir string
+		rawTags  []string
+	)
+	cmd := &cobra.Command{
+		Use:   "start",
+		Short: "Run a provisioner daemon that connects to a Coder deployment",
+		Long: "Run a Terraform provisioner daemon outside of the Coder server. " +
+			"Jobs are acquired from the deployment over the network, so any cloud " +
+			"credentials Terraform requires only need to exist where this command runs.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithCancel(cmd.Context())
+			defer cancel()
+
+			notifyCtx, notifyStop := signal.NotifyContext(ctx, interruptSignals...)
+			defer notifyStop()
+
+			client, err := CreateClient(cmd)
+			if err != nil {
+				return err
+			}
+
+			tags, err := parseProvisionerTags(rawTags)
+			if err != nil {
+				return err
+			}
+
+			logger := slog.Make(sloghuman.Sink(cmd.ErrOrStderr()))
+			if cliflag.IsSetBool(cmd, varVerbose) {
+				logger = logger.Leveled(slog.LevelDebug)
+			}
+
+			errCh := make(chan error, 1)
+			daemon, err := newProvisionerDaemon(ctx, func(ctx context.Context) (proto.DRPCProvisionerDaemonClient, error) {
+				return client.ServeProvisionerDaemon(ctx, []codersdk.ProvisionerType{
+					codersdk.ProvisionerTypeTerraform,
+				}, tags)
+			}, nil, logger, cacheDir, errCh, false)
+			if err != nil {
+				return xerrors.Errorf("create provisioner daemon: %w", err)
+			}
+
+			_, _ = cmd.OutOrStdout().Write([]byte(cliui.Styles.Paragraph.Render(
+				"Started provisioner daemon connected to "+cliui.Styles.Field.Render(client.URL.String())+"! "+
+					"Press Ctrl+C to exit.") + "\n"))
+
+			var exitErr error
+			select {
+			case <-notifyCtx.Done():
+				exitErr = notifyCtx.Err()
+				_, _ = cmd.OutOrStdout().Write([]byte(cliui.Styles.Bold.Render(
+					"Interrupt caught, gracefully exiting. Use ctrl+\\ to force quit") + "\n"))
+			case exitErr = <-errCh:
+			}
+			if exitErr != nil && !xerrors.Is(exitErr, context.Canceled) {
+				cmd.PrintErrf("Unexpected error, shutting down provisioner daemon: %s\n", exitErr)
+			}
+
+			err = shutdownWithTimeout(daemon.Shutdown, 5*time.Second)
+			if err != nil {
+				cmd.PrintErrf("Failed to shutdown provisioner daemon: %s\n", err)
+			}
+			err = daemon.Close()
+			if err != nil {
+				return xerrors.Errorf("close provisioner daemon: %w", err)
+			}
+			cmd.Println("Gracefully shut down provisioner daemon")
+
+			if xerrors.Is(exitErr, context.Canceled) {
+				return nil
+			}
+			return exitErr
+		},
+	}
+
+	defaultCacheDir := filepath.Join(os.TempDir(), "coder-cache")
+	if dir := os.Getenv("CACHE_DIRECTORY"); dir != "" {
+		// For compatibility with systemd.
+		defaultCacheDir = dir
+	}
+	cliflag.StringVarP(cmd.Flags(), &cacheDir, "cache-dir", "c", "CODER_CACHE_DIRECTORY", defaultCacheDir,
+		"Specify a directory to cache provisioner job files.")
+	cliflag.StringArrayVarP(cmd.Flags(), &rawTags, "tag", "t", "CODER_PROVISIONERD_TAGS", []string{},
+		"Specify a key=value tag. Only jobs with a subset of these tags are acquired.")
+	return cmd
+}
+
+// parseProvisionerTags parses "key=value" pairs into a map of tags.
+func parseProvisionerTags(rawTags []string) (map[string]string, error) {
+	tags := map[string]string{}
+	for _, rawTag := range rawTags {
+		key, value, ok := strings.Cut(rawTag, "=")
+		if !ok || key == "" {
+			return nil, xerrors.Errorf("invalid tag %q: must be in the format \"key=value\"", rawTag)
+		}
+		tags[key] = value
+	}
+	return tags, nil
+}
@@ -66,7 +66,9 @@ func init() {
 
 func Core() []*cobra.Command {
 	return []*cobra.Command{
+		autoupdate(),
 		configSSH(),
+		cp(),
 		create(),
 		deleteWorkspace(),
 		dotfiles(),
@@ -75,7 +77,9 @@ func Core() []*cobra.Command {
 		login(),
 		logout(),
 		parameters(),
+		port(),
 		portForward(),
+		provisionerDaemons(),
 		publickey(),
 		resetPassword(),
 		schedules(),
@@ -87,6 +91,7 @@ func Core() []*cobra.Command {
 		stop(),
 		rename(),
 		templates(),
+		tokens(),
 		update(),
 		users(),
 		versionCmd(),
@@ -320,8 +325,8 @@ func createAgentClient(cmd *cobra.Command) (*codersdk.Client, error) {
 	return client, nil
 }
 
-// currentOrganization returns the currently active organization for the authenticated user.
-func currentOrganization(cmd *cobra.Command, client *codersdk.Client) (codersdk.Organization, error) {
+// CurrentOrganization returns the currently active organization for the authenticated user.
+func CurrentOrganization(cmd *cobra.Command, client *codersdk.Client) (codersdk.Organization, error) {
 	orgs, err := client.OrganizationsByUser(cmd.Context(), codersdk.Me)
 	if err != nil {
 		return codersdk.Organization{}, nil
@@ -39,7 +39,9 @@ import (
 	"golang.org/x/xerrors"
 	"google.golang.org/api/idtoken"
 	"google.golang.org/api/option"
+	"tailscale.com/derp"
 	"tailscale.com/tailcfg"
+	"tailscale.com/types/key"
 
 	"cdr.dev/slog"
 	"cdr.dev/slog/sloggers/sloghuman"
@@ -71,6 +73,7 @@ import (
 func Server(newAPI func(context.Context, *coderd.Options) (*coderd.API, error)) *cobra.Command {
 	var (
 		accessURL             string
+		wildcardAccessURL     string
 		address               string
 		autobuildPollInterval time.Duration
 		derpServerEnabled     bool
@@ -119,6 +122,7 @@ func Server(newAPI func(context.Context, *coderd.Options) (*coderd.API, error))
 		verbose                          bool
 		metricsCacheRefreshInterval      time.Duration
 		agentStatRefreshInterval         time.Duration
+		maxTokenLifetime                 time.Duration
 	)
 
 	root := &cobra.Command{
@@ -284,6 +288,10 @@ func Server(newAPI func(context.Context, *coderd.Options) (*coderd.API, error))
 
 			cmd.Printf("View the Web UI: %s\n", accessURLParsed.String())
 
+			if wildcardAccessURL != "" && !strings.HasPrefix(wildcardAccessURL, "*.") {
+				return xerrors.Errorf("wildcard access URL %q must start with \"*.\"", wildcardAccessURL)
+			}
+
 			// Used for zero-trust instance identity with Google Cloud.
 			googleTokenValidator, err := idtoken.NewValidator(ctx, option.WithoutAuthentication())
 			if err != nil {
@@ -339,6 +347,7 @@ func Server(newAPI func(context.Context, *coderd.Options) (*coderd.API, error))
 
 			options := &coderd.Options{
 				AccessURL:                   accessURLParsed,
+				AppHostname:                 wildcardAccessURL,
 				Logger:                      logger.Named("coderd"),
 				Database:                    databasefake.New(),
 				DERPMap:                     derpMap,
@@ -352,6 +361,7 @@ func Server(newAPI func(context.Context, *coderd.Options) (*coderd.API, error))
 				AutoImportTemplates:         validatedAutoImportTemplates,
 				MetricsCacheRefreshInterval: metricsCacheRefreshInterval,
 				AgentStatsRefreshInterval:   agentStatRefreshInterval,
+				MaxTokenLifetime:            maxTokenLifetime,
 			}
 
 			if oauth2GithubClientSecret != "" {
@@ -434,6 +444,40 @@ func Server(newAPI func(context.Context, *coderd.Options) (*coderd.API, error))
 				}
 			}
 
+			// Every replica shares the same DERP mesh key so relays can
+			// forward packets between each other.
+			options.DERPServer = derp.NewServer(key.NewNode(), tailnet.Logger(logger.Named("derp")))
+			// Replicas starting together may both generate a key, the insert
+			// keeps the first one so every replica reads back the same key.
+			var meshKey string
+			err = options.Database.InTx(func(tx database.Store) error {
+				var err error
+				meshKey, err = tx.GetDERPMeshKey(ctx)
+				if !errors.Is(err, sql.ErrNoRows) {
+					if err != nil {
+						return xerrors.Errorf("get derp mesh key: %w", err)
+					}
+					return nil
+				}
+				newKey, err := cryptorand.HexString(64)
+				if err != nil {
+					return xerrors.Errorf("generate derp mesh key: %w", err)
+				}
+				err = tx.InsertDERPMeshKey(ctx, newKey)
+				if err != nil {
+					return xerrors.Errorf("insert derp mesh key: %w", err)
+				}
+				meshKey, err = tx.GetDERPMeshKey(ctx)
+				if err != nil {
+					return xerrors.Errorf("get derp mesh key: %w", err)
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			options.DERPServer.SetMeshKey(meshKey)
+
 			// Parse the raw telemetry URL!
 			telemetryURL, err := parseURL(ctx, telemetryURL)
 			if err != nil {
@@ -483,6 +527,12 @@ func Server(newAPI func(context.Context, *coderd.Options) (*coderd.API, error))
 				}
 				defer closeWorkspacesFunc()
 
+				closeAgentStatsFunc, err := prometheusmetrics.AgentStats(ctx, options.PrometheusRegistry, options.Database, 0)
+				if err != nil {
+					return xerrors.Errorf("register agent stats prometheus metric: %w", err)
+				}
+				defer closeAgentStatsFunc()
+
 				//nolint:revive
 				defer serveHandler(ctx, logger, promhttp.InstrumentMetricHandler(
 					options.PrometheusRegistry, promhttp.HandlerFor(options.PrometheusRegistry, promhttp.HandlerOpts{}),
@@ -521,7 +571,7 @@ func Server(newAPI func(context.Context, *coderd.Options) (*coderd.API, error))
 				}
 			}()
 			for i := 0; uint8(i) < provisionerDaemonCount; i++ {
-				daemon, err := newProvisionerDaemon(ctx, coderAPI, logger, cacheDir, errCh, false)
+				daemon, err := newProvisionerDaemon(ctx, coderAPI.ListenProvisionerDaemon, coderAPI.TracerProvider, logger, cacheDir, errCh, false)
 				if err != nil {
 					return xerrors.Errorf("create provisioner daemon: %w", err)
 				}
@@ -589,7 +639,7 @@ func Server(newAPI func(context.Context, *coderd.Options) (*coderd.API, error))
 
 			autobuildPoller := time.NewTicker(autobuildPollInterval)
 			defer autobuildPoller.Stop()
-			autobuildExecutor := executor.New(ctx, options.Database, logger, autobuildPoller.C)
+			autobuildExecutor := executor.New(ctx, options.Database, logger, autobuildPoller.C).WithAuditor(&coderAPI.Auditor).WithPubsub(options.Pubsub)
 			autobuildExecutor.Run()
 
 			// This is helpful for tests, but can be silently ignored.
@@ -743,6 +793,8 @@ func Server(newAPI func(context.Context, *coderd.Options) (*coderd.API, error))
 	_ = root.Flags().MarkHidden("autobuild-poll-interval")
 	cliflag.StringVarP(root.Flags(), &accessURL, "access-url", "", "CODER_ACCESS_URL", "",
 		"External URL to access your deployment. This must be accessible by all provisioned workspaces.")
+	cliflag.StringVarP(root.Flags(), &wildcardAccessURL, "wildcard-access-url", "", "CODER_WILDCARD_ACCESS_URL", "",
+		"Wildcard hostname workspace applications are served from, e.g. \"*.coder.example.com\". Ports can only be shared with other users when this is set.")
 	cliflag.StringVarP(root.Flags(), &address, "address", "a", "CODER_ADDRESS", "127.0.0.1:3000",
 		"Bind address of the server.")
 	cliflag.StringVarP(root.Flags(), &derpConfigURL, "derp-config-url", "", "CODER_DERP_CONFIG_URL", "",
@@ -834,6 +886,8 @@ func Server(newAPI func(context.Context, *coderd.Options) (*coderd.API, error))
 		"Workspaces must be able to reach the `access-url`. This overrides your access URL with a public access URL that tunnels your Coder deployment.")
 	cliflag.BoolVarP(root.Flags(), &traceEnable, "trace", "", "CODER_TRACE", false,
 		"Whether application tracing data is collected.")
+	cliflag.DurationVarP(root.Flags(), &maxTokenLifetime, "max-token-lifetime", "", "CODER_MAX_TOKEN_LIFETIME", 365*24*time.Hour,
+		"The maximum lifetime users can create tokens with.")
 	cliflag.BoolVarP(root.Flags(), &secureAuthCookie, "secure-auth-cookie", "", "CODER_SECURE_AUTH_COOKIE", false,
 		"Controls if the 'Secure' property is set on browser session cookies")
 	cliflag.StringVarP(root.Flags(), &sshKeygenAlgorithmRaw, "ssh-keygen-algorithm", "", "CODER_SSH_KEYGEN_ALGORITHM", "ed25519",
@@ -915,7 +969,8 @@ func shutdownWithTimeout(shutdown func(context.Context) error, timeout time.Dura
 // nolint:revive
 func newProvisionerDaemon(
 	ctx context.Context,
-	coderAPI *coderd.API,
+	dialer provisionerd.Dialer,
+	tracerProvider trace.TracerProvider,
 	logger slog.Logger,
 	cacheDir string,
 	errCh chan error,
@@ -986,13 +1041,13 @@ func newProvisionerDaemon(
 		}()
 		provisioners[string(database.ProvisionerTypeEcho)] = proto.NewDRPCProvisionerClient(provisionersdk.Conn(echoClient))
 	}
-	return provisionerd.New(coderAPI.ListenProvisionerDaemon, &provisionerd.Options{
+	return provisionerd.New(dialer, &provisionerd.Options{
 		Logger:         logger,
 		PollInterval:   500 * time.Millisecond,
 		UpdateInterval: 500 * time.Millisecond,
 		Provisioners:   provisioners,
 		WorkDirectory:  tempDir,
-		Tracer:         coderAPI.TracerProvider,
+		Tracer:         tracerProvider,
 	}), nil
 }
 
@@ -7,6 +7,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -22,6 +23,7 @@ import (
 
 	"cdr.dev/slog"
 
+	"github.com/coder/coder/agent"
 	"github.com/coder/coder/cli/cliflag"
 	"github.com/coder/coder/cli/cliui"
 	"github.com/coder/coder/coderd/autobuild/notify"
@@ -42,6 +44,7 @@ func ssh() *cobra.Command {
 		forwardAgent   bool
 		identityAgent  string
 		wsPollInterval time.Duration
+		wait           bool
 	)
 	cmd := &cobra.Command{
 		Annotations: workspaceCommand,
@@ -85,6 +88,12 @@ func ssh() *cobra.Command {
 			if err != nil {
 				return xerrors.Errorf("await agent: %w", err)
 			}
+			if wait {
+				err = waitForAgentReady(ctx, cmd.ErrOrStderr(), client, workspaceAgent.ID)
+				if err != nil {
+					return xerrors.Errorf("await agent startup: %w", err)
+				}
+			}
 
 			conn, err := client.DialWorkspaceAgentTailnet(ctx, slog.Logger{}, workspaceAgent.ID)
 			if err != nil {
@@ -109,6 +118,8 @@ func ssh() *cobra.Command {
 				return nil
 			}
 
+			printListeningPorts(ctx, cmd.ErrOrStderr(), conn, workspace.Name)
+
 			sshClient, err := conn.SSHClient()
 			if err != nil {
 				return err
@@ -213,10 +224,71 @@ func ssh() *cobra.Command {
 	_ = cmd.Flags().MarkHidden("shuffle")
 	cliflag.BoolVarP(cmd.Flags(), &forwardAgent, "forward-agent", "A", "CODER_SSH_FORWARD_AGENT", false, "Specifies whether to forward the SSH agent specified in $SSH_AUTH_SOCK")
 	cliflag.StringVarP(cmd.Flags(), &identityAgent, "identity-agent", "", "CODER_SSH_IDENTITY_AGENT", "", "Specifies which identity agent to use (overrides $SSH_AUTH_SOCK), forward agent must also be enabled")
+	cliflag.BoolVarP(cmd.Flags(), &wait, "wait", "", "CODER_SSH_WAIT", false, "Specifies whether to wait for the startup script to finish before connecting, printing its output.")
 	cliflag.DurationVarP(cmd.Flags(), &wsPollInterval, "workspace-poll-interval", "", "CODER_WORKSPACE_POLL_INTERVAL", workspacePollInterval, "Specifies how often to poll for workspace automated shutdown.")
 	return cmd
 }
 
+// waitForAgentReady prints the output of the agent's startup script until
+// it finishes running.
+func waitForAgentReady(ctx context.Context, writer io.Writer, client *codersdk.Client, agentID uuid.UUID) error {
+	var after int64
+	for {
+		workspaceAgent, err := client.WorkspaceAgent(ctx, agentID)
+		if err != nil {
+			return xerrors.Errorf("fetch agent: %w", err)
+		}
+		switch workspaceAgent.LifecycleState {
+		case codersdk.WorkspaceAgentLifecycleStartTimeout:
+			cliui.Warn(writer, "The startup script timed out.", "Your workspace may be incomplete.")
+			return nil
+		case codersdk.WorkspaceAgentLifecycleStartError:
+			cliui.Warn(writer, "The startup script exited with an error.", "Your workspace may be incomplete.")
+			return nil
+		}
+		if !workspaceAgent.LifecycleState.Starting() {
+			return nil
+		}
+
+		logs, closer, err := client.WorkspaceAgentStartupLogsAfter(ctx, agentID, after)
+		if err != nil {
+			return xerrors.Errorf("follow startup logs: %w", err)
+		}
+		for log := range logs {
+			_, _ = fmt.Fprintln(writer, log.Output)
+			after = log.ID
+		}
+		_ = closer.Close()
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		// The stream ends when the agent finishes starting, but may also
+		// end if the connection drops, so check the state again.
+	}
+}
+
+// printListeningPorts hints at the ports that can be forwarded from the
+// workspace. Agents that can't list ports are ignored.
+func printListeningPorts(ctx context.Context, writer io.Writer, conn *agent.Conn, workspaceName string) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	resp, err := conn.ListeningPorts(ctx)
+	if err != nil || len(resp.Ports) == 0 {
+		return
+	}
+	ports := make([]string, 0, len(resp.Ports))
+	for _, port := range resp.Ports {
+		if port.ProcessName == "" {
+			ports = append(ports, strconv.Itoa(int(port.Port)))
+			continue
+		}
+		ports = append(ports, fmt.Sprintf("%d (%s)", port.Port, port.ProcessName))
+	}
+	_, _ = fmt.Fprintf(writer, "Listening ports: %s\nForward them with %s\n",
+		strings.Join(ports, ", "),
+		cliui.Styles.Code.Render(fmt.Sprintf("coder port-forward %s --tcp <port>", workspaceName)))
+}
+
 // getWorkspaceAgent returns the workspace and agent selected using either the
 // `<workspace>[.<agent>]` syntax via `in` or picks a random workspace and agent
 // if `shuffle` is true.
@@ -39,6 +39,10 @@ func start() *cobra.Command {
 			}
 
 			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "\nThe %s workspace has been started at %s!\n", cliui.Styles.Keyword.Render(workspace.Name), cliui.Styles.DateTimeStamp.Render(time.Now().Format(time.Stamp)))
+			if workspace.Outdated && build.TemplateVersionID == workspace.LatestBuild.TemplateVersionID {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "The workspace is outdated. Run %s to use the active template version.\n",
+					cliui.Styles.Code.Render("coder update "+workspace.Name))
+			}
 			return nil
 		},
 	}
@@ -5,18 +5,47 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
 
 	"github.com/coder/coder/cli/cliui"
 	"github.com/coder/coder/codersdk"
 )
 
 func stop() *cobra.Command {
+	var (
+		searchQuery string
+		dryRun      bool
+	)
 	cmd := &cobra.Command{
 		Annotations: workspaceCommand,
-		Use:         "stop <workspace>",
+		Use:         "stop [workspace]",
 		Short:       "Stop a workspace",
-		Args:        cobra.ExactArgs(1),
+		Example: formatExamples(
+			example{
+				Description: "Stop all workspaces of a template",
+				Command:     "coder stop --search template:legacy",
+			},
+		),
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if cmd.Flags().Changed("search") {
+				if len(args) > 0 {
+					return xerrors.New("a workspace can't be specified together with --search")
+				}
+				client, err := CreateClient(cmd)
+				if err != nil {
+					return err
+				}
+				return bulkWorkspaceBuild(cmd, client, codersdk.BulkWorkspaceBuildRequest{
+					Query:      searchQuery,
+					Transition: codersdk.WorkspaceTransitionStop,
+					DryRun:     dryRun,
+				})
+			}
+			if len(args) == 0 {
+				return xerrors.New("specify a workspace to stop, or stop many with --search")
+			}
+
 			_, err := cliui.Prompt(cmd, cliui.PromptOptions{
 				Text:      "Confirm stop workspace?",
 				IsConfirm: true,
@@ -50,6 +79,8 @@ func stop() *cobra.Command {
 			return nil
 		},
 	}
+	cmd.Flags().StringVar(&searchQuery, "search", "", "Stop all workspaces matching a search query, e.g. \"owner:me template:legacy\".")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "List the workspaces --search would stop without stopping them.")
 	cliui.AllowSkipPrompt(cmd)
 	return cmd
 }
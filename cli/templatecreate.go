@@ -27,6 +27,7 @@ func templateCreate() *cobra.Command {
 		parameterFile        string
 		maxTTL               time.Duration
 		minAutostartInterval time.Duration
+		provisionerTags      []string
 	)
 	cmd := &cobra.Command{
 		Use:   "create [name]",
@@ -38,7 +39,12 @@ func templateCreate() *cobra.Command {
 				return err
 			}
 
-			organization, err := currentOrganization(cmd, client)
+			organization, err := CurrentOrganization(cmd, client)
+			if err != nil {
+				return err
+			}
+
+			tags, err := parseProvisionerTags(provisionerTags)
 			if err != nil {
 				return err
 			}
@@ -87,11 +93,12 @@ func templateCreate() *cobra.Command {
 			spin.Stop()
 
 			job, _, err := createValidTemplateVersion(cmd, createValidTemplateVersionArgs{
-				Client:        client,
-				Organization:  organization,
-				Provisioner:   database.ProvisionerType(provisioner),
-				FileHash:      resp.Hash,
-				ParameterFile: parameterFile,
+				Client:          client,
+				Organization:    organization,
+				Provisioner:     database.ProvisionerType(provisioner),
+				FileHash:        resp.Hash,
+				ParameterFile:   parameterFile,
+				ProvisionerTags: tags,
 			})
 			if err != nil {
 				return err
@@ -131,6 +138,7 @@ func templateCreate() *cobra.Command {
 	cmd.Flags().StringVarP(&directory, "directory", "d", currentDirectory, "Specify the directory to create from")
 	cmd.Flags().StringVarP(&provisioner, "test.provisioner", "", "terraform", "Customize the provisioner backend")
 	cmd.Flags().StringVarP(&parameterFile, "parameter-file", "", "", "Specify a file path with parameter values.")
+	cmd.Flags().StringArrayVarP(&provisionerTags, "provisioner-tag", "", []string{}, "Specify a key=value tag. Only provisioner daemons with all of these tags will build the template.")
 	cmd.Flags().DurationVarP(&maxTTL, "max-ttl", "", 24*time.Hour, "Specify a maximum TTL for workspaces created from this template.")
 	cmd.Flags().DurationVarP(&minAutostartInterval, "min-autostart-interval", "", time.Hour, "Specify a minimum autostart interval for workspaces created from this template.")
 	// This is for testing!
@@ -148,6 +156,10 @@ type createValidTemplateVersionArgs struct {
 	Provisioner   database.ProvisionerType
 	FileHash      string
 	ParameterFile string
+	// ProvisionerTags restricts the version's jobs to daemons with these tags.
+	ProvisionerTags map[string]string
+	// Message describes the changes of the version, e.g. release notes.
+	Message string
 	// Template is only required if updating a template's active version.
 	Template *codersdk.Template
 	// ReuseParameters will attempt to reuse params from the Template field
@@ -165,6 +177,8 @@ func createValidTemplateVersion(cmd *cobra.Command, args createValidTemplateVers
 		StorageSource:   args.FileHash,
 		Provisioner:     codersdk.ProvisionerType(args.Provisioner),
 		ParameterValues: parameters,
+		ProvisionerTags: args.ProvisionerTags,
+		Message:         args.Message,
 	}
 	if args.Template != nil {
 		req.TemplateID = args.Template.ID
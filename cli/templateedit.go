@@ -18,6 +18,7 @@ func templateEdit() *cobra.Command {
 		icon                 string
 		maxTTL               time.Duration
 		minAutostartInterval time.Duration
+		requireActiveVersion bool
 	)
 
 	cmd := &cobra.Command{
@@ -29,7 +30,7 @@ func templateEdit() *cobra.Command {
 			if err != nil {
 				return xerrors.Errorf("create client: %w", err)
 			}
-			organization, err := currentOrganization(cmd, client)
+			organization, err := CurrentOrganization(cmd, client)
 			if err != nil {
 				return xerrors.Errorf("get current organization: %w", err)
 			}
@@ -46,6 +47,9 @@ func templateEdit() *cobra.Command {
 				MaxTTLMillis:               maxTTL.Milliseconds(),
 				MinAutostartIntervalMillis: minAutostartInterval.Milliseconds(),
 			}
+			if cmd.Flags().Changed("require-active-version") {
+				req.RequireActiveVersion = &requireActiveVersion
+			}
 
 			_, err = client.UpdateTemplateMeta(cmd.Context(), template.ID, req)
 			if err != nil {
@@ -61,6 +65,7 @@ func templateEdit() *cobra.Command {
 	cmd.Flags().StringVarP(&icon, "icon", "", "", "Edit the template icon path")
 	cmd.Flags().DurationVarP(&maxTTL, "max-ttl", "", 0, "Edit the template maximum time before shutdown - workspaces created from this template cannot stay running longer than this.")
 	cmd.Flags().DurationVarP(&minAutostartInterval, "min-autostart-interval", "", 0, "Edit the template minimum autostart interval - workspaces created from this template must wait at least this long between autostarts.")
+	cmd.Flags().BoolVarP(&requireActiveVersion, "require-active-version", "", false, "Require workspaces to be started with the active template version. Template admins can still use other versions.")
 	cliui.AllowSkipPrompt(cmd)
 
 	return cmd
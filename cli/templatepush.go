@@ -18,10 +18,13 @@ import (
 
 func templatePush() *cobra.Command {
 	var (
-		directory     string
-		provisioner   string
-		parameterFile string
-		alwaysPrompt  bool
+		directory       string
+		provisioner     string
+		parameterFile   string
+		alwaysPrompt    bool
+		provisionerTags []string
+		message         string
+		activate        bool
 	)
 
 	cmd := &cobra.Command{
@@ -33,7 +36,7 @@ func templatePush() *cobra.Command {
 			if err != nil {
 				return err
 			}
-			organization, err := currentOrganization(cmd, client)
+			organization, err := CurrentOrganization(cmd, client)
 			if err != nil {
 				return err
 			}
@@ -48,6 +51,20 @@ func templatePush() *cobra.Command {
 				return err
 			}
 
+			tags, err := parseProvisionerTags(provisionerTags)
+			if err != nil {
+				return err
+			}
+			if len(tags) == 0 {
+				// Keep the tags of the active version, otherwise pushing
+				// would silently move the template to untagged daemons.
+				activeVersion, err := client.TemplateVersion(cmd.Context(), template.ActiveVersionID)
+				if err != nil {
+					return xerrors.Errorf("get active template version: %w", err)
+				}
+				tags = activeVersion.Job.Tags
+			}
+
 			// Confirm upload of the directory.
 			prettyDir := prettyDirectoryPath(directory)
 			_, err = cliui.Prompt(cmd, cliui.PromptOptions{
@@ -80,6 +97,8 @@ func templatePush() *cobra.Command {
 				Provisioner:     database.ProvisionerType(provisioner),
 				FileHash:        resp.Hash,
 				ParameterFile:   parameterFile,
+				ProvisionerTags: tags,
+				Message:         message,
 				Template:        &template,
 				ReuseParameters: !alwaysPrompt,
 			})
@@ -91,6 +110,14 @@ func templatePush() *cobra.Command {
 				return xerrors.Errorf("job failed: %s", job.Job.Status)
 			}
 
+			if !activate {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Created version %s at %s! Promote it with %s\n",
+					cliui.Styles.Keyword.Render(job.Name),
+					cliui.Styles.DateTimeStamp.Render(time.Now().Format(time.Stamp)),
+					cliui.Styles.Code.Render(fmt.Sprintf("coder templates versions promote %s %s", template.Name, job.Name)))
+				return nil
+			}
+
 			err = client.UpdateActiveTemplateVersion(cmd.Context(), template.ID, codersdk.UpdateActiveTemplateVersion{
 				ID: job.ID,
 			})
@@ -107,7 +134,10 @@ func templatePush() *cobra.Command {
 	cmd.Flags().StringVarP(&directory, "directory", "d", currentDirectory, "Specify the directory to create from")
 	cmd.Flags().StringVarP(&provisioner, "test.provisioner", "", "terraform", "Customize the provisioner backend")
 	cmd.Flags().StringVarP(&parameterFile, "parameter-file", "", "", "Specify a file path with parameter values.")
+	cmd.Flags().StringArrayVarP(&provisionerTags, "provisioner-tag", "", []string{}, "Specify a key=value tag. Defaults to the tags of the active template version.")
 	cmd.Flags().BoolVar(&alwaysPrompt, "always-prompt", false, "Always prompt all parameters. Does not pull parameter values from active template version")
+	cmd.Flags().StringVarP(&message, "message", "m", "", "Specify a message describing the changes in this version of the template.")
+	cmd.Flags().BoolVar(&activate, "activate", true, "Make the new version active. Use --activate=false to stage the version and promote it later.")
 	cliui.AllowSkipPrompt(cmd)
 	// This is for testing!
 	err := cmd.Flags().MarkHidden("test.provisioner")
@@ -155,6 +155,43 @@ func TestTemplatePush(t *testing.T) {
 		assert.NotEqual(t, template.ActiveVersionID, templateVersions[1].ID)
 	})
 
+	t.Run("Stage", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		_ = coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+
+		source := clitest.CreateTemplateVersionSource(t, &echo.Responses{
+			Parse:     echo.ParseComplete,
+			Provision: echo.ProvisionComplete,
+		})
+		cmd, root := clitest.New(t, "templates", "push", template.Name, "-y", "--directory", source, "--test.provisioner", string(database.ProvisionerTypeEcho),
+			"--activate=false", "--message", "Bump the base image")
+		clitest.SetupConfig(t, client, root)
+		require.NoError(t, cmd.Execute())
+
+		// The new version is created, but the active version is unchanged.
+		templateVersions, err := client.TemplateVersionsByTemplate(context.Background(), codersdk.TemplateVersionsByTemplateRequest{
+			TemplateID: template.ID,
+		})
+		require.NoError(t, err)
+		require.Len(t, templateVersions, 2)
+		staged := templateVersions[1]
+		assert.Equal(t, "Bump the base image", staged.Message)
+		latestTV, _ := latestTemplateVersion(t, client, template.ID)
+		assert.Equal(t, version.ID, latestTV.ID)
+
+		cmd, root = clitest.New(t, "templates", "versions", "promote", template.Name, staged.Name)
+		clitest.SetupConfig(t, client, root)
+		require.NoError(t, cmd.Execute())
+
+		latestTV, _ = latestTemplateVersion(t, client, template.ID)
+		assert.Equal(t, staged.ID, latestTV.ID)
+	})
+
 	t.Run("UseWorkingDir", func(t *testing.T) {
 		t.Parallel()
 		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
@@ -23,17 +23,25 @@ func templateVersions() *cobra.Command {
 				Description: "List versions of a specific template",
 				Command:     "coder templates versions list my-template",
 			},
+			example{
+				Description: "Make a version that was pushed with --activate=false active",
+				Command:     "coder templates versions promote my-template my-version",
+			},
 		),
 	}
 	cmd.AddCommand(
 		templateVersionsList(),
+		templateVersionsPromote(),
+		templateVersionsArchive(),
+		templateVersionsUnarchive(),
 	)
 
 	return cmd
 }
 
 func templateVersionsList() *cobra.Command {
-	return &cobra.Command{
+	var includeArchived bool
+	cmd := &cobra.Command{
 		Use:   "list <template>",
 		Args:  cobra.ExactArgs(1),
 		Short: "List all the versions of the specified template",
@@ -42,7 +50,7 @@ func templateVersionsList() *cobra.Command {
 			if err != nil {
 				return xerrors.Errorf("create client: %w", err)
 			}
-			organization, err := currentOrganization(cmd, client)
+			organization, err := CurrentOrganization(cmd, client)
 			if err != nil {
 				return xerrors.Errorf("get current organization: %w", err)
 			}
@@ -51,7 +59,8 @@ func templateVersionsList() *cobra.Command {
 				return xerrors.Errorf("get template by name: %w", err)
 			}
 			req := codersdk.TemplateVersionsByTemplateRequest{
-				TemplateID: template.ID,
+				TemplateID:      template.ID,
+				IncludeArchived: includeArchived,
 			}
 
 			versions, err := client.TemplateVersionsByTemplate(cmd.Context(), req)
@@ -68,6 +77,93 @@ func templateVersionsList() *cobra.Command {
 			return err
 		},
 	}
+	cmd.Flags().BoolVar(&includeArchived, "include-archived", false, "Include archived versions in the list.")
+	return cmd
+}
+
+func templateVersionsPromote() *cobra.Command {
+	return &cobra.Command{
+		Use:   "promote <template> <version>",
+		Args:  cobra.ExactArgs(2),
+		Short: "Make a version of the specified template active",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, template, version, err := templateVersionByArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+			err = client.UpdateActiveTemplateVersion(cmd.Context(), template.ID, codersdk.UpdateActiveTemplateVersion{
+				ID: version.ID,
+			})
+			if err != nil {
+				return xerrors.Errorf("update active template version: %w", err)
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Version %s of %s is now active\n",
+				cliui.Styles.Keyword.Render(version.Name), cliui.Styles.Keyword.Render(template.Name))
+			return nil
+		},
+	}
+}
+
+func templateVersionsArchive() *cobra.Command {
+	return &cobra.Command{
+		Use:   "archive <template> <version>",
+		Args:  cobra.ExactArgs(2),
+		Short: "Hide a version of the specified template and prevent new workspace builds from using it",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, _, version, err := templateVersionByArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+			err = client.ArchiveTemplateVersion(cmd.Context(), version.ID)
+			if err != nil {
+				return xerrors.Errorf("archive template version: %w", err)
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Version %s was archived\n", cliui.Styles.Keyword.Render(version.Name))
+			return nil
+		},
+	}
+}
+
+func templateVersionsUnarchive() *cobra.Command {
+	return &cobra.Command{
+		Use:   "unarchive <template> <version>",
+		Args:  cobra.ExactArgs(2),
+		Short: "Restore an archived version of the specified template",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, _, version, err := templateVersionByArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+			err = client.UnarchiveTemplateVersion(cmd.Context(), version.ID)
+			if err != nil {
+				return xerrors.Errorf("unarchive template version: %w", err)
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Version %s was unarchived\n", cliui.Styles.Keyword.Render(version.Name))
+			return nil
+		},
+	}
+}
+
+// templateVersionByArgs fetches the template and version named by the
+// "<template> <version>" arguments.
+func templateVersionByArgs(cmd *cobra.Command, args []string) (*codersdk.Client, codersdk.Template, codersdk.TemplateVersion, error) {
+	client, err := CreateClient(cmd)
+	if err != nil {
+		return nil, codersdk.Template{}, codersdk.TemplateVersion{}, xerrors.Errorf("create client: %w", err)
+	}
+	organization, err := CurrentOrganization(cmd, client)
+	if err != nil {
+		return nil, codersdk.Template{}, codersdk.TemplateVersion{}, xerrors.Errorf("get current organization: %w", err)
+	}
+	template, err := client.TemplateByName(cmd.Context(), organization.ID, args[0])
+	if err != nil {
+		return nil, codersdk.Template{}, codersdk.TemplateVersion{}, xerrors.Errorf("get template by name: %w", err)
+	}
+	version, err := client.TemplateVersionByName(cmd.Context(), template.ID, args[1])
+	if err != nil {
+		return nil, codersdk.Template{}, codersdk.TemplateVersion{}, xerrors.Errorf("get template version by name: %w", err)
+	}
+	return client, template, version, nil
 }
 
 type templateVersionRow struct {
@@ -76,6 +172,7 @@ type templateVersionRow struct {
 	CreatedBy string    `table:"created by"`
 	Status    string    `table:"status"`
 	Active    string    `table:"active"`
+	Message   string    `table:"message"`
 }
 
 // displayTemplateVersions will return a table displaying existing
@@ -86,6 +183,8 @@ func displayTemplateVersions(activeVersionID uuid.UUID, templateVersions ...code
 		var activeStatus = ""
 		if templateVersion.ID == activeVersionID {
 			activeStatus = cliui.Styles.Code.Render(cliui.Styles.Keyword.Render("Active"))
+		} else if templateVersion.Archived {
+			activeStatus = cliui.Styles.Placeholder.Render("Archived")
 		}
 
 		rows[i] = templateVersionRow{
@@ -94,6 +193,7 @@ func displayTemplateVersions(activeVersionID uuid.UUID, templateVersions ...code
 			CreatedBy: templateVersion.CreatedByName,
 			Status:    strings.Title(string(templateVersion.Job.Status)),
 			Active:    activeStatus,
+			Message:   templateVersion.Message,
 		}
 	}
 
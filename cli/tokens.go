@@ -0,0 +1,164 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/cli/cliflag"
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/codersdk"
+)
+
+func tokens() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tokens",
+		Short: "Manage long-lived tokens, e.g. for CI pipelines",
+		Example: formatExamples(
+			example{
+				Description: "Create a token for a CI pipeline",
+				Command:     "coder tokens create --name ci",
+			},
+			example{
+				Description: "Create a token that can only create, start, stop and delete workspaces",
+				Command:     "coder tokens create --name preview-bot --scope workspace_lifecycle",
+			},
+			example{
+				Description: "Revoke a token that's no longer used",
+				Command:     "coder tokens remove <id>",
+			},
+		),
+		Aliases: []string{"token"},
+	}
+	cmd.AddCommand(
+		createToken(),
+		listTokens(),
+		removeToken(),
+	)
+	return cmd
+}
+
+func createToken() *cobra.Command {
+	var (
+		name     string
+		lifetime time.Duration
+		scope    string
+	)
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a token",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := CreateClient(cmd)
+			if err != nil {
+				return err
+			}
+			if name == "" {
+				return xerrors.New("a name is required, set one with --name")
+			}
+
+			res, err := client.CreateToken(cmd.Context(), codersdk.Me, codersdk.CreateTokenRequest{
+				TokenName:       name,
+				LifetimeSeconds: int64(lifetime.Seconds()),
+				Scope:           codersdk.APIKeyScope(scope),
+			})
+			if err != nil {
+				return xerrors.Errorf("create token: %w", err)
+			}
+
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Token %s was created. It won't be shown again, so store it somewhere safe:\n\n",
+				cliui.Styles.Keyword.Render(name))
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), res.Key)
+			return nil
+		},
+	}
+	cliflag.StringVarP(cmd.Flags(), &name, "name", "n", "CODER_TOKEN_NAME", "",
+		"Specify a name for the token, which must be unique among your tokens.")
+	cliflag.DurationVarP(cmd.Flags(), &lifetime, "lifetime", "", "CODER_TOKEN_LIFETIME", 0,
+		"Specify how long the token is valid for. Defaults to 30 days.")
+	cliflag.StringVarP(cmd.Flags(), &scope, "scope", "", "CODER_TOKEN_SCOPE", string(codersdk.APIKeyScopeAll),
+		`Restrict what the token can be used for. Accepted values are "all", "read_only", "workspace_lifecycle", "template_push", "agent_connect", or "application_connect".`)
+	return cmd
+}
+
+type tokenRow struct {
+	ID        string    `table:"id"`
+	Name      string    `table:"name"`
+	Scope     string    `table:"scope"`
+	LastUsed  time.Time `table:"last used"`
+	ExpiresAt time.Time `table:"expires at"`
+	CreatedAt time.Time `table:"created at"`
+}
+
+func listTokens() *cobra.Command {
+	var (
+		user    string
+		columns []string
+	)
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List tokens",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := CreateClient(cmd)
+			if err != nil {
+				return err
+			}
+			keys, err := client.Tokens(cmd.Context(), user)
+			if err != nil {
+				return xerrors.Errorf("get tokens: %w", err)
+			}
+			if len(keys) == 0 {
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), "No tokens found.")
+				return nil
+			}
+
+			rows := make([]tokenRow, 0, len(keys))
+			for _, key := range keys {
+				rows = append(rows, tokenRow{
+					ID:        key.ID,
+					Name:      key.TokenName,
+					Scope:     string(key.Scope),
+					LastUsed:  key.LastUsed,
+					ExpiresAt: key.ExpiresAt,
+					CreatedAt: key.CreatedAt,
+				})
+			}
+			out, err := cliui.DisplayTable(rows, "name", columns)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(cmd.OutOrStdout(), out)
+			return err
+		},
+	}
+	cmd.Flags().StringVarP(&user, "user", "u", codersdk.Me,
+		"List the tokens of another user. Only admins can view the tokens of other users.")
+	cmd.Flags().StringArrayVarP(&columns, "column", "c", nil,
+		"Specify a column to filter in the table.")
+	return cmd
+}
+
+func removeToken() *cobra.Command {
+	return &cobra.Command{
+		Use:     "remove <id>",
+		Aliases: []string{"rm"},
+		Short:   "Revoke a token",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := CreateClient(cmd)
+			if err != nil {
+				return err
+			}
+			err = client.DeleteAPIKey(cmd.Context(), codersdk.Me, args[0])
+			if err != nil {
+				return xerrors.Errorf("remove token: %w", err)
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Token %s was revoked\n", cliui.Styles.Keyword.Render(args[0]))
+			return nil
+		},
+	}
+}
@@ -0,0 +1,58 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/cli/clitest"
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/testutil"
+)
+
+func TestTokens(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, nil)
+	_ = coderdtest.CreateFirstUser(t, client)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	cmd, root := clitest.New(t, "tokens", "create", "--name", "ci", "--lifetime", "24h")
+	clitest.SetupConfig(t, client, root)
+	out := bytes.NewBuffer(nil)
+	cmd.SetOut(out)
+	require.NoError(t, cmd.ExecuteContext(ctx))
+	key := strings.TrimSpace(out.String())
+
+	tokenClient := codersdk.New(client.URL)
+	tokenClient.SessionToken = key
+	_, err := tokenClient.User(ctx, codersdk.Me)
+	require.NoError(t, err)
+
+	keys, err := client.Tokens(ctx, codersdk.Me)
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	require.EqualValues(t, 24*60*60, keys[0].LifetimeSeconds)
+
+	cmd, root = clitest.New(t, "tokens", "list")
+	clitest.SetupConfig(t, client, root)
+	out = bytes.NewBuffer(nil)
+	cmd.SetOut(out)
+	require.NoError(t, cmd.ExecuteContext(ctx))
+	require.Contains(t, out.String(), keys[0].ID)
+	require.Contains(t, out.String(), "ci")
+
+	cmd, root = clitest.New(t, "tokens", "remove", keys[0].ID)
+	clitest.SetupConfig(t, client, root)
+	require.NoError(t, cmd.ExecuteContext(ctx))
+
+	keys, err = client.Tokens(ctx, codersdk.Me)
+	require.NoError(t, err)
+	require.Empty(t, keys)
+}
@@ -5,27 +5,66 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
 
 	"github.com/coder/coder/cli/cliflag"
+	"github.com/coder/coder/cli/cliui"
 	"github.com/coder/coder/codersdk"
 )
 
 func update() *cobra.Command {
 	var (
-		parameterFile string
-		alwaysPrompt  bool
+		parameterFile  string
+		alwaysPrompt   bool
+		all            bool
+		searchQuery    string
+		includeStopped bool
+		dryRun         bool
 	)
 
 	cmd := &cobra.Command{
 		Annotations: workspaceCommand,
-		Use:         "update <workspace>",
-		Args:        cobra.ExactArgs(1),
+		Use:         "update [workspace]",
+		Args:        cobra.MaximumNArgs(1),
 		Short:       "Update a workspace",
+		Example: formatExamples(
+			example{
+				Description: "Update all outdated workspaces of a template",
+				Command:     "coder update --search template:docker",
+			},
+			example{
+				Description: "List every outdated workspace without updating it",
+				Command:     "coder update --all --dry-run",
+			},
+		),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			client, err := CreateClient(cmd)
 			if err != nil {
 				return err
 			}
+			if all || cmd.Flags().Changed("search") {
+				if len(args) > 0 {
+					return xerrors.New("a workspace can't be specified together with --all or --search")
+				}
+				if all && cmd.Flags().Changed("search") {
+					return xerrors.New("--all can't be used together with --search")
+				}
+				// Outdated workspaces are started with the active version,
+				// reusing their existing parameter values.
+				return bulkWorkspaceBuild(cmd, client, codersdk.BulkWorkspaceBuildRequest{
+					Query:          searchQuery,
+					Transition:     codersdk.WorkspaceTransitionStart,
+					ActiveVersion:  true,
+					IncludeStopped: includeStopped,
+					DryRun:         dryRun,
+				})
+			}
+			if includeStopped {
+				return xerrors.New("--include-stopped can only be used together with --all or --search")
+			}
+			if len(args) == 0 {
+				return xerrors.New("specify a workspace to update, or update many with --all or --search")
+			}
 			workspace, err := namedWorkspace(cmd, client, args[0])
 			if err != nil {
 				return err
@@ -82,6 +121,11 @@ func update() *cobra.Command {
 	}
 
 	cmd.Flags().BoolVar(&alwaysPrompt, "always-prompt", false, "Always prompt all parameters. Does not pull parameter values from existing workspace")
+	cmd.Flags().BoolVarP(&all, "all", "a", false, "Update all outdated running workspaces you have access to.")
+	cmd.Flags().StringVar(&searchQuery, "search", "", "Update all outdated running workspaces matching a search query, e.g. \"template:docker\".")
+	cmd.Flags().BoolVar(&includeStopped, "include-stopped", false, "Also update outdated workspaces that aren't running with --all or --search. This starts them.")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "List the workspaces --all or --search would update without updating them.")
+	cliui.AllowSkipPrompt(cmd)
 	cliflag.StringVarP(cmd.Flags(), &parameterFile, "parameter-file", "", "CODER_PARAMETER_FILE", "", "Specify a file path with parameter values.")
 	return cmd
 }
@@ -0,0 +1,59 @@
+// Package agentshutdown notifies workspace agents that their workspace is
+// stopping, so they can run their shutdown scripts before the build that
+// stops it is provisioned.
+package agentshutdown
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/coderd/database"
+)
+
+// Channel is the pubsub channel an agent awaiting a shutdown request
+// listens on.
+func Channel(agentID uuid.UUID) string {
+	return fmt.Sprintf("workspace-agent-shutdown:%s", agentID)
+}
+
+// Request notifies the agents of the build preceding the one provided that
+// their workspace is stopping. The build's job isn't acquired by a
+// provisioner until they've run their shutdown scripts. Failures are only
+// logged, since agents also poll for builds that stop their workspace.
+func Request(ctx context.Context, db database.Store, pubsub database.Pubsub, logger slog.Logger, build database.WorkspaceBuild) {
+	logger = logger.With(slog.F("workspace_id", build.WorkspaceID), slog.F("build_number", build.BuildNumber))
+	if build.Transition == database.WorkspaceTransitionStart || build.BuildNumber <= 1 {
+		return
+	}
+	previousBuild, err := db.GetWorkspaceBuildByWorkspaceIDAndBuildNumber(ctx, database.GetWorkspaceBuildByWorkspaceIDAndBuildNumberParams{
+		WorkspaceID: build.WorkspaceID,
+		BuildNumber: build.BuildNumber - 1,
+	})
+	if err != nil {
+		logger.Warn(ctx, "get previous workspace build", slog.Error(err))
+		return
+	}
+	resources, err := db.GetWorkspaceResourcesByJobID(ctx, previousBuild.JobID)
+	if err != nil {
+		logger.Warn(ctx, "get previous workspace resources", slog.Error(err))
+		return
+	}
+	resourceIDs := make([]uuid.UUID, 0, len(resources))
+	for _, resource := range resources {
+		resourceIDs = append(resourceIDs, resource.ID)
+	}
+	agents, err := db.GetWorkspaceAgentsByResourceIDs(ctx, resourceIDs)
+	if err != nil {
+		logger.Warn(ctx, "get previous workspace agents", slog.Error(err))
+		return
+	}
+	for _, agent := range agents {
+		err = pubsub.Publish(Channel(agent.ID), []byte{})
+		if err != nil {
+			logger.Warn(ctx, "publish agent shutdown", slog.F("agent_id", agent.ID), slog.Error(err))
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package agentshutdown_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/coderd/agentshutdown"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/databasefake"
+	"github.com/coder/coder/testutil"
+)
+
+func TestRequest(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitShort)
+	defer cancel()
+
+	db := databasefake.New()
+	pubsub := database.NewPubsubInMemory()
+	logger := slogtest.Make(t, nil)
+
+	workspaceID := uuid.New()
+	insertBuild := func(buildNumber int32, transition database.WorkspaceTransition) database.WorkspaceBuild {
+		build, err := db.InsertWorkspaceBuild(ctx, database.InsertWorkspaceBuildParams{
+			ID:          uuid.New(),
+			WorkspaceID: workspaceID,
+			BuildNumber: buildNumber,
+			Transition:  transition,
+			JobID:       uuid.New(),
+			Reason:      database.BuildReasonInitiator,
+		})
+		require.NoError(t, err)
+		return build
+	}
+	started := insertBuild(1, database.WorkspaceTransitionStart)
+	resource, err := db.InsertWorkspaceResource(ctx, database.InsertWorkspaceResourceParams{
+		ID:         uuid.New(),
+		JobID:      started.JobID,
+		Transition: database.WorkspaceTransitionStart,
+	})
+	require.NoError(t, err)
+	agent, err := db.InsertWorkspaceAgent(ctx, database.InsertWorkspaceAgentParams{
+		ID:         uuid.New(),
+		ResourceID: resource.ID,
+	})
+	require.NoError(t, err)
+
+	requested := make(chan struct{}, 2)
+	cancelSubscribe, err := pubsub.Subscribe(agentshutdown.Channel(agent.ID), func(_ context.Context, _ []byte) {
+		requested <- struct{}{}
+	})
+	require.NoError(t, err)
+	defer cancelSubscribe()
+
+	// Starting the workspace doesn't shut down its agents.
+	agentshutdown.Request(ctx, db, pubsub, logger, started)
+	agentshutdown.Request(ctx, db, pubsub, logger, insertBuild(2, database.WorkspaceTransitionStop))
+
+	select {
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for shutdown request")
+	case <-requested:
+	}
+	require.Empty(t, requested)
+}
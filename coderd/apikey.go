@@ -0,0 +1,151 @@
+package coderd
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/codersdk"
+)
+
+// defaultTokenLifetime is the lifetime of tokens created without one.
+const defaultTokenLifetime = 30 * 24 * time.Hour
+
+// Creates a long-lived token for a user. Unlike session keys, tokens have a
+// name, expire at a fixed time and are only removed when they're revoked.
+func (api *API) postToken(rw http.ResponseWriter, r *http.Request) {
+	user := httpmw.UserParam(r)
+
+	if !api.Authorize(r, rbac.ActionCreate, rbac.ResourceAPIKey.WithOwner(user.ID.String())) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	var req codersdk.CreateTokenRequest
+	if !httpapi.Read(rw, r, &req) {
+		return
+	}
+
+	lifetime := time.Duration(req.LifetimeSeconds) * time.Second
+	if lifetime == 0 {
+		lifetime = defaultTokenLifetime
+		if lifetime > api.MaxTokenLifetime {
+			lifetime = api.MaxTokenLifetime
+		}
+	}
+	if lifetime < 0 || lifetime > api.MaxTokenLifetime {
+		httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Token lifetime must be positive and at most %s.", api.MaxTokenLifetime),
+			Validations: []codersdk.ValidationError{{
+				Field:  "lifetime_seconds",
+				Detail: fmt.Sprintf("Must be between 1 and %d.", int64(api.MaxTokenLifetime.Seconds())),
+			}},
+		})
+		return
+	}
+
+	cookie, err := api.createAPIKey(r, createAPIKeyParams{
+		UserID:          user.ID,
+		LoginType:       database.LoginTypeToken,
+		LifetimeSeconds: int64(lifetime.Seconds()),
+		TokenName:       req.TokenName,
+		Scope:           database.APIKeyScope(req.Scope),
+	})
+	if database.IsUniqueViolation(err) {
+		httpapi.Write(rw, http.StatusConflict, codersdk.Response{
+			Message: fmt.Sprintf("Token %q already exists.", req.TokenName),
+			Validations: []codersdk.ValidationError{{
+				Field:  "token_name",
+				Detail: "This value is already in use and should be unique.",
+			}},
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to create token.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(rw, http.StatusCreated, codersdk.GenerateAPIKeyResponse{Key: cookie.Value})
+}
+
+// Lists the long-lived tokens of a user. Admins can view the tokens of other
+// users.
+func (api *API) tokens(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		user = httpmw.UserParam(r)
+	)
+
+	if !api.Authorize(r, rbac.ActionRead, rbac.ResourceAPIKey.WithOwner(user.ID.String())) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	keys, err := api.Database.GetAPIKeysByUserID(ctx, database.GetAPIKeysByUserIDParams{
+		LoginType: database.LoginTypeToken,
+		UserID:    user.ID,
+	})
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching tokens.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	apiKeys := make([]codersdk.APIKey, 0, len(keys))
+	for _, key := range keys {
+		apiKeys = append(apiKeys, convertAPIKey(key))
+	}
+	httpapi.Write(rw, http.StatusOK, apiKeys)
+}
+
+// Revokes an API key of a user, e.g. a token that's no longer used.
+func (api *API) deleteAPIKey(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		user = httpmw.UserParam(r)
+	)
+
+	if !api.Authorize(r, rbac.ActionDelete, rbac.ResourceAPIKey.WithOwner(user.ID.String())) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	keyID := chi.URLParam(r, "keyid")
+	key, err := api.Database.GetAPIKeyByID(ctx, keyID)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && key.UserID != user.ID) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching API key.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	err = api.Database.DeleteAPIKeyByID(ctx, key.ID)
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting API key.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(rw, http.StatusNoContent, nil)
+}
@@ -0,0 +1,179 @@
+package coderd_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/testutil"
+)
+
+func TestTokens(t *testing.T) {
+	t.Parallel()
+	t.Run("CRUD", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		keys, err := client.Tokens(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Empty(t, keys)
+
+		res, err := client.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+			TokenName: "ci",
+		})
+		require.NoError(t, err)
+		require.Greater(t, len(res.Key), 2)
+
+		keys, err = client.Tokens(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Len(t, keys, 1)
+		require.Equal(t, "ci", keys[0].TokenName)
+		require.Equal(t, codersdk.LoginTypeToken, keys[0].LoginType)
+		require.EqualValues(t, (30 * 24 * time.Hour).Seconds(), keys[0].LifetimeSeconds)
+
+		// The token authenticates requests.
+		tokenClient := codersdk.New(client.URL)
+		tokenClient.SessionToken = res.Key
+		_, err = tokenClient.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+
+		err = client.DeleteAPIKey(ctx, codersdk.Me, keys[0].ID)
+		require.NoError(t, err)
+		keys, err = client.Tokens(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Empty(t, keys)
+
+		_, err = tokenClient.User(ctx, codersdk.Me)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+	})
+
+	t.Run("DuplicateName", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		_, err := client.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+			TokenName: "ci",
+		})
+		require.NoError(t, err)
+		_, err = client.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+			TokenName: "ci",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+	})
+
+	t.Run("LifetimeTooLong", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		_, err := client.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+			TokenName:       "ci",
+			LifetimeSeconds: int64((10 * 365 * 24 * time.Hour).Seconds()),
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("Scope", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		res, err := client.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+			TokenName: "ci",
+			Scope:     codersdk.APIKeyScopeReadOnly,
+		})
+		require.NoError(t, err)
+		keys, err := client.Tokens(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Len(t, keys, 1)
+		require.Equal(t, codersdk.APIKeyScopeReadOnly, keys[0].Scope)
+
+		tokenClient := codersdk.New(client.URL)
+		tokenClient.SessionToken = res.Key
+		_, err = tokenClient.Tokens(ctx, codersdk.Me)
+		require.NoError(t, err)
+
+		// The scope prevents the owner from creating tokens with the key.
+		_, err = tokenClient.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+			TokenName: "escalate",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("InvalidScope", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		_, err := client.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+			TokenName: "ci",
+			Scope:     "everything",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("OtherUsers", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		admin := coderdtest.CreateFirstUser(t, client)
+		memberClient, member := coderdtest.CreateAnotherUserWithUser(t, client, admin.OrganizationID)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		_, err := memberClient.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+			TokenName: "ci",
+		})
+		require.NoError(t, err)
+
+		// Admins can view the tokens of other users.
+		keys, err := client.Tokens(ctx, member.ID.String())
+		require.NoError(t, err)
+		require.Len(t, keys, 1)
+
+		// Members can't view the tokens of other users.
+		_, err = memberClient.Tokens(ctx, admin.UserID.String())
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+		// A key can't be revoked through another user.
+		err = client.DeleteAPIKey(ctx, codersdk.Me, keys[0].ID)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+		err = client.DeleteAPIKey(ctx, member.ID.String(), keys[0].ID)
+		require.NoError(t, err)
+	})
+}
@@ -1,18 +1,23 @@
 package coderd
 
 import (
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"net/netip"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/tabbed/pqtype"
+	"golang.org/x/xerrors"
 
+	"cdr.dev/slog"
 	"github.com/coder/coder/coderd/database"
 	"github.com/coder/coder/coderd/httpapi"
 	"github.com/coder/coder/coderd/httpmw"
@@ -42,15 +47,9 @@ func (api *API) auditLogs(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	dblogs, err := api.Database.GetAuditLogsOffset(ctx, database.GetAuditLogsOffsetParams{
-		Offset:       int32(page.Offset),
-		Limit:        int32(page.Limit),
-		ResourceType: filter.ResourceType,
-		ResourceID:   filter.ResourceID,
-		Action:       filter.Action,
-		Username:     filter.Username,
-		Email:        filter.Email,
-	})
+	filter.Offset = int32(page.Offset)
+	filter.Limit = int32(page.Limit)
+	dblogs, err := api.Database.GetAuditLogsOffset(ctx, filter)
 	if err != nil {
 		httpapi.InternalServerError(rw, err)
 		return
@@ -79,11 +78,14 @@ func (api *API) auditLogCount(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	count, err := api.Database.GetAuditLogCount(ctx, database.GetAuditLogCountParams{
-		ResourceType: filter.ResourceType,
-		ResourceID:   filter.ResourceID,
-		Action:       filter.Action,
-		Username:     filter.Username,
-		Email:        filter.Email,
+		ResourceType:   filter.ResourceType,
+		ResourceID:     filter.ResourceID,
+		ResourceTarget: filter.ResourceTarget,
+		Action:         filter.Action,
+		Username:       filter.Username,
+		Email:          filter.Email,
+		TimeAfter:      filter.TimeAfter,
+		TimeBefore:     filter.TimeBefore,
 	})
 	if err != nil {
 		httpapi.InternalServerError(rw, err)
@@ -221,6 +223,11 @@ func convertAuditLog(dblog database.GetAuditLogsOffsetRow) codersdk.AuditLog {
 }
 
 func auditLogDescription(alog database.GetAuditLogsOffsetRow) string {
+	// Logins and logouts are performed by the user on themselves, so the
+	// target would be redundant.
+	if alog.Action == database.AuditActionLogin || alog.Action == database.AuditActionLogout {
+		return fmt.Sprintf("{user} %s", codersdk.AuditAction(alog.Action).FriendlyString())
+	}
 	return fmt.Sprintf("{user} %s %s {target}",
 		codersdk.AuditAction(alog.Action).FriendlyString(),
 		codersdk.ResourceType(alog.ResourceType).FriendlyString(),
@@ -259,12 +266,210 @@ func auditSearchQuery(query string) (database.GetAuditLogsOffsetParams, []coders
 	// other parsing.
 	parser := httpapi.NewQueryParamParser()
 	filter := database.GetAuditLogsOffsetParams{
-		ResourceType: parser.String(searchParams, "", "resource_type"),
-		ResourceID:   parser.UUID(searchParams, uuid.Nil, "resource_id"),
-		Action:       parser.String(searchParams, "", "action"),
-		Username:     parser.String(searchParams, "", "username"),
-		Email:        parser.String(searchParams, "", "email"),
+		ResourceType:   parser.String(searchParams, "", "resource_type"),
+		ResourceID:     parser.UUID(searchParams, uuid.Nil, "resource_id"),
+		ResourceTarget: parser.String(searchParams, "", "resource_target"),
+		Action:         parser.String(searchParams, "", "action"),
+		Username:       parser.String(searchParams, "", "username"),
+		Email:          parser.String(searchParams, "", "email"),
+		TimeAfter:      httpapi.ParseCustom(parser, searchParams, time.Time{}, "time_after", parseAuditTime),
+		TimeBefore:     httpapi.ParseCustom(parser, searchParams, time.Time{}, "time_before", parseAuditTime),
 	}
 
 	return filter, parser.Errors
 }
+
+// parseAuditTime parses a date like 2022-09-01, or a quoted RFC 3339
+// timestamp. The search query is lowercased, so the timestamp is
+// uppercased again before parsing.
+func parseAuditTime(v string) (time.Time, error) {
+	t, err := time.Parse("2006-01-02", v)
+	if err == nil {
+		return t, nil
+	}
+	t, err = time.Parse(time.RFC3339, strings.ToUpper(v))
+	if err != nil {
+		return time.Time{}, xerrors.Errorf("must be a date like 2006-01-02 or an RFC 3339 timestamp")
+	}
+	return t, nil
+}
+
+// auditLogExportPageSize is the number of audit logs fetched from the
+// database at a time while exporting.
+const auditLogExportPageSize = 1000
+
+// auditLogExport streams every audit log matching the search query.
+func (api *API) auditLogExport(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.Authorize(r, rbac.ActionRead, rbac.ResourceAuditLog) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	format := codersdk.AuditLogExportFormat(r.URL.Query().Get("format"))
+	if format == "" {
+		format = codersdk.AuditLogExportFormatJSON
+	}
+	if format != codersdk.AuditLogExportFormatJSON && format != codersdk.AuditLogExportFormatCSV {
+		httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Invalid export format %q.", format),
+			Detail:  "Supported formats are json and csv.",
+		})
+		return
+	}
+
+	filter, errs := auditSearchQuery(r.URL.Query().Get("q"))
+	if len(errs) > 0 {
+		httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid audit search query.",
+			Validations: errs,
+		})
+		return
+	}
+	// Logs inserted while exporting would shift the offset of every
+	// following page, so the export is bounded to logs that exist now.
+	// The query orders by time and ID, so pages don't overlap when logs
+	// share a time.
+	now := database.Now()
+	if filter.TimeBefore.IsZero() || filter.TimeBefore.After(now) {
+		filter.TimeBefore = now
+	}
+	filter.Limit = auditLogExportPageSize
+
+	var writer auditLogExportWriter
+	for {
+		dblogs, err := api.Database.GetAuditLogsOffset(ctx, filter)
+		if err != nil {
+			if writer == nil {
+				httpapi.InternalServerError(rw, err)
+				return
+			}
+			// The status has already been written, so the best we can
+			// do is end the response early.
+			api.Logger.Error(ctx, "export audit logs", slog.Error(err))
+			return
+		}
+		if writer == nil {
+			rw.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=audit-logs.%s", format))
+			writer = newAuditLogExportWriter(rw, format)
+		}
+		for _, dblog := range dblogs {
+			err = writer.Write(convertAuditLog(dblog))
+			if err != nil {
+				api.Logger.Debug(ctx, "write exported audit log", slog.Error(err))
+				return
+			}
+		}
+		if flusher, ok := rw.(http.Flusher); ok {
+			flusher.Flush()
+		}
+		if len(dblogs) < auditLogExportPageSize {
+			break
+		}
+		filter.Offset += int32(len(dblogs))
+	}
+	err := writer.Close()
+	if err != nil {
+		api.Logger.Debug(ctx, "finish audit log export", slog.Error(err))
+	}
+}
+
+type auditLogExportWriter interface {
+	Write(alog codersdk.AuditLog) error
+	Close() error
+}
+
+func newAuditLogExportWriter(rw http.ResponseWriter, format codersdk.AuditLogExportFormat) auditLogExportWriter {
+	if format == codersdk.AuditLogExportFormatCSV {
+		rw.Header().Set("Content-Type", "text/csv")
+		rw.WriteHeader(http.StatusOK)
+		writer := csv.NewWriter(rw)
+		// The header is flushed along with the first row.
+		_ = writer.Write(auditLogCSVHeader)
+		return &auditLogCSVWriter{writer: writer}
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
+	return &auditLogJSONWriter{writer: rw, encoder: json.NewEncoder(rw)}
+}
+
+// auditLogJSONWriter writes audit logs as a JSON array.
+type auditLogJSONWriter struct {
+	writer  io.Writer
+	encoder *json.Encoder
+	count   int
+}
+
+func (w *auditLogJSONWriter) Write(alog codersdk.AuditLog) error {
+	prefix := ","
+	if w.count == 0 {
+		prefix = "["
+	}
+	w.count++
+	_, err := io.WriteString(w.writer, prefix)
+	if err != nil {
+		return err
+	}
+	return w.encoder.Encode(alog)
+}
+
+func (w *auditLogJSONWriter) Close() error {
+	suffix := "]\n"
+	if w.count == 0 {
+		suffix = "[]\n"
+	}
+	_, err := io.WriteString(w.writer, suffix)
+	return err
+}
+
+var auditLogCSVHeader = []string{
+	"time", "id", "request_id", "organization_id", "user_id", "username", "email", "ip", "user_agent",
+	"action", "resource_type", "resource_id", "resource_target", "status_code", "description", "diff", "additional_fields",
+}
+
+// auditLogCSVWriter writes audit logs as CSV rows.
+type auditLogCSVWriter struct {
+	writer *csv.Writer
+}
+
+func (w *auditLogCSVWriter) Write(alog codersdk.AuditLog) error {
+	var userID, username, email string
+	if alog.User != nil {
+		userID = alog.User.ID.String()
+		username = alog.User.Username
+		email = alog.User.Email
+	}
+	diff, err := json.Marshal(alog.Diff)
+	if err != nil {
+		return err
+	}
+	err = w.writer.Write([]string{
+		alog.Time.Format(time.RFC3339Nano),
+		alog.ID.String(),
+		alog.RequestID.String(),
+		alog.OrganizationID.String(),
+		userID,
+		username,
+		email,
+		alog.IP.String(),
+		alog.UserAgent,
+		string(alog.Action),
+		string(alog.ResourceType),
+		alog.ResourceID.String(),
+		alog.ResourceTarget,
+		strconv.Itoa(int(alog.StatusCode)),
+		alog.Description,
+		string(diff),
+		string(alog.AdditionalFields),
+	})
+	if err != nil {
+		return err
+	}
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+func (w *auditLogCSVWriter) Close() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
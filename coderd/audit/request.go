@@ -29,6 +29,35 @@ type Request[T Auditable] struct {
 
 	Old T
 	New T
+
+	// UserID is the user the audit log is attributed to. It defaults to the
+	// owner of the request's API key, and must be set on unauthenticated
+	// requests such as logins.
+	UserID uuid.UUID
+	// AdditionalFields is extra JSON encoded context for the audit log,
+	// e.g. the reason for a workspace build.
+	AdditionalFields json.RawMessage
+}
+
+// BackgroundAuditParams describes an audit log that isn't tied to the
+// lifetime of an HTTP handler, e.g. one created by a background job or at
+// the start of a long-lived connection.
+type BackgroundAuditParams[T Auditable] struct {
+	Audit Auditor
+	Log   slog.Logger
+
+	UserID    uuid.UUID
+	RequestID uuid.UUID
+	// IP is the address of the client, if there is one.
+	IP        string
+	UserAgent string
+	Status    int
+	Action    database.AuditAction
+
+	AdditionalFields json.RawMessage
+
+	Old T
+	New T
 }
 
 func ResourceTarget[T Auditable](tgt T) string {
@@ -106,7 +135,7 @@ func InitRequest[T Auditable](w http.ResponseWriter, p *RequestParams) (*Request
 		logCtx := p.Request.Context()
 
 		// If no resources were provided, there's nothing we can audit.
-		if ResourceID(req.Old) == uuid.Nil && ResourceID(req.New) == uuid.Nil {
+		if isEmpty(req.Old) && isEmpty(req.New) {
 			return
 		}
 
@@ -118,10 +147,15 @@ func InitRequest[T Auditable](w http.ResponseWriter, p *RequestParams) (*Request
 			p.Log.Warn(logCtx, "parse ip", slog.Error(err))
 		}
 
+		userID := req.UserID
+		if apiKey, ok := httpmw.APIKeyOptional(p.Request); ok && userID == uuid.Nil {
+			userID = apiKey.UserID
+		}
+
 		err = p.Audit.Export(ctx, database.AuditLog{
 			ID:               uuid.New(),
 			Time:             database.Now(),
-			UserID:           httpmw.APIKey(p.Request).UserID,
+			UserID:           userID,
 			Ip:               ip,
 			UserAgent:        p.Request.UserAgent(),
 			ResourceType:     either(req.Old, req.New, ResourceType[T]),
@@ -131,7 +165,7 @@ func InitRequest[T Auditable](w http.ResponseWriter, p *RequestParams) (*Request
 			Diff:             diffRaw,
 			StatusCode:       int32(sw.Status),
 			RequestID:        httpmw.RequestID(p.Request),
-			AdditionalFields: json.RawMessage("{}"),
+			AdditionalFields: additionalFields(req.AdditionalFields),
 		})
 		if err != nil {
 			p.Log.Error(logCtx, "export audit log", slog.Error(err))
@@ -140,16 +174,70 @@ func InitRequest[T Auditable](w http.ResponseWriter, p *RequestParams) (*Request
 	}
 }
 
+// BackgroundAudit creates an audit log immediately, without an HTTP
+// request to derive it from.
+func BackgroundAudit[T Auditable](ctx context.Context, p *BackgroundAuditParams[T]) {
+	if isEmpty(p.Old) && isEmpty(p.New) {
+		return
+	}
+
+	diff := Diff(p.Audit, p.Old, p.New)
+	diffRaw, _ := json.Marshal(diff)
+
+	var ip pqtype.Inet
+	if parsed := net.ParseIP(p.IP); parsed != nil {
+		ip = pqtype.Inet{
+			IPNet: net.IPNet{
+				IP:   parsed,
+				Mask: net.CIDRMask(len(parsed)*8, len(parsed)*8),
+			},
+			Valid: true,
+		}
+	}
+
+	err := p.Audit.Export(ctx, database.AuditLog{
+		ID:               uuid.New(),
+		Time:             database.Now(),
+		UserID:           p.UserID,
+		Ip:               ip,
+		UserAgent:        p.UserAgent,
+		ResourceType:     either(p.Old, p.New, ResourceType[T]),
+		ResourceID:       either(p.Old, p.New, ResourceID[T]),
+		ResourceTarget:   either(p.Old, p.New, ResourceTarget[T]),
+		Action:           p.Action,
+		Diff:             diffRaw,
+		StatusCode:       int32(p.Status),
+		RequestID:        p.RequestID,
+		AdditionalFields: additionalFields(p.AdditionalFields),
+	})
+	if err != nil {
+		p.Log.Error(ctx, "export audit log", slog.Error(err))
+	}
+}
+
+// isEmpty returns true if the resource wasn't provided. Resources may lack
+// an ID but still have a target, e.g. a failed login for an unknown user.
+func isEmpty[T Auditable](tgt T) bool {
+	return ResourceID(tgt) == uuid.Nil && ResourceTarget(tgt) == ""
+}
+
 func either[T Auditable, R any](old, new T, fn func(T) R) R {
-	if ResourceID(new) != uuid.Nil {
+	if !isEmpty(new) {
 		return fn(new)
-	} else if ResourceID(old) != uuid.Nil {
+	} else if !isEmpty(old) {
 		return fn(old)
 	} else {
 		panic("both old and new are nil")
 	}
 }
 
+func additionalFields(raw json.RawMessage) json.RawMessage {
+	if len(raw) == 0 {
+		return json.RawMessage("{}")
+	}
+	return raw
+}
+
 func parseIP(ipStr string) (pqtype.Inet, error) {
 	var err error
 
@@ -173,3 +261,26 @@ func parseIP(ipStr string) (pqtype.Inet, error) {
 		Valid: ip != nil,
 	}, nil
 }
+
+// WorkspaceBuildAction returns the audit action for a workspace transition.
+func WorkspaceBuildAction(transition database.WorkspaceTransition) database.AuditAction {
+	switch transition {
+	case database.WorkspaceTransitionStart:
+		return database.AuditActionStart
+	case database.WorkspaceTransitionStop:
+		return database.AuditActionStop
+	default:
+		return database.AuditActionDelete
+	}
+}
+
+// WorkspaceBuildFields returns the additional fields recorded when auditing
+// a workspace build.
+func WorkspaceBuildFields(build database.WorkspaceBuild) json.RawMessage {
+	raw, _ := json.Marshal(map[string]any{
+		"build_number": build.BuildNumber,
+		"build_reason": build.Reason,
+		"transition":   build.Transition,
+	})
+	return raw
+}
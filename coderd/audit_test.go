@@ -2,13 +2,18 @@ package coderd_test
 
 import (
 	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 
 	"github.com/coder/coder/coderd/coderdtest"
 	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/testutil"
 )
 
 func TestAuditLogs(t *testing.T) {
@@ -112,6 +117,21 @@ func TestAuditLogsFilter(t *testing.T) {
 				SearchQuery:    "resource_id:" + userResourceID.String(),
 				ExpectedResult: 2,
 			},
+			{
+				Name:           "FilterByTimeAfter",
+				SearchQuery:    "time_after:" + time.Now().Add(-48*time.Hour).Format("2006-01-02"),
+				ExpectedResult: 3,
+			},
+			{
+				Name:           "FilterByTimeBefore",
+				SearchQuery:    "time_before:" + time.Now().Add(-48*time.Hour).Format("2006-01-02"),
+				ExpectedResult: 0,
+			},
+			{
+				Name:           "FilterByTimestamp",
+				SearchQuery:    "action:create time_after:\"" + time.Now().Add(-time.Hour).Format(time.RFC3339) + "\"",
+				ExpectedResult: 2,
+			},
 		}
 
 		for _, testCase := range testCases {
@@ -140,3 +160,79 @@ func TestAuditLogsFilter(t *testing.T) {
 		}
 	})
 }
+
+func TestAuditLogsExport(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+	client := coderdtest.New(t, nil)
+	_ = coderdtest.CreateFirstUser(t, client)
+
+	for _, action := range []codersdk.AuditAction{codersdk.AuditActionCreate, codersdk.AuditActionDelete, codersdk.AuditActionDelete} {
+		err := client.CreateTestAuditLog(ctx, codersdk.CreateTestAuditLogRequest{
+			Action: action,
+		})
+		require.NoError(t, err)
+	}
+
+	t.Run("JSON", func(t *testing.T) {
+		logs, err := client.ExportAuditLogs(ctx, codersdk.AuditLogExportRequest{
+			SearchQuery: "action:delete",
+		})
+		require.NoError(t, err)
+		defer logs.Close()
+		var exported []codersdk.AuditLog
+		err = json.NewDecoder(logs).Decode(&exported)
+		require.NoError(t, err)
+		require.Len(t, exported, 2)
+		for _, alog := range exported {
+			require.Equal(t, codersdk.AuditActionDelete, alog.Action)
+			require.NotNil(t, alog.User)
+		}
+	})
+
+	t.Run("CSV", func(t *testing.T) {
+		logs, err := client.ExportAuditLogs(ctx, codersdk.AuditLogExportRequest{
+			Format: codersdk.AuditLogExportFormatCSV,
+		})
+		require.NoError(t, err)
+		defer logs.Close()
+		records, err := csv.NewReader(logs).ReadAll()
+		require.NoError(t, err)
+		// A header and a row for each log.
+		require.Len(t, records, 4)
+		require.Equal(t, "time", records[0][0])
+		require.Equal(t, coderdtest.FirstUserParams.Username, records[1][5])
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		logs, err := client.ExportAuditLogs(ctx, codersdk.AuditLogExportRequest{
+			SearchQuery: "resource_type:template",
+		})
+		require.NoError(t, err)
+		defer logs.Close()
+		var exported []codersdk.AuditLog
+		err = json.NewDecoder(logs).Decode(&exported)
+		require.NoError(t, err)
+		require.Empty(t, exported)
+	})
+
+	t.Run("InvalidFormat", func(t *testing.T) {
+		_, err := client.ExportAuditLogs(ctx, codersdk.AuditLogExportRequest{
+			Format: "xml",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("InvalidTime", func(t *testing.T) {
+		_, err := client.ExportAuditLogs(ctx, codersdk.AuditLogExportRequest{
+			SearchQuery: "time_after:yesterday",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+}
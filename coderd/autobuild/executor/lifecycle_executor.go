@@ -3,6 +3,8 @@ package executor
 import (
 	"context"
 	"encoding/json"
+	"net/http"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
@@ -10,6 +12,8 @@ import (
 	"golang.org/x/xerrors"
 
 	"cdr.dev/slog"
+	"github.com/coder/coder/coderd/agentshutdown"
+	"github.com/coder/coder/coderd/audit"
 	"github.com/coder/coder/coderd/autobuild/schedule"
 	"github.com/coder/coder/coderd/database"
 )
@@ -21,6 +25,8 @@ type Executor struct {
 	log     slog.Logger
 	tick    <-chan time.Time
 	statsCh chan<- Stats
+	auditor *atomic.Pointer[audit.Auditor]
+	pubsub  database.Pubsub
 }
 
 // Stats contains information about one run of Executor.
@@ -48,6 +54,20 @@ func (e *Executor) WithStatsChannel(ch chan<- Stats) *Executor {
 	return e
 }
 
+// WithAuditor will cause Executor to create an audit log for every
+// workspace it starts or stops.
+func (e *Executor) WithAuditor(auditor *atomic.Pointer[audit.Auditor]) *Executor {
+	e.auditor = auditor
+	return e
+}
+
+// WithPubsub will cause Executor to notify the agents of workspaces it
+// stops, so they run their shutdown scripts without waiting to poll.
+func (e *Executor) WithPubsub(pubsub database.Pubsub) *Executor {
+	e.pubsub = pubsub
+	return e
+}
+
 // Run will cause executor to start or stop workspaces on every
 // tick from its channel. It will stop when its context is Done, or when
 // its channel is closed.
@@ -125,6 +145,10 @@ func (e *Executor) runOnce(t time.Time) Stats {
 		log := e.log.With(slog.F("workspace_id", wsID))
 
 		eg.Go(func() error {
+			var (
+				workspace database.Workspace
+				newBuild  database.WorkspaceBuild
+			)
 			err := e.db.InTx(func(db database.Store) error {
 				// Re-check eligibility since the first check was outside the
 				// transaction and the workspace settings may have changed.
@@ -168,7 +192,9 @@ func (e *Executor) runOnce(t time.Time) Stats {
 				log.Info(e.ctx, "scheduling workspace transition", slog.F("transition", validTransition))
 
 				stats.Transitions[ws.ID] = validTransition
-				if err := build(e.ctx, db, ws, validTransition, priorHistory, priorJob); err != nil {
+				workspace = ws
+				newBuild, err = build(e.ctx, db, ws, validTransition, priorHistory, priorJob)
+				if err != nil {
 					log.Error(e.ctx, "unable to transition workspace",
 						slog.F("transition", validTransition),
 						slog.Error(err),
@@ -180,6 +206,13 @@ func (e *Executor) runOnce(t time.Time) Stats {
 			})
 			if err != nil {
 				log.Error(e.ctx, "workspace scheduling failed", slog.Error(err))
+				return nil
+			}
+			if newBuild.ID != uuid.Nil {
+				e.audit(workspace, newBuild)
+				if e.pubsub != nil {
+					agentshutdown.Request(e.ctx, e.db, e.pubsub, log, newBuild)
+				}
 			}
 			return nil
 		})
@@ -194,6 +227,28 @@ func (e *Executor) runOnce(t time.Time) Stats {
 	return stats
 }
 
+// audit creates an audit log for a build started by the executor. It's
+// attributed to the workspace owner, since they configured the schedule.
+func (e *Executor) audit(workspace database.Workspace, build database.WorkspaceBuild) {
+	if e.auditor == nil {
+		return
+	}
+	auditor := e.auditor.Load()
+	if auditor == nil {
+		return
+	}
+	audit.BackgroundAudit(e.ctx, &audit.BackgroundAuditParams[database.Workspace]{
+		Audit:            *auditor,
+		Log:              e.log,
+		UserID:           workspace.OwnerID,
+		Status:           http.StatusOK,
+		Action:           audit.WorkspaceBuildAction(build.Transition),
+		AdditionalFields: audit.WorkspaceBuildFields(build),
+		Old:              workspace,
+		New:              workspace,
+	})
+}
+
 func isEligibleForAutoStartStop(ws database.Workspace) bool {
 	return !ws.Deleted && (ws.AutostartSchedule.String != "" || ws.Ttl.Int64 > 0)
 }
@@ -235,14 +290,43 @@ func getNextTransition(
 
 // TODO(cian): this function duplicates most of api.postWorkspaceBuilds. Refactor.
 // See: https://github.com/coder/coder/issues/1401
-func build(ctx context.Context, store database.Store, workspace database.Workspace, trans database.WorkspaceTransition, priorHistory database.WorkspaceBuild, priorJob database.ProvisionerJob) error {
+func build(ctx context.Context, store database.Store, workspace database.Workspace, trans database.WorkspaceTransition, priorHistory database.WorkspaceBuild, priorJob database.ProvisionerJob) (database.WorkspaceBuild, error) {
 	template, err := store.GetTemplateByID(ctx, workspace.TemplateID)
 	if err != nil {
-		return xerrors.Errorf("get workspace template: %w", err)
+		return database.WorkspaceBuild{}, xerrors.Errorf("get workspace template: %w", err)
 	}
 
 	priorBuildNumber := priorHistory.BuildNumber
 
+	// Autostarts move to the active version if the template or the workspace
+	// asks for it, or if the version of the workspace has been archived, since
+	// workspaces can't be started with archived versions. The build is
+	// provisioned from the import job of the version it uses.
+	templateVersionID := priorHistory.TemplateVersionID
+	templateVersionJob := priorJob
+	useActiveVersion := false
+	if trans == database.WorkspaceTransitionStart && templateVersionID != template.ActiveVersionID {
+		useActiveVersion = template.RequireActiveVersion || workspace.AutomaticUpdates == database.AutomaticUpdatesAlways
+		if !useActiveVersion {
+			templateVersion, err := store.GetTemplateVersionByID(ctx, templateVersionID)
+			if err != nil {
+				return database.WorkspaceBuild{}, xerrors.Errorf("get template version: %w", err)
+			}
+			useActiveVersion = templateVersion.Archived
+		}
+	}
+	if useActiveVersion {
+		activeVersion, err := store.GetTemplateVersionByID(ctx, template.ActiveVersionID)
+		if err != nil {
+			return database.WorkspaceBuild{}, xerrors.Errorf("get active template version: %w", err)
+		}
+		templateVersionJob, err = store.GetProvisionerJobByID(ctx, activeVersion.JobID)
+		if err != nil {
+			return database.WorkspaceBuild{}, xerrors.Errorf("get active template version job: %w", err)
+		}
+		templateVersionID = activeVersion.ID
+	}
+
 	// This must happen in a transaction to ensure history can be inserted, and
 	// the prior history can update it's "after" column to point at the new.
 	workspaceBuildID := uuid.New()
@@ -252,7 +336,7 @@ func build(ctx context.Context, store database.Store, workspace database.Workspa
 		WorkspaceBuildID: workspaceBuildID.String(),
 	})
 	if err != nil {
-		return xerrors.Errorf("marshal provision job: %w", err)
+		return database.WorkspaceBuild{}, xerrors.Errorf("marshal provision job: %w", err)
 	}
 	provisionerJobID := uuid.New()
 	now := database.Now()
@@ -264,7 +348,7 @@ func build(ctx context.Context, store database.Store, workspace database.Workspa
 	case database.WorkspaceTransitionStop:
 		buildReason = database.BuildReasonAutostop
 	default:
-		return xerrors.Errorf("Unsupported transition: %q", trans)
+		return database.WorkspaceBuild{}, xerrors.Errorf("Unsupported transition: %q", trans)
 	}
 
 	newProvisionerJob, err := store.InsertProvisionerJob(ctx, database.InsertProvisionerJobParams{
@@ -275,19 +359,20 @@ func build(ctx context.Context, store database.Store, workspace database.Workspa
 		OrganizationID: template.OrganizationID,
 		Provisioner:    template.Provisioner,
 		Type:           database.ProvisionerJobTypeWorkspaceBuild,
-		StorageMethod:  priorJob.StorageMethod,
-		StorageSource:  priorJob.StorageSource,
+		StorageMethod:  templateVersionJob.StorageMethod,
+		StorageSource:  templateVersionJob.StorageSource,
 		Input:          input,
+		Tags:           templateVersionJob.Tags,
 	})
 	if err != nil {
-		return xerrors.Errorf("insert provisioner job: %w", err)
+		return database.WorkspaceBuild{}, xerrors.Errorf("insert provisioner job: %w", err)
 	}
-	_, err = store.InsertWorkspaceBuild(ctx, database.InsertWorkspaceBuildParams{
+	workspaceBuild, err := store.InsertWorkspaceBuild(ctx, database.InsertWorkspaceBuildParams{
 		ID:                workspaceBuildID,
 		CreatedAt:         now,
 		UpdatedAt:         now,
 		WorkspaceID:       workspace.ID,
-		TemplateVersionID: priorHistory.TemplateVersionID,
+		TemplateVersionID: templateVersionID,
 		BuildNumber:       priorBuildNumber + 1,
 		ProvisionerState:  priorHistory.ProvisionerState,
 		InitiatorID:       workspace.OwnerID,
@@ -296,7 +381,7 @@ func build(ctx context.Context, store database.Store, workspace database.Workspa
 		Reason:            buildReason,
 	})
 	if err != nil {
-		return xerrors.Errorf("insert workspace build: %w", err)
+		return database.WorkspaceBuild{}, xerrors.Errorf("insert workspace build: %w", err)
 	}
-	return nil
+	return workspaceBuild, nil
 }
@@ -8,6 +8,7 @@ import (
 
 	"go.uber.org/goleak"
 
+	"github.com/coder/coder/coderd/audit"
 	"github.com/coder/coder/coderd/autobuild/executor"
 	"github.com/coder/coder/coderd/autobuild/schedule"
 	"github.com/coder/coder/coderd/coderdtest"
@@ -26,10 +27,12 @@ func TestExecutorAutostartOK(t *testing.T) {
 		sched   = mustSchedule(t, "CRON_TZ=UTC 0 * * * *")
 		tickCh  = make(chan time.Time)
 		statsCh = make(chan executor.Stats)
+		auditor = audit.NewMock()
 		client  = coderdtest.New(t, &coderdtest.Options{
 			AutobuildTicker:          tickCh,
 			IncludeProvisionerDaemon: true,
 			AutobuildStats:           statsCh,
+			Auditor:                  auditor,
 		})
 		// Given: we have a user with a workspace that has autostart enabled
 		workspace = mustProvisionWorkspace(t, client, func(cwr *codersdk.CreateWorkspaceRequest) {
@@ -54,6 +57,14 @@ func TestExecutorAutostartOK(t *testing.T) {
 
 	workspace = coderdtest.MustWorkspace(t, client, workspace.ID)
 	assert.Equal(t, codersdk.BuildReasonAutostart, workspace.LatestBuild.Reason)
+
+	// And: the transition should be audited on behalf of the owner
+	require.NotEmpty(t, auditor.AuditLogs)
+	alog := auditor.AuditLogs[len(auditor.AuditLogs)-1]
+	assert.Equal(t, database.AuditActionStart, alog.Action)
+	assert.Equal(t, workspace.ID, alog.ResourceID)
+	assert.Equal(t, workspace.OwnerID, alog.UserID)
+	assert.Contains(t, string(alog.AdditionalFields), string(database.BuildReasonAutostart))
 }
 
 func TestExecutorAutostartTemplateUpdated(t *testing.T) {
@@ -105,6 +116,107 @@ func TestExecutorAutostartTemplateUpdated(t *testing.T) {
 	assert.Equal(t, workspace.LatestBuild.TemplateVersionID, ws.LatestBuild.TemplateVersionID, "expected workspace build to be using the old template version")
 }
 
+func TestExecutorAutostartAutomaticUpdates(t *testing.T) {
+	t.Parallel()
+
+	var (
+		sched   = mustSchedule(t, "CRON_TZ=UTC 0 * * * *")
+		ctx     = context.Background()
+		err     error
+		tickCh  = make(chan time.Time)
+		statsCh = make(chan executor.Stats)
+		client  = coderdtest.New(t, &coderdtest.Options{
+			AutobuildTicker:          tickCh,
+			IncludeProvisionerDaemon: true,
+			AutobuildStats:           statsCh,
+		})
+		// Given: we have a user with a workspace that has autostart and
+		// automatic updates enabled
+		workspace = mustProvisionWorkspace(t, client, func(cwr *codersdk.CreateWorkspaceRequest) {
+			cwr.AutostartSchedule = ptr.Ref(sched.String())
+			cwr.AutomaticUpdates = codersdk.AutomaticUpdatesAlways
+		})
+	)
+	// Given: workspace is stopped
+	workspace = coderdtest.MustTransitionWorkspace(t, client, workspace.ID, database.WorkspaceTransitionStart, database.WorkspaceTransitionStop)
+
+	// Given: the workspace template has been updated
+	orgs, err := client.OrganizationsByUser(ctx, workspace.OwnerID.String())
+	require.NoError(t, err)
+	require.Len(t, orgs, 1)
+
+	newVersion := coderdtest.UpdateTemplateVersion(t, client, orgs[0].ID, nil, workspace.TemplateID)
+	coderdtest.AwaitTemplateVersionJob(t, client, newVersion.ID)
+	require.NoError(t, client.UpdateActiveTemplateVersion(ctx, workspace.TemplateID, codersdk.UpdateActiveTemplateVersion{
+		ID: newVersion.ID,
+	}))
+
+	// When: the autobuild executor ticks after the scheduled time
+	go func() {
+		tickCh <- sched.Next(workspace.LatestBuild.CreatedAt)
+		close(tickCh)
+	}()
+
+	// Then: the workspace should be started using the active template version.
+	stats := <-statsCh
+	assert.NoError(t, stats.Error)
+	assert.Len(t, stats.Transitions, 1)
+	assert.Equal(t, database.WorkspaceTransitionStart, stats.Transitions[workspace.ID])
+	ws := coderdtest.MustWorkspace(t, client, workspace.ID)
+	assert.Equal(t, newVersion.ID, ws.LatestBuild.TemplateVersionID, "expected workspace build to be using the active template version")
+}
+
+func TestExecutorAutostartArchivedVersion(t *testing.T) {
+	t.Parallel()
+
+	var (
+		sched   = mustSchedule(t, "CRON_TZ=UTC 0 * * * *")
+		ctx     = context.Background()
+		err     error
+		tickCh  = make(chan time.Time)
+		statsCh = make(chan executor.Stats)
+		client  = coderdtest.New(t, &coderdtest.Options{
+			AutobuildTicker:          tickCh,
+			IncludeProvisionerDaemon: true,
+			AutobuildStats:           statsCh,
+		})
+		// Given: we have a user with a workspace that has autostart enabled
+		workspace = mustProvisionWorkspace(t, client, func(cwr *codersdk.CreateWorkspaceRequest) {
+			cwr.AutostartSchedule = ptr.Ref(sched.String())
+		})
+	)
+	// Given: workspace is stopped
+	workspace = coderdtest.MustTransitionWorkspace(t, client, workspace.ID, database.WorkspaceTransitionStart, database.WorkspaceTransitionStop)
+
+	// Given: the version of the workspace has been archived after a new
+	// version was promoted
+	orgs, err := client.OrganizationsByUser(ctx, workspace.OwnerID.String())
+	require.NoError(t, err)
+	require.Len(t, orgs, 1)
+
+	newVersion := coderdtest.UpdateTemplateVersion(t, client, orgs[0].ID, nil, workspace.TemplateID)
+	coderdtest.AwaitTemplateVersionJob(t, client, newVersion.ID)
+	require.NoError(t, client.UpdateActiveTemplateVersion(ctx, workspace.TemplateID, codersdk.UpdateActiveTemplateVersion{
+		ID: newVersion.ID,
+	}))
+	require.NoError(t, client.ArchiveTemplateVersion(ctx, workspace.LatestBuild.TemplateVersionID))
+
+	// When: the autobuild executor ticks after the scheduled time
+	go func() {
+		tickCh <- sched.Next(workspace.LatestBuild.CreatedAt)
+		close(tickCh)
+	}()
+
+	// Then: the workspace should be started using the active template
+	// version, since it can't be started with the archived one.
+	stats := <-statsCh
+	assert.NoError(t, stats.Error)
+	assert.Len(t, stats.Transitions, 1)
+	assert.Equal(t, database.WorkspaceTransitionStart, stats.Transitions[workspace.ID])
+	ws := coderdtest.MustWorkspace(t, client, workspace.ID)
+	assert.Equal(t, newVersion.ID, ws.LatestBuild.TemplateVersionID, "expected workspace build to be using the active template version")
+}
+
 func TestExecutorAutostartAlreadyRunning(t *testing.T) {
 	t.Parallel()
 
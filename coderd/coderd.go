@@ -44,9 +44,15 @@ import (
 // Options are requires parameters for Coder to start.
 type Options struct {
 	AccessURL *url.URL
-	Logger    slog.Logger
-	Database  database.Store
-	Pubsub    database.Pubsub
+	// AppHostname is the wildcard hostname workspace applications are
+	// served from, e.g. "*.coder.example.com". Subdomain applications are
+	// only served under it, and ports can only be shared with other users
+	// when it's set, as path-based applications are served from the
+	// dashboard origin.
+	AppHostname string
+	Logger      slog.Logger
+	Database    database.Store
+	Pubsub      database.Pubsub
 
 	// CacheDir is used for caching files served by the API.
 	CacheDir string
@@ -71,11 +77,14 @@ type Options struct {
 	TracerProvider       trace.TracerProvider
 	AutoImportTemplates  []AutoImportTemplate
 
-	TailnetCoordinator *tailnet.Coordinator
+	TailnetCoordinator tailnet.Coordinator
+	DERPServer         *derp.Server
 	DERPMap            *tailcfg.DERPMap
 
 	MetricsCacheRefreshInterval time.Duration
 	AgentStatsRefreshInterval   time.Duration
+	// MaxTokenLifetime is the longest lifetime users can create tokens with.
+	MaxTokenLifetime time.Duration
 }
 
 // New constructs a Coder API handler.
@@ -99,6 +108,9 @@ func New(options *Options) *API {
 	if options.APIRateLimit == 0 {
 		options.APIRateLimit = 512
 	}
+	if options.MaxTokenLifetime == 0 {
+		options.MaxTokenLifetime = 365 * 24 * time.Hour
+	}
 	if options.AgentStatsRefreshInterval == 0 {
 		options.AgentStatsRefreshInterval = 10 * time.Minute
 	}
@@ -123,6 +135,9 @@ func New(options *Options) *API {
 	if options.Auditor == nil {
 		options.Auditor = audit.NewNop()
 	}
+	if options.DERPServer == nil {
+		options.DERPServer = derp.NewServer(key.NewNode(), tailnet.Logger(options.Logger.Named("derp")))
+	}
 
 	siteCacheDir := options.CacheDir
 	if siteCacheDir != "" {
@@ -148,12 +163,13 @@ func New(options *Options) *API {
 			Authorizer: options.Authorizer,
 			Logger:     options.Logger,
 		},
-		metricsCache: metricsCache,
-		Auditor:      atomic.Pointer[audit.Auditor]{},
+		metricsCache:       metricsCache,
+		Auditor:            atomic.Pointer[audit.Auditor]{},
+		TailnetCoordinator: atomic.Pointer[tailnet.Coordinator]{},
 	}
 	api.Auditor.Store(&options.Auditor)
+	api.TailnetCoordinator.Store(&options.TailnetCoordinator)
 	api.workspaceAgentCache = wsconncache.New(api.dialWorkspaceAgentTailnet, 0)
-	api.derpServer = derp.NewServer(key.NewNode(), tailnet.Logger(options.Logger))
 	oauthConfigs := &httpmw.OAuth2Configs{
 		Github: options.GithubOAuth2Config,
 		OIDC:   options.OIDCConfig,
@@ -180,8 +196,10 @@ func New(options *Options) *API {
 				return &u
 			}()),
 			// This should extract the application specific API key when we
-			// implement a scoped token.
-			httpmw.ExtractAPIKey(options.Database, oauthConfigs, true),
+			// implement a scoped token. Requests without a key may still
+			// access publicly shared ports.
+			httpmw.ExtractAPIKeyOptional(options.Database, oauthConfigs),
+			redirectAnonymousMe,
 			httpmw.ExtractUserParam(api.Database),
 			httpmw.ExtractWorkspaceAndAgentParam(api.Database),
 		),
@@ -199,7 +217,10 @@ func New(options *Options) *API {
 		r.Use(
 			tracing.Middleware(api.TracerProvider),
 			httpmw.RateLimitPerMinute(options.APIRateLimit),
-			httpmw.ExtractAPIKey(options.Database, oauthConfigs, true),
+			// Signing in is only required once ports have been redirected
+			// to their subdomain URL, where they may be shared publicly.
+			httpmw.ExtractAPIKeyOptional(options.Database, oauthConfigs),
+			redirectAnonymousMe,
 			httpmw.ExtractUserParam(api.Database),
 			// Extracts the <workspace.agent> from the url
 			httpmw.ExtractWorkspaceAndAgentParam(api.Database),
@@ -212,7 +233,7 @@ func New(options *Options) *API {
 	r.Route("/%40{user}/{workspace_and_agent}/apps/{workspaceapp}", apps)
 	r.Route("/@{user}/{workspace_and_agent}/apps/{workspaceapp}", apps)
 	r.Route("/derp", func(r chi.Router) {
-		r.Get("/", derphttp.Handler(api.derpServer).ServeHTTP)
+		r.Get("/", derphttp.Handler(api.DERPServer).ServeHTTP)
 		// This is used when UDP is blocked, and latency must be checked via HTTP(s).
 		r.Get("/latency-check", func(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusOK)
@@ -252,6 +273,7 @@ func New(options *Options) *API {
 
 			r.Get("/", api.auditLogs)
 			r.Get("/count", api.auditLogCount)
+			r.Get("/export", api.auditLogExport)
 			r.Post("/testgenerate", api.generateFakeAuditLog)
 		})
 		r.Route("/files", func(r chi.Router) {
@@ -269,6 +291,7 @@ func New(options *Options) *API {
 				apiKeyMiddleware,
 			)
 			r.Get("/", api.provisionerDaemons)
+			r.Get("/serve", api.provisionerDaemonServe)
 		})
 		r.Route("/organizations", func(r chi.Router) {
 			r.Use(
@@ -313,6 +336,7 @@ func New(options *Options) *API {
 				httpmw.ExtractTemplateParam(options.Database),
 			)
 			r.Get("/daus", api.templateDAUs)
+			r.Get("/usage", api.templateUsage)
 			r.Get("/", api.template)
 			r.Delete("/", api.deleteTemplate)
 			r.Patch("/", api.patchTemplateMeta)
@@ -330,6 +354,8 @@ func New(options *Options) *API {
 
 			r.Get("/", api.templateVersion)
 			r.Patch("/cancel", api.patchCancelTemplateVersion)
+			r.Post("/archive", api.postArchiveTemplateVersion)
+			r.Post("/unarchive", api.postUnarchiveTemplateVersion)
 			r.Get("/schema", api.templateVersionSchema)
 			r.Get("/parameters", api.templateVersionParameters)
 			r.Get("/resources", api.templateVersionResources)
@@ -388,7 +414,12 @@ func New(options *Options) *API {
 
 					r.Route("/keys", func(r chi.Router) {
 						r.Post("/", api.postAPIKey)
+						r.Route("/tokens", func(r chi.Router) {
+							r.Post("/", api.postToken)
+							r.Get("/", api.tokens)
+						})
 						r.Get("/{keyid}", api.apiKey)
+						r.Delete("/{keyid}", api.deleteAPIKey)
 					})
 
 					r.Route("/organizations", func(r chi.Router) {
@@ -400,6 +431,7 @@ func New(options *Options) *API {
 						r.Get("/builds/{buildnumber}", api.workspaceBuildByBuildNumber)
 					})
 					r.Get("/gitsshkey", api.gitSSHKey)
+					r.Get("/usage", api.userUsage)
 					r.Put("/gitsshkey", api.regenerateGitSSHKey)
 				})
 			})
@@ -415,6 +447,12 @@ func New(options *Options) *API {
 				r.Get("/gitsshkey", api.agentGitSSHKey)
 				r.Get("/coordinate", api.workspaceAgentCoordinate)
 				r.Get("/report-stats", api.workspaceAgentReportStats)
+				r.Post("/report-lifecycle", api.workspaceAgentReportLifecycle)
+				r.Patch("/startup-logs", api.patchWorkspaceAgentStartupLogs)
+				r.Get("/await-shutdown", api.workspaceAgentAwaitShutdown)
+				r.Post("/metadata/{key}", api.workspaceAgentPostMetadata)
+				r.Post("/app-health", api.postWorkspaceAgentAppHealth)
+				r.Post("/health", api.postWorkspaceAgentHealth)
 			})
 			r.Route("/{workspaceagent}", func(r chi.Router) {
 				r.Use(
@@ -424,8 +462,14 @@ func New(options *Options) *API {
 				)
 				r.Get("/", api.workspaceAgent)
 				r.Get("/pty", api.workspaceAgentPTY)
+				r.Get("/listening-ports", api.workspaceAgentListeningPorts)
+				r.Get("/files", api.workspaceAgentListFiles)
+				r.Get("/files/read", api.workspaceAgentReadFile)
+				r.Post("/files/write", api.workspaceAgentWriteFile)
 				r.Get("/connection", api.workspaceAgentConnection)
 				r.Get("/coordinate", api.workspaceAgentClientCoordinate)
+				r.Get("/startup-logs", api.workspaceAgentStartupLogs)
+				r.Get("/watch-metadata", api.watchWorkspaceAgentMetadata)
 				// TODO: This can be removed in October. It allows for a friendly
 				// error message when transitioning from WebRTC to Tailscale. See:
 				// https://github.com/coder/coder/issues/4126
@@ -449,6 +493,7 @@ func New(options *Options) *API {
 				apiKeyMiddleware,
 			)
 			r.Get("/", api.workspaces)
+			r.Post("/builds", api.postBulkWorkspaceBuilds)
 			r.Route("/{workspace}", func(r chi.Router) {
 				r.Use(
 					httpmw.ExtractWorkspaceParam(options.Database),
@@ -465,8 +510,14 @@ func New(options *Options) *API {
 				r.Route("/ttl", func(r chi.Router) {
 					r.Put("/", api.putWorkspaceTTL)
 				})
+				r.Put("/autoupdates", api.putWorkspaceAutoupdates)
 				r.Get("/watch", api.watchWorkspace)
 				r.Put("/extend", api.putExtendWorkspace)
+				r.Route("/port-share", func(r chi.Router) {
+					r.Get("/", api.workspacePortShares)
+					r.Post("/", api.postWorkspacePortShare)
+					r.Delete("/", api.deleteWorkspacePortShare)
+				})
 			})
 		})
 		r.Route("/workspacebuilds/{workspacebuild}", func(r chi.Router) {
@@ -489,15 +540,20 @@ func New(options *Options) *API {
 
 type API struct {
 	*Options
-	Auditor  atomic.Pointer[audit.Auditor]
-	HTTPAuth *HTTPAuthorizer
+	Auditor atomic.Pointer[audit.Auditor]
+	// TailnetCoordinator is swapped by Enterprise for a coordinator that
+	// supports multiple replicas.
+	TailnetCoordinator atomic.Pointer[tailnet.Coordinator]
+	// QuotaCommitter is set by Enterprise to enforce workspace quotas.
+	// It's nil when quotas aren't enforced.
+	QuotaCommitter atomic.Pointer[QuotaCommitter]
+	HTTPAuth       *HTTPAuthorizer
 
 	// APIHandler serves "/api/v2"
 	APIHandler chi.Router
 	// RootHandler serves "/"
 	RootHandler chi.Router
 
-	derpServer          *derp.Server
 	metricsCache        *metricscache.Cache
 	siteHandler         http.Handler
 	websocketWaitMutex  sync.Mutex
@@ -512,7 +568,10 @@ func (api *API) Close() error {
 	api.websocketWaitMutex.Unlock()
 
 	api.metricsCache.Close()
-
+	coordinator := api.TailnetCoordinator.Load()
+	if coordinator != nil {
+		_ = (*coordinator).Close()
+	}
 	return api.workspaceAgentCache.Close()
 }
 
@@ -1,8 +1,10 @@
 package databasefake
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
+	"encoding/json"
 	"sort"
 	"strings"
 	"sync"
@@ -12,12 +14,18 @@ import (
 	"github.com/lib/pq"
 	"golang.org/x/exp/maps"
 	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
 
 	"github.com/coder/coder/coderd/database"
 	"github.com/coder/coder/coderd/rbac"
 	"github.com/coder/coder/coderd/util/slice"
 )
 
+var errDuplicateKey = &pq.Error{
+	Code:    "23505",
+	Message: "duplicate key value violates unique constraint",
+}
+
 // New returns an in-memory fake of the database.
 func New() database.Store {
 	return &fakeQuerier{
@@ -98,11 +106,22 @@ type data struct {
 	workspaceApps                  []database.WorkspaceApp
 	workspaces                     []database.Workspace
 	licenses                       []database.License
+	replicas                       []database.Replica
+	groups                         []database.Group
+	groupMembers                   []database.GroupMember
+	workspaceAgentStartupLogs      []database.WorkspaceAgentStartupLog
+	workspaceAgentMetadata         []database.WorkspaceAgentMetadatum
+	workspacePortShares            []database.WorkspacePortShare
 
 	deploymentID  string
+	derpMeshKey   string
 	lastLicenseID int32
 }
 
+func (*fakeQuerier) Ping(_ context.Context) (time.Duration, error) {
+	return 0, nil
+}
+
 // InTx doesn't rollback data properly for in-memory yet.
 func (q *fakeQuerier) InTx(fn func(database.Store) error) error {
 	q.mutex.Lock()
@@ -110,10 +129,23 @@ func (q *fakeQuerier) InTx(fn func(database.Store) error) error {
 	return fn(&fakeQuerier{mutex: inTxMutex{}, data: q.data})
 }
 
+func (*fakeQuerier) AcquireLockForUserQuota(_ context.Context, _ uuid.UUID) error {
+	// Transactions are already serialized by InTx.
+	return nil
+}
+
 func (q *fakeQuerier) AcquireProvisionerJob(_ context.Context, arg database.AcquireProvisionerJobParams) (database.ProvisionerJob, error) {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
 
+	tags := map[string]string{}
+	if arg.Tags != nil {
+		err := json.Unmarshal(arg.Tags, &tags)
+		if err != nil {
+			return database.ProvisionerJob{}, xerrors.Errorf("unmarshal tags: %w", err)
+		}
+	}
+
 	for index, provisionerJob := range q.provisionerJobs {
 		if provisionerJob.StartedAt.Valid {
 			continue
@@ -129,6 +161,19 @@ func (q *fakeQuerier) AcquireProvisionerJob(_ context.Context, arg database.Acqu
 		if !found {
 			continue
 		}
+		missing := false
+		for key, value := range provisionerJob.Tags {
+			if tags[key] != value {
+				missing = true
+				break
+			}
+		}
+		if missing {
+			continue
+		}
+		if q.awaitingAgentShutdown(provisionerJob, arg) {
+			continue
+		}
 		provisionerJob.StartedAt = arg.StartedAt
 		provisionerJob.UpdatedAt = arg.StartedAt.Time
 		provisionerJob.WorkerID = arg.WorkerID
@@ -137,6 +182,57 @@ func (q *fakeQuerier) AcquireProvisionerJob(_ context.Context, arg database.Acqu
 	}
 	return database.ProvisionerJob{}, sql.ErrNoRows
 }
+
+// awaitingAgentShutdown returns true if the job stops a workspace whose
+// agents are still running their shutdown scripts.
+func (q *fakeQuerier) awaitingAgentShutdown(job database.ProvisionerJob, arg database.AcquireProvisionerJobParams) bool {
+	var build database.WorkspaceBuild
+	for _, workspaceBuild := range q.workspaceBuilds {
+		if workspaceBuild.JobID == job.ID {
+			build = workspaceBuild
+			break
+		}
+	}
+	if build.ID == uuid.Nil || build.Transition == database.WorkspaceTransitionStart {
+		return false
+	}
+	previousJobID := uuid.Nil
+	for _, workspaceBuild := range q.workspaceBuilds {
+		if workspaceBuild.WorkspaceID == build.WorkspaceID && workspaceBuild.BuildNumber == build.BuildNumber-1 {
+			previousJobID = workspaceBuild.JobID
+			break
+		}
+	}
+	for _, resource := range q.provisionerJobResources {
+		if resource.JobID != previousJobID {
+			continue
+		}
+		for _, agent := range q.provisionerJobAgents {
+			if agent.ResourceID != resource.ID || !agent.ShutdownScript.Valid {
+				continue
+			}
+			switch agent.LifecycleState {
+			case database.WorkspaceAgentLifecycleStateShutdownTimeout,
+				database.WorkspaceAgentLifecycleStateShutdownError,
+				database.WorkspaceAgentLifecycleStateOff:
+				continue
+			}
+			if !agent.LastConnectedAt.Valid || !agent.LastConnectedAt.Time.After(arg.AgentConnectedAfter) {
+				continue
+			}
+			timeout := arg.AgentShutdownMaxTimeoutSeconds
+			if agent.ShutdownScriptTimeoutSeconds > 0 && agent.ShutdownScriptTimeoutSeconds < timeout {
+				timeout = agent.ShutdownScriptTimeoutSeconds
+			}
+			deadline := job.CreatedAt.Add(time.Duration(timeout+arg.AgentShutdownGraceSeconds) * time.Second)
+			if deadline.After(arg.StartedAt.Time) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func (*fakeQuerier) DeleteOldAgentStats(_ context.Context) error {
 	// no-op
 	return nil
@@ -154,6 +250,17 @@ func (q *fakeQuerier) InsertAgentStat(_ context.Context, p database.InsertAgentS
 		UserID:      p.UserID,
 		Payload:     p.Payload,
 		TemplateID:  p.TemplateID,
+
+		ConnectionCount:             p.ConnectionCount,
+		RxBytes:                     p.RxBytes,
+		TxBytes:                     p.TxBytes,
+		SessionCountSSH:             p.SessionCountSSH,
+		SessionCountVSCode:          p.SessionCountVSCode,
+		SessionCountJetBrains:       p.SessionCountJetBrains,
+		SessionCountReconnectingPTY: p.SessionCountReconnectingPTY,
+		SessionCountPortForward:     p.SessionCountPortForward,
+		SessionDurationSeconds:      p.SessionDurationSeconds,
+		LatencyMS:                   p.LatencyMS,
 	}
 	q.agentStats = append(q.agentStats, stat)
 	return stat, nil
@@ -226,6 +333,59 @@ func (q *fakeQuerier) GetTemplateDAUs(_ context.Context, templateID uuid.UUID) (
 	return rs, nil
 }
 
+func (q *fakeQuerier) GetDailyAgentStats(_ context.Context, createdAfter time.Time) ([]database.GetDailyAgentStatsRow, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	type key struct {
+		date       time.Time
+		templateID uuid.UUID
+		userID     uuid.UUID
+	}
+	rows := make(map[key]*database.GetDailyAgentStatsRow)
+	for _, as := range q.agentStats {
+		if !as.CreatedAt.After(createdAfter) {
+			continue
+		}
+		k := key{
+			date:       as.CreatedAt.UTC().Truncate(time.Hour * 24),
+			templateID: as.TemplateID,
+			userID:     as.UserID,
+		}
+		row, ok := rows[k]
+		if !ok {
+			row = &database.GetDailyAgentStatsRow{
+				Date:       k.date,
+				TemplateID: k.templateID,
+				UserID:     k.userID,
+			}
+			rows[k] = row
+		}
+		row.ConnectionCount += as.ConnectionCount
+		row.RxBytes += as.RxBytes
+		row.TxBytes += as.TxBytes
+		row.SessionCountSSH += as.SessionCountSSH
+		row.SessionCountVSCode += as.SessionCountVSCode
+		row.SessionCountJetBrains += as.SessionCountJetBrains
+		row.SessionCountReconnectingPTY += as.SessionCountReconnectingPTY
+		row.SessionCountPortForward += as.SessionCountPortForward
+		row.SessionDurationSeconds += as.SessionDurationSeconds
+		row.TotalLatencyMS += as.LatencyMS
+		if as.LatencyMS > 0 {
+			row.LatencySamples++
+		}
+	}
+
+	rs := make([]database.GetDailyAgentStatsRow, 0, len(rows))
+	for _, row := range rows {
+		rs = append(rs, *row)
+	}
+	sort.Slice(rs, func(i, j int) bool {
+		return rs[i].Date.Before(rs[j].Date)
+	})
+	return rs, nil
+}
+
 func (q *fakeQuerier) ParameterValue(_ context.Context, id uuid.UUID) (database.ParameterValue, error) {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -279,6 +439,19 @@ func (q *fakeQuerier) GetAPIKeysLastUsedAfter(_ context.Context, after time.Time
 	return apiKeys, nil
 }
 
+func (q *fakeQuerier) GetAPIKeysByUserID(_ context.Context, arg database.GetAPIKeysByUserIDParams) ([]database.APIKey, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	apiKeys := make([]database.APIKey, 0)
+	for _, key := range q.apiKeys {
+		if key.UserID == arg.UserID && key.LoginType == arg.LoginType {
+			apiKeys = append(apiKeys, key)
+		}
+	}
+	return apiKeys, nil
+}
+
 func (q *fakeQuerier) DeleteAPIKeyByID(_ context.Context, id string) error {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -512,11 +685,19 @@ func (q *fakeQuerier) GetAuthorizationUserRoles(_ context.Context, userID uuid.U
 		return database.GetAuthorizationUserRolesRow{}, sql.ErrNoRows
 	}
 
+	groups := make([]string, 0)
+	for _, member := range q.groupMembers {
+		if member.UserID == userID {
+			groups = append(groups, member.GroupID.String())
+		}
+	}
+
 	return database.GetAuthorizationUserRolesRow{
 		ID:       userID,
 		Username: user.Username,
 		Status:   user.Status,
 		Roles:    roles,
+		Groups:   groups,
 	}, nil
 }
 
@@ -997,6 +1178,7 @@ func (q *fakeQuerier) UpdateTemplateMetaByID(_ context.Context, arg database.Upd
 		tpl.Icon = arg.Icon
 		tpl.MaxTtl = arg.MaxTtl
 		tpl.MinAutostartInterval = arg.MinAutostartInterval
+		tpl.RequireActiveVersion = arg.RequireActiveVersion
 		q.templates[idx] = tpl
 		return tpl, nil
 	}
@@ -1086,6 +1268,16 @@ func (q *fakeQuerier) GetTemplateVersionsByTemplateID(_ context.Context, arg dat
 		}
 	}
 
+	if !arg.IncludeArchived {
+		unarchived := make([]database.TemplateVersion, 0, len(version))
+		for _, v := range version {
+			if !v.Archived {
+				unarchived = append(unarchived, v)
+			}
+		}
+		version = unarchived
+	}
+
 	if arg.OffsetOpt > 0 {
 		if int(arg.OffsetOpt) > len(version)-1 {
 			return nil, sql.ErrNoRows
@@ -1361,6 +1553,43 @@ func (q *fakeQuerier) GetWorkspaceAgentByInstanceID(_ context.Context, instanceI
 	return database.WorkspaceAgent{}, sql.ErrNoRows
 }
 
+func (q *fakeQuerier) GetWorkspaceAgentMetadata(ctx context.Context, workspaceAgentID uuid.UUID) ([]database.WorkspaceAgentMetadatum, error) {
+	return q.GetWorkspaceAgentMetadataByAgentIDs(ctx, []uuid.UUID{workspaceAgentID})
+}
+
+func (q *fakeQuerier) GetWorkspaceAgentMetadataByAgentIDs(_ context.Context, ids []uuid.UUID) ([]database.WorkspaceAgentMetadatum, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	metadata := make([]database.WorkspaceAgentMetadatum, 0)
+	for _, m := range q.workspaceAgentMetadata {
+		if slices.Contains(ids, m.WorkspaceAgentID) {
+			metadata = append(metadata, m)
+		}
+	}
+	sort.Slice(metadata, func(i, j int) bool {
+		return metadata[i].Key < metadata[j].Key
+	})
+	return metadata, nil
+}
+
+func (q *fakeQuerier) GetWorkspaceAgentStartupLogsAfter(_ context.Context, arg database.GetWorkspaceAgentStartupLogsAfterParams) ([]database.WorkspaceAgentStartupLog, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	logs := []database.WorkspaceAgentStartupLog{}
+	for _, log := range q.workspaceAgentStartupLogs {
+		if log.AgentID != arg.AgentID {
+			continue
+		}
+		if log.ID <= arg.CreatedAfter {
+			continue
+		}
+		logs = append(logs, log)
+	}
+	return logs, nil
+}
+
 func (q *fakeQuerier) GetWorkspaceAgentsByResourceIDs(_ context.Context, resourceIDs []uuid.UUID) ([]database.WorkspaceAgent, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -1603,6 +1832,14 @@ func (q *fakeQuerier) InsertAPIKey(_ context.Context, arg database.InsertAPIKeyP
 		arg.LifetimeSeconds = 86400
 	}
 
+	if arg.TokenName != "" {
+		for _, key := range q.apiKeys {
+			if key.UserID == arg.UserID && key.TokenName == arg.TokenName {
+				return database.APIKey{}, errDuplicateKey
+			}
+		}
+	}
+
 	//nolint:gosimple
 	key := database.APIKey{
 		ID:              arg.ID,
@@ -1616,6 +1853,7 @@ func (q *fakeQuerier) InsertAPIKey(_ context.Context, arg database.InsertAPIKeyP
 		LastUsed:        arg.LastUsed,
 		LoginType:       arg.LoginType,
 		Scope:           arg.Scope,
+		TokenName:       arg.TokenName,
 	}
 	q.apiKeys = append(q.apiKeys, key)
 	return key, nil
@@ -1708,6 +1946,9 @@ func (q *fakeQuerier) InsertTemplate(_ context.Context, arg database.InsertTempl
 		MaxTtl:               arg.MaxTtl,
 		MinAutostartInterval: arg.MinAutostartInterval,
 		CreatedBy:            arg.CreatedBy,
+		UserACL:              arg.UserACL,
+		GroupACL:             arg.GroupACL,
+		RequireActiveVersion: arg.RequireActiveVersion,
 	}
 	q.templates = append(q.templates, template)
 	return template, nil
@@ -1728,6 +1969,7 @@ func (q *fakeQuerier) InsertTemplateVersion(_ context.Context, arg database.Inse
 		Readme:         arg.Readme,
 		JobID:          arg.JobID,
 		CreatedBy:      arg.CreatedBy,
+		Message:        arg.Message,
 	}
 	q.templateVersions = append(q.templateVersions, version)
 	return version, nil
@@ -1790,11 +2032,26 @@ func (q *fakeQuerier) InsertProvisionerDaemon(_ context.Context, arg database.In
 		CreatedAt:    arg.CreatedAt,
 		Name:         arg.Name,
 		Provisioners: arg.Provisioners,
+		Tags:         arg.Tags,
 	}
 	q.provisionerDaemons = append(q.provisionerDaemons, daemon)
 	return daemon, nil
 }
 
+func (q *fakeQuerier) DeleteProvisionerDaemonByID(_ context.Context, id uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, daemon := range q.provisionerDaemons {
+		if daemon.ID != id {
+			continue
+		}
+		q.provisionerDaemons = append(q.provisionerDaemons[:index], q.provisionerDaemons[index+1:]...)
+		return nil
+	}
+	return sql.ErrNoRows
+}
+
 func (q *fakeQuerier) InsertProvisionerJob(_ context.Context, arg database.InsertProvisionerJobParams) (database.ProvisionerJob, error) {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -1810,6 +2067,7 @@ func (q *fakeQuerier) InsertProvisionerJob(_ context.Context, arg database.Inser
 		StorageSource:  arg.StorageSource,
 		Type:           arg.Type,
 		Input:          arg.Input,
+		Tags:           arg.Tags,
 	}
 	q.provisionerJobs = append(q.provisionerJobs, job)
 	return job, nil
@@ -1834,12 +2092,70 @@ func (q *fakeQuerier) InsertWorkspaceAgent(_ context.Context, arg database.Inser
 		StartupScript:        arg.StartupScript,
 		InstanceMetadata:     arg.InstanceMetadata,
 		ResourceMetadata:     arg.ResourceMetadata,
+		LifecycleState:       database.WorkspaceAgentLifecycleStateCreated,
+
+		StartupScriptTimeoutSeconds:  arg.StartupScriptTimeoutSeconds,
+		ShutdownScript:               arg.ShutdownScript,
+		ShutdownScriptTimeoutSeconds: arg.ShutdownScriptTimeoutSeconds,
+		SessionCPULimit:              arg.SessionCPULimit,
+		SessionMemoryLimitMB:         arg.SessionMemoryLimitMB,
+		SessionPidsLimit:             arg.SessionPidsLimit,
+		HealthWarnings:               []string{},
 	}
 
 	q.provisionerJobAgents = append(q.provisionerJobAgents, agent)
 	return agent, nil
 }
 
+func (q *fakeQuerier) InsertWorkspaceAgentMetadata(_ context.Context, arg database.InsertWorkspaceAgentMetadataParams) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, m := range q.workspaceAgentMetadata {
+		if m.WorkspaceAgentID == arg.WorkspaceAgentID && m.Key == arg.Key {
+			return errDuplicateKey
+		}
+	}
+	q.workspaceAgentMetadata = append(q.workspaceAgentMetadata, database.WorkspaceAgentMetadatum{
+		WorkspaceAgentID: arg.WorkspaceAgentID,
+		DisplayName:      arg.DisplayName,
+		Key:              arg.Key,
+		Script:           arg.Script,
+		Timeout:          arg.Timeout,
+		Interval:         arg.Interval,
+	})
+	return nil
+}
+
+func (q *fakeQuerier) InsertWorkspaceAgentStartupLogs(_ context.Context, arg database.InsertWorkspaceAgentStartupLogsParams) ([]database.WorkspaceAgentStartupLog, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	logs := []database.WorkspaceAgentStartupLog{}
+	id := int64(1)
+	if len(q.workspaceAgentStartupLogs) > 0 {
+		id = q.workspaceAgentStartupLogs[len(q.workspaceAgentStartupLogs)-1].ID + 1
+	}
+	for index, output := range arg.Output {
+		logs = append(logs, database.WorkspaceAgentStartupLog{
+			ID:        id,
+			AgentID:   arg.AgentID,
+			CreatedAt: arg.CreatedAt[index],
+			Output:    output,
+		})
+		id++
+	}
+	for index, agent := range q.provisionerJobAgents {
+		if agent.ID != arg.AgentID {
+			continue
+		}
+		agent.StartupLogsLength += arg.OutputLength
+		q.provisionerJobAgents[index] = agent
+	}
+	q.workspaceAgentStartupLogs = append(q.workspaceAgentStartupLogs, logs...)
+	return logs, nil
+}
+
 func (q *fakeQuerier) InsertWorkspaceResource(_ context.Context, arg database.InsertWorkspaceResourceParams) (database.WorkspaceResource, error) {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -1854,6 +2170,7 @@ func (q *fakeQuerier) InsertWorkspaceResource(_ context.Context, arg database.In
 		Name:       arg.Name,
 		Hide:       arg.Hide,
 		Icon:       arg.Icon,
+		DailyCost:  arg.DailyCost,
 	}
 	q.provisionerJobResources = append(q.provisionerJobResources, resource)
 	return resource, nil
@@ -1940,6 +2257,22 @@ func (q *fakeQuerier) UpdateUserProfile(_ context.Context, arg database.UpdateUs
 	return database.User{}, sql.ErrNoRows
 }
 
+func (q *fakeQuerier) UpdateUserQuotaAllowance(_ context.Context, arg database.UpdateUserQuotaAllowanceParams) (database.User, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, user := range q.users {
+		if user.ID != arg.ID {
+			continue
+		}
+		user.QuotaAllowance = arg.QuotaAllowance
+		user.UpdatedAt = arg.UpdatedAt
+		q.users[index] = user
+		return user, nil
+	}
+	return database.User{}, sql.ErrNoRows
+}
+
 func (q *fakeQuerier) UpdateUserStatus(_ context.Context, arg database.UpdateUserStatusParams) (database.User, error) {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -1986,6 +2319,7 @@ func (q *fakeQuerier) InsertWorkspace(_ context.Context, arg database.InsertWork
 		Name:              arg.Name,
 		AutostartSchedule: arg.AutostartSchedule,
 		Ttl:               arg.Ttl,
+		AutomaticUpdates:  arg.AutomaticUpdates,
 	}
 	q.workspaces = append(q.workspaces, workspace)
 	return workspace, nil
@@ -2019,19 +2353,38 @@ func (q *fakeQuerier) InsertWorkspaceApp(_ context.Context, arg database.InsertW
 
 	// nolint:gosimple
 	workspaceApp := database.WorkspaceApp{
-		ID:           arg.ID,
-		AgentID:      arg.AgentID,
-		CreatedAt:    arg.CreatedAt,
-		Name:         arg.Name,
-		Icon:         arg.Icon,
-		Command:      arg.Command,
-		Url:          arg.Url,
-		RelativePath: arg.RelativePath,
+		ID:                   arg.ID,
+		AgentID:              arg.AgentID,
+		CreatedAt:            arg.CreatedAt,
+		Name:                 arg.Name,
+		Icon:                 arg.Icon,
+		Command:              arg.Command,
+		Url:                  arg.Url,
+		RelativePath:         arg.RelativePath,
+		HealthcheckUrl:       arg.HealthcheckUrl,
+		HealthcheckInterval:  arg.HealthcheckInterval,
+		HealthcheckThreshold: arg.HealthcheckThreshold,
+		Health:               arg.Health,
 	}
 	q.workspaceApps = append(q.workspaceApps, workspaceApp)
 	return workspaceApp, nil
 }
 
+func (q *fakeQuerier) UpdateWorkspaceAppHealthByID(_ context.Context, arg database.UpdateWorkspaceAppHealthByIDParams) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, app := range q.workspaceApps {
+		if app.ID != arg.ID {
+			continue
+		}
+		app.Health = arg.Health
+		q.workspaceApps[index] = app
+		return nil
+	}
+	return sql.ErrNoRows
+}
+
 func (q *fakeQuerier) UpdateAPIKeyByID(_ context.Context, arg database.UpdateAPIKeyByIDParams) error {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -2081,6 +2434,22 @@ func (q *fakeQuerier) UpdateTemplateDeletedByID(_ context.Context, arg database.
 	return sql.ErrNoRows
 }
 
+func (q *fakeQuerier) UpdateTemplateVersionArchivedByID(_ context.Context, arg database.UpdateTemplateVersionArchivedByIDParams) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, templateVersion := range q.templateVersions {
+		if templateVersion.ID != arg.ID {
+			continue
+		}
+		templateVersion.Archived = arg.Archived
+		templateVersion.UpdatedAt = arg.UpdatedAt
+		q.templateVersions[index] = templateVersion
+		return nil
+	}
+	return sql.ErrNoRows
+}
+
 func (q *fakeQuerier) UpdateTemplateVersionByID(_ context.Context, arg database.UpdateTemplateVersionByIDParams) error {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -2147,6 +2516,71 @@ func (q *fakeQuerier) UpdateWorkspaceAgentConnectionByID(_ context.Context, arg
 	return sql.ErrNoRows
 }
 
+func (q *fakeQuerier) UpdateWorkspaceAgentHealthWarningsByID(_ context.Context, arg database.UpdateWorkspaceAgentHealthWarningsByIDParams) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, agent := range q.provisionerJobAgents {
+		if agent.ID != arg.ID {
+			continue
+		}
+
+		agent.HealthWarnings = arg.HealthWarnings
+		q.provisionerJobAgents[index] = agent
+		return nil
+	}
+	return sql.ErrNoRows
+}
+
+func (q *fakeQuerier) UpdateWorkspaceAgentLifecycleStateByID(_ context.Context, arg database.UpdateWorkspaceAgentLifecycleStateByIDParams) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, agent := range q.provisionerJobAgents {
+		if agent.ID != arg.ID {
+			continue
+		}
+
+		agent.LifecycleState = arg.LifecycleState
+		q.provisionerJobAgents[index] = agent
+		return nil
+	}
+	return sql.ErrNoRows
+}
+
+func (q *fakeQuerier) UpdateWorkspaceAgentMetadata(_ context.Context, arg database.UpdateWorkspaceAgentMetadataParams) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, m := range q.workspaceAgentMetadata {
+		if m.WorkspaceAgentID != arg.WorkspaceAgentID || m.Key != arg.Key {
+			continue
+		}
+		m.Value = arg.Value
+		m.Error = arg.Error
+		m.CollectedAt = arg.CollectedAt
+		q.workspaceAgentMetadata[index] = m
+		return nil
+	}
+	return nil
+}
+
+func (q *fakeQuerier) UpdateWorkspaceAgentStartupLogOverflowByID(_ context.Context, arg database.UpdateWorkspaceAgentStartupLogOverflowByIDParams) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, agent := range q.provisionerJobAgents {
+		if agent.ID != arg.ID {
+			continue
+		}
+
+		agent.StartupLogsOverflowed = arg.StartupLogsOverflowed
+		q.provisionerJobAgents[index] = agent
+		return nil
+	}
+	return sql.ErrNoRows
+}
+
 func (q *fakeQuerier) UpdateWorkspaceAgentVersionByID(_ context.Context, arg database.UpdateWorkspaceAgentVersionByIDParams) error {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -2236,6 +2670,22 @@ func (q *fakeQuerier) UpdateWorkspace(_ context.Context, arg database.UpdateWork
 	return database.Workspace{}, sql.ErrNoRows
 }
 
+func (q *fakeQuerier) UpdateWorkspaceAutomaticUpdates(_ context.Context, arg database.UpdateWorkspaceAutomaticUpdatesParams) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, workspace := range q.workspaces {
+		if workspace.ID != arg.ID {
+			continue
+		}
+		workspace.AutomaticUpdates = arg.AutomaticUpdates
+		q.workspaces[index] = workspace
+		return nil
+	}
+
+	return sql.ErrNoRows
+}
+
 func (q *fakeQuerier) UpdateWorkspaceAutostart(_ context.Context, arg database.UpdateWorkspaceAutostartParams) error {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -2382,39 +2832,23 @@ func (q *fakeQuerier) GetAuditLogsOffset(ctx context.Context, arg database.GetAu
 
 	logs := make([]database.GetAuditLogsOffsetRow, 0, arg.Limit)
 
-	// q.auditLogs are already sorted by time DESC, so no need to sort after the fact.
-	for _, alog := range q.auditLogs {
-		if arg.Offset > 0 {
-			arg.Offset--
-			continue
-		}
-		if arg.Action != "" && !strings.Contains(string(alog.Action), arg.Action) {
-			continue
-		}
-		if arg.ResourceType != "" && !strings.Contains(string(alog.ResourceType), arg.ResourceType) {
+	// q.auditLogs are sorted by time ASC, so iterate in reverse.
+	for i := len(q.auditLogs) - 1; i >= 0; i-- {
+		alog := q.auditLogs[i]
+		if !q.auditLogMatches(alog, arg.Action, arg.ResourceType, arg.ResourceID, arg.ResourceTarget, arg.Username, arg.Email, arg.TimeAfter, arg.TimeBefore) {
 			continue
 		}
-		if arg.ResourceID != uuid.Nil && alog.ResourceID != arg.ResourceID {
+		if arg.Offset > 0 {
+			arg.Offset--
 			continue
 		}
-		if arg.Username != "" {
-			user, err := q.GetUserByID(context.Background(), alog.UserID)
-			if err == nil && !strings.EqualFold(arg.Username, user.Username) {
-				continue
-			}
-		}
-		if arg.Email != "" {
-			user, err := q.GetUserByID(context.Background(), alog.UserID)
-			if err == nil && !strings.EqualFold(arg.Email, user.Email) {
-				continue
-			}
-		}
 
 		user, err := q.GetUserByID(ctx, alog.UserID)
 		userValid := err == nil
 
 		logs = append(logs, database.GetAuditLogsOffsetRow{
 			ID:               alog.ID,
+			Time:             alog.Time,
 			RequestID:        alog.RequestID,
 			OrganizationID:   alog.OrganizationID,
 			Ip:               alog.Ip,
@@ -2450,32 +2884,48 @@ func (q *fakeQuerier) GetAuditLogCount(_ context.Context, arg database.GetAuditL
 	logs := make([]database.AuditLog, 0)
 
 	for _, alog := range q.auditLogs {
-		if arg.Action != "" && !strings.Contains(string(alog.Action), arg.Action) {
+		if !q.auditLogMatches(alog, arg.Action, arg.ResourceType, arg.ResourceID, arg.ResourceTarget, arg.Username, arg.Email, arg.TimeAfter, arg.TimeBefore) {
 			continue
 		}
-		if arg.ResourceType != "" && !strings.Contains(string(alog.ResourceType), arg.ResourceType) {
-			continue
-		}
-		if arg.ResourceID != uuid.Nil && alog.ResourceID != arg.ResourceID {
+		logs = append(logs, alog)
+	}
+
+	return int64(len(logs)), nil
+}
+
+// auditLogMatches reports whether an audit log matches the filters shared
+// by GetAuditLogsOffset and GetAuditLogCount. The caller must hold the lock.
+func (q *fakeQuerier) auditLogMatches(alog database.AuditLog, action, resourceType string, resourceID uuid.UUID, resourceTarget, username, email string, timeAfter, timeBefore time.Time) bool {
+	if action != "" && !strings.Contains(string(alog.Action), action) {
+		return false
+	}
+	if resourceType != "" && !strings.Contains(string(alog.ResourceType), resourceType) {
+		return false
+	}
+	if resourceID != uuid.Nil && alog.ResourceID != resourceID {
+		return false
+	}
+	if resourceTarget != "" && alog.ResourceTarget != resourceTarget {
+		return false
+	}
+	for _, user := range q.users {
+		if user.ID != alog.UserID {
 			continue
 		}
-		if arg.Username != "" {
-			user, err := q.GetUserByID(context.Background(), alog.UserID)
-			if err == nil && !strings.EqualFold(arg.Username, user.Username) {
-				continue
-			}
+		if username != "" && !strings.EqualFold(username, user.Username) {
+			return false
 		}
-		if arg.Email != "" {
-			user, err := q.GetUserByID(context.Background(), alog.UserID)
-			if err == nil && !strings.EqualFold(arg.Email, user.Email) {
-				continue
-			}
+		if email != "" && !strings.EqualFold(email, user.Email) {
+			return false
 		}
-
-		logs = append(logs, alog)
 	}
-
-	return int64(len(logs)), nil
+	if !timeAfter.IsZero() && alog.Time.Before(timeAfter) {
+		return false
+	}
+	if !timeBefore.IsZero() && !alog.Time.Before(timeBefore) {
+		return false
+	}
+	return true
 }
 
 func (q *fakeQuerier) InsertAuditLog(_ context.Context, arg database.InsertAuditLogParams) (database.AuditLog, error) {
@@ -2486,6 +2936,9 @@ func (q *fakeQuerier) InsertAuditLog(_ context.Context, arg database.InsertAudit
 
 	q.auditLogs = append(q.auditLogs, alog)
 	slices.SortFunc(q.auditLogs, func(a, b database.AuditLog) bool {
+		if a.Time.Equal(b.Time) {
+			return bytes.Compare(a.ID[:], b.ID[:]) < 0
+		}
 		return a.Time.Before(b.Time)
 	})
 
@@ -2507,6 +2960,26 @@ func (q *fakeQuerier) GetDeploymentID(_ context.Context) (string, error) {
 	return q.deploymentID, nil
 }
 
+func (q *fakeQuerier) InsertDERPMeshKey(_ context.Context, id string) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if q.derpMeshKey == "" {
+		q.derpMeshKey = id
+	}
+	return nil
+}
+
+func (q *fakeQuerier) GetDERPMeshKey(_ context.Context) (string, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	if q.derpMeshKey == "" {
+		return "", sql.ErrNoRows
+	}
+	return q.derpMeshKey, nil
+}
+
 func (q *fakeQuerier) InsertLicense(
 	_ context.Context, arg database.InsertLicenseParams,
 ) (database.License, error) {
@@ -2638,3 +3111,405 @@ func (q *fakeQuerier) UpdateUserLink(_ context.Context, params database.UpdateUs
 
 	return database.UserLink{}, sql.ErrNoRows
 }
+
+func (q *fakeQuerier) DeleteReplicasUpdatedBefore(_ context.Context, before time.Time) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	replicas := make([]database.Replica, 0, len(q.replicas))
+	for _, replica := range q.replicas {
+		if replica.UpdatedAt.Before(before) {
+			continue
+		}
+		replicas = append(replicas, replica)
+	}
+	q.replicas = replicas
+
+	return nil
+}
+
+func (q *fakeQuerier) GetReplicaByID(_ context.Context, id uuid.UUID) (database.Replica, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, replica := range q.replicas {
+		if replica.ID == id {
+			return replica, nil
+		}
+	}
+
+	return database.Replica{}, sql.ErrNoRows
+}
+
+func (q *fakeQuerier) GetReplicasUpdatedAfter(_ context.Context, updatedAt time.Time) ([]database.Replica, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	replicas := make([]database.Replica, 0)
+	for _, replica := range q.replicas {
+		if replica.UpdatedAt.After(updatedAt) && !replica.StoppedAt.Valid {
+			replicas = append(replicas, replica)
+		}
+	}
+	return replicas, nil
+}
+
+func (q *fakeQuerier) InsertReplica(_ context.Context, arg database.InsertReplicaParams) (database.Replica, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	replica := database.Replica{
+		ID:              arg.ID,
+		CreatedAt:       arg.CreatedAt,
+		StartedAt:       arg.StartedAt,
+		UpdatedAt:       arg.UpdatedAt,
+		Hostname:        arg.Hostname,
+		RelayAddress:    arg.RelayAddress,
+		Version:         arg.Version,
+		DatabaseLatency: arg.DatabaseLatency,
+	}
+	q.replicas = append(q.replicas, replica)
+	return replica, nil
+}
+
+func (q *fakeQuerier) UpdateReplica(_ context.Context, arg database.UpdateReplicaParams) (database.Replica, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, replica := range q.replicas {
+		if replica.ID != arg.ID {
+			continue
+		}
+		replica.Hostname = arg.Hostname
+		replica.StartedAt = arg.StartedAt
+		replica.StoppedAt = arg.StoppedAt
+		replica.UpdatedAt = arg.UpdatedAt
+		replica.RelayAddress = arg.RelayAddress
+		replica.Version = arg.Version
+		replica.Error = arg.Error
+		replica.DatabaseLatency = arg.DatabaseLatency
+		q.replicas[index] = replica
+		return replica, nil
+	}
+	return database.Replica{}, sql.ErrNoRows
+}
+
+func (q *fakeQuerier) UpdateTemplateACLByID(_ context.Context, arg database.UpdateTemplateACLByIDParams) (database.Template, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, template := range q.templates {
+		if template.ID == arg.ID {
+			template.GroupACL = arg.GroupACL
+			template.UserACL = arg.UserACL
+
+			q.templates[i] = template
+			return template, nil
+		}
+	}
+
+	return database.Template{}, sql.ErrNoRows
+}
+
+func (q *fakeQuerier) GetGroupByID(_ context.Context, id uuid.UUID) (database.Group, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, group := range q.groups {
+		if group.ID == id {
+			return group, nil
+		}
+	}
+
+	return database.Group{}, sql.ErrNoRows
+}
+
+func (q *fakeQuerier) GetGroupByOrgAndName(_ context.Context, arg database.GetGroupByOrgAndNameParams) (database.Group, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, group := range q.groups {
+		if group.OrganizationID == arg.OrganizationID &&
+			group.Name == arg.Name {
+			return group, nil
+		}
+	}
+
+	return database.Group{}, sql.ErrNoRows
+}
+
+func (q *fakeQuerier) InsertAllUsersGroup(ctx context.Context, orgID uuid.UUID) (database.Group, error) {
+	return q.InsertGroup(ctx, database.InsertGroupParams{
+		ID:             orgID,
+		Name:           database.AllUsersGroup,
+		OrganizationID: orgID,
+	})
+}
+
+func (q *fakeQuerier) InsertGroup(_ context.Context, arg database.InsertGroupParams) (database.Group, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, group := range q.groups {
+		if group.OrganizationID == arg.OrganizationID &&
+			group.Name == arg.Name {
+			return database.Group{}, errDuplicateKey
+		}
+	}
+
+	//nolint:gosimple
+	group := database.Group{
+		ID:             arg.ID,
+		Name:           arg.Name,
+		OrganizationID: arg.OrganizationID,
+		QuotaAllowance: arg.QuotaAllowance,
+	}
+
+	q.groups = append(q.groups, group)
+
+	return group, nil
+}
+
+func (q *fakeQuerier) UpdateGroupByID(_ context.Context, arg database.UpdateGroupByIDParams) (database.Group, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, group := range q.groups {
+		if group.ID == arg.ID {
+			group.Name = arg.Name
+			group.QuotaAllowance = arg.QuotaAllowance
+			q.groups[i] = group
+			return group, nil
+		}
+	}
+	return database.Group{}, sql.ErrNoRows
+}
+
+func (q *fakeQuerier) DeleteGroupMemberFromGroup(_ context.Context, arg database.DeleteGroupMemberFromGroupParams) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, member := range q.groupMembers {
+		if member.UserID == arg.UserID && member.GroupID == arg.GroupID {
+			q.groupMembers = append(q.groupMembers[:i], q.groupMembers[i+1:]...)
+		}
+	}
+	return nil
+}
+
+func (q *fakeQuerier) InsertGroupMember(_ context.Context, arg database.InsertGroupMemberParams) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, member := range q.groupMembers {
+		if member.GroupID == arg.GroupID &&
+			member.UserID == arg.UserID {
+			return errDuplicateKey
+		}
+	}
+
+	//nolint:gosimple
+	q.groupMembers = append(q.groupMembers, database.GroupMember{
+		GroupID: arg.GroupID,
+		UserID:  arg.UserID,
+	})
+
+	return nil
+}
+
+func (q *fakeQuerier) DeleteGroupByID(_ context.Context, id uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, group := range q.groups {
+		if group.ID == id {
+			q.groups = append(q.groups[:i], q.groups[i+1:]...)
+			members := make([]database.GroupMember, 0, len(q.groupMembers))
+			for _, member := range q.groupMembers {
+				if member.GroupID != id {
+					members = append(members, member)
+				}
+			}
+			q.groupMembers = members
+			return nil
+		}
+	}
+
+	return sql.ErrNoRows
+}
+
+func (q *fakeQuerier) GetGroupMembers(_ context.Context, groupID uuid.UUID) ([]database.User, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	var members []database.GroupMember
+	for _, member := range q.groupMembers {
+		if member.GroupID == groupID {
+			members = append(members, member)
+		}
+	}
+
+	users := make([]database.User, 0, len(members))
+
+	for _, member := range members {
+		for _, user := range q.users {
+			if user.ID == member.UserID && user.Status == database.UserStatusActive && !user.Deleted {
+				users = append(users, user)
+				break
+			}
+		}
+	}
+
+	return users, nil
+}
+
+func (q *fakeQuerier) GetGroupsByOrganizationID(_ context.Context, organizationID uuid.UUID) ([]database.Group, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	var groups []database.Group
+	for _, group := range q.groups {
+		// Omit the allUsers group.
+		if group.OrganizationID == organizationID && group.ID != organizationID {
+			groups = append(groups, group)
+		}
+	}
+
+	return groups, nil
+}
+
+func (q *fakeQuerier) GetQuotaAllowanceForUser(_ context.Context, userID uuid.UUID) (int64, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	var sum int64
+	for _, user := range q.users {
+		if user.ID == userID {
+			sum += int64(user.QuotaAllowance)
+			break
+		}
+	}
+	for _, group := range q.groups {
+		isMember := false
+		for _, member := range q.groupMembers {
+			if member.GroupID == group.ID && member.UserID == userID {
+				isMember = true
+				break
+			}
+		}
+		// Organization members are implicitly in its "Everyone" group.
+		for _, member := range q.organizationMembers {
+			if member.OrganizationID == group.ID && member.UserID == userID {
+				isMember = true
+				break
+			}
+		}
+		if isMember {
+			sum += int64(group.QuotaAllowance)
+		}
+	}
+	return sum, nil
+}
+
+func (q *fakeQuerier) GetQuotaConsumedForUser(_ context.Context, userID uuid.UUID) (int64, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	var sum int64
+	for _, workspace := range q.workspaces {
+		if workspace.OwnerID != userID || workspace.Deleted {
+			continue
+		}
+
+		var lastBuild database.WorkspaceBuild
+		for _, build := range q.workspaceBuilds {
+			if build.WorkspaceID != workspace.ID {
+				continue
+			}
+			if build.CreatedAt.After(lastBuild.CreatedAt) {
+				lastBuild = build
+			}
+		}
+		sum += int64(lastBuild.DailyCost)
+	}
+	return sum, nil
+}
+
+func (q *fakeQuerier) UpdateWorkspaceBuildCostByID(_ context.Context, arg database.UpdateWorkspaceBuildCostByIDParams) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, workspaceBuild := range q.workspaceBuilds {
+		if workspaceBuild.ID != arg.ID {
+			continue
+		}
+		workspaceBuild.DailyCost = arg.DailyCost
+		q.workspaceBuilds[index] = workspaceBuild
+		return nil
+	}
+	return sql.ErrNoRows
+}
+
+func (q *fakeQuerier) GetWorkspacePortShare(_ context.Context, arg database.GetWorkspacePortShareParams) (database.WorkspacePortShare, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, share := range q.workspacePortShares {
+		if share.WorkspaceID == arg.WorkspaceID && share.AgentName == arg.AgentName && share.Port == arg.Port {
+			return share, nil
+		}
+	}
+	return database.WorkspacePortShare{}, sql.ErrNoRows
+}
+
+func (q *fakeQuerier) GetWorkspacePortShares(_ context.Context, workspaceID uuid.UUID) ([]database.WorkspacePortShare, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	shares := make([]database.WorkspacePortShare, 0)
+	for _, share := range q.workspacePortShares {
+		if share.WorkspaceID == workspaceID {
+			shares = append(shares, share)
+		}
+	}
+	sort.Slice(shares, func(i, j int) bool {
+		if shares[i].AgentName != shares[j].AgentName {
+			return shares[i].AgentName < shares[j].AgentName
+		}
+		return shares[i].Port < shares[j].Port
+	})
+	return shares, nil
+}
+
+func (q *fakeQuerier) UpsertWorkspacePortShare(_ context.Context, arg database.UpsertWorkspacePortShareParams) (database.WorkspacePortShare, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	share := database.WorkspacePortShare{
+		WorkspaceID: arg.WorkspaceID,
+		AgentName:   arg.AgentName,
+		Port:        arg.Port,
+		ShareLevel:  arg.ShareLevel,
+	}
+	for index, existing := range q.workspacePortShares {
+		if existing.WorkspaceID == arg.WorkspaceID && existing.AgentName == arg.AgentName && existing.Port == arg.Port {
+			q.workspacePortShares[index] = share
+			return share, nil
+		}
+	}
+	q.workspacePortShares = append(q.workspacePortShares, share)
+	return share, nil
+}
+
+func (q *fakeQuerier) DeleteWorkspacePortShare(_ context.Context, arg database.DeleteWorkspacePortShareParams) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, share := range q.workspacePortShares {
+		if share.WorkspaceID == arg.WorkspaceID && share.AgentName == arg.AgentName && share.Port == arg.Port {
+			q.workspacePortShares = append(q.workspacePortShares[:index], q.workspacePortShares[index+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
@@ -12,6 +12,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"time"
 
 	"golang.org/x/xerrors"
 )
@@ -21,6 +22,9 @@ import (
 type Store interface {
 	querier
 
+	// Ping returns the time it takes to reach the database.
+	Ping(ctx context.Context) (time.Duration, error)
+
 	InTx(func(Store) error) error
 }
 
@@ -45,6 +49,12 @@ type sqlQuerier struct {
 	db  DBTX
 }
 
+func (q *sqlQuerier) Ping(ctx context.Context) (time.Duration, error) {
+	start := time.Now()
+	err := q.sdb.PingContext(ctx)
+	return time.Since(start), err
+}
+
 // InTx performs database operations inside a transaction.
 func (q *sqlQuerier) InTx(function func(Store) error) error {
 	if _, ok := q.db.(*sql.Tx); ok {
@@ -71,7 +81,7 @@ func (q *sqlQuerier) InTx(function func(Store) error) error {
 		// couldn't roll back for some reason, extend returned error
 		err = xerrors.Errorf("defer (%s): %w", rerr.Error(), err)
 	}()
-	err = function(&sqlQuerier{db: transaction})
+	err = function(&sqlQuerier{sdb: q.sdb, db: transaction})
 	if err != nil {
 		return xerrors.Errorf("execute transaction: %w", err)
 	}
@@ -2,13 +2,28 @@
 
 CREATE TYPE api_key_scope AS ENUM (
     'all',
-    'application_connect'
+    'application_connect',
+    'read_only',
+    'workspace_lifecycle',
+    'template_push',
+    'agent_connect'
 );
 
 CREATE TYPE audit_action AS ENUM (
     'create',
     'write',
-    'delete'
+    'delete',
+    'start',
+    'stop',
+    'login',
+    'logout',
+    'connect',
+    'disconnect'
+);
+
+CREATE TYPE automatic_updates AS ENUM (
+    'always',
+    'never'
 );
 
 CREATE TYPE build_reason AS ENUM (
@@ -33,7 +48,8 @@ CREATE TYPE log_source AS ENUM (
 CREATE TYPE login_type AS ENUM (
     'password',
     'github',
-    'oidc'
+    'oidc',
+    'token'
 );
 
 CREATE TYPE parameter_destination_scheme AS ENUM (
@@ -88,6 +104,31 @@ CREATE TYPE user_status AS ENUM (
     'suspended'
 );
 
+CREATE TYPE workspace_agent_lifecycle_state AS ENUM (
+    'created',
+    'starting',
+    'start_timeout',
+    'start_error',
+    'ready',
+    'shutting_down',
+    'shutdown_timeout',
+    'shutdown_error',
+    'off'
+);
+
+CREATE TYPE workspace_app_health AS ENUM (
+    'disabled',
+    'initializing',
+    'healthy',
+    'unhealthy'
+);
+
+CREATE TYPE workspace_port_share_level AS ENUM (
+    'owner',
+    'authenticated',
+    'public'
+);
+
 CREATE TYPE workspace_transition AS ENUM (
     'start',
     'stop',
@@ -101,9 +142,25 @@ CREATE TABLE agent_stats (
     agent_id uuid NOT NULL,
     workspace_id uuid NOT NULL,
     template_id uuid NOT NULL,
-    payload jsonb NOT NULL
+    payload jsonb NOT NULL,
+    connection_count bigint DEFAULT 0 NOT NULL,
+    rx_bytes bigint DEFAULT 0 NOT NULL,
+    tx_bytes bigint DEFAULT 0 NOT NULL,
+    session_count_ssh bigint DEFAULT 0 NOT NULL,
+    session_count_vscode bigint DEFAULT 0 NOT NULL,
+    session_count_jetbrains bigint DEFAULT 0 NOT NULL,
+    session_count_reconnecting_pty bigint DEFAULT 0 NOT NULL,
+    session_count_port_forward bigint DEFAULT 0 NOT NULL,
+    session_duration_seconds double precision DEFAULT 0 NOT NULL,
+    latency_ms double precision DEFAULT 0 NOT NULL
 );
 
+COMMENT ON COLUMN agent_stats.connection_count IS 'The number of connections opened since the previous report.';
+
+COMMENT ON COLUMN agent_stats.session_duration_seconds IS 'The time spent in sessions since the previous report.';
+
+COMMENT ON COLUMN agent_stats.latency_ms IS 'The round trip time between coderd and the agent when the report was requested.';
+
 CREATE TABLE api_keys (
     id text NOT NULL,
     hashed_secret bytea NOT NULL,
@@ -115,9 +172,12 @@ CREATE TABLE api_keys (
     login_type login_type NOT NULL,
     lifetime_seconds bigint DEFAULT 86400 NOT NULL,
     ip_address inet DEFAULT '0.0.0.0'::inet NOT NULL,
-    scope api_key_scope DEFAULT 'all'::public.api_key_scope NOT NULL
+    scope api_key_scope DEFAULT 'all'::public.api_key_scope NOT NULL,
+    token_name text DEFAULT ''::text NOT NULL
 );
 
+COMMENT ON COLUMN api_keys.token_name IS 'The name of a long-lived token created by the user. Empty for session keys.';
+
 CREATE TABLE audit_logs (
     id uuid NOT NULL,
     "time" timestamp with time zone NOT NULL,
@@ -152,6 +212,18 @@ CREATE TABLE gitsshkeys (
     public_key text NOT NULL
 );
 
+CREATE TABLE group_members (
+    user_id uuid NOT NULL,
+    group_id uuid NOT NULL
+);
+
+CREATE TABLE groups (
+    id uuid NOT NULL,
+    name text NOT NULL,
+    organization_id uuid NOT NULL,
+    quota_allowance integer DEFAULT 0 NOT NULL
+);
+
 CREATE TABLE licenses (
     id integer NOT NULL,
     uploaded_at timestamp with time zone NOT NULL,
@@ -224,7 +296,8 @@ CREATE TABLE provisioner_daemons (
     created_at timestamp with time zone NOT NULL,
     updated_at timestamp with time zone,
     name character varying(64) NOT NULL,
-    provisioners provisioner_type[] NOT NULL
+    provisioners provisioner_type[] NOT NULL,
+    tags jsonb DEFAULT '{}'::jsonb NOT NULL
 );
 
 CREATE TABLE provisioner_job_logs (
@@ -252,7 +325,21 @@ CREATE TABLE provisioner_jobs (
     storage_source text NOT NULL,
     type provisioner_job_type NOT NULL,
     input jsonb NOT NULL,
-    worker_id uuid
+    worker_id uuid,
+    tags jsonb DEFAULT '{}'::jsonb NOT NULL
+);
+
+CREATE TABLE replicas (
+    id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    started_at timestamp with time zone NOT NULL,
+    stopped_at timestamp with time zone,
+    updated_at timestamp with time zone NOT NULL,
+    hostname text NOT NULL,
+    relay_address text NOT NULL,
+    database_latency integer NOT NULL,
+    version text NOT NULL,
+    error text DEFAULT ''::text NOT NULL
 );
 
 CREATE TABLE site_configs (
@@ -269,9 +356,15 @@ CREATE TABLE template_versions (
     name character varying(64) NOT NULL,
     readme character varying(1048576) NOT NULL,
     job_id uuid NOT NULL,
-    created_by uuid
+    created_by uuid,
+    message character varying(1048576) DEFAULT ''::character varying NOT NULL,
+    archived boolean DEFAULT false NOT NULL
 );
 
+COMMENT ON COLUMN template_versions.message IS 'Describes the changes of the version, e.g. release notes.';
+
+COMMENT ON COLUMN template_versions.archived IS 'Archived versions are hidden and new workspace builds cannot use them.';
+
 CREATE TABLE templates (
     id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -285,9 +378,14 @@ CREATE TABLE templates (
     max_ttl bigint DEFAULT '604800000000000'::bigint NOT NULL,
     min_autostart_interval bigint DEFAULT '3600000000000'::bigint NOT NULL,
     created_by uuid NOT NULL,
-    icon character varying(256) DEFAULT ''::character varying NOT NULL
+    icon character varying(256) DEFAULT ''::character varying NOT NULL,
+    user_acl jsonb DEFAULT '{}'::jsonb NOT NULL,
+    group_acl jsonb DEFAULT '{}'::jsonb NOT NULL,
+    require_active_version boolean DEFAULT false NOT NULL
 );
 
+COMMENT ON COLUMN templates.require_active_version IS 'Workspaces can only be started with the active version of the template.';
+
 CREATE TABLE user_links (
     user_id uuid NOT NULL,
     login_type login_type NOT NULL,
@@ -308,9 +406,42 @@ CREATE TABLE users (
     rbac_roles text[] DEFAULT '{}'::text[] NOT NULL,
     login_type login_type DEFAULT 'password'::public.login_type NOT NULL,
     avatar_url text,
-    deleted boolean DEFAULT false NOT NULL
+    deleted boolean DEFAULT false NOT NULL,
+    quota_allowance integer DEFAULT 0 NOT NULL
+);
+
+CREATE TABLE workspace_agent_metadata (
+    workspace_agent_id uuid NOT NULL,
+    display_name character varying(127) NOT NULL,
+    key character varying(127) NOT NULL,
+    script character varying(65535) NOT NULL,
+    value character varying(65535) DEFAULT ''::character varying NOT NULL,
+    error character varying(65535) DEFAULT ''::character varying NOT NULL,
+    timeout bigint NOT NULL,
+    "interval" bigint NOT NULL,
+    collected_at timestamp with time zone DEFAULT '0001-01-01 00:00:00+00'::timestamp with time zone NOT NULL
+);
+
+COMMENT ON COLUMN workspace_agent_metadata.timeout IS 'The number of seconds the script may run before it is killed.';
+
+COMMENT ON COLUMN workspace_agent_metadata."interval" IS 'The number of seconds between script runs.';
+
+CREATE TABLE workspace_agent_startup_logs (
+    id bigint NOT NULL,
+    agent_id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    output character varying(1024) NOT NULL
 );
 
+CREATE SEQUENCE workspace_agent_startup_logs_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+ALTER SEQUENCE workspace_agent_startup_logs_id_seq OWNED BY public.workspace_agent_startup_logs.id;
+
 CREATE TABLE workspace_agents (
     id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -329,11 +460,41 @@ CREATE TABLE workspace_agents (
     instance_metadata jsonb,
     resource_metadata jsonb,
     directory character varying(4096) DEFAULT ''::character varying NOT NULL,
-    version text DEFAULT ''::text NOT NULL
+    version text DEFAULT ''::text NOT NULL,
+    lifecycle_state workspace_agent_lifecycle_state DEFAULT 'created'::workspace_agent_lifecycle_state NOT NULL,
+    startup_script_timeout_seconds integer DEFAULT 0 NOT NULL,
+    startup_logs_length integer DEFAULT 0 NOT NULL,
+    startup_logs_overflowed boolean DEFAULT false NOT NULL,
+    shutdown_script character varying(65534),
+    shutdown_script_timeout_seconds integer DEFAULT 0 NOT NULL,
+    session_cpu_limit double precision DEFAULT 0 NOT NULL,
+    session_memory_limit_mb bigint DEFAULT 0 NOT NULL,
+    session_pids_limit bigint DEFAULT 0 NOT NULL,
+    health_warnings text[] DEFAULT '{}'::text[] NOT NULL
 );
 
 COMMENT ON COLUMN workspace_agents.version IS 'Version tracks the version of the currently running workspace agent. Workspace agents register their version upon start.';
 
+COMMENT ON COLUMN workspace_agents.lifecycle_state IS 'The current lifecycle state reported by the workspace agent.';
+
+COMMENT ON COLUMN workspace_agents.startup_script_timeout_seconds IS 'The number of seconds to wait for the startup script to complete. If the script does not complete within this time, the agent lifecycle will be marked as start_timeout.';
+
+COMMENT ON COLUMN workspace_agents.startup_logs_length IS 'Total length of startup logs';
+
+COMMENT ON COLUMN workspace_agents.startup_logs_overflowed IS 'Whether the startup logs overflowed in length';
+
+COMMENT ON COLUMN workspace_agents.shutdown_script IS 'Script that is executed before the agent is stopped.';
+
+COMMENT ON COLUMN workspace_agents.shutdown_script_timeout_seconds IS 'The number of seconds to wait for the shutdown script to complete. If the script does not complete within this time, the agent lifecycle will be marked as shutdown_timeout.';
+
+COMMENT ON COLUMN workspace_agents.session_cpu_limit IS 'The number of CPUs the processes of each session can use. Zero is unlimited.';
+
+COMMENT ON COLUMN workspace_agents.session_memory_limit_mb IS 'The number of megabytes of memory the processes of each session can use. Zero is unlimited.';
+
+COMMENT ON COLUMN workspace_agents.session_pids_limit IS 'The number of processes each session can run. Zero is unlimited.';
+
+COMMENT ON COLUMN workspace_agents.health_warnings IS 'Problems reported by the workspace agent, e.g. session limits that could not be applied.';
+
 CREATE TABLE workspace_apps (
     id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -342,9 +503,17 @@ CREATE TABLE workspace_apps (
     icon character varying(256) NOT NULL,
     command character varying(65534),
     url character varying(65534),
-    relative_path boolean DEFAULT false NOT NULL
+    relative_path boolean DEFAULT false NOT NULL,
+    healthcheck_url text DEFAULT ''::text NOT NULL,
+    healthcheck_interval integer DEFAULT 0 NOT NULL,
+    healthcheck_threshold integer DEFAULT 0 NOT NULL,
+    health workspace_app_health DEFAULT 'disabled'::public.workspace_app_health NOT NULL
 );
 
+COMMENT ON COLUMN workspace_apps.healthcheck_interval IS 'The number of seconds between healthcheck requests.';
+
+COMMENT ON COLUMN workspace_apps.healthcheck_threshold IS 'The number of consecutive failed healthchecks before the app is unhealthy.';
+
 CREATE TABLE workspace_builds (
     id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -357,9 +526,19 @@ CREATE TABLE workspace_builds (
     provisioner_state bytea,
     job_id uuid NOT NULL,
     deadline timestamp with time zone DEFAULT '0001-01-01 00:00:00+00'::timestamp with time zone NOT NULL,
-    reason build_reason DEFAULT 'initiator'::public.build_reason NOT NULL
+    reason build_reason DEFAULT 'initiator'::public.build_reason NOT NULL,
+    daily_cost integer DEFAULT 0 NOT NULL
+);
+
+CREATE TABLE workspace_port_shares (
+    workspace_id uuid NOT NULL,
+    agent_name text NOT NULL,
+    port integer NOT NULL,
+    share_level workspace_port_share_level NOT NULL
 );
 
+COMMENT ON TABLE workspace_port_shares IS 'Ports of a workspace agent that can be accessed by users other than the workspace owner.';
+
 CREATE TABLE workspace_resource_metadata (
     workspace_resource_id uuid NOT NULL,
     key character varying(1024) NOT NULL,
@@ -375,7 +554,8 @@ CREATE TABLE workspace_resources (
     type character varying(192) NOT NULL,
     name character varying(64) NOT NULL,
     hide boolean DEFAULT false NOT NULL,
-    icon character varying(256) DEFAULT ''::character varying NOT NULL
+    icon character varying(256) DEFAULT ''::character varying NOT NULL,
+    daily_cost integer DEFAULT 0 NOT NULL
 );
 
 CREATE TABLE workspaces (
@@ -389,11 +569,16 @@ CREATE TABLE workspaces (
     name character varying(64) NOT NULL,
     autostart_schedule text,
     ttl bigint,
-    last_used_at timestamp without time zone DEFAULT '0001-01-01 00:00:00'::timestamp without time zone NOT NULL
+    last_used_at timestamp without time zone DEFAULT '0001-01-01 00:00:00'::timestamp without time zone NOT NULL,
+    automatic_updates automatic_updates DEFAULT 'never'::automatic_updates NOT NULL
 );
 
+COMMENT ON COLUMN workspaces.automatic_updates IS 'Whether the workspace is updated to the active template version when it''s started.';
+
 ALTER TABLE ONLY licenses ALTER COLUMN id SET DEFAULT nextval('public.licenses_id_seq'::regclass);
 
+ALTER TABLE ONLY workspace_agent_startup_logs ALTER COLUMN id SET DEFAULT nextval('public.workspace_agent_startup_logs_id_seq'::regclass);
+
 ALTER TABLE ONLY agent_stats
     ADD CONSTRAINT agent_stats_pkey PRIMARY KEY (id);
 
@@ -409,6 +594,15 @@ ALTER TABLE ONLY files
 ALTER TABLE ONLY gitsshkeys
     ADD CONSTRAINT gitsshkeys_pkey PRIMARY KEY (user_id);
 
+ALTER TABLE ONLY group_members
+    ADD CONSTRAINT group_members_user_id_group_id_key UNIQUE (user_id, group_id);
+
+ALTER TABLE ONLY groups
+    ADD CONSTRAINT groups_name_organization_id_key UNIQUE (name, organization_id);
+
+ALTER TABLE ONLY groups
+    ADD CONSTRAINT groups_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY licenses
     ADD CONSTRAINT licenses_jwt_key UNIQUE (jwt);
 
@@ -445,6 +639,9 @@ ALTER TABLE ONLY provisioner_job_logs
 ALTER TABLE ONLY provisioner_jobs
     ADD CONSTRAINT provisioner_jobs_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY replicas
+    ADD CONSTRAINT replicas_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY site_configs
     ADD CONSTRAINT site_configs_key_key UNIQUE (key);
 
@@ -463,6 +660,12 @@ ALTER TABLE ONLY user_links
 ALTER TABLE ONLY users
     ADD CONSTRAINT users_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY workspace_agent_metadata
+    ADD CONSTRAINT workspace_agent_metadata_pkey PRIMARY KEY (workspace_agent_id, key);
+
+ALTER TABLE ONLY workspace_agent_startup_logs
+    ADD CONSTRAINT workspace_agent_startup_logs_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY workspace_agents
     ADD CONSTRAINT workspace_agents_pkey PRIMARY KEY (id);
 
@@ -481,6 +684,9 @@ ALTER TABLE ONLY workspace_builds
 ALTER TABLE ONLY workspace_builds
     ADD CONSTRAINT workspace_builds_workspace_id_build_number_key UNIQUE (workspace_id, build_number);
 
+ALTER TABLE ONLY workspace_port_shares
+    ADD CONSTRAINT workspace_port_shares_pkey PRIMARY KEY (workspace_id, agent_name, port);
+
 ALTER TABLE ONLY workspace_resource_metadata
     ADD CONSTRAINT workspace_resource_metadata_pkey PRIMARY KEY (workspace_resource_id, key);
 
@@ -496,13 +702,15 @@ CREATE INDEX idx_agent_stats_user_id ON agent_stats USING btree (user_id);
 
 CREATE INDEX idx_api_keys_user ON api_keys USING btree (user_id);
 
+CREATE UNIQUE INDEX idx_api_keys_user_id_token_name ON api_keys USING btree (user_id, token_name) WHERE (token_name <> ''::text);
+
 CREATE INDEX idx_audit_log_organization_id ON audit_logs USING btree (organization_id);
 
 CREATE INDEX idx_audit_log_resource_id ON audit_logs USING btree (resource_id);
 
 CREATE INDEX idx_audit_log_user_id ON audit_logs USING btree (user_id);
 
-CREATE INDEX idx_audit_logs_time_desc ON audit_logs USING btree ("time" DESC);
+CREATE INDEX idx_audit_logs_time_desc ON audit_logs USING btree ("time" DESC, id DESC);
 
 CREATE INDEX idx_organization_member_organization_id_uuid ON organization_members USING btree (organization_id);
 
@@ -520,6 +728,8 @@ CREATE UNIQUE INDEX templates_organization_id_name_idx ON templates USING btree
 
 CREATE UNIQUE INDEX users_username_lower_idx ON users USING btree (lower(username)) WHERE (deleted = false);
 
+CREATE INDEX workspace_agent_startup_logs_id_agent_id_idx ON workspace_agent_startup_logs USING btree (agent_id, id);
+
 CREATE UNIQUE INDEX workspaces_owner_id_lower_idx ON workspaces USING btree (owner_id, lower((name)::text)) WHERE (deleted = false);
 
 ALTER TABLE ONLY api_keys
@@ -528,6 +738,15 @@ ALTER TABLE ONLY api_keys
 ALTER TABLE ONLY gitsshkeys
     ADD CONSTRAINT gitsshkeys_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id);
 
+ALTER TABLE ONLY group_members
+    ADD CONSTRAINT group_members_group_id_fkey FOREIGN KEY (group_id) REFERENCES groups(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY group_members
+    ADD CONSTRAINT group_members_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY groups
+    ADD CONSTRAINT groups_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY organization_members
     ADD CONSTRAINT organization_members_organization_id_uuid_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
 
@@ -564,6 +783,12 @@ ALTER TABLE ONLY user_links
 ALTER TABLE ONLY workspace_agents
     ADD CONSTRAINT workspace_agents_resource_id_fkey FOREIGN KEY (resource_id) REFERENCES workspace_resources(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY workspace_agent_metadata
+    ADD CONSTRAINT workspace_agent_metadata_workspace_agent_id_fkey FOREIGN KEY (workspace_agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_agent_startup_logs
+    ADD CONSTRAINT workspace_agent_startup_logs_agent_id_fkey FOREIGN KEY (agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspace_apps
     ADD CONSTRAINT workspace_apps_agent_id_fkey FOREIGN KEY (agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
 
@@ -576,6 +801,9 @@ ALTER TABLE ONLY workspace_builds
 ALTER TABLE ONLY workspace_builds
     ADD CONSTRAINT workspace_builds_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY workspace_port_shares
+    ADD CONSTRAINT workspace_port_shares_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspace_resource_metadata
     ADD CONSTRAINT workspace_resource_metadata_workspace_resource_id_fkey FOREIGN KEY (workspace_resource_id) REFERENCES workspace_resources(id) ON DELETE CASCADE;
 
@@ -0,0 +1,2 @@
+ALTER TABLE provisioner_daemons DROP COLUMN tags;
+ALTER TABLE provisioner_jobs DROP COLUMN tags;
@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS replicas (
+    -- A unique identifier for the replica that is generated on start.
+    id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    -- The time the replica was created.
+    started_at timestamp with time zone NOT NULL,
+    -- The time the replica was last seen shutting down.
+    stopped_at timestamp with time zone,
+    -- Updated periodically to ensure the replica is still alive.
+    updated_at timestamp with time zone NOT NULL,
+    -- Hostname is the hostname of the replica.
+    hostname text NOT NULL,
+    -- An address that should be accessible to other replicas.
+    relay_address text NOT NULL,
+    -- The latency of the replica to the database in microseconds.
+    database_latency int NOT NULL,
+    -- Version is the Coder version of the replica.
+    version text NOT NULL,
+    error text NOT NULL DEFAULT '',
+    PRIMARY KEY (id)
+);
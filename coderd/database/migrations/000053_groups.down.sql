@@ -0,0 +1,4 @@
+ALTER TABLE templates DROP COLUMN IF EXISTS group_acl;
+ALTER TABLE templates DROP COLUMN IF EXISTS user_acl;
+DROP TABLE IF EXISTS group_members;
+DROP TABLE IF EXISTS groups;
@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS groups (
+	id uuid NOT NULL,
+	name text NOT NULL,
+	organization_id uuid NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+	PRIMARY KEY(id),
+	UNIQUE(name, organization_id)
+);
+
+CREATE TABLE IF NOT EXISTS group_members (
+	user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	group_id uuid NOT NULL REFERENCES groups(id) ON DELETE CASCADE,
+	UNIQUE(user_id, group_id)
+);
+
+-- Access control lists map user and group IDs to the actions they may
+-- perform on a template.
+ALTER TABLE templates ADD COLUMN user_acl jsonb NOT NULL DEFAULT '{}';
+ALTER TABLE templates ADD COLUMN group_acl jsonb NOT NULL DEFAULT '{}';
+
+-- Every organization has an "Everyone" group that shares its ID and
+-- implicitly contains all of its members.
+INSERT INTO groups (id, name, organization_id) SELECT id, 'Everyone', id FROM organizations;
+
+-- Existing templates remain visible to every member of their organization.
+UPDATE templates SET group_acl = jsonb_build_object(organization_id::text, jsonb_build_array('read'));
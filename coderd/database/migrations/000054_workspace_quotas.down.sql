@@ -0,0 +1,5 @@
+ALTER TABLE workspace_builds DROP COLUMN daily_cost;
+
+ALTER TABLE workspace_resources DROP COLUMN daily_cost;
+
+ALTER TABLE groups DROP COLUMN quota_allowance;
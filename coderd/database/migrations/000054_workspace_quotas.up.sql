@@ -0,0 +1,5 @@
+ALTER TABLE groups ADD COLUMN quota_allowance integer NOT NULL DEFAULT 0;
+
+ALTER TABLE workspace_resources ADD COLUMN daily_cost integer NOT NULL DEFAULT 0;
+
+ALTER TABLE workspace_builds ADD COLUMN daily_cost integer NOT NULL DEFAULT 0;
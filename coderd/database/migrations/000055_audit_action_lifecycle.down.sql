@@ -0,0 +1,8 @@
+-- It's not possible to drop enum values from enum types, so the UP has "IF NOT
+-- EXISTS".
+
+-- Delete all audit logs that use the new enum values.
+DELETE FROM
+    audit_logs
+WHERE
+    action IN ('start', 'stop', 'login', 'logout', 'connect', 'disconnect');
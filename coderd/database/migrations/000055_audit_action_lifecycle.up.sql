@@ -0,0 +1,6 @@
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'start';
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'stop';
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'login';
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'logout';
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'connect';
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'disconnect';
@@ -0,0 +1,9 @@
+DROP TABLE workspace_agent_startup_logs;
+
+ALTER TABLE workspace_agents
+	DROP COLUMN lifecycle_state,
+	DROP COLUMN startup_script_timeout_seconds,
+	DROP COLUMN startup_logs_length,
+	DROP COLUMN startup_logs_overflowed;
+
+DROP TYPE workspace_agent_lifecycle_state;
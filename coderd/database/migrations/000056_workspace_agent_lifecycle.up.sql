@@ -0,0 +1,21 @@
+CREATE TYPE workspace_agent_lifecycle_state AS ENUM ('created', 'starting', 'start_timeout', 'start_error', 'ready', 'shutting_down');
+
+ALTER TABLE workspace_agents
+	ADD COLUMN lifecycle_state workspace_agent_lifecycle_state NOT NULL DEFAULT 'created',
+	ADD COLUMN startup_script_timeout_seconds integer NOT NULL DEFAULT 0,
+	ADD COLUMN startup_logs_length integer NOT NULL DEFAULT 0,
+	ADD COLUMN startup_logs_overflowed boolean NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN workspace_agents.lifecycle_state IS 'The current lifecycle state reported by the workspace agent.';
+COMMENT ON COLUMN workspace_agents.startup_script_timeout_seconds IS 'The number of seconds to wait for the startup script to complete. If the script does not complete within this time, the agent lifecycle will be marked as start_timeout.';
+COMMENT ON COLUMN workspace_agents.startup_logs_length IS 'Total length of startup logs';
+COMMENT ON COLUMN workspace_agents.startup_logs_overflowed IS 'Whether the startup logs overflowed in length';
+
+CREATE TABLE workspace_agent_startup_logs (
+	id bigserial PRIMARY KEY,
+	agent_id uuid NOT NULL REFERENCES workspace_agents(id) ON DELETE CASCADE,
+	created_at timestamptz NOT NULL,
+	output varchar(1024) NOT NULL
+);
+
+CREATE INDEX workspace_agent_startup_logs_id_agent_id_idx ON workspace_agent_startup_logs USING btree (agent_id, id);
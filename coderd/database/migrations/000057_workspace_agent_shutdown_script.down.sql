@@ -0,0 +1,13 @@
+-- It's not possible to drop enum values from enum types, so the UP has "IF NOT
+-- EXISTS".
+
+UPDATE
+	workspace_agents
+SET
+	lifecycle_state = 'shutting_down'
+WHERE
+	lifecycle_state IN ('shutdown_timeout', 'shutdown_error', 'off');
+
+ALTER TABLE workspace_agents
+	DROP COLUMN shutdown_script,
+	DROP COLUMN shutdown_script_timeout_seconds;
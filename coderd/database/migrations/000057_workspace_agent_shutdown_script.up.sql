@@ -0,0 +1,10 @@
+ALTER TYPE workspace_agent_lifecycle_state ADD VALUE IF NOT EXISTS 'shutdown_timeout';
+ALTER TYPE workspace_agent_lifecycle_state ADD VALUE IF NOT EXISTS 'shutdown_error';
+ALTER TYPE workspace_agent_lifecycle_state ADD VALUE IF NOT EXISTS 'off';
+
+ALTER TABLE workspace_agents
+	ADD COLUMN shutdown_script varchar(65534),
+	ADD COLUMN shutdown_script_timeout_seconds integer NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN workspace_agents.shutdown_script IS 'Script that is executed before the agent is stopped.';
+COMMENT ON COLUMN workspace_agents.shutdown_script_timeout_seconds IS 'The number of seconds to wait for the shutdown script to complete. If the script does not complete within this time, the agent lifecycle will be marked as shutdown_timeout.';
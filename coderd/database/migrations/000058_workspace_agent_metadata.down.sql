@@ -0,0 +1 @@
+DROP TABLE workspace_agent_metadata;
@@ -0,0 +1,11 @@
+ALTER TABLE agent_stats
+	DROP COLUMN connection_count,
+	DROP COLUMN rx_bytes,
+	DROP COLUMN tx_bytes,
+	DROP COLUMN session_count_ssh,
+	DROP COLUMN session_count_vscode,
+	DROP COLUMN session_count_jetbrains,
+	DROP COLUMN session_count_reconnecting_pty,
+	DROP COLUMN session_count_port_forward,
+	DROP COLUMN session_duration_seconds,
+	DROP COLUMN latency_ms;
@@ -0,0 +1,15 @@
+ALTER TABLE agent_stats
+	ADD COLUMN connection_count bigint NOT NULL DEFAULT 0,
+	ADD COLUMN rx_bytes bigint NOT NULL DEFAULT 0,
+	ADD COLUMN tx_bytes bigint NOT NULL DEFAULT 0,
+	ADD COLUMN session_count_ssh bigint NOT NULL DEFAULT 0,
+	ADD COLUMN session_count_vscode bigint NOT NULL DEFAULT 0,
+	ADD COLUMN session_count_jetbrains bigint NOT NULL DEFAULT 0,
+	ADD COLUMN session_count_reconnecting_pty bigint NOT NULL DEFAULT 0,
+	ADD COLUMN session_count_port_forward bigint NOT NULL DEFAULT 0,
+	ADD COLUMN session_duration_seconds double precision NOT NULL DEFAULT 0,
+	ADD COLUMN latency_ms double precision NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN agent_stats.connection_count IS 'The number of connections opened since the previous report.';
+COMMENT ON COLUMN agent_stats.session_duration_seconds IS 'The time spent in sessions since the previous report.';
+COMMENT ON COLUMN agent_stats.latency_ms IS 'The round trip time between coderd and the agent when the report was requested.';
@@ -0,0 +1,3 @@
+DROP TABLE workspace_port_shares;
+
+DROP TYPE workspace_port_share_level;
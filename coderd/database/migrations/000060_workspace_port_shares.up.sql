@@ -0,0 +1,11 @@
+CREATE TYPE workspace_port_share_level AS ENUM ('owner', 'authenticated', 'public');
+
+CREATE TABLE workspace_port_shares (
+	workspace_id uuid NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+	agent_name text NOT NULL,
+	port integer NOT NULL,
+	share_level workspace_port_share_level NOT NULL,
+	PRIMARY KEY (workspace_id, agent_name, port)
+);
+
+COMMENT ON TABLE workspace_port_shares IS 'Ports of a workspace agent that can be accessed by users other than the workspace owner.';
@@ -0,0 +1,7 @@
+ALTER TABLE workspace_apps
+	DROP COLUMN healthcheck_url,
+	DROP COLUMN healthcheck_interval,
+	DROP COLUMN healthcheck_threshold,
+	DROP COLUMN health;
+
+DROP TYPE workspace_app_health;
@@ -0,0 +1,10 @@
+CREATE TYPE workspace_app_health AS ENUM ('disabled', 'initializing', 'healthy', 'unhealthy');
+
+ALTER TABLE workspace_apps
+	ADD COLUMN healthcheck_url text NOT NULL DEFAULT '',
+	ADD COLUMN healthcheck_interval int NOT NULL DEFAULT 0,
+	ADD COLUMN healthcheck_threshold int NOT NULL DEFAULT 0,
+	ADD COLUMN health workspace_app_health NOT NULL DEFAULT 'disabled';
+
+COMMENT ON COLUMN workspace_apps.healthcheck_interval IS 'The number of seconds between healthcheck requests.';
+COMMENT ON COLUMN workspace_apps.healthcheck_threshold IS 'The number of consecutive failed healthchecks before the app is unhealthy.';
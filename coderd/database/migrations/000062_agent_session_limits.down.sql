@@ -0,0 +1,5 @@
+ALTER TABLE workspace_agents
+	DROP COLUMN session_cpu_limit,
+	DROP COLUMN session_memory_limit_mb,
+	DROP COLUMN session_pids_limit,
+	DROP COLUMN health_warnings;
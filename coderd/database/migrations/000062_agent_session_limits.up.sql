@@ -0,0 +1,10 @@
+ALTER TABLE workspace_agents
+	ADD COLUMN session_cpu_limit double precision NOT NULL DEFAULT 0,
+	ADD COLUMN session_memory_limit_mb bigint NOT NULL DEFAULT 0,
+	ADD COLUMN session_pids_limit bigint NOT NULL DEFAULT 0,
+	ADD COLUMN health_warnings text[] NOT NULL DEFAULT '{}';
+
+COMMENT ON COLUMN workspace_agents.session_cpu_limit IS 'The number of CPUs the processes of each session can use. Zero is unlimited.';
+COMMENT ON COLUMN workspace_agents.session_memory_limit_mb IS 'The number of megabytes of memory the processes of each session can use. Zero is unlimited.';
+COMMENT ON COLUMN workspace_agents.session_pids_limit IS 'The number of processes each session can run. Zero is unlimited.';
+COMMENT ON COLUMN workspace_agents.health_warnings IS 'Problems reported by the workspace agent, e.g. session limits that could not be applied.';
@@ -0,0 +1,12 @@
+-- It's not possible to drop enum values from enum types, so the UP has "IF NOT
+-- EXISTS".
+
+DELETE FROM
+	api_keys
+WHERE
+	login_type = 'token';
+
+DROP INDEX idx_api_keys_user_id_token_name;
+
+ALTER TABLE api_keys
+	DROP COLUMN token_name;
@@ -0,0 +1,8 @@
+ALTER TYPE login_type ADD VALUE IF NOT EXISTS 'token';
+
+ALTER TABLE api_keys
+	ADD COLUMN token_name text NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN api_keys.token_name IS 'The name of a long-lived token created by the user. Empty for session keys.';
+
+CREATE UNIQUE INDEX idx_api_keys_user_id_token_name ON api_keys USING btree (user_id, token_name) WHERE (token_name != '');
@@ -0,0 +1,8 @@
+-- It's not possible to drop enum values from enum types, so the UP has "IF NOT
+-- EXISTS".
+
+-- Keys with the scopes are removed rather than widened to "all".
+DELETE FROM
+	api_keys
+WHERE
+	scope IN ('read_only', 'workspace_lifecycle', 'template_push', 'agent_connect');
@@ -0,0 +1,4 @@
+ALTER TYPE api_key_scope ADD VALUE IF NOT EXISTS 'read_only';
+ALTER TYPE api_key_scope ADD VALUE IF NOT EXISTS 'workspace_lifecycle';
+ALTER TYPE api_key_scope ADD VALUE IF NOT EXISTS 'template_push';
+ALTER TYPE api_key_scope ADD VALUE IF NOT EXISTS 'agent_connect';
@@ -0,0 +1,3 @@
+ALTER TABLE template_versions
+	DROP COLUMN message,
+	DROP COLUMN archived;
@@ -0,0 +1,6 @@
+ALTER TABLE template_versions
+	ADD COLUMN message varchar(1048576) NOT NULL DEFAULT '',
+	ADD COLUMN archived boolean NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN template_versions.message IS 'Describes the changes of the version, e.g. release notes.';
+COMMENT ON COLUMN template_versions.archived IS 'Archived versions are hidden and new workspace builds cannot use them.';
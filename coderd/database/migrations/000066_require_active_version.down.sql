@@ -0,0 +1,7 @@
+ALTER TABLE workspaces
+	DROP COLUMN automatic_updates;
+
+ALTER TABLE templates
+	DROP COLUMN require_active_version;
+
+DROP TYPE automatic_updates;
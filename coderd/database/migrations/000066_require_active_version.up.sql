@@ -0,0 +1,14 @@
+CREATE TYPE automatic_updates AS ENUM (
+	'always',
+	'never'
+);
+
+ALTER TABLE templates
+	ADD COLUMN require_active_version boolean NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN templates.require_active_version IS 'Workspaces can only be started with the active version of the template.';
+
+ALTER TABLE workspaces
+	ADD COLUMN automatic_updates automatic_updates NOT NULL DEFAULT 'never';
+
+COMMENT ON COLUMN workspaces.automatic_updates IS 'Whether the workspace is updated to the active template version when it''s started.';
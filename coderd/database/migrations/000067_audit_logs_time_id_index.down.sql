@@ -0,0 +1,2 @@
+DROP INDEX idx_audit_logs_time_desc;
+CREATE INDEX idx_audit_logs_time_desc ON audit_logs USING btree ("time" DESC);
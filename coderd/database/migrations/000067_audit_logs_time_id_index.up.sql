@@ -0,0 +1,4 @@
+-- Audit logs are paginated by time, and logs can share a time, so the ID
+-- is included to keep the order stable.
+DROP INDEX idx_audit_logs_time_desc;
+CREATE INDEX idx_audit_logs_time_desc ON audit_logs USING btree ("time" DESC, id DESC);
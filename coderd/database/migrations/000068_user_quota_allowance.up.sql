@@ -0,0 +1,3 @@
+-- Users can be granted a quota allowance directly, in addition to the
+-- allowances of their groups.
+ALTER TABLE users ADD COLUMN quota_allowance integer NOT NULL DEFAULT 0;
@@ -10,17 +10,42 @@ func (s APIKeyScope) ToRBAC() rbac.Scope {
 		return rbac.ScopeAll
 	case APIKeyScopeApplicationConnect:
 		return rbac.ScopeApplicationConnect
+	case APIKeyScopeReadOnly:
+		return rbac.ScopeReadOnly
+	case APIKeyScopeWorkspaceLifecycle:
+		return rbac.ScopeWorkspaceLifecycle
+	case APIKeyScopeTemplatePush:
+		return rbac.ScopeTemplatePush
+	case APIKeyScopeAgentConnect:
+		return rbac.ScopeAgentConnect
 	default:
 		panic("developer error: unknown scope type " + string(s))
 	}
 }
 
+// AllUsersGroup is the name of the group every organization member
+// implicitly belongs to. It shares its ID with the organization.
+const AllUsersGroup = "Everyone"
+
+func (g Group) RBACObject() rbac.Object {
+	return rbac.ResourceGroup.InOrg(g.OrganizationID)
+}
+
 func (t Template) RBACObject() rbac.Object {
-	return rbac.ResourceTemplate.InOrg(t.OrganizationID)
+	return rbac.ResourceTemplate.InOrg(t.OrganizationID).
+		WithACLUserList(t.UserACL).
+		WithGroupACL(t.GroupACL)
+}
+
+// RBACObject uses the parent template resource for controlling versions,
+// so the template's access control lists apply to its versions.
+func (TemplateVersion) RBACObject(template Template) rbac.Object {
+	return template.RBACObject()
 }
 
-func (t TemplateVersion) RBACObject() rbac.Object {
-	// Just use the parent template resource for controlling versions
+// RBACObjectNoTemplate is for orphaned template versions that have not
+// been assigned to a template yet.
+func (t TemplateVersion) RBACObjectNoTemplate() rbac.Object {
 	return rbac.ResourceTemplate.InOrg(t.OrganizationID)
 }
 
@@ -19,6 +19,10 @@ type APIKeyScope string
 const (
 	APIKeyScopeAll                APIKeyScope = "all"
 	APIKeyScopeApplicationConnect APIKeyScope = "application_connect"
+	APIKeyScopeReadOnly           APIKeyScope = "read_only"
+	APIKeyScopeWorkspaceLifecycle APIKeyScope = "workspace_lifecycle"
+	APIKeyScopeTemplatePush       APIKeyScope = "template_push"
+	APIKeyScopeAgentConnect       APIKeyScope = "agent_connect"
 )
 
 func (e *APIKeyScope) Scan(src interface{}) error {
@@ -36,9 +40,15 @@ func (e *APIKeyScope) Scan(src interface{}) error {
 type AuditAction string
 
 const (
-	AuditActionCreate AuditAction = "create"
-	AuditActionWrite  AuditAction = "write"
-	AuditActionDelete AuditAction = "delete"
+	AuditActionCreate     AuditAction = "create"
+	AuditActionWrite      AuditAction = "write"
+	AuditActionDelete     AuditAction = "delete"
+	AuditActionStart      AuditAction = "start"
+	AuditActionStop       AuditAction = "stop"
+	AuditActionLogin      AuditAction = "login"
+	AuditActionLogout     AuditAction = "logout"
+	AuditActionConnect    AuditAction = "connect"
+	AuditActionDisconnect AuditAction = "disconnect"
 )
 
 func (e *AuditAction) Scan(src interface{}) error {
@@ -53,6 +63,25 @@ func (e *AuditAction) Scan(src interface{}) error {
 	return nil
 }
 
+type AutomaticUpdates string
+
+const (
+	AutomaticUpdatesAlways AutomaticUpdates = "always"
+	AutomaticUpdatesNever  AutomaticUpdates = "never"
+)
+
+func (e *AutomaticUpdates) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = AutomaticUpdates(s)
+	case string:
+		*e = AutomaticUpdates(s)
+	default:
+		return fmt.Errorf("unsupported scan type for AutomaticUpdates: %T", src)
+	}
+	return nil
+}
+
 type BuildReason string
 
 const (
@@ -120,6 +149,7 @@ const (
 	LoginTypePassword LoginType = "password"
 	LoginTypeGithub   LoginType = "github"
 	LoginTypeOIDC     LoginType = "oidc"
+	LoginTypeToken    LoginType = "token"
 )
 
 func (e *LoginType) Scan(src interface{}) error {
@@ -312,6 +342,73 @@ func (e *UserStatus) Scan(src interface{}) error {
 	return nil
 }
 
+type WorkspaceAgentLifecycleState string
+
+const (
+	WorkspaceAgentLifecycleStateCreated         WorkspaceAgentLifecycleState = "created"
+	WorkspaceAgentLifecycleStateStarting        WorkspaceAgentLifecycleState = "starting"
+	WorkspaceAgentLifecycleStateStartTimeout    WorkspaceAgentLifecycleState = "start_timeout"
+	WorkspaceAgentLifecycleStateStartError      WorkspaceAgentLifecycleState = "start_error"
+	WorkspaceAgentLifecycleStateReady           WorkspaceAgentLifecycleState = "ready"
+	WorkspaceAgentLifecycleStateShuttingDown    WorkspaceAgentLifecycleState = "shutting_down"
+	WorkspaceAgentLifecycleStateShutdownTimeout WorkspaceAgentLifecycleState = "shutdown_timeout"
+	WorkspaceAgentLifecycleStateShutdownError   WorkspaceAgentLifecycleState = "shutdown_error"
+	WorkspaceAgentLifecycleStateOff             WorkspaceAgentLifecycleState = "off"
+)
+
+func (e *WorkspaceAgentLifecycleState) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WorkspaceAgentLifecycleState(s)
+	case string:
+		*e = WorkspaceAgentLifecycleState(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WorkspaceAgentLifecycleState: %T", src)
+	}
+	return nil
+}
+
+type WorkspaceAppHealth string
+
+const (
+	WorkspaceAppHealthDisabled     WorkspaceAppHealth = "disabled"
+	WorkspaceAppHealthInitializing WorkspaceAppHealth = "initializing"
+	WorkspaceAppHealthHealthy      WorkspaceAppHealth = "healthy"
+	WorkspaceAppHealthUnhealthy    WorkspaceAppHealth = "unhealthy"
+)
+
+func (e *WorkspaceAppHealth) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WorkspaceAppHealth(s)
+	case string:
+		*e = WorkspaceAppHealth(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WorkspaceAppHealth: %T", src)
+	}
+	return nil
+}
+
+type WorkspacePortShareLevel string
+
+const (
+	WorkspacePortShareLevelOwner         WorkspacePortShareLevel = "owner"
+	WorkspacePortShareLevelAuthenticated WorkspacePortShareLevel = "authenticated"
+	WorkspacePortShareLevelPublic        WorkspacePortShareLevel = "public"
+)
+
+func (e *WorkspacePortShareLevel) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WorkspacePortShareLevel(s)
+	case string:
+		*e = WorkspacePortShareLevel(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WorkspacePortShareLevel: %T", src)
+	}
+	return nil
+}
+
 type WorkspaceTransition string
 
 const (
@@ -344,6 +441,7 @@ type APIKey struct {
 	LifetimeSeconds int64       `db:"lifetime_seconds" json:"lifetime_seconds"`
 	IPAddress       pqtype.Inet `db:"ip_address" json:"ip_address"`
 	Scope           APIKeyScope `db:"scope" json:"scope"`
+	TokenName       string      `db:"token_name" json:"token_name"`
 }
 
 type AgentStat struct {
@@ -354,6 +452,19 @@ type AgentStat struct {
 	WorkspaceID uuid.UUID       `db:"workspace_id" json:"workspace_id"`
 	TemplateID  uuid.UUID       `db:"template_id" json:"template_id"`
 	Payload     json.RawMessage `db:"payload" json:"payload"`
+	// The number of connections opened since the previous report.
+	ConnectionCount             int64 `db:"connection_count" json:"connection_count"`
+	RxBytes                     int64 `db:"rx_bytes" json:"rx_bytes"`
+	TxBytes                     int64 `db:"tx_bytes" json:"tx_bytes"`
+	SessionCountSSH             int64 `db:"session_count_ssh" json:"session_count_ssh"`
+	SessionCountVSCode          int64 `db:"session_count_vscode" json:"session_count_vscode"`
+	SessionCountJetBrains       int64 `db:"session_count_jetbrains" json:"session_count_jetbrains"`
+	SessionCountReconnectingPTY int64 `db:"session_count_reconnecting_pty" json:"session_count_reconnecting_pty"`
+	SessionCountPortForward     int64 `db:"session_count_port_forward" json:"session_count_port_forward"`
+	// The time spent in sessions since the previous report.
+	SessionDurationSeconds float64 `db:"session_duration_seconds" json:"session_duration_seconds"`
+	// The round trip time between coderd and the agent when the report was requested.
+	LatencyMS float64 `db:"latency_ms" json:"latency_ms"`
 }
 
 type AuditLog struct {
@@ -390,6 +501,18 @@ type GitSSHKey struct {
 	PublicKey  string    `db:"public_key" json:"public_key"`
 }
 
+type Group struct {
+	ID             uuid.UUID `db:"id" json:"id"`
+	Name           string    `db:"name" json:"name"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	QuotaAllowance int32     `db:"quota_allowance" json:"quota_allowance"`
+}
+
+type GroupMember struct {
+	UserID  uuid.UUID `db:"user_id" json:"user_id"`
+	GroupID uuid.UUID `db:"group_id" json:"group_id"`
+}
+
 type License struct {
 	ID         int32     `db:"id" json:"id"`
 	UploadedAt time.Time `db:"uploaded_at" json:"uploaded_at"`
@@ -452,6 +575,7 @@ type ProvisionerDaemon struct {
 	UpdatedAt    sql.NullTime      `db:"updated_at" json:"updated_at"`
 	Name         string            `db:"name" json:"name"`
 	Provisioners []ProvisionerType `db:"provisioners" json:"provisioners"`
+	Tags         StringMap         `db:"tags" json:"tags"`
 }
 
 type ProvisionerJob struct {
@@ -470,6 +594,7 @@ type ProvisionerJob struct {
 	Type           ProvisionerJobType       `db:"type" json:"type"`
 	Input          json.RawMessage          `db:"input" json:"input"`
 	WorkerID       uuid.NullUUID            `db:"worker_id" json:"worker_id"`
+	Tags           StringMap                `db:"tags" json:"tags"`
 }
 
 type ProvisionerJobLog struct {
@@ -482,6 +607,19 @@ type ProvisionerJobLog struct {
 	Output    string    `db:"output" json:"output"`
 }
 
+type Replica struct {
+	ID              uuid.UUID    `db:"id" json:"id"`
+	CreatedAt       time.Time    `db:"created_at" json:"created_at"`
+	StartedAt       time.Time    `db:"started_at" json:"started_at"`
+	StoppedAt       sql.NullTime `db:"stopped_at" json:"stopped_at"`
+	UpdatedAt       time.Time    `db:"updated_at" json:"updated_at"`
+	Hostname        string       `db:"hostname" json:"hostname"`
+	RelayAddress    string       `db:"relay_address" json:"relay_address"`
+	DatabaseLatency int32        `db:"database_latency" json:"database_latency"`
+	Version         string       `db:"version" json:"version"`
+	Error           string       `db:"error" json:"error"`
+}
+
 type SiteConfig struct {
 	Key   string `db:"key" json:"key"`
 	Value string `db:"value" json:"value"`
@@ -501,6 +639,10 @@ type Template struct {
 	MinAutostartInterval int64           `db:"min_autostart_interval" json:"min_autostart_interval"`
 	CreatedBy            uuid.UUID       `db:"created_by" json:"created_by"`
 	Icon                 string          `db:"icon" json:"icon"`
+	UserACL              TemplateACL     `db:"user_acl" json:"user_acl"`
+	GroupACL             TemplateACL     `db:"group_acl" json:"group_acl"`
+	// Workspaces can only be started with the active version of the template.
+	RequireActiveVersion bool `db:"require_active_version" json:"require_active_version"`
 }
 
 type TemplateVersion struct {
@@ -513,6 +655,10 @@ type TemplateVersion struct {
 	Readme         string        `db:"readme" json:"readme"`
 	JobID          uuid.UUID     `db:"job_id" json:"job_id"`
 	CreatedBy      uuid.NullUUID `db:"created_by" json:"created_by"`
+	// Describes the changes of the version, e.g. release notes.
+	Message string `db:"message" json:"message"`
+	// Archived versions are hidden and new workspace builds cannot use them.
+	Archived bool `db:"archived" json:"archived"`
 }
 
 type User struct {
@@ -527,6 +673,7 @@ type User struct {
 	LoginType      LoginType      `db:"login_type" json:"login_type"`
 	AvatarURL      sql.NullString `db:"avatar_url" json:"avatar_url"`
 	Deleted        bool           `db:"deleted" json:"deleted"`
+	QuotaAllowance int32          `db:"quota_allowance" json:"quota_allowance"`
 }
 
 type UserLink struct {
@@ -550,6 +697,8 @@ type Workspace struct {
 	AutostartSchedule sql.NullString `db:"autostart_schedule" json:"autostart_schedule"`
 	Ttl               sql.NullInt64  `db:"ttl" json:"ttl"`
 	LastUsedAt        time.Time      `db:"last_used_at" json:"last_used_at"`
+	// Whether the workspace is updated to the active template version when it's started.
+	AutomaticUpdates AutomaticUpdates `db:"automatic_updates" json:"automatic_updates"`
 }
 
 type WorkspaceAgent struct {
@@ -572,17 +721,64 @@ type WorkspaceAgent struct {
 	Directory            string                `db:"directory" json:"directory"`
 	// Version tracks the version of the currently running workspace agent. Workspace agents register their version upon start.
 	Version string `db:"version" json:"version"`
+	// The current lifecycle state reported by the workspace agent.
+	LifecycleState WorkspaceAgentLifecycleState `db:"lifecycle_state" json:"lifecycle_state"`
+	// The number of seconds to wait for the startup script to complete. If the script does not complete within this time, the agent lifecycle will be marked as start_timeout.
+	StartupScriptTimeoutSeconds int32 `db:"startup_script_timeout_seconds" json:"startup_script_timeout_seconds"`
+	// Total length of startup logs
+	StartupLogsLength int32 `db:"startup_logs_length" json:"startup_logs_length"`
+	// Whether the startup logs overflowed in length
+	StartupLogsOverflowed bool `db:"startup_logs_overflowed" json:"startup_logs_overflowed"`
+	// Script that is executed before the agent is stopped.
+	ShutdownScript sql.NullString `db:"shutdown_script" json:"shutdown_script"`
+	// The number of seconds to wait for the shutdown script to complete. If the script does not complete within this time, the agent lifecycle will be marked as shutdown_timeout.
+	ShutdownScriptTimeoutSeconds int32 `db:"shutdown_script_timeout_seconds" json:"shutdown_script_timeout_seconds"`
+	// The number of CPUs the processes of each session can use. Zero is unlimited.
+	SessionCPULimit float64 `db:"session_cpu_limit" json:"session_cpu_limit"`
+	// The number of megabytes of memory the processes of each session can use. Zero is unlimited.
+	SessionMemoryLimitMB int64 `db:"session_memory_limit_mb" json:"session_memory_limit_mb"`
+	// The number of processes each session can run. Zero is unlimited.
+	SessionPidsLimit int64 `db:"session_pids_limit" json:"session_pids_limit"`
+	// Problems reported by the workspace agent, e.g. session limits that could not be applied.
+	HealthWarnings []string `db:"health_warnings" json:"health_warnings"`
+}
+
+type WorkspaceAgentMetadatum struct {
+	WorkspaceAgentID uuid.UUID `db:"workspace_agent_id" json:"workspace_agent_id"`
+	DisplayName      string    `db:"display_name" json:"display_name"`
+	Key              string    `db:"key" json:"key"`
+	Script           string    `db:"script" json:"script"`
+	Value            string    `db:"value" json:"value"`
+	Error            string    `db:"error" json:"error"`
+	// The number of seconds the script may run before it is killed.
+	Timeout int64 `db:"timeout" json:"timeout"`
+	// The number of seconds between script runs.
+	Interval    int64     `db:"interval" json:"interval"`
+	CollectedAt time.Time `db:"collected_at" json:"collected_at"`
+}
+
+type WorkspaceAgentStartupLog struct {
+	ID        int64     `db:"id" json:"id"`
+	AgentID   uuid.UUID `db:"agent_id" json:"agent_id"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	Output    string    `db:"output" json:"output"`
 }
 
 type WorkspaceApp struct {
-	ID           uuid.UUID      `db:"id" json:"id"`
-	CreatedAt    time.Time      `db:"created_at" json:"created_at"`
-	AgentID      uuid.UUID      `db:"agent_id" json:"agent_id"`
-	Name         string         `db:"name" json:"name"`
-	Icon         string         `db:"icon" json:"icon"`
-	Command      sql.NullString `db:"command" json:"command"`
-	Url          sql.NullString `db:"url" json:"url"`
-	RelativePath bool           `db:"relative_path" json:"relative_path"`
+	ID             uuid.UUID      `db:"id" json:"id"`
+	CreatedAt      time.Time      `db:"created_at" json:"created_at"`
+	AgentID        uuid.UUID      `db:"agent_id" json:"agent_id"`
+	Name           string         `db:"name" json:"name"`
+	Icon           string         `db:"icon" json:"icon"`
+	Command        sql.NullString `db:"command" json:"command"`
+	Url            sql.NullString `db:"url" json:"url"`
+	RelativePath   bool           `db:"relative_path" json:"relative_path"`
+	HealthcheckUrl string         `db:"healthcheck_url" json:"healthcheck_url"`
+	// The number of seconds between healthcheck requests.
+	HealthcheckInterval int32 `db:"healthcheck_interval" json:"healthcheck_interval"`
+	// The number of consecutive failed healthchecks before the app is unhealthy.
+	HealthcheckThreshold int32              `db:"healthcheck_threshold" json:"healthcheck_threshold"`
+	Health               WorkspaceAppHealth `db:"health" json:"health"`
 }
 
 type WorkspaceBuild struct {
@@ -598,6 +794,15 @@ type WorkspaceBuild struct {
 	JobID             uuid.UUID           `db:"job_id" json:"job_id"`
 	Deadline          time.Time           `db:"deadline" json:"deadline"`
 	Reason            BuildReason         `db:"reason" json:"reason"`
+	DailyCost         int32               `db:"daily_cost" json:"daily_cost"`
+}
+
+// Ports of a workspace agent that can be accessed by users other than the workspace owner.
+type WorkspacePortShare struct {
+	WorkspaceID uuid.UUID               `db:"workspace_id" json:"workspace_id"`
+	AgentName   string                  `db:"agent_name" json:"agent_name"`
+	Port        int32                   `db:"port" json:"port"`
+	ShareLevel  WorkspacePortShareLevel `db:"share_level" json:"share_level"`
 }
 
 type WorkspaceResource struct {
@@ -609,6 +814,7 @@ type WorkspaceResource struct {
 	Name       string              `db:"name" json:"name"`
 	Hide       bool                `db:"hide" json:"hide"`
 	Icon       string              `db:"icon" json:"icon"`
+	DailyCost  int32               `db:"daily_cost" json:"daily_cost"`
 }
 
 type WorkspaceResourceMetadatum struct {
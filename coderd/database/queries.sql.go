@@ -24,8 +24,91 @@ func (q *sqlQuerier) DeleteOldAgentStats(ctx context.Context) error {
 	return err
 }
 
+const getDailyAgentStats = `-- name: GetDailyAgentStats :many
+SELECT
+	(created_at at TIME ZONE 'UTC')::date AS date,
+	template_id,
+	user_id,
+	SUM(connection_count)::bigint AS connection_count,
+	SUM(rx_bytes)::bigint AS rx_bytes,
+	SUM(tx_bytes)::bigint AS tx_bytes,
+	SUM(session_count_ssh)::bigint AS session_count_ssh,
+	SUM(session_count_vscode)::bigint AS session_count_vscode,
+	SUM(session_count_jetbrains)::bigint AS session_count_jetbrains,
+	SUM(session_count_reconnecting_pty)::bigint AS session_count_reconnecting_pty,
+	SUM(session_count_port_forward)::bigint AS session_count_port_forward,
+	SUM(session_duration_seconds)::float AS session_duration_seconds,
+	SUM(latency_ms)::float AS total_latency_ms,
+	COUNT(*) FILTER (WHERE latency_ms > 0) AS latency_samples
+FROM
+	agent_stats
+WHERE
+	created_at > $1
+GROUP BY
+	date, template_id, user_id
+ORDER BY
+	date ASC
+`
+
+type GetDailyAgentStatsRow struct {
+	Date                        time.Time `db:"date" json:"date"`
+	TemplateID                  uuid.UUID `db:"template_id" json:"template_id"`
+	UserID                      uuid.UUID `db:"user_id" json:"user_id"`
+	ConnectionCount             int64     `db:"connection_count" json:"connection_count"`
+	RxBytes                     int64     `db:"rx_bytes" json:"rx_bytes"`
+	TxBytes                     int64     `db:"tx_bytes" json:"tx_bytes"`
+	SessionCountSSH             int64     `db:"session_count_ssh" json:"session_count_ssh"`
+	SessionCountVSCode          int64     `db:"session_count_vscode" json:"session_count_vscode"`
+	SessionCountJetBrains       int64     `db:"session_count_jetbrains" json:"session_count_jetbrains"`
+	SessionCountReconnectingPTY int64     `db:"session_count_reconnecting_pty" json:"session_count_reconnecting_pty"`
+	SessionCountPortForward     int64     `db:"session_count_port_forward" json:"session_count_port_forward"`
+	SessionDurationSeconds      float64   `db:"session_duration_seconds" json:"session_duration_seconds"`
+	TotalLatencyMS              float64   `db:"total_latency_ms" json:"total_latency_ms"`
+	LatencySamples              int64     `db:"latency_samples" json:"latency_samples"`
+}
+
+// Stats are summed per day, template, and user so callers can aggregate them
+// further. The average latency is total_latency_ms / latency_samples.
+func (q *sqlQuerier) GetDailyAgentStats(ctx context.Context, createdAfter time.Time) ([]GetDailyAgentStatsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getDailyAgentStats, createdAfter)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetDailyAgentStatsRow
+	for rows.Next() {
+		var i GetDailyAgentStatsRow
+		if err := rows.Scan(
+			&i.Date,
+			&i.TemplateID,
+			&i.UserID,
+			&i.ConnectionCount,
+			&i.RxBytes,
+			&i.TxBytes,
+			&i.SessionCountSSH,
+			&i.SessionCountVSCode,
+			&i.SessionCountJetBrains,
+			&i.SessionCountReconnectingPTY,
+			&i.SessionCountPortForward,
+			&i.SessionDurationSeconds,
+			&i.TotalLatencyMS,
+			&i.LatencySamples,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getLatestAgentStat = `-- name: GetLatestAgentStat :one
-SELECT id, created_at, user_id, agent_id, workspace_id, template_id, payload FROM agent_stats WHERE agent_id = $1 ORDER BY created_at DESC LIMIT 1
+SELECT id, created_at, user_id, agent_id, workspace_id, template_id, payload, connection_count, rx_bytes, tx_bytes, session_count_ssh, session_count_vscode, session_count_jetbrains, session_count_reconnecting_pty, session_count_port_forward, session_duration_seconds, latency_ms FROM agent_stats WHERE agent_id = $1 ORDER BY created_at DESC LIMIT 1
 `
 
 func (q *sqlQuerier) GetLatestAgentStat(ctx context.Context, agentID uuid.UUID) (AgentStat, error) {
@@ -39,6 +122,16 @@ func (q *sqlQuerier) GetLatestAgentStat(ctx context.Context, agentID uuid.UUID)
 		&i.WorkspaceID,
 		&i.TemplateID,
 		&i.Payload,
+		&i.ConnectionCount,
+		&i.RxBytes,
+		&i.TxBytes,
+		&i.SessionCountSSH,
+		&i.SessionCountVSCode,
+		&i.SessionCountJetBrains,
+		&i.SessionCountReconnectingPTY,
+		&i.SessionCountPortForward,
+		&i.SessionDurationSeconds,
+		&i.LatencyMS,
 	)
 	return i, err
 }
@@ -93,20 +186,40 @@ INSERT INTO
 		workspace_id,
 		template_id,
 		agent_id,
-		payload
+		payload,
+		connection_count,
+		rx_bytes,
+		tx_bytes,
+		session_count_ssh,
+		session_count_vscode,
+		session_count_jetbrains,
+		session_count_reconnecting_pty,
+		session_count_port_forward,
+		session_duration_seconds,
+		latency_ms
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7) RETURNING id, created_at, user_id, agent_id, workspace_id, template_id, payload
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17) RETURNING id, created_at, user_id, agent_id, workspace_id, template_id, payload, connection_count, rx_bytes, tx_bytes, session_count_ssh, session_count_vscode, session_count_jetbrains, session_count_reconnecting_pty, session_count_port_forward, session_duration_seconds, latency_ms
 `
 
 type InsertAgentStatParams struct {
-	ID          uuid.UUID       `db:"id" json:"id"`
-	CreatedAt   time.Time       `db:"created_at" json:"created_at"`
-	UserID      uuid.UUID       `db:"user_id" json:"user_id"`
-	WorkspaceID uuid.UUID       `db:"workspace_id" json:"workspace_id"`
-	TemplateID  uuid.UUID       `db:"template_id" json:"template_id"`
-	AgentID     uuid.UUID       `db:"agent_id" json:"agent_id"`
-	Payload     json.RawMessage `db:"payload" json:"payload"`
+	ID                          uuid.UUID       `db:"id" json:"id"`
+	CreatedAt                   time.Time       `db:"created_at" json:"created_at"`
+	UserID                      uuid.UUID       `db:"user_id" json:"user_id"`
+	WorkspaceID                 uuid.UUID       `db:"workspace_id" json:"workspace_id"`
+	TemplateID                  uuid.UUID       `db:"template_id" json:"template_id"`
+	AgentID                     uuid.UUID       `db:"agent_id" json:"agent_id"`
+	Payload                     json.RawMessage `db:"payload" json:"payload"`
+	ConnectionCount             int64           `db:"connection_count" json:"connection_count"`
+	RxBytes                     int64           `db:"rx_bytes" json:"rx_bytes"`
+	TxBytes                     int64           `db:"tx_bytes" json:"tx_bytes"`
+	SessionCountSSH             int64           `db:"session_count_ssh" json:"session_count_ssh"`
+	SessionCountVSCode          int64           `db:"session_count_vscode" json:"session_count_vscode"`
+	SessionCountJetBrains       int64           `db:"session_count_jetbrains" json:"session_count_jetbrains"`
+	SessionCountReconnectingPTY int64           `db:"session_count_reconnecting_pty" json:"session_count_reconnecting_pty"`
+	SessionCountPortForward     int64           `db:"session_count_port_forward" json:"session_count_port_forward"`
+	SessionDurationSeconds      float64         `db:"session_duration_seconds" json:"session_duration_seconds"`
+	LatencyMS                   float64         `db:"latency_ms" json:"latency_ms"`
 }
 
 func (q *sqlQuerier) InsertAgentStat(ctx context.Context, arg InsertAgentStatParams) (AgentStat, error) {
@@ -118,6 +231,16 @@ func (q *sqlQuerier) InsertAgentStat(ctx context.Context, arg InsertAgentStatPar
 		arg.TemplateID,
 		arg.AgentID,
 		arg.Payload,
+		arg.ConnectionCount,
+		arg.RxBytes,
+		arg.TxBytes,
+		arg.SessionCountSSH,
+		arg.SessionCountVSCode,
+		arg.SessionCountJetBrains,
+		arg.SessionCountReconnectingPTY,
+		arg.SessionCountPortForward,
+		arg.SessionDurationSeconds,
+		arg.LatencyMS,
 	)
 	var i AgentStat
 	err := row.Scan(
@@ -128,6 +251,16 @@ func (q *sqlQuerier) InsertAgentStat(ctx context.Context, arg InsertAgentStatPar
 		&i.WorkspaceID,
 		&i.TemplateID,
 		&i.Payload,
+		&i.ConnectionCount,
+		&i.RxBytes,
+		&i.TxBytes,
+		&i.SessionCountSSH,
+		&i.SessionCountVSCode,
+		&i.SessionCountJetBrains,
+		&i.SessionCountReconnectingPTY,
+		&i.SessionCountPortForward,
+		&i.SessionDurationSeconds,
+		&i.LatencyMS,
 	)
 	return i, err
 }
@@ -147,7 +280,7 @@ func (q *sqlQuerier) DeleteAPIKeyByID(ctx context.Context, id string) error {
 
 const getAPIKeyByID = `-- name: GetAPIKeyByID :one
 SELECT
-	id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope
+	id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name
 FROM
 	api_keys
 WHERE
@@ -171,12 +304,58 @@ func (q *sqlQuerier) GetAPIKeyByID(ctx context.Context, id string) (APIKey, erro
 		&i.LifetimeSeconds,
 		&i.IPAddress,
 		&i.Scope,
+		&i.TokenName,
 	)
 	return i, err
 }
 
+const getAPIKeysByUserID = `-- name: GetAPIKeysByUserID :many
+SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name FROM api_keys WHERE login_type = $1 AND user_id = $2
+`
+
+type GetAPIKeysByUserIDParams struct {
+	LoginType LoginType `db:"login_type" json:"login_type"`
+	UserID    uuid.UUID `db:"user_id" json:"user_id"`
+}
+
+func (q *sqlQuerier) GetAPIKeysByUserID(ctx context.Context, arg GetAPIKeysByUserIDParams) ([]APIKey, error) {
+	rows, err := q.db.QueryContext(ctx, getAPIKeysByUserID, arg.LoginType, arg.UserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []APIKey
+	for rows.Next() {
+		var i APIKey
+		if err := rows.Scan(
+			&i.ID,
+			&i.HashedSecret,
+			&i.UserID,
+			&i.LastUsed,
+			&i.ExpiresAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.LoginType,
+			&i.LifetimeSeconds,
+			&i.IPAddress,
+			&i.Scope,
+			&i.TokenName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getAPIKeysLastUsedAfter = `-- name: GetAPIKeysLastUsedAfter :many
-SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope FROM api_keys WHERE last_used > $1
+SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name FROM api_keys WHERE last_used > $1
 `
 
 func (q *sqlQuerier) GetAPIKeysLastUsedAfter(ctx context.Context, lastUsed time.Time) ([]APIKey, error) {
@@ -200,6 +379,7 @@ func (q *sqlQuerier) GetAPIKeysLastUsedAfter(ctx context.Context, lastUsed time.
 			&i.LifetimeSeconds,
 			&i.IPAddress,
 			&i.Scope,
+			&i.TokenName,
 		); err != nil {
 			return nil, err
 		}
@@ -227,7 +407,8 @@ INSERT INTO
 		created_at,
 		updated_at,
 		login_type,
-		scope
+		scope,
+		token_name
 	)
 VALUES
 	($1,
@@ -236,7 +417,7 @@ VALUES
 	     WHEN 0 THEN 86400
 		 ELSE $2::bigint
 	 END
-	 , $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope
+	 , $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name
 `
 
 type InsertAPIKeyParams struct {
@@ -251,6 +432,7 @@ type InsertAPIKeyParams struct {
 	UpdatedAt       time.Time   `db:"updated_at" json:"updated_at"`
 	LoginType       LoginType   `db:"login_type" json:"login_type"`
 	Scope           APIKeyScope `db:"scope" json:"scope"`
+	TokenName       string      `db:"token_name" json:"token_name"`
 }
 
 func (q *sqlQuerier) InsertAPIKey(ctx context.Context, arg InsertAPIKeyParams) (APIKey, error) {
@@ -266,6 +448,7 @@ func (q *sqlQuerier) InsertAPIKey(ctx context.Context, arg InsertAPIKeyParams) (
 		arg.UpdatedAt,
 		arg.LoginType,
 		arg.Scope,
+		arg.TokenName,
 	)
 	var i APIKey
 	err := row.Scan(
@@ -280,6 +463,7 @@ func (q *sqlQuerier) InsertAPIKey(ctx context.Context, arg InsertAPIKeyParams) (
 		&i.LifetimeSeconds,
 		&i.IPAddress,
 		&i.Scope,
+		&i.TokenName,
 	)
 	return i, err
 }
@@ -354,6 +538,18 @@ WHERE
 			user_id = (SELECT id from users WHERE users.email = $6 )
 		ELSE true
 	END
+	-- Filter by time_after
+	AND CASE
+		WHEN $7 :: timestamp with time zone != '0001-01-01 00:00:00Z' THEN
+			"time" >= $7
+		ELSE true
+	END
+	-- Filter by time_before
+	AND CASE
+		WHEN $8 :: timestamp with time zone != '0001-01-01 00:00:00Z' THEN
+			"time" < $8
+		ELSE true
+	END
 `
 
 type GetAuditLogCountParams struct {
@@ -363,6 +559,8 @@ type GetAuditLogCountParams struct {
 	Action         string    `db:"action" json:"action"`
 	Username       string    `db:"username" json:"username"`
 	Email          string    `db:"email" json:"email"`
+	TimeAfter      time.Time `db:"time_after" json:"time_after"`
+	TimeBefore     time.Time `db:"time_before" json:"time_before"`
 }
 
 func (q *sqlQuerier) GetAuditLogCount(ctx context.Context, arg GetAuditLogCountParams) (int64, error) {
@@ -373,6 +571,8 @@ func (q *sqlQuerier) GetAuditLogCount(ctx context.Context, arg GetAuditLogCountP
 		arg.Action,
 		arg.Username,
 		arg.Email,
+		arg.TimeAfter,
+		arg.TimeBefore,
 	)
 	var count int64
 	err := row.Scan(&count)
@@ -429,8 +629,23 @@ WHERE
 			users.email = $8
 		ELSE true
 	END
+	-- Filter by time_after
+	AND CASE
+		WHEN $9 :: timestamp with time zone != '0001-01-01 00:00:00Z' THEN
+			"time" >= $9
+		ELSE true
+	END
+	-- Filter by time_before
+	AND CASE
+		WHEN $10 :: timestamp with time zone != '0001-01-01 00:00:00Z' THEN
+			"time" < $10
+		ELSE true
+	END
 ORDER BY
-    "time" DESC
+    "time" DESC,
+    -- Logs can share a time, so the ID keeps the order stable between
+    -- pages.
+    id DESC
 LIMIT
     $1
 OFFSET
@@ -446,6 +661,8 @@ type GetAuditLogsOffsetParams struct {
 	Action         string    `db:"action" json:"action"`
 	Username       string    `db:"username" json:"username"`
 	Email          string    `db:"email" json:"email"`
+	TimeAfter      time.Time `db:"time_after" json:"time_after"`
+	TimeBefore     time.Time `db:"time_before" json:"time_before"`
 }
 
 type GetAuditLogsOffsetRow struct {
@@ -484,6 +701,8 @@ func (q *sqlQuerier) GetAuditLogsOffset(ctx context.Context, arg GetAuditLogsOff
 		arg.Action,
 		arg.Username,
 		arg.Email,
+		arg.TimeAfter,
+		arg.TimeBefore,
 	)
 	if err != nil {
 		return nil, err
@@ -768,6 +987,288 @@ func (q *sqlQuerier) UpdateGitSSHKey(ctx context.Context, arg UpdateGitSSHKeyPar
 	return err
 }
 
+const deleteGroupByID = `-- name: DeleteGroupByID :exec
+DELETE FROM
+	groups
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) DeleteGroupByID(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteGroupByID, id)
+	return err
+}
+
+const deleteGroupMemberFromGroup = `-- name: DeleteGroupMemberFromGroup :exec
+DELETE FROM
+	group_members
+WHERE
+	user_id = $1 AND
+	group_id = $2
+`
+
+type DeleteGroupMemberFromGroupParams struct {
+	UserID  uuid.UUID `db:"user_id" json:"user_id"`
+	GroupID uuid.UUID `db:"group_id" json:"group_id"`
+}
+
+func (q *sqlQuerier) DeleteGroupMemberFromGroup(ctx context.Context, arg DeleteGroupMemberFromGroupParams) error {
+	_, err := q.db.ExecContext(ctx, deleteGroupMemberFromGroup, arg.UserID, arg.GroupID)
+	return err
+}
+
+const getGroupByID = `-- name: GetGroupByID :one
+SELECT
+	id, name, organization_id, quota_allowance
+FROM
+	groups
+WHERE
+	id = $1
+LIMIT
+	1
+`
+
+func (q *sqlQuerier) GetGroupByID(ctx context.Context, id uuid.UUID) (Group, error) {
+	row := q.db.QueryRowContext(ctx, getGroupByID, id)
+	var i Group
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.OrganizationID,
+		&i.QuotaAllowance,
+	)
+	return i, err
+}
+
+const getGroupByOrgAndName = `-- name: GetGroupByOrgAndName :one
+SELECT
+	id, name, organization_id, quota_allowance
+FROM
+	groups
+WHERE
+	organization_id = $1
+AND
+	name = $2
+LIMIT
+	1
+`
+
+type GetGroupByOrgAndNameParams struct {
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	Name           string    `db:"name" json:"name"`
+}
+
+func (q *sqlQuerier) GetGroupByOrgAndName(ctx context.Context, arg GetGroupByOrgAndNameParams) (Group, error) {
+	row := q.db.QueryRowContext(ctx, getGroupByOrgAndName, arg.OrganizationID, arg.Name)
+	var i Group
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.OrganizationID,
+		&i.QuotaAllowance,
+	)
+	return i, err
+}
+
+const getGroupMembers = `-- name: GetGroupMembers :many
+SELECT
+	users.id, users.email, users.username, users.hashed_password, users.created_at, users.updated_at, users.status, users.rbac_roles, users.login_type, users.avatar_url, users.deleted, users.quota_allowance
+FROM
+	users
+JOIN
+	group_members
+ON
+	users.id = group_members.user_id
+WHERE
+	group_members.group_id = $1
+AND
+	users.status = 'active'
+AND
+	users.deleted = 'false'
+`
+
+func (q *sqlQuerier) GetGroupMembers(ctx context.Context, groupID uuid.UUID) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, getGroupMembers, groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Email,
+			&i.Username,
+			&i.HashedPassword,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+			pq.Array(&i.RBACRoles),
+			&i.LoginType,
+			&i.AvatarURL,
+			&i.Deleted,
+			&i.QuotaAllowance,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getGroupsByOrganizationID = `-- name: GetGroupsByOrganizationID :many
+SELECT
+	id, name, organization_id, quota_allowance
+FROM
+	groups
+WHERE
+	organization_id = $1
+AND
+	id != $1
+`
+
+func (q *sqlQuerier) GetGroupsByOrganizationID(ctx context.Context, organizationID uuid.UUID) ([]Group, error) {
+	rows, err := q.db.QueryContext(ctx, getGroupsByOrganizationID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Group
+	for rows.Next() {
+		var i Group
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.OrganizationID,
+			&i.QuotaAllowance,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertAllUsersGroup = `-- name: InsertAllUsersGroup :one
+INSERT INTO groups (
+	id,
+	name,
+	organization_id
+)
+VALUES
+	( $1, 'Everyone', $1) RETURNING id, name, organization_id, quota_allowance
+`
+
+// We use the organization_id as the id
+// for simplicity since all users is
+// every member of the org.
+func (q *sqlQuerier) InsertAllUsersGroup(ctx context.Context, organizationID uuid.UUID) (Group, error) {
+	row := q.db.QueryRowContext(ctx, insertAllUsersGroup, organizationID)
+	var i Group
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.OrganizationID,
+		&i.QuotaAllowance,
+	)
+	return i, err
+}
+
+const insertGroup = `-- name: InsertGroup :one
+INSERT INTO groups (
+	id,
+	name,
+	organization_id,
+	quota_allowance
+)
+VALUES
+	( $1, $2, $3, $4) RETURNING id, name, organization_id, quota_allowance
+`
+
+type InsertGroupParams struct {
+	ID             uuid.UUID `db:"id" json:"id"`
+	Name           string    `db:"name" json:"name"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	QuotaAllowance int32     `db:"quota_allowance" json:"quota_allowance"`
+}
+
+func (q *sqlQuerier) InsertGroup(ctx context.Context, arg InsertGroupParams) (Group, error) {
+	row := q.db.QueryRowContext(ctx, insertGroup,
+		arg.ID,
+		arg.Name,
+		arg.OrganizationID,
+		arg.QuotaAllowance,
+	)
+	var i Group
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.OrganizationID,
+		&i.QuotaAllowance,
+	)
+	return i, err
+}
+
+const insertGroupMember = `-- name: InsertGroupMember :exec
+INSERT INTO group_members (
+	user_id,
+	group_id
+)
+VALUES ( $1, $2)
+`
+
+type InsertGroupMemberParams struct {
+	UserID  uuid.UUID `db:"user_id" json:"user_id"`
+	GroupID uuid.UUID `db:"group_id" json:"group_id"`
+}
+
+func (q *sqlQuerier) InsertGroupMember(ctx context.Context, arg InsertGroupMemberParams) error {
+	_, err := q.db.ExecContext(ctx, insertGroupMember, arg.UserID, arg.GroupID)
+	return err
+}
+
+const updateGroupByID = `-- name: UpdateGroupByID :one
+UPDATE
+	groups
+SET
+	name = $1,
+	quota_allowance = $2
+WHERE
+	id = $3
+RETURNING id, name, organization_id, quota_allowance
+`
+
+type UpdateGroupByIDParams struct {
+	Name           string    `db:"name" json:"name"`
+	QuotaAllowance int32     `db:"quota_allowance" json:"quota_allowance"`
+	ID             uuid.UUID `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) UpdateGroupByID(ctx context.Context, arg UpdateGroupByIDParams) (Group, error) {
+	row := q.db.QueryRowContext(ctx, updateGroupByID, arg.Name, arg.QuotaAllowance, arg.ID)
+	var i Group
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.OrganizationID,
+		&i.QuotaAllowance,
+	)
+	return i, err
+}
+
 const deleteLicense = `-- name: DeleteLicense :one
 DELETE
 FROM licenses
@@ -1622,9 +2123,21 @@ func (q *sqlQuerier) ParameterValues(ctx context.Context, arg ParameterValuesPar
 	return items, nil
 }
 
+const deleteProvisionerDaemonByID = `-- name: DeleteProvisionerDaemonByID :exec
+DELETE FROM
+	provisioner_daemons
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) DeleteProvisionerDaemonByID(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteProvisionerDaemonByID, id)
+	return err
+}
+
 const getProvisionerDaemonByID = `-- name: GetProvisionerDaemonByID :one
 SELECT
-	id, created_at, updated_at, name, provisioners
+	id, created_at, updated_at, name, provisioners, tags
 FROM
 	provisioner_daemons
 WHERE
@@ -1640,13 +2153,14 @@ func (q *sqlQuerier) GetProvisionerDaemonByID(ctx context.Context, id uuid.UUID)
 		&i.UpdatedAt,
 		&i.Name,
 		pq.Array(&i.Provisioners),
+		&i.Tags,
 	)
 	return i, err
 }
 
 const getProvisionerDaemons = `-- name: GetProvisionerDaemons :many
 SELECT
-	id, created_at, updated_at, name, provisioners
+	id, created_at, updated_at, name, provisioners, tags
 FROM
 	provisioner_daemons
 `
@@ -1666,6 +2180,7 @@ func (q *sqlQuerier) GetProvisionerDaemons(ctx context.Context) ([]ProvisionerDa
 			&i.UpdatedAt,
 			&i.Name,
 			pq.Array(&i.Provisioners),
+			&i.Tags,
 		); err != nil {
 			return nil, err
 		}
@@ -1686,10 +2201,11 @@ INSERT INTO
 		id,
 		created_at,
 		"name",
-		provisioners
+		provisioners,
+		tags
 	)
 VALUES
-	($1, $2, $3, $4) RETURNING id, created_at, updated_at, name, provisioners
+	($1, $2, $3, $4, $5) RETURNING id, created_at, updated_at, name, provisioners, tags
 `
 
 type InsertProvisionerDaemonParams struct {
@@ -1697,6 +2213,7 @@ type InsertProvisionerDaemonParams struct {
 	CreatedAt    time.Time         `db:"created_at" json:"created_at"`
 	Name         string            `db:"name" json:"name"`
 	Provisioners []ProvisionerType `db:"provisioners" json:"provisioners"`
+	Tags         StringMap         `db:"tags" json:"tags"`
 }
 
 func (q *sqlQuerier) InsertProvisionerDaemon(ctx context.Context, arg InsertProvisionerDaemonParams) (ProvisionerDaemon, error) {
@@ -1705,6 +2222,7 @@ func (q *sqlQuerier) InsertProvisionerDaemon(ctx context.Context, arg InsertProv
 		arg.CreatedAt,
 		arg.Name,
 		pq.Array(arg.Provisioners),
+		arg.Tags,
 	)
 	var i ProvisionerDaemon
 	err := row.Scan(
@@ -1713,6 +2231,7 @@ func (q *sqlQuerier) InsertProvisionerDaemon(ctx context.Context, arg InsertProv
 		&i.UpdatedAt,
 		&i.Name,
 		pq.Array(&i.Provisioners),
+		&i.Tags,
 	)
 	return i, err
 }
@@ -1870,29 +2389,80 @@ WHERE
 			AND nested.canceled_at IS NULL
 			AND nested.completed_at IS NULL
 			AND nested.provisioner = ANY($3 :: provisioner_type [ ])
+			AND nested.tags <@ $4 :: jsonb
+			-- Builds that stop a workspace wait for its connected agents
+			-- to run their shutdown scripts, up to the script's timeout.
+			AND NOT EXISTS (
+				SELECT
+					1
+				FROM
+					workspace_builds
+				JOIN
+					workspace_builds AS previous_build
+				ON
+					previous_build.workspace_id = workspace_builds.workspace_id
+					AND previous_build.build_number = workspace_builds.build_number - 1
+				JOIN
+					workspace_resources
+				ON
+					workspace_resources.job_id = previous_build.job_id
+				JOIN
+					workspace_agents
+				ON
+					workspace_agents.resource_id = workspace_resources.id
+				WHERE
+					workspace_builds.job_id = nested.id
+					AND workspace_builds.transition != 'start'
+					AND workspace_agents.shutdown_script IS NOT NULL
+					AND workspace_agents.lifecycle_state NOT IN ('shutdown_timeout', 'shutdown_error', 'off')
+					AND workspace_agents.last_connected_at > $5 :: timestamptz
+					AND nested.created_at + make_interval(secs => LEAST(
+						NULLIF(workspace_agents.shutdown_script_timeout_seconds, 0),
+						$6 :: integer
+					) + $7 :: integer) > $1
+			)
 		ORDER BY
 			nested.created_at FOR
 		UPDATE
 			SKIP LOCKED
 		LIMIT
 			1
-	) RETURNING id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, storage_source, type, input, worker_id
+	) RETURNING id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, storage_source, type, input, worker_id, tags
 `
 
 type AcquireProvisionerJobParams struct {
-	StartedAt sql.NullTime      `db:"started_at" json:"started_at"`
-	WorkerID  uuid.NullUUID     `db:"worker_id" json:"worker_id"`
-	Types     []ProvisionerType `db:"types" json:"types"`
+	StartedAt                      sql.NullTime      `db:"started_at" json:"started_at"`
+	WorkerID                       uuid.NullUUID     `db:"worker_id" json:"worker_id"`
+	Types                          []ProvisionerType `db:"types" json:"types"`
+	Tags                           json.RawMessage   `db:"tags" json:"tags"`
+	AgentConnectedAfter            time.Time         `db:"agent_connected_after" json:"agent_connected_after"`
+	AgentShutdownMaxTimeoutSeconds int32             `db:"agent_shutdown_max_timeout_seconds" json:"agent_shutdown_max_timeout_seconds"`
+	AgentShutdownGraceSeconds      int32             `db:"agent_shutdown_grace_seconds" json:"agent_shutdown_grace_seconds"`
 }
 
 // Acquires the lock for a single job that isn't started, completed,
 // canceled, and that matches an array of provisioner types.
 //
+// Jobs are only acquired by daemons whose tags are a superset of
+// the job's tags. Untagged daemons only acquire untagged jobs.
+//
+// Jobs that stop a workspace aren't acquired until the agents of the
+// previous build have run their shutdown scripts, so resources aren't
+// destroyed while a script is running and no daemon is held waiting.
+//
 // SKIP LOCKED is used to jump over locked rows. This prevents
 // multiple provisioners from acquiring the same jobs. See:
 // https://www.postgresql.org/docs/9.5/sql-select.html#SQL-FOR-UPDATE-SHARE
 func (q *sqlQuerier) AcquireProvisionerJob(ctx context.Context, arg AcquireProvisionerJobParams) (ProvisionerJob, error) {
-	row := q.db.QueryRowContext(ctx, acquireProvisionerJob, arg.StartedAt, arg.WorkerID, pq.Array(arg.Types))
+	row := q.db.QueryRowContext(ctx, acquireProvisionerJob,
+		arg.StartedAt,
+		arg.WorkerID,
+		pq.Array(arg.Types),
+		arg.Tags,
+		arg.AgentConnectedAfter,
+		arg.AgentShutdownMaxTimeoutSeconds,
+		arg.AgentShutdownGraceSeconds,
+	)
 	var i ProvisionerJob
 	err := row.Scan(
 		&i.ID,
@@ -1910,13 +2480,14 @@ func (q *sqlQuerier) AcquireProvisionerJob(ctx context.Context, arg AcquireProvi
 		&i.Type,
 		&i.Input,
 		&i.WorkerID,
+		&i.Tags,
 	)
 	return i, err
 }
 
 const getProvisionerJobByID = `-- name: GetProvisionerJobByID :one
 SELECT
-	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, storage_source, type, input, worker_id
+	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, storage_source, type, input, worker_id, tags
 FROM
 	provisioner_jobs
 WHERE
@@ -1942,13 +2513,14 @@ func (q *sqlQuerier) GetProvisionerJobByID(ctx context.Context, id uuid.UUID) (P
 		&i.Type,
 		&i.Input,
 		&i.WorkerID,
+		&i.Tags,
 	)
 	return i, err
 }
 
 const getProvisionerJobsByIDs = `-- name: GetProvisionerJobsByIDs :many
 SELECT
-	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, storage_source, type, input, worker_id
+	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, storage_source, type, input, worker_id, tags
 FROM
 	provisioner_jobs
 WHERE
@@ -1980,6 +2552,7 @@ func (q *sqlQuerier) GetProvisionerJobsByIDs(ctx context.Context, ids []uuid.UUI
 			&i.Type,
 			&i.Input,
 			&i.WorkerID,
+			&i.Tags,
 		); err != nil {
 			return nil, err
 		}
@@ -2023,6 +2596,7 @@ func (q *sqlQuerier) GetProvisionerJobsCreatedAfter(ctx context.Context, created
 			&i.Type,
 			&i.Input,
 			&i.WorkerID,
+			&i.Tags,
 		); err != nil {
 			return nil, err
 		}
@@ -2049,10 +2623,11 @@ INSERT INTO
 		storage_method,
 		storage_source,
 		"type",
-		"input"
+		"input",
+		tags
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, storage_source, type, input, worker_id
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, storage_source, type, input, worker_id, tags
 `
 
 type InsertProvisionerJobParams struct {
@@ -2066,6 +2641,7 @@ type InsertProvisionerJobParams struct {
 	StorageSource  string                   `db:"storage_source" json:"storage_source"`
 	Type           ProvisionerJobType       `db:"type" json:"type"`
 	Input          json.RawMessage          `db:"input" json:"input"`
+	Tags           StringMap                `db:"tags" json:"tags"`
 }
 
 func (q *sqlQuerier) InsertProvisionerJob(ctx context.Context, arg InsertProvisionerJobParams) (ProvisionerJob, error) {
@@ -2080,6 +2656,7 @@ func (q *sqlQuerier) InsertProvisionerJob(ctx context.Context, arg InsertProvisi
 		arg.StorageSource,
 		arg.Type,
 		arg.Input,
+		arg.Tags,
 	)
 	var i ProvisionerJob
 	err := row.Scan(
@@ -2098,6 +2675,7 @@ func (q *sqlQuerier) InsertProvisionerJob(ctx context.Context, arg InsertProvisi
 		&i.Type,
 		&i.Input,
 		&i.WorkerID,
+		&i.Tags,
 	)
 	return i, err
 }
@@ -2135,37 +2713,290 @@ type UpdateProvisionerJobWithCancelByIDParams struct {
 	CanceledAt sql.NullTime `db:"canceled_at" json:"canceled_at"`
 }
 
-func (q *sqlQuerier) UpdateProvisionerJobWithCancelByID(ctx context.Context, arg UpdateProvisionerJobWithCancelByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateProvisionerJobWithCancelByID, arg.ID, arg.CanceledAt)
-	return err
+func (q *sqlQuerier) UpdateProvisionerJobWithCancelByID(ctx context.Context, arg UpdateProvisionerJobWithCancelByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateProvisionerJobWithCancelByID, arg.ID, arg.CanceledAt)
+	return err
+}
+
+const updateProvisionerJobWithCompleteByID = `-- name: UpdateProvisionerJobWithCompleteByID :exec
+UPDATE
+	provisioner_jobs
+SET
+	updated_at = $2,
+	completed_at = $3,
+	error = $4
+WHERE
+	id = $1
+`
+
+type UpdateProvisionerJobWithCompleteByIDParams struct {
+	ID          uuid.UUID      `db:"id" json:"id"`
+	UpdatedAt   time.Time      `db:"updated_at" json:"updated_at"`
+	CompletedAt sql.NullTime   `db:"completed_at" json:"completed_at"`
+	Error       sql.NullString `db:"error" json:"error"`
+}
+
+func (q *sqlQuerier) UpdateProvisionerJobWithCompleteByID(ctx context.Context, arg UpdateProvisionerJobWithCompleteByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateProvisionerJobWithCompleteByID,
+		arg.ID,
+		arg.UpdatedAt,
+		arg.CompletedAt,
+		arg.Error,
+	)
+	return err
+}
+
+const acquireLockForUserQuota = `-- name: AcquireLockForUserQuota :exec
+SELECT pg_advisory_xact_lock(hashtext('quota:' || $1::uuid))
+`
+
+// Serializes quota checks for a user, so builds acquired at the same time
+// can't both spend the remaining quota. The lock is released when the
+// transaction ends.
+func (q *sqlQuerier) AcquireLockForUserQuota(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, acquireLockForUserQuota, userID)
+	return err
+}
+
+const getQuotaAllowanceForUser = `-- name: GetQuotaAllowanceForUser :one
+SELECT
+	(
+		-- Allowances granted to the user directly.
+		(SELECT coalesce(SUM(quota_allowance), 0) FROM users WHERE id = $1)
+		+
+		(
+			SELECT
+				coalesce(SUM(quota_allowance), 0)
+			FROM
+				groups
+			WHERE
+				id IN (SELECT group_id FROM group_members WHERE user_id = $1)
+			OR
+				-- Members of an organization are implicitly members of its
+				-- "Everyone" group, which shares the organization's ID.
+				id IN (SELECT organization_id FROM organization_members WHERE user_id = $1)
+		)
+	)::BIGINT
+`
+
+func (q *sqlQuerier) GetQuotaAllowanceForUser(ctx context.Context, userID uuid.UUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getQuotaAllowanceForUser, userID)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const getQuotaConsumedForUser = `-- name: GetQuotaConsumedForUser :one
+WITH latest_builds AS (
+SELECT
+	DISTINCT ON
+	(workspace_id) id,
+	workspace_id,
+	daily_cost
+FROM
+	workspace_builds wb
+ORDER BY
+	workspace_id,
+	created_at DESC
+)
+SELECT
+	coalesce(SUM(daily_cost), 0)::BIGINT
+FROM
+	workspaces
+JOIN latest_builds ON
+	latest_builds.workspace_id = workspaces.id
+WHERE NOT deleted AND workspaces.owner_id = $1
+`
+
+func (q *sqlQuerier) GetQuotaConsumedForUser(ctx context.Context, ownerID uuid.UUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getQuotaConsumedForUser, ownerID)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const deleteReplicasUpdatedBefore = `-- name: DeleteReplicasUpdatedBefore :exec
+DELETE FROM replicas WHERE updated_at < $1
+`
+
+func (q *sqlQuerier) DeleteReplicasUpdatedBefore(ctx context.Context, updatedAt time.Time) error {
+	_, err := q.db.ExecContext(ctx, deleteReplicasUpdatedBefore, updatedAt)
+	return err
+}
+
+const getReplicaByID = `-- name: GetReplicaByID :one
+SELECT id, created_at, started_at, stopped_at, updated_at, hostname, relay_address, database_latency, version, error FROM replicas WHERE id = $1
+`
+
+func (q *sqlQuerier) GetReplicaByID(ctx context.Context, id uuid.UUID) (Replica, error) {
+	row := q.db.QueryRowContext(ctx, getReplicaByID, id)
+	var i Replica
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.StartedAt,
+		&i.StoppedAt,
+		&i.UpdatedAt,
+		&i.Hostname,
+		&i.RelayAddress,
+		&i.DatabaseLatency,
+		&i.Version,
+		&i.Error,
+	)
+	return i, err
+}
+
+const getReplicasUpdatedAfter = `-- name: GetReplicasUpdatedAfter :many
+SELECT id, created_at, started_at, stopped_at, updated_at, hostname, relay_address, database_latency, version, error FROM replicas WHERE updated_at > $1 AND stopped_at IS NULL
+`
+
+func (q *sqlQuerier) GetReplicasUpdatedAfter(ctx context.Context, updatedAt time.Time) ([]Replica, error) {
+	rows, err := q.db.QueryContext(ctx, getReplicasUpdatedAfter, updatedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Replica
+	for rows.Next() {
+		var i Replica
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.StartedAt,
+			&i.StoppedAt,
+			&i.UpdatedAt,
+			&i.Hostname,
+			&i.RelayAddress,
+			&i.DatabaseLatency,
+			&i.Version,
+			&i.Error,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const updateProvisionerJobWithCompleteByID = `-- name: UpdateProvisionerJobWithCompleteByID :exec
-UPDATE
-	provisioner_jobs
-SET
-	updated_at = $2,
-	completed_at = $3,
-	error = $4
-WHERE
-	id = $1
+const insertReplica = `-- name: InsertReplica :one
+INSERT INTO replicas (
+    id,
+    created_at,
+    started_at,
+    updated_at,
+    hostname,
+    relay_address,
+    version,
+    database_latency
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, created_at, started_at, stopped_at, updated_at, hostname, relay_address, database_latency, version, error
 `
 
-type UpdateProvisionerJobWithCompleteByIDParams struct {
-	ID          uuid.UUID      `db:"id" json:"id"`
-	UpdatedAt   time.Time      `db:"updated_at" json:"updated_at"`
-	CompletedAt sql.NullTime   `db:"completed_at" json:"completed_at"`
-	Error       sql.NullString `db:"error" json:"error"`
+type InsertReplicaParams struct {
+	ID              uuid.UUID `db:"id" json:"id"`
+	CreatedAt       time.Time `db:"created_at" json:"created_at"`
+	StartedAt       time.Time `db:"started_at" json:"started_at"`
+	UpdatedAt       time.Time `db:"updated_at" json:"updated_at"`
+	Hostname        string    `db:"hostname" json:"hostname"`
+	RelayAddress    string    `db:"relay_address" json:"relay_address"`
+	Version         string    `db:"version" json:"version"`
+	DatabaseLatency int32     `db:"database_latency" json:"database_latency"`
 }
 
-func (q *sqlQuerier) UpdateProvisionerJobWithCompleteByID(ctx context.Context, arg UpdateProvisionerJobWithCompleteByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateProvisionerJobWithCompleteByID,
+func (q *sqlQuerier) InsertReplica(ctx context.Context, arg InsertReplicaParams) (Replica, error) {
+	row := q.db.QueryRowContext(ctx, insertReplica,
 		arg.ID,
+		arg.CreatedAt,
+		arg.StartedAt,
 		arg.UpdatedAt,
-		arg.CompletedAt,
+		arg.Hostname,
+		arg.RelayAddress,
+		arg.Version,
+		arg.DatabaseLatency,
+	)
+	var i Replica
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.StartedAt,
+		&i.StoppedAt,
+		&i.UpdatedAt,
+		&i.Hostname,
+		&i.RelayAddress,
+		&i.DatabaseLatency,
+		&i.Version,
+		&i.Error,
+	)
+	return i, err
+}
+
+const updateReplica = `-- name: UpdateReplica :one
+UPDATE replicas SET
+    updated_at = $2,
+    started_at = $3,
+    stopped_at = $4,
+    relay_address = $5,
+    hostname = $6,
+    version = $7,
+    error = $8,
+    database_latency = $9
+WHERE id = $1 RETURNING id, created_at, started_at, stopped_at, updated_at, hostname, relay_address, database_latency, version, error
+`
+
+type UpdateReplicaParams struct {
+	ID              uuid.UUID    `db:"id" json:"id"`
+	UpdatedAt       time.Time    `db:"updated_at" json:"updated_at"`
+	StartedAt       time.Time    `db:"started_at" json:"started_at"`
+	StoppedAt       sql.NullTime `db:"stopped_at" json:"stopped_at"`
+	RelayAddress    string       `db:"relay_address" json:"relay_address"`
+	Hostname        string       `db:"hostname" json:"hostname"`
+	Version         string       `db:"version" json:"version"`
+	Error           string       `db:"error" json:"error"`
+	DatabaseLatency int32        `db:"database_latency" json:"database_latency"`
+}
+
+func (q *sqlQuerier) UpdateReplica(ctx context.Context, arg UpdateReplicaParams) (Replica, error) {
+	row := q.db.QueryRowContext(ctx, updateReplica,
+		arg.ID,
+		arg.UpdatedAt,
+		arg.StartedAt,
+		arg.StoppedAt,
+		arg.RelayAddress,
+		arg.Hostname,
+		arg.Version,
 		arg.Error,
+		arg.DatabaseLatency,
 	)
-	return err
+	var i Replica
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.StartedAt,
+		&i.StoppedAt,
+		&i.UpdatedAt,
+		&i.Hostname,
+		&i.RelayAddress,
+		&i.DatabaseLatency,
+		&i.Version,
+		&i.Error,
+	)
+	return i, err
+}
+
+const getDERPMeshKey = `-- name: GetDERPMeshKey :one
+SELECT value FROM site_configs WHERE key = 'derp_mesh_key'
+`
+
+func (q *sqlQuerier) GetDERPMeshKey(ctx context.Context) (string, error) {
+	row := q.db.QueryRowContext(ctx, getDERPMeshKey)
+	var value string
+	err := row.Scan(&value)
+	return value, err
 }
 
 const getDeploymentID = `-- name: GetDeploymentID :one
@@ -2179,6 +3010,16 @@ func (q *sqlQuerier) GetDeploymentID(ctx context.Context) (string, error) {
 	return value, err
 }
 
+const insertDERPMeshKey = `-- name: InsertDERPMeshKey :exec
+INSERT INTO site_configs (key, value) VALUES ('derp_mesh_key', $1)
+ON CONFLICT (key) DO NOTHING
+`
+
+func (q *sqlQuerier) InsertDERPMeshKey(ctx context.Context, value string) error {
+	_, err := q.db.ExecContext(ctx, insertDERPMeshKey, value)
+	return err
+}
+
 const insertDeploymentID = `-- name: InsertDeploymentID :exec
 INSERT INTO site_configs (key, value) VALUES ('deployment_id', $1)
 `
@@ -2190,7 +3031,7 @@ func (q *sqlQuerier) InsertDeploymentID(ctx context.Context, value string) error
 
 const getTemplateByID = `-- name: GetTemplateByID :one
 SELECT
-	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, max_ttl, min_autostart_interval, created_by, icon
+	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, max_ttl, min_autostart_interval, created_by, icon, user_acl, group_acl, require_active_version
 FROM
 	templates
 WHERE
@@ -2216,13 +3057,16 @@ func (q *sqlQuerier) GetTemplateByID(ctx context.Context, id uuid.UUID) (Templat
 		&i.MinAutostartInterval,
 		&i.CreatedBy,
 		&i.Icon,
+		&i.UserACL,
+		&i.GroupACL,
+		&i.RequireActiveVersion,
 	)
 	return i, err
 }
 
 const getTemplateByOrganizationAndName = `-- name: GetTemplateByOrganizationAndName :one
 SELECT
-	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, max_ttl, min_autostart_interval, created_by, icon
+	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, max_ttl, min_autostart_interval, created_by, icon, user_acl, group_acl, require_active_version
 FROM
 	templates
 WHERE
@@ -2256,12 +3100,15 @@ func (q *sqlQuerier) GetTemplateByOrganizationAndName(ctx context.Context, arg G
 		&i.MinAutostartInterval,
 		&i.CreatedBy,
 		&i.Icon,
+		&i.UserACL,
+		&i.GroupACL,
+		&i.RequireActiveVersion,
 	)
 	return i, err
 }
 
 const getTemplates = `-- name: GetTemplates :many
-SELECT id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, max_ttl, min_autostart_interval, created_by, icon FROM templates
+SELECT id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, max_ttl, min_autostart_interval, created_by, icon, user_acl, group_acl, require_active_version FROM templates
 ORDER BY (name, id) ASC
 `
 
@@ -2288,6 +3135,9 @@ func (q *sqlQuerier) GetTemplates(ctx context.Context) ([]Template, error) {
 			&i.MinAutostartInterval,
 			&i.CreatedBy,
 			&i.Icon,
+			&i.UserACL,
+			&i.GroupACL,
+			&i.RequireActiveVersion,
 		); err != nil {
 			return nil, err
 		}
@@ -2304,7 +3154,7 @@ func (q *sqlQuerier) GetTemplates(ctx context.Context) ([]Template, error) {
 
 const getTemplatesWithFilter = `-- name: GetTemplatesWithFilter :many
 SELECT
-	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, max_ttl, min_autostart_interval, created_by, icon
+	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, max_ttl, min_autostart_interval, created_by, icon, user_acl, group_acl, require_active_version
 FROM
 	templates
 WHERE
@@ -2366,6 +3216,9 @@ func (q *sqlQuerier) GetTemplatesWithFilter(ctx context.Context, arg GetTemplate
 			&i.MinAutostartInterval,
 			&i.CreatedBy,
 			&i.Icon,
+			&i.UserACL,
+			&i.GroupACL,
+			&i.RequireActiveVersion,
 		); err != nil {
 			return nil, err
 		}
@@ -2394,10 +3247,13 @@ INSERT INTO
 		max_ttl,
 		min_autostart_interval,
 		created_by,
-		icon
+		icon,
+		user_acl,
+		group_acl,
+		require_active_version
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, max_ttl, min_autostart_interval, created_by, icon
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15) RETURNING id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, max_ttl, min_autostart_interval, created_by, icon, user_acl, group_acl, require_active_version
 `
 
 type InsertTemplateParams struct {
@@ -2413,6 +3269,9 @@ type InsertTemplateParams struct {
 	MinAutostartInterval int64           `db:"min_autostart_interval" json:"min_autostart_interval"`
 	CreatedBy            uuid.UUID       `db:"created_by" json:"created_by"`
 	Icon                 string          `db:"icon" json:"icon"`
+	UserACL              TemplateACL     `db:"user_acl" json:"user_acl"`
+	GroupACL             TemplateACL     `db:"group_acl" json:"group_acl"`
+	RequireActiveVersion bool            `db:"require_active_version" json:"require_active_version"`
 }
 
 func (q *sqlQuerier) InsertTemplate(ctx context.Context, arg InsertTemplateParams) (Template, error) {
@@ -2429,7 +3288,52 @@ func (q *sqlQuerier) InsertTemplate(ctx context.Context, arg InsertTemplateParam
 		arg.MinAutostartInterval,
 		arg.CreatedBy,
 		arg.Icon,
+		arg.UserACL,
+		arg.GroupACL,
+		arg.RequireActiveVersion,
+	)
+	var i Template
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.OrganizationID,
+		&i.Deleted,
+		&i.Name,
+		&i.Provisioner,
+		&i.ActiveVersionID,
+		&i.Description,
+		&i.MaxTtl,
+		&i.MinAutostartInterval,
+		&i.CreatedBy,
+		&i.Icon,
+		&i.UserACL,
+		&i.GroupACL,
+		&i.RequireActiveVersion,
 	)
+	return i, err
+}
+
+const updateTemplateACLByID = `-- name: UpdateTemplateACLByID :one
+UPDATE
+	templates
+SET
+	group_acl = $1,
+	user_acl = $2
+WHERE
+	id = $3
+RETURNING
+	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, max_ttl, min_autostart_interval, created_by, icon, user_acl, group_acl, require_active_version
+`
+
+type UpdateTemplateACLByIDParams struct {
+	GroupACL TemplateACL `db:"group_acl" json:"group_acl"`
+	UserACL  TemplateACL `db:"user_acl" json:"user_acl"`
+	ID       uuid.UUID   `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) UpdateTemplateACLByID(ctx context.Context, arg UpdateTemplateACLByIDParams) (Template, error) {
+	row := q.db.QueryRowContext(ctx, updateTemplateACLByID, arg.GroupACL, arg.UserACL, arg.ID)
 	var i Template
 	err := row.Scan(
 		&i.ID,
@@ -2445,6 +3349,9 @@ func (q *sqlQuerier) InsertTemplate(ctx context.Context, arg InsertTemplateParam
 		&i.MinAutostartInterval,
 		&i.CreatedBy,
 		&i.Icon,
+		&i.UserACL,
+		&i.GroupACL,
+		&i.RequireActiveVersion,
 	)
 	return i, err
 }
@@ -2500,11 +3407,12 @@ SET
 	max_ttl = $4,
 	min_autostart_interval = $5,
 	name = $6,
-	icon = $7
+	icon = $7,
+	require_active_version = $8
 WHERE
 	id = $1
 RETURNING
-	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, max_ttl, min_autostart_interval, created_by, icon
+	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, max_ttl, min_autostart_interval, created_by, icon, user_acl, group_acl, require_active_version
 `
 
 type UpdateTemplateMetaByIDParams struct {
@@ -2515,6 +3423,7 @@ type UpdateTemplateMetaByIDParams struct {
 	MinAutostartInterval int64     `db:"min_autostart_interval" json:"min_autostart_interval"`
 	Name                 string    `db:"name" json:"name"`
 	Icon                 string    `db:"icon" json:"icon"`
+	RequireActiveVersion bool      `db:"require_active_version" json:"require_active_version"`
 }
 
 func (q *sqlQuerier) UpdateTemplateMetaByID(ctx context.Context, arg UpdateTemplateMetaByIDParams) (Template, error) {
@@ -2526,6 +3435,7 @@ func (q *sqlQuerier) UpdateTemplateMetaByID(ctx context.Context, arg UpdateTempl
 		arg.MinAutostartInterval,
 		arg.Name,
 		arg.Icon,
+		arg.RequireActiveVersion,
 	)
 	var i Template
 	err := row.Scan(
@@ -2542,13 +3452,16 @@ func (q *sqlQuerier) UpdateTemplateMetaByID(ctx context.Context, arg UpdateTempl
 		&i.MinAutostartInterval,
 		&i.CreatedBy,
 		&i.Icon,
+		&i.UserACL,
+		&i.GroupACL,
+		&i.RequireActiveVersion,
 	)
 	return i, err
 }
 
 const getTemplateVersionByID = `-- name: GetTemplateVersionByID :one
 SELECT
-	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by
+	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, message, archived
 FROM
 	template_versions
 WHERE
@@ -2568,13 +3481,15 @@ func (q *sqlQuerier) GetTemplateVersionByID(ctx context.Context, id uuid.UUID) (
 		&i.Readme,
 		&i.JobID,
 		&i.CreatedBy,
+		&i.Message,
+		&i.Archived,
 	)
 	return i, err
 }
 
 const getTemplateVersionByJobID = `-- name: GetTemplateVersionByJobID :one
 SELECT
-	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by
+	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, message, archived
 FROM
 	template_versions
 WHERE
@@ -2594,13 +3509,15 @@ func (q *sqlQuerier) GetTemplateVersionByJobID(ctx context.Context, jobID uuid.U
 		&i.Readme,
 		&i.JobID,
 		&i.CreatedBy,
+		&i.Message,
+		&i.Archived,
 	)
 	return i, err
 }
 
 const getTemplateVersionByTemplateIDAndName = `-- name: GetTemplateVersionByTemplateIDAndName :one
 SELECT
-	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by
+	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, message, archived
 FROM
 	template_versions
 WHERE
@@ -2626,13 +3543,15 @@ func (q *sqlQuerier) GetTemplateVersionByTemplateIDAndName(ctx context.Context,
 		&i.Readme,
 		&i.JobID,
 		&i.CreatedBy,
+		&i.Message,
+		&i.Archived,
 	)
 	return i, err
 }
 
 const getTemplateVersionsByTemplateID = `-- name: GetTemplateVersionsByTemplateID :many
 SELECT
-	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by
+	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, message, archived
 FROM
 	template_versions
 WHERE
@@ -2656,26 +3575,33 @@ WHERE
 		)
 		ELSE true
 	END
+	-- Archived versions are hidden unless requested.
+	AND CASE
+		WHEN $3 :: boolean THEN true
+		ELSE archived = false
+	END
 ORDER BY
     -- Deterministic and consistent ordering of all rows, even if they share
     -- a timestamp. This is to ensure consistent pagination.
-	(created_at, id) ASC OFFSET $3
+	(created_at, id) ASC OFFSET $4
 LIMIT
 	-- A null limit means "no limit", so 0 means return all
-	NULLIF($4 :: int, 0)
+	NULLIF($5 :: int, 0)
 `
 
 type GetTemplateVersionsByTemplateIDParams struct {
-	TemplateID uuid.UUID `db:"template_id" json:"template_id"`
-	AfterID    uuid.UUID `db:"after_id" json:"after_id"`
-	OffsetOpt  int32     `db:"offset_opt" json:"offset_opt"`
-	LimitOpt   int32     `db:"limit_opt" json:"limit_opt"`
+	TemplateID      uuid.UUID `db:"template_id" json:"template_id"`
+	AfterID         uuid.UUID `db:"after_id" json:"after_id"`
+	IncludeArchived bool      `db:"include_archived" json:"include_archived"`
+	OffsetOpt       int32     `db:"offset_opt" json:"offset_opt"`
+	LimitOpt        int32     `db:"limit_opt" json:"limit_opt"`
 }
 
 func (q *sqlQuerier) GetTemplateVersionsByTemplateID(ctx context.Context, arg GetTemplateVersionsByTemplateIDParams) ([]TemplateVersion, error) {
 	rows, err := q.db.QueryContext(ctx, getTemplateVersionsByTemplateID,
 		arg.TemplateID,
 		arg.AfterID,
+		arg.IncludeArchived,
 		arg.OffsetOpt,
 		arg.LimitOpt,
 	)
@@ -2696,6 +3622,8 @@ func (q *sqlQuerier) GetTemplateVersionsByTemplateID(ctx context.Context, arg Ge
 			&i.Readme,
 			&i.JobID,
 			&i.CreatedBy,
+			&i.Message,
+			&i.Archived,
 		); err != nil {
 			return nil, err
 		}
@@ -2711,7 +3639,7 @@ func (q *sqlQuerier) GetTemplateVersionsByTemplateID(ctx context.Context, arg Ge
 }
 
 const getTemplateVersionsCreatedAfter = `-- name: GetTemplateVersionsCreatedAfter :many
-SELECT id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by FROM template_versions WHERE created_at > $1
+SELECT id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, message, archived FROM template_versions WHERE created_at > $1
 `
 
 func (q *sqlQuerier) GetTemplateVersionsCreatedAfter(ctx context.Context, createdAt time.Time) ([]TemplateVersion, error) {
@@ -2733,6 +3661,8 @@ func (q *sqlQuerier) GetTemplateVersionsCreatedAfter(ctx context.Context, create
 			&i.Readme,
 			&i.JobID,
 			&i.CreatedBy,
+			&i.Message,
+			&i.Archived,
 		); err != nil {
 			return nil, err
 		}
@@ -2758,10 +3688,11 @@ INSERT INTO
 		"name",
 		readme,
 		job_id,
-		created_by
+		created_by,
+		message
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, message, archived
 `
 
 type InsertTemplateVersionParams struct {
@@ -2774,6 +3705,7 @@ type InsertTemplateVersionParams struct {
 	Readme         string        `db:"readme" json:"readme"`
 	JobID          uuid.UUID     `db:"job_id" json:"job_id"`
 	CreatedBy      uuid.NullUUID `db:"created_by" json:"created_by"`
+	Message        string        `db:"message" json:"message"`
 }
 
 func (q *sqlQuerier) InsertTemplateVersion(ctx context.Context, arg InsertTemplateVersionParams) (TemplateVersion, error) {
@@ -2787,6 +3719,7 @@ func (q *sqlQuerier) InsertTemplateVersion(ctx context.Context, arg InsertTempla
 		arg.Readme,
 		arg.JobID,
 		arg.CreatedBy,
+		arg.Message,
 	)
 	var i TemplateVersion
 	err := row.Scan(
@@ -2799,10 +3732,33 @@ func (q *sqlQuerier) InsertTemplateVersion(ctx context.Context, arg InsertTempla
 		&i.Readme,
 		&i.JobID,
 		&i.CreatedBy,
+		&i.Message,
+		&i.Archived,
 	)
 	return i, err
 }
 
+const updateTemplateVersionArchivedByID = `-- name: UpdateTemplateVersionArchivedByID :exec
+UPDATE
+	template_versions
+SET
+	archived = $2,
+	updated_at = $3
+WHERE
+	id = $1
+`
+
+type UpdateTemplateVersionArchivedByIDParams struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	Archived  bool      `db:"archived" json:"archived"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpdateTemplateVersionArchivedByID(ctx context.Context, arg UpdateTemplateVersionArchivedByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateTemplateVersionArchivedByID, arg.ID, arg.Archived, arg.UpdatedAt)
+	return err
+}
+
 const updateTemplateVersionByID = `-- name: UpdateTemplateVersionByID :exec
 UPDATE
 	template_versions
@@ -3035,7 +3991,17 @@ SELECT
 			array_append(users.rbac_roles, 'member'),
 		-- All org_members get the org-member role for their orgs
 			array_append(organization_members.roles, 'organization-member:'||organization_members.organization_id::text)) :: text[]
-		AS roles
+		AS roles,
+	-- All groups the user is a member of. The "Everyone" group is
+	-- implied by organization membership and is not stored here.
+	ARRAY(
+		SELECT
+			group_id :: text
+		FROM
+			group_members
+		WHERE
+			user_id = $1
+	) :: text[] AS groups
 FROM
 	users
 LEFT JOIN organization_members
@@ -3049,6 +4015,7 @@ type GetAuthorizationUserRolesRow struct {
 	Username string     `db:"username" json:"username"`
 	Status   UserStatus `db:"status" json:"status"`
 	Roles    []string   `db:"roles" json:"roles"`
+	Groups   []string   `db:"groups" json:"groups"`
 }
 
 // This function returns roles for authorization purposes. Implied member roles
@@ -3061,13 +4028,14 @@ func (q *sqlQuerier) GetAuthorizationUserRoles(ctx context.Context, userID uuid.
 		&i.Username,
 		&i.Status,
 		pq.Array(&i.Roles),
+		pq.Array(&i.Groups),
 	)
 	return i, err
 }
 
 const getUserByEmailOrUsername = `-- name: GetUserByEmailOrUsername :one
 SELECT
-	id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted
+	id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, quota_allowance
 FROM
 	users
 WHERE
@@ -3098,13 +4066,14 @@ func (q *sqlQuerier) GetUserByEmailOrUsername(ctx context.Context, arg GetUserBy
 		&i.LoginType,
 		&i.AvatarURL,
 		&i.Deleted,
+		&i.QuotaAllowance,
 	)
 	return i, err
 }
 
 const getUserByID = `-- name: GetUserByID :one
 SELECT
-	id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted
+	id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, quota_allowance
 FROM
 	users
 WHERE
@@ -3128,6 +4097,7 @@ func (q *sqlQuerier) GetUserByID(ctx context.Context, id uuid.UUID) (User, error
 		&i.LoginType,
 		&i.AvatarURL,
 		&i.Deleted,
+		&i.QuotaAllowance,
 	)
 	return i, err
 }
@@ -3148,7 +4118,7 @@ func (q *sqlQuerier) GetUserCount(ctx context.Context) (int64, error) {
 
 const getUsers = `-- name: GetUsers :many
 SELECT
-	id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted
+	id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, quota_allowance
 FROM
 	users
 WHERE
@@ -3246,6 +4216,7 @@ func (q *sqlQuerier) GetUsers(ctx context.Context, arg GetUsersParams) ([]User,
 			&i.LoginType,
 			&i.AvatarURL,
 			&i.Deleted,
+			&i.QuotaAllowance,
 		); err != nil {
 			return nil, err
 		}
@@ -3261,7 +4232,7 @@ func (q *sqlQuerier) GetUsers(ctx context.Context, arg GetUsersParams) ([]User,
 }
 
 const getUsersByIDs = `-- name: GetUsersByIDs :many
-SELECT id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted FROM users WHERE id = ANY($1 :: uuid [ ]) AND deleted = $2
+SELECT id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, quota_allowance FROM users WHERE id = ANY($1 :: uuid [ ]) AND deleted = $2
 `
 
 type GetUsersByIDsParams struct {
@@ -3290,6 +4261,7 @@ func (q *sqlQuerier) GetUsersByIDs(ctx context.Context, arg GetUsersByIDsParams)
 			&i.LoginType,
 			&i.AvatarURL,
 			&i.Deleted,
+			&i.QuotaAllowance,
 		); err != nil {
 			return nil, err
 		}
@@ -3317,7 +4289,7 @@ INSERT INTO
 		login_type
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted
+	($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, quota_allowance
 `
 
 type InsertUserParams struct {
@@ -3355,6 +4327,7 @@ func (q *sqlQuerier) InsertUser(ctx context.Context, arg InsertUserParams) (User
 		&i.LoginType,
 		&i.AvatarURL,
 		&i.Deleted,
+		&i.QuotaAllowance,
 	)
 	return i, err
 }
@@ -3406,7 +4379,7 @@ SET
 	avatar_url = $4,
 	updated_at = $5
 WHERE
-	id = $1 RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted
+	id = $1 RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, quota_allowance
 `
 
 type UpdateUserProfileParams struct {
@@ -3438,6 +4411,43 @@ func (q *sqlQuerier) UpdateUserProfile(ctx context.Context, arg UpdateUserProfil
 		&i.LoginType,
 		&i.AvatarURL,
 		&i.Deleted,
+		&i.QuotaAllowance,
+	)
+	return i, err
+}
+
+const updateUserQuotaAllowance = `-- name: UpdateUserQuotaAllowance :one
+UPDATE
+	users
+SET
+	quota_allowance = $2,
+	updated_at = $3
+WHERE
+	id = $1 RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, quota_allowance
+`
+
+type UpdateUserQuotaAllowanceParams struct {
+	ID             uuid.UUID `db:"id" json:"id"`
+	QuotaAllowance int32     `db:"quota_allowance" json:"quota_allowance"`
+	UpdatedAt      time.Time `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpdateUserQuotaAllowance(ctx context.Context, arg UpdateUserQuotaAllowanceParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserQuotaAllowance, arg.ID, arg.QuotaAllowance, arg.UpdatedAt)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Username,
+		&i.HashedPassword,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		pq.Array(&i.RBACRoles),
+		&i.LoginType,
+		&i.AvatarURL,
+		&i.Deleted,
+		&i.QuotaAllowance,
 	)
 	return i, err
 }
@@ -3450,7 +4460,7 @@ SET
 	rbac_roles = ARRAY(SELECT DISTINCT UNNEST($1 :: text[]))
 WHERE
 	id = $2
-RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted
+RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, quota_allowance
 `
 
 type UpdateUserRolesParams struct {
@@ -3473,6 +4483,7 @@ func (q *sqlQuerier) UpdateUserRoles(ctx context.Context, arg UpdateUserRolesPar
 		&i.LoginType,
 		&i.AvatarURL,
 		&i.Deleted,
+		&i.QuotaAllowance,
 	)
 	return i, err
 }
@@ -3484,7 +4495,7 @@ SET
 	status = $2,
 	updated_at = $3
 WHERE
-	id = $1 RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted
+	id = $1 RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, quota_allowance
 `
 
 type UpdateUserStatusParams struct {
@@ -3508,13 +4519,14 @@ func (q *sqlQuerier) UpdateUserStatus(ctx context.Context, arg UpdateUserStatusP
 		&i.LoginType,
 		&i.AvatarURL,
 		&i.Deleted,
+		&i.QuotaAllowance,
 	)
 	return i, err
 }
 
 const getWorkspaceAgentByAuthToken = `-- name: GetWorkspaceAgentByAuthToken :one
 SELECT
-	id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, startup_script, instance_metadata, resource_metadata, directory, version
+	id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, startup_script, instance_metadata, resource_metadata, directory, version, lifecycle_state, startup_script_timeout_seconds, startup_logs_length, startup_logs_overflowed, shutdown_script, shutdown_script_timeout_seconds, session_cpu_limit, session_memory_limit_mb, session_pids_limit, health_warnings
 FROM
 	workspace_agents
 WHERE
@@ -3545,13 +4557,23 @@ func (q *sqlQuerier) GetWorkspaceAgentByAuthToken(ctx context.Context, authToken
 		&i.ResourceMetadata,
 		&i.Directory,
 		&i.Version,
+		&i.LifecycleState,
+		&i.StartupScriptTimeoutSeconds,
+		&i.StartupLogsLength,
+		&i.StartupLogsOverflowed,
+		&i.ShutdownScript,
+		&i.ShutdownScriptTimeoutSeconds,
+		&i.SessionCPULimit,
+		&i.SessionMemoryLimitMB,
+		&i.SessionPidsLimit,
+		pq.Array(&i.HealthWarnings),
 	)
 	return i, err
 }
 
 const getWorkspaceAgentByID = `-- name: GetWorkspaceAgentByID :one
 SELECT
-	id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, startup_script, instance_metadata, resource_metadata, directory, version
+	id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, startup_script, instance_metadata, resource_metadata, directory, version, lifecycle_state, startup_script_timeout_seconds, startup_logs_length, startup_logs_overflowed, shutdown_script, shutdown_script_timeout_seconds, session_cpu_limit, session_memory_limit_mb, session_pids_limit, health_warnings
 FROM
 	workspace_agents
 WHERE
@@ -3580,13 +4602,23 @@ func (q *sqlQuerier) GetWorkspaceAgentByID(ctx context.Context, id uuid.UUID) (W
 		&i.ResourceMetadata,
 		&i.Directory,
 		&i.Version,
+		&i.LifecycleState,
+		&i.StartupScriptTimeoutSeconds,
+		&i.StartupLogsLength,
+		&i.StartupLogsOverflowed,
+		&i.ShutdownScript,
+		&i.ShutdownScriptTimeoutSeconds,
+		&i.SessionCPULimit,
+		&i.SessionMemoryLimitMB,
+		&i.SessionPidsLimit,
+		pq.Array(&i.HealthWarnings),
 	)
 	return i, err
 }
 
 const getWorkspaceAgentByInstanceID = `-- name: GetWorkspaceAgentByInstanceID :one
 SELECT
-	id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, startup_script, instance_metadata, resource_metadata, directory, version
+	id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, startup_script, instance_metadata, resource_metadata, directory, version, lifecycle_state, startup_script_timeout_seconds, startup_logs_length, startup_logs_overflowed, shutdown_script, shutdown_script_timeout_seconds, session_cpu_limit, session_memory_limit_mb, session_pids_limit, health_warnings
 FROM
 	workspace_agents
 WHERE
@@ -3595,35 +4627,178 @@ ORDER BY
 	created_at DESC
 `
 
-func (q *sqlQuerier) GetWorkspaceAgentByInstanceID(ctx context.Context, authInstanceID string) (WorkspaceAgent, error) {
-	row := q.db.QueryRowContext(ctx, getWorkspaceAgentByInstanceID, authInstanceID)
-	var i WorkspaceAgent
-	err := row.Scan(
-		&i.ID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Name,
-		&i.FirstConnectedAt,
-		&i.LastConnectedAt,
-		&i.DisconnectedAt,
-		&i.ResourceID,
-		&i.AuthToken,
-		&i.AuthInstanceID,
-		&i.Architecture,
-		&i.EnvironmentVariables,
-		&i.OperatingSystem,
-		&i.StartupScript,
-		&i.InstanceMetadata,
-		&i.ResourceMetadata,
-		&i.Directory,
-		&i.Version,
-	)
-	return i, err
+func (q *sqlQuerier) GetWorkspaceAgentByInstanceID(ctx context.Context, authInstanceID string) (WorkspaceAgent, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceAgentByInstanceID, authInstanceID)
+	var i WorkspaceAgent
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.FirstConnectedAt,
+		&i.LastConnectedAt,
+		&i.DisconnectedAt,
+		&i.ResourceID,
+		&i.AuthToken,
+		&i.AuthInstanceID,
+		&i.Architecture,
+		&i.EnvironmentVariables,
+		&i.OperatingSystem,
+		&i.StartupScript,
+		&i.InstanceMetadata,
+		&i.ResourceMetadata,
+		&i.Directory,
+		&i.Version,
+		&i.LifecycleState,
+		&i.StartupScriptTimeoutSeconds,
+		&i.StartupLogsLength,
+		&i.StartupLogsOverflowed,
+		&i.ShutdownScript,
+		&i.ShutdownScriptTimeoutSeconds,
+		&i.SessionCPULimit,
+		&i.SessionMemoryLimitMB,
+		&i.SessionPidsLimit,
+		pq.Array(&i.HealthWarnings),
+	)
+	return i, err
+}
+
+const getWorkspaceAgentMetadata = `-- name: GetWorkspaceAgentMetadata :many
+SELECT
+	workspace_agent_id, display_name, key, script, value, error, timeout, interval, collected_at
+FROM
+	workspace_agent_metadata
+WHERE
+	workspace_agent_id = $1
+ORDER BY
+	key ASC
+`
+
+func (q *sqlQuerier) GetWorkspaceAgentMetadata(ctx context.Context, workspaceAgentID uuid.UUID) ([]WorkspaceAgentMetadatum, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentMetadata, workspaceAgentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceAgentMetadatum
+	for rows.Next() {
+		var i WorkspaceAgentMetadatum
+		if err := rows.Scan(
+			&i.WorkspaceAgentID,
+			&i.DisplayName,
+			&i.Key,
+			&i.Script,
+			&i.Value,
+			&i.Error,
+			&i.Timeout,
+			&i.Interval,
+			&i.CollectedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkspaceAgentMetadataByAgentIDs = `-- name: GetWorkspaceAgentMetadataByAgentIDs :many
+SELECT
+	workspace_agent_id, display_name, key, script, value, error, timeout, interval, collected_at
+FROM
+	workspace_agent_metadata
+WHERE
+	workspace_agent_id = ANY($1 :: uuid [ ])
+ORDER BY
+	key ASC
+`
+
+func (q *sqlQuerier) GetWorkspaceAgentMetadataByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceAgentMetadatum, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentMetadataByAgentIDs, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceAgentMetadatum
+	for rows.Next() {
+		var i WorkspaceAgentMetadatum
+		if err := rows.Scan(
+			&i.WorkspaceAgentID,
+			&i.DisplayName,
+			&i.Key,
+			&i.Script,
+			&i.Value,
+			&i.Error,
+			&i.Timeout,
+			&i.Interval,
+			&i.CollectedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkspaceAgentStartupLogsAfter = `-- name: GetWorkspaceAgentStartupLogsAfter :many
+SELECT
+	id, agent_id, created_at, output
+FROM
+	workspace_agent_startup_logs
+WHERE
+	agent_id = $1
+	AND (
+		id > $2
+	) ORDER BY id ASC
+`
+
+type GetWorkspaceAgentStartupLogsAfterParams struct {
+	AgentID      uuid.UUID `db:"agent_id" json:"agent_id"`
+	CreatedAfter int64     `db:"created_after" json:"created_after"`
+}
+
+func (q *sqlQuerier) GetWorkspaceAgentStartupLogsAfter(ctx context.Context, arg GetWorkspaceAgentStartupLogsAfterParams) ([]WorkspaceAgentStartupLog, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentStartupLogsAfter, arg.AgentID, arg.CreatedAfter)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceAgentStartupLog
+	for rows.Next() {
+		var i WorkspaceAgentStartupLog
+		if err := rows.Scan(
+			&i.ID,
+			&i.AgentID,
+			&i.CreatedAt,
+			&i.Output,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
 const getWorkspaceAgentsByResourceIDs = `-- name: GetWorkspaceAgentsByResourceIDs :many
 SELECT
-	id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, startup_script, instance_metadata, resource_metadata, directory, version
+	id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, startup_script, instance_metadata, resource_metadata, directory, version, lifecycle_state, startup_script_timeout_seconds, startup_logs_length, startup_logs_overflowed, shutdown_script, shutdown_script_timeout_seconds, session_cpu_limit, session_memory_limit_mb, session_pids_limit, health_warnings
 FROM
 	workspace_agents
 WHERE
@@ -3658,6 +4833,16 @@ func (q *sqlQuerier) GetWorkspaceAgentsByResourceIDs(ctx context.Context, ids []
 			&i.ResourceMetadata,
 			&i.Directory,
 			&i.Version,
+			&i.LifecycleState,
+			&i.StartupScriptTimeoutSeconds,
+			&i.StartupLogsLength,
+			&i.StartupLogsOverflowed,
+			&i.ShutdownScript,
+			&i.ShutdownScriptTimeoutSeconds,
+			&i.SessionCPULimit,
+			&i.SessionMemoryLimitMB,
+			&i.SessionPidsLimit,
+			pq.Array(&i.HealthWarnings),
 		); err != nil {
 			return nil, err
 		}
@@ -3673,7 +4858,7 @@ func (q *sqlQuerier) GetWorkspaceAgentsByResourceIDs(ctx context.Context, ids []
 }
 
 const getWorkspaceAgentsCreatedAfter = `-- name: GetWorkspaceAgentsCreatedAfter :many
-SELECT id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, startup_script, instance_metadata, resource_metadata, directory, version FROM workspace_agents WHERE created_at > $1
+SELECT id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, startup_script, instance_metadata, resource_metadata, directory, version, lifecycle_state, startup_script_timeout_seconds, startup_logs_length, startup_logs_overflowed, shutdown_script, shutdown_script_timeout_seconds, session_cpu_limit, session_memory_limit_mb, session_pids_limit, health_warnings FROM workspace_agents WHERE created_at > $1
 `
 
 func (q *sqlQuerier) GetWorkspaceAgentsCreatedAfter(ctx context.Context, createdAt time.Time) ([]WorkspaceAgent, error) {
@@ -3704,6 +4889,16 @@ func (q *sqlQuerier) GetWorkspaceAgentsCreatedAfter(ctx context.Context, created
 			&i.ResourceMetadata,
 			&i.Directory,
 			&i.Version,
+			&i.LifecycleState,
+			&i.StartupScriptTimeoutSeconds,
+			&i.StartupLogsLength,
+			&i.StartupLogsOverflowed,
+			&i.ShutdownScript,
+			&i.ShutdownScriptTimeoutSeconds,
+			&i.SessionCPULimit,
+			&i.SessionMemoryLimitMB,
+			&i.SessionPidsLimit,
+			pq.Array(&i.HealthWarnings),
 		); err != nil {
 			return nil, err
 		}
@@ -3734,27 +4929,39 @@ INSERT INTO
 		startup_script,
 		directory,
 		instance_metadata,
-		resource_metadata
+		resource_metadata,
+		startup_script_timeout_seconds,
+		shutdown_script,
+		shutdown_script_timeout_seconds,
+		session_cpu_limit,
+		session_memory_limit_mb,
+		session_pids_limit
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14) RETURNING id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, startup_script, instance_metadata, resource_metadata, directory, version
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20) RETURNING id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, startup_script, instance_metadata, resource_metadata, directory, version, lifecycle_state, startup_script_timeout_seconds, startup_logs_length, startup_logs_overflowed, shutdown_script, shutdown_script_timeout_seconds, session_cpu_limit, session_memory_limit_mb, session_pids_limit, health_warnings
 `
 
 type InsertWorkspaceAgentParams struct {
-	ID                   uuid.UUID             `db:"id" json:"id"`
-	CreatedAt            time.Time             `db:"created_at" json:"created_at"`
-	UpdatedAt            time.Time             `db:"updated_at" json:"updated_at"`
-	Name                 string                `db:"name" json:"name"`
-	ResourceID           uuid.UUID             `db:"resource_id" json:"resource_id"`
-	AuthToken            uuid.UUID             `db:"auth_token" json:"auth_token"`
-	AuthInstanceID       sql.NullString        `db:"auth_instance_id" json:"auth_instance_id"`
-	Architecture         string                `db:"architecture" json:"architecture"`
-	EnvironmentVariables pqtype.NullRawMessage `db:"environment_variables" json:"environment_variables"`
-	OperatingSystem      string                `db:"operating_system" json:"operating_system"`
-	StartupScript        sql.NullString        `db:"startup_script" json:"startup_script"`
-	Directory            string                `db:"directory" json:"directory"`
-	InstanceMetadata     pqtype.NullRawMessage `db:"instance_metadata" json:"instance_metadata"`
-	ResourceMetadata     pqtype.NullRawMessage `db:"resource_metadata" json:"resource_metadata"`
+	ID                           uuid.UUID             `db:"id" json:"id"`
+	CreatedAt                    time.Time             `db:"created_at" json:"created_at"`
+	UpdatedAt                    time.Time             `db:"updated_at" json:"updated_at"`
+	Name                         string                `db:"name" json:"name"`
+	ResourceID                   uuid.UUID             `db:"resource_id" json:"resource_id"`
+	AuthToken                    uuid.UUID             `db:"auth_token" json:"auth_token"`
+	AuthInstanceID               sql.NullString        `db:"auth_instance_id" json:"auth_instance_id"`
+	Architecture                 string                `db:"architecture" json:"architecture"`
+	EnvironmentVariables         pqtype.NullRawMessage `db:"environment_variables" json:"environment_variables"`
+	OperatingSystem              string                `db:"operating_system" json:"operating_system"`
+	StartupScript                sql.NullString        `db:"startup_script" json:"startup_script"`
+	Directory                    string                `db:"directory" json:"directory"`
+	InstanceMetadata             pqtype.NullRawMessage `db:"instance_metadata" json:"instance_metadata"`
+	ResourceMetadata             pqtype.NullRawMessage `db:"resource_metadata" json:"resource_metadata"`
+	StartupScriptTimeoutSeconds  int32                 `db:"startup_script_timeout_seconds" json:"startup_script_timeout_seconds"`
+	ShutdownScript               sql.NullString        `db:"shutdown_script" json:"shutdown_script"`
+	ShutdownScriptTimeoutSeconds int32                 `db:"shutdown_script_timeout_seconds" json:"shutdown_script_timeout_seconds"`
+	SessionCPULimit              float64               `db:"session_cpu_limit" json:"session_cpu_limit"`
+	SessionMemoryLimitMB         int64                 `db:"session_memory_limit_mb" json:"session_memory_limit_mb"`
+	SessionPidsLimit             int64                 `db:"session_pids_limit" json:"session_pids_limit"`
 }
 
 func (q *sqlQuerier) InsertWorkspaceAgent(ctx context.Context, arg InsertWorkspaceAgentParams) (WorkspaceAgent, error) {
@@ -3773,6 +4980,12 @@ func (q *sqlQuerier) InsertWorkspaceAgent(ctx context.Context, arg InsertWorkspa
 		arg.Directory,
 		arg.InstanceMetadata,
 		arg.ResourceMetadata,
+		arg.StartupScriptTimeoutSeconds,
+		arg.ShutdownScript,
+		arg.ShutdownScriptTimeoutSeconds,
+		arg.SessionCPULimit,
+		arg.SessionMemoryLimitMB,
+		arg.SessionPidsLimit,
 	)
 	var i WorkspaceAgent
 	err := row.Scan(
@@ -3794,10 +5007,109 @@ func (q *sqlQuerier) InsertWorkspaceAgent(ctx context.Context, arg InsertWorkspa
 		&i.ResourceMetadata,
 		&i.Directory,
 		&i.Version,
+		&i.LifecycleState,
+		&i.StartupScriptTimeoutSeconds,
+		&i.StartupLogsLength,
+		&i.StartupLogsOverflowed,
+		&i.ShutdownScript,
+		&i.ShutdownScriptTimeoutSeconds,
+		&i.SessionCPULimit,
+		&i.SessionMemoryLimitMB,
+		&i.SessionPidsLimit,
+		pq.Array(&i.HealthWarnings),
 	)
 	return i, err
 }
 
+const insertWorkspaceAgentMetadata = `-- name: InsertWorkspaceAgentMetadata :exec
+INSERT INTO
+	workspace_agent_metadata (
+		workspace_agent_id,
+		display_name,
+		key,
+		script,
+		timeout,
+		interval
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6)
+`
+
+type InsertWorkspaceAgentMetadataParams struct {
+	WorkspaceAgentID uuid.UUID `db:"workspace_agent_id" json:"workspace_agent_id"`
+	DisplayName      string    `db:"display_name" json:"display_name"`
+	Key              string    `db:"key" json:"key"`
+	Script           string    `db:"script" json:"script"`
+	Timeout          int64     `db:"timeout" json:"timeout"`
+	Interval         int64     `db:"interval" json:"interval"`
+}
+
+func (q *sqlQuerier) InsertWorkspaceAgentMetadata(ctx context.Context, arg InsertWorkspaceAgentMetadataParams) error {
+	_, err := q.db.ExecContext(ctx, insertWorkspaceAgentMetadata,
+		arg.WorkspaceAgentID,
+		arg.DisplayName,
+		arg.Key,
+		arg.Script,
+		arg.Timeout,
+		arg.Interval,
+	)
+	return err
+}
+
+const insertWorkspaceAgentStartupLogs = `-- name: InsertWorkspaceAgentStartupLogs :many
+WITH new_length AS (
+	UPDATE workspace_agents SET
+	startup_logs_length = startup_logs_length + $1 WHERE workspace_agents.id = $2
+)
+INSERT INTO
+	workspace_agent_startup_logs (agent_id, created_at, output)
+SELECT
+	$2 :: uuid,
+	unnest($3 :: timestamptz [ ]) AS created_at,
+	unnest($4 :: VARCHAR(1024) [ ]) AS output
+	RETURNING workspace_agent_startup_logs.id, workspace_agent_startup_logs.agent_id, workspace_agent_startup_logs.created_at, workspace_agent_startup_logs.output
+`
+
+type InsertWorkspaceAgentStartupLogsParams struct {
+	OutputLength int32       `db:"output_length" json:"output_length"`
+	AgentID      uuid.UUID   `db:"agent_id" json:"agent_id"`
+	CreatedAt    []time.Time `db:"created_at" json:"created_at"`
+	Output       []string    `db:"output" json:"output"`
+}
+
+func (q *sqlQuerier) InsertWorkspaceAgentStartupLogs(ctx context.Context, arg InsertWorkspaceAgentStartupLogsParams) ([]WorkspaceAgentStartupLog, error) {
+	rows, err := q.db.QueryContext(ctx, insertWorkspaceAgentStartupLogs,
+		arg.OutputLength,
+		arg.AgentID,
+		pq.Array(arg.CreatedAt),
+		pq.Array(arg.Output),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceAgentStartupLog
+	for rows.Next() {
+		var i WorkspaceAgentStartupLog
+		if err := rows.Scan(
+			&i.ID,
+			&i.AgentID,
+			&i.CreatedAt,
+			&i.Output,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const updateWorkspaceAgentConnectionByID = `-- name: UpdateWorkspaceAgentConnectionByID :exec
 UPDATE
 	workspace_agents
@@ -3829,6 +5141,94 @@ func (q *sqlQuerier) UpdateWorkspaceAgentConnectionByID(ctx context.Context, arg
 	return err
 }
 
+const updateWorkspaceAgentHealthWarningsByID = `-- name: UpdateWorkspaceAgentHealthWarningsByID :exec
+UPDATE
+	workspace_agents
+SET
+	health_warnings = $2
+WHERE
+	id = $1
+`
+
+type UpdateWorkspaceAgentHealthWarningsByIDParams struct {
+	ID             uuid.UUID `db:"id" json:"id"`
+	HealthWarnings []string  `db:"health_warnings" json:"health_warnings"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceAgentHealthWarningsByID(ctx context.Context, arg UpdateWorkspaceAgentHealthWarningsByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceAgentHealthWarningsByID, arg.ID, pq.Array(arg.HealthWarnings))
+	return err
+}
+
+const updateWorkspaceAgentLifecycleStateByID = `-- name: UpdateWorkspaceAgentLifecycleStateByID :exec
+UPDATE
+	workspace_agents
+SET
+	lifecycle_state = $2
+WHERE
+	id = $1
+`
+
+type UpdateWorkspaceAgentLifecycleStateByIDParams struct {
+	ID             uuid.UUID                    `db:"id" json:"id"`
+	LifecycleState WorkspaceAgentLifecycleState `db:"lifecycle_state" json:"lifecycle_state"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceAgentLifecycleStateByID(ctx context.Context, arg UpdateWorkspaceAgentLifecycleStateByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceAgentLifecycleStateByID, arg.ID, arg.LifecycleState)
+	return err
+}
+
+const updateWorkspaceAgentMetadata = `-- name: UpdateWorkspaceAgentMetadata :exec
+UPDATE
+	workspace_agent_metadata
+SET
+	value = $3,
+	error = $4,
+	collected_at = $5
+WHERE
+	workspace_agent_id = $1
+	AND key = $2
+`
+
+type UpdateWorkspaceAgentMetadataParams struct {
+	WorkspaceAgentID uuid.UUID `db:"workspace_agent_id" json:"workspace_agent_id"`
+	Key              string    `db:"key" json:"key"`
+	Value            string    `db:"value" json:"value"`
+	Error            string    `db:"error" json:"error"`
+	CollectedAt      time.Time `db:"collected_at" json:"collected_at"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceAgentMetadata(ctx context.Context, arg UpdateWorkspaceAgentMetadataParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceAgentMetadata,
+		arg.WorkspaceAgentID,
+		arg.Key,
+		arg.Value,
+		arg.Error,
+		arg.CollectedAt,
+	)
+	return err
+}
+
+const updateWorkspaceAgentStartupLogOverflowByID = `-- name: UpdateWorkspaceAgentStartupLogOverflowByID :exec
+UPDATE
+	workspace_agents
+SET
+	startup_logs_overflowed = $2
+WHERE
+	id = $1
+`
+
+type UpdateWorkspaceAgentStartupLogOverflowByIDParams struct {
+	ID                    uuid.UUID `db:"id" json:"id"`
+	StartupLogsOverflowed bool      `db:"startup_logs_overflowed" json:"startup_logs_overflowed"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceAgentStartupLogOverflowByID(ctx context.Context, arg UpdateWorkspaceAgentStartupLogOverflowByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceAgentStartupLogOverflowByID, arg.ID, arg.StartupLogsOverflowed)
+	return err
+}
+
 const updateWorkspaceAgentVersionByID = `-- name: UpdateWorkspaceAgentVersionByID :exec
 UPDATE
 	workspace_agents
@@ -3849,7 +5249,7 @@ func (q *sqlQuerier) UpdateWorkspaceAgentVersionByID(ctx context.Context, arg Up
 }
 
 const getWorkspaceAppByAgentIDAndName = `-- name: GetWorkspaceAppByAgentIDAndName :one
-SELECT id, created_at, agent_id, name, icon, command, url, relative_path FROM workspace_apps WHERE agent_id = $1 AND name = $2
+SELECT id, created_at, agent_id, name, icon, command, url, relative_path, healthcheck_url, healthcheck_interval, healthcheck_threshold, health FROM workspace_apps WHERE agent_id = $1 AND name = $2
 `
 
 type GetWorkspaceAppByAgentIDAndNameParams struct {
@@ -3869,12 +5269,16 @@ func (q *sqlQuerier) GetWorkspaceAppByAgentIDAndName(ctx context.Context, arg Ge
 		&i.Command,
 		&i.Url,
 		&i.RelativePath,
+		&i.HealthcheckUrl,
+		&i.HealthcheckInterval,
+		&i.HealthcheckThreshold,
+		&i.Health,
 	)
 	return i, err
 }
 
 const getWorkspaceAppsByAgentID = `-- name: GetWorkspaceAppsByAgentID :many
-SELECT id, created_at, agent_id, name, icon, command, url, relative_path FROM workspace_apps WHERE agent_id = $1 ORDER BY name ASC
+SELECT id, created_at, agent_id, name, icon, command, url, relative_path, healthcheck_url, healthcheck_interval, healthcheck_threshold, health FROM workspace_apps WHERE agent_id = $1 ORDER BY name ASC
 `
 
 func (q *sqlQuerier) GetWorkspaceAppsByAgentID(ctx context.Context, agentID uuid.UUID) ([]WorkspaceApp, error) {
@@ -3895,6 +5299,10 @@ func (q *sqlQuerier) GetWorkspaceAppsByAgentID(ctx context.Context, agentID uuid
 			&i.Command,
 			&i.Url,
 			&i.RelativePath,
+			&i.HealthcheckUrl,
+			&i.HealthcheckInterval,
+			&i.HealthcheckThreshold,
+			&i.Health,
 		); err != nil {
 			return nil, err
 		}
@@ -3910,7 +5318,7 @@ func (q *sqlQuerier) GetWorkspaceAppsByAgentID(ctx context.Context, agentID uuid
 }
 
 const getWorkspaceAppsByAgentIDs = `-- name: GetWorkspaceAppsByAgentIDs :many
-SELECT id, created_at, agent_id, name, icon, command, url, relative_path FROM workspace_apps WHERE agent_id = ANY($1 :: uuid [ ]) ORDER BY name ASC
+SELECT id, created_at, agent_id, name, icon, command, url, relative_path, healthcheck_url, healthcheck_interval, healthcheck_threshold, health FROM workspace_apps WHERE agent_id = ANY($1 :: uuid [ ]) ORDER BY name ASC
 `
 
 func (q *sqlQuerier) GetWorkspaceAppsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceApp, error) {
@@ -3931,6 +5339,10 @@ func (q *sqlQuerier) GetWorkspaceAppsByAgentIDs(ctx context.Context, ids []uuid.
 			&i.Command,
 			&i.Url,
 			&i.RelativePath,
+			&i.HealthcheckUrl,
+			&i.HealthcheckInterval,
+			&i.HealthcheckThreshold,
+			&i.Health,
 		); err != nil {
 			return nil, err
 		}
@@ -3946,7 +5358,7 @@ func (q *sqlQuerier) GetWorkspaceAppsByAgentIDs(ctx context.Context, ids []uuid.
 }
 
 const getWorkspaceAppsCreatedAfter = `-- name: GetWorkspaceAppsCreatedAfter :many
-SELECT id, created_at, agent_id, name, icon, command, url, relative_path FROM workspace_apps WHERE created_at > $1 ORDER BY name ASC
+SELECT id, created_at, agent_id, name, icon, command, url, relative_path, healthcheck_url, healthcheck_interval, healthcheck_threshold, health FROM workspace_apps WHERE created_at > $1 ORDER BY name ASC
 `
 
 func (q *sqlQuerier) GetWorkspaceAppsCreatedAfter(ctx context.Context, createdAt time.Time) ([]WorkspaceApp, error) {
@@ -3967,6 +5379,10 @@ func (q *sqlQuerier) GetWorkspaceAppsCreatedAfter(ctx context.Context, createdAt
 			&i.Command,
 			&i.Url,
 			&i.RelativePath,
+			&i.HealthcheckUrl,
+			&i.HealthcheckInterval,
+			&i.HealthcheckThreshold,
+			&i.Health,
 		); err != nil {
 			return nil, err
 		}
@@ -3991,21 +5407,29 @@ INSERT INTO
         icon,
         command,
         url,
-        relative_path
+        relative_path,
+        healthcheck_url,
+        healthcheck_interval,
+        healthcheck_threshold,
+        health
     )
 VALUES
-    ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, created_at, agent_id, name, icon, command, url, relative_path
+    ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id, created_at, agent_id, name, icon, command, url, relative_path, healthcheck_url, healthcheck_interval, healthcheck_threshold, health
 `
 
 type InsertWorkspaceAppParams struct {
-	ID           uuid.UUID      `db:"id" json:"id"`
-	CreatedAt    time.Time      `db:"created_at" json:"created_at"`
-	AgentID      uuid.UUID      `db:"agent_id" json:"agent_id"`
-	Name         string         `db:"name" json:"name"`
-	Icon         string         `db:"icon" json:"icon"`
-	Command      sql.NullString `db:"command" json:"command"`
-	Url          sql.NullString `db:"url" json:"url"`
-	RelativePath bool           `db:"relative_path" json:"relative_path"`
+	ID                   uuid.UUID          `db:"id" json:"id"`
+	CreatedAt            time.Time          `db:"created_at" json:"created_at"`
+	AgentID              uuid.UUID          `db:"agent_id" json:"agent_id"`
+	Name                 string             `db:"name" json:"name"`
+	Icon                 string             `db:"icon" json:"icon"`
+	Command              sql.NullString     `db:"command" json:"command"`
+	Url                  sql.NullString     `db:"url" json:"url"`
+	RelativePath         bool               `db:"relative_path" json:"relative_path"`
+	HealthcheckUrl       string             `db:"healthcheck_url" json:"healthcheck_url"`
+	HealthcheckInterval  int32              `db:"healthcheck_interval" json:"healthcheck_interval"`
+	HealthcheckThreshold int32              `db:"healthcheck_threshold" json:"healthcheck_threshold"`
+	Health               WorkspaceAppHealth `db:"health" json:"health"`
 }
 
 func (q *sqlQuerier) InsertWorkspaceApp(ctx context.Context, arg InsertWorkspaceAppParams) (WorkspaceApp, error) {
@@ -4018,6 +5442,10 @@ func (q *sqlQuerier) InsertWorkspaceApp(ctx context.Context, arg InsertWorkspace
 		arg.Command,
 		arg.Url,
 		arg.RelativePath,
+		arg.HealthcheckUrl,
+		arg.HealthcheckInterval,
+		arg.HealthcheckThreshold,
+		arg.Health,
 	)
 	var i WorkspaceApp
 	err := row.Scan(
@@ -4029,13 +5457,36 @@ func (q *sqlQuerier) InsertWorkspaceApp(ctx context.Context, arg InsertWorkspace
 		&i.Command,
 		&i.Url,
 		&i.RelativePath,
+		&i.HealthcheckUrl,
+		&i.HealthcheckInterval,
+		&i.HealthcheckThreshold,
+		&i.Health,
 	)
 	return i, err
 }
 
+const updateWorkspaceAppHealthByID = `-- name: UpdateWorkspaceAppHealthByID :exec
+UPDATE
+	workspace_apps
+SET
+	health = $2
+WHERE
+	id = $1
+`
+
+type UpdateWorkspaceAppHealthByIDParams struct {
+	ID     uuid.UUID          `db:"id" json:"id"`
+	Health WorkspaceAppHealth `db:"health" json:"health"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceAppHealthByID(ctx context.Context, arg UpdateWorkspaceAppHealthByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceAppHealthByID, arg.ID, arg.Health)
+	return err
+}
+
 const getLatestWorkspaceBuildByWorkspaceID = `-- name: GetLatestWorkspaceBuildByWorkspaceID :one
 SELECT
-	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason
+	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost
 FROM
 	workspace_builds
 WHERE
@@ -4062,12 +5513,13 @@ func (q *sqlQuerier) GetLatestWorkspaceBuildByWorkspaceID(ctx context.Context, w
 		&i.JobID,
 		&i.Deadline,
 		&i.Reason,
+		&i.DailyCost,
 	)
 	return i, err
 }
 
 const getLatestWorkspaceBuilds = `-- name: GetLatestWorkspaceBuilds :many
-SELECT wb.id, wb.created_at, wb.updated_at, wb.workspace_id, wb.template_version_id, wb.build_number, wb.transition, wb.initiator_id, wb.provisioner_state, wb.job_id, wb.deadline, wb.reason
+SELECT wb.id, wb.created_at, wb.updated_at, wb.workspace_id, wb.template_version_id, wb.build_number, wb.transition, wb.initiator_id, wb.provisioner_state, wb.job_id, wb.deadline, wb.reason, wb.daily_cost
 FROM (
     SELECT
         workspace_id, MAX(build_number) as max_build_number
@@ -4103,6 +5555,7 @@ func (q *sqlQuerier) GetLatestWorkspaceBuilds(ctx context.Context) ([]WorkspaceB
 			&i.JobID,
 			&i.Deadline,
 			&i.Reason,
+			&i.DailyCost,
 		); err != nil {
 			return nil, err
 		}
@@ -4118,7 +5571,7 @@ func (q *sqlQuerier) GetLatestWorkspaceBuilds(ctx context.Context) ([]WorkspaceB
 }
 
 const getLatestWorkspaceBuildsByWorkspaceIDs = `-- name: GetLatestWorkspaceBuildsByWorkspaceIDs :many
-SELECT wb.id, wb.created_at, wb.updated_at, wb.workspace_id, wb.template_version_id, wb.build_number, wb.transition, wb.initiator_id, wb.provisioner_state, wb.job_id, wb.deadline, wb.reason
+SELECT wb.id, wb.created_at, wb.updated_at, wb.workspace_id, wb.template_version_id, wb.build_number, wb.transition, wb.initiator_id, wb.provisioner_state, wb.job_id, wb.deadline, wb.reason, wb.daily_cost
 FROM (
     SELECT
         workspace_id, MAX(build_number) as max_build_number
@@ -4156,6 +5609,7 @@ func (q *sqlQuerier) GetLatestWorkspaceBuildsByWorkspaceIDs(ctx context.Context,
 			&i.JobID,
 			&i.Deadline,
 			&i.Reason,
+			&i.DailyCost,
 		); err != nil {
 			return nil, err
 		}
@@ -4172,7 +5626,7 @@ func (q *sqlQuerier) GetLatestWorkspaceBuildsByWorkspaceIDs(ctx context.Context,
 
 const getWorkspaceBuildByID = `-- name: GetWorkspaceBuildByID :one
 SELECT
-	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason
+	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost
 FROM
 	workspace_builds
 WHERE
@@ -4197,13 +5651,14 @@ func (q *sqlQuerier) GetWorkspaceBuildByID(ctx context.Context, id uuid.UUID) (W
 		&i.JobID,
 		&i.Deadline,
 		&i.Reason,
+		&i.DailyCost,
 	)
 	return i, err
 }
 
 const getWorkspaceBuildByJobID = `-- name: GetWorkspaceBuildByJobID :one
 SELECT
-	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason
+	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost
 FROM
 	workspace_builds
 WHERE
@@ -4228,13 +5683,14 @@ func (q *sqlQuerier) GetWorkspaceBuildByJobID(ctx context.Context, jobID uuid.UU
 		&i.JobID,
 		&i.Deadline,
 		&i.Reason,
+		&i.DailyCost,
 	)
 	return i, err
 }
 
 const getWorkspaceBuildByWorkspaceID = `-- name: GetWorkspaceBuildByWorkspaceID :many
 SELECT
-	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason
+	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost
 FROM
 	workspace_builds
 WHERE
@@ -4299,6 +5755,7 @@ func (q *sqlQuerier) GetWorkspaceBuildByWorkspaceID(ctx context.Context, arg Get
 			&i.JobID,
 			&i.Deadline,
 			&i.Reason,
+			&i.DailyCost,
 		); err != nil {
 			return nil, err
 		}
@@ -4315,7 +5772,7 @@ func (q *sqlQuerier) GetWorkspaceBuildByWorkspaceID(ctx context.Context, arg Get
 
 const getWorkspaceBuildByWorkspaceIDAndBuildNumber = `-- name: GetWorkspaceBuildByWorkspaceIDAndBuildNumber :one
 SELECT
-	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason
+	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost
 FROM
 	workspace_builds
 WHERE
@@ -4344,12 +5801,13 @@ func (q *sqlQuerier) GetWorkspaceBuildByWorkspaceIDAndBuildNumber(ctx context.Co
 		&i.JobID,
 		&i.Deadline,
 		&i.Reason,
+		&i.DailyCost,
 	)
 	return i, err
 }
 
 const getWorkspaceBuildsCreatedAfter = `-- name: GetWorkspaceBuildsCreatedAfter :many
-SELECT id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason FROM workspace_builds WHERE created_at > $1
+SELECT id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost FROM workspace_builds WHERE created_at > $1
 `
 
 func (q *sqlQuerier) GetWorkspaceBuildsCreatedAfter(ctx context.Context, createdAt time.Time) ([]WorkspaceBuild, error) {
@@ -4374,6 +5832,7 @@ func (q *sqlQuerier) GetWorkspaceBuildsCreatedAfter(ctx context.Context, created
 			&i.JobID,
 			&i.Deadline,
 			&i.Reason,
+			&i.DailyCost,
 		); err != nil {
 			return nil, err
 		}
@@ -4405,7 +5864,7 @@ INSERT INTO
 		reason
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost
 `
 
 type InsertWorkspaceBuildParams struct {
@@ -4452,6 +5911,7 @@ func (q *sqlQuerier) InsertWorkspaceBuild(ctx context.Context, arg InsertWorkspa
 		&i.JobID,
 		&i.Deadline,
 		&i.Reason,
+		&i.DailyCost,
 	)
 	return i, err
 }
@@ -4484,9 +5944,150 @@ func (q *sqlQuerier) UpdateWorkspaceBuildByID(ctx context.Context, arg UpdateWor
 	return err
 }
 
+const updateWorkspaceBuildCostByID = `-- name: UpdateWorkspaceBuildCostByID :exec
+UPDATE
+	workspace_builds
+SET
+	daily_cost = $2
+WHERE
+	id = $1
+`
+
+type UpdateWorkspaceBuildCostByIDParams struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	DailyCost int32     `db:"daily_cost" json:"daily_cost"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceBuildCostByID(ctx context.Context, arg UpdateWorkspaceBuildCostByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceBuildCostByID, arg.ID, arg.DailyCost)
+	return err
+}
+
+const deleteWorkspacePortShare = `-- name: DeleteWorkspacePortShare :exec
+DELETE FROM
+	workspace_port_shares
+WHERE
+	workspace_id = $1
+	AND agent_name = $2
+	AND port = $3
+`
+
+type DeleteWorkspacePortShareParams struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	AgentName   string    `db:"agent_name" json:"agent_name"`
+	Port        int32     `db:"port" json:"port"`
+}
+
+func (q *sqlQuerier) DeleteWorkspacePortShare(ctx context.Context, arg DeleteWorkspacePortShareParams) error {
+	_, err := q.db.ExecContext(ctx, deleteWorkspacePortShare, arg.WorkspaceID, arg.AgentName, arg.Port)
+	return err
+}
+
+const getWorkspacePortShare = `-- name: GetWorkspacePortShare :one
+SELECT
+	workspace_id, agent_name, port, share_level
+FROM
+	workspace_port_shares
+WHERE
+	workspace_id = $1
+	AND agent_name = $2
+	AND port = $3
+`
+
+type GetWorkspacePortShareParams struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	AgentName   string    `db:"agent_name" json:"agent_name"`
+	Port        int32     `db:"port" json:"port"`
+}
+
+func (q *sqlQuerier) GetWorkspacePortShare(ctx context.Context, arg GetWorkspacePortShareParams) (WorkspacePortShare, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspacePortShare, arg.WorkspaceID, arg.AgentName, arg.Port)
+	var i WorkspacePortShare
+	err := row.Scan(
+		&i.WorkspaceID,
+		&i.AgentName,
+		&i.Port,
+		&i.ShareLevel,
+	)
+	return i, err
+}
+
+const getWorkspacePortShares = `-- name: GetWorkspacePortShares :many
+SELECT
+	workspace_id, agent_name, port, share_level
+FROM
+	workspace_port_shares
+WHERE
+	workspace_id = $1
+ORDER BY
+	agent_name ASC, port ASC
+`
+
+func (q *sqlQuerier) GetWorkspacePortShares(ctx context.Context, workspaceID uuid.UUID) ([]WorkspacePortShare, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspacePortShares, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspacePortShare
+	for rows.Next() {
+		var i WorkspacePortShare
+		if err := rows.Scan(
+			&i.WorkspaceID,
+			&i.AgentName,
+			&i.Port,
+			&i.ShareLevel,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertWorkspacePortShare = `-- name: UpsertWorkspacePortShare :one
+INSERT INTO
+	workspace_port_shares (workspace_id, agent_name, port, share_level)
+VALUES
+	($1, $2, $3, $4)
+ON CONFLICT (workspace_id, agent_name, port) DO UPDATE SET
+	share_level = $4
+RETURNING workspace_id, agent_name, port, share_level
+`
+
+type UpsertWorkspacePortShareParams struct {
+	WorkspaceID uuid.UUID               `db:"workspace_id" json:"workspace_id"`
+	AgentName   string                  `db:"agent_name" json:"agent_name"`
+	Port        int32                   `db:"port" json:"port"`
+	ShareLevel  WorkspacePortShareLevel `db:"share_level" json:"share_level"`
+}
+
+func (q *sqlQuerier) UpsertWorkspacePortShare(ctx context.Context, arg UpsertWorkspacePortShareParams) (WorkspacePortShare, error) {
+	row := q.db.QueryRowContext(ctx, upsertWorkspacePortShare,
+		arg.WorkspaceID,
+		arg.AgentName,
+		arg.Port,
+		arg.ShareLevel,
+	)
+	var i WorkspacePortShare
+	err := row.Scan(
+		&i.WorkspaceID,
+		&i.AgentName,
+		&i.Port,
+		&i.ShareLevel,
+	)
+	return i, err
+}
+
 const getWorkspaceResourceByID = `-- name: GetWorkspaceResourceByID :one
 SELECT
-	id, created_at, job_id, transition, type, name, hide, icon
+	id, created_at, job_id, transition, type, name, hide, icon, daily_cost
 FROM
 	workspace_resources
 WHERE
@@ -4505,6 +6106,7 @@ func (q *sqlQuerier) GetWorkspaceResourceByID(ctx context.Context, id uuid.UUID)
 		&i.Name,
 		&i.Hide,
 		&i.Icon,
+		&i.DailyCost,
 	)
 	return i, err
 }
@@ -4619,7 +6221,7 @@ func (q *sqlQuerier) GetWorkspaceResourceMetadataCreatedAfter(ctx context.Contex
 
 const getWorkspaceResourcesByJobID = `-- name: GetWorkspaceResourcesByJobID :many
 SELECT
-	id, created_at, job_id, transition, type, name, hide, icon
+	id, created_at, job_id, transition, type, name, hide, icon, daily_cost
 FROM
 	workspace_resources
 WHERE
@@ -4644,6 +6246,7 @@ func (q *sqlQuerier) GetWorkspaceResourcesByJobID(ctx context.Context, jobID uui
 			&i.Name,
 			&i.Hide,
 			&i.Icon,
+			&i.DailyCost,
 		); err != nil {
 			return nil, err
 		}
@@ -4660,7 +6263,7 @@ func (q *sqlQuerier) GetWorkspaceResourcesByJobID(ctx context.Context, jobID uui
 
 const getWorkspaceResourcesByJobIDs = `-- name: GetWorkspaceResourcesByJobIDs :many
 SELECT
-	id, created_at, job_id, transition, type, name, hide, icon
+	id, created_at, job_id, transition, type, name, hide, icon, daily_cost
 FROM
 	workspace_resources
 WHERE
@@ -4685,6 +6288,7 @@ func (q *sqlQuerier) GetWorkspaceResourcesByJobIDs(ctx context.Context, ids []uu
 			&i.Name,
 			&i.Hide,
 			&i.Icon,
+			&i.DailyCost,
 		); err != nil {
 			return nil, err
 		}
@@ -4700,7 +6304,7 @@ func (q *sqlQuerier) GetWorkspaceResourcesByJobIDs(ctx context.Context, ids []uu
 }
 
 const getWorkspaceResourcesCreatedAfter = `-- name: GetWorkspaceResourcesCreatedAfter :many
-SELECT id, created_at, job_id, transition, type, name, hide, icon FROM workspace_resources WHERE created_at > $1
+SELECT id, created_at, job_id, transition, type, name, hide, icon, daily_cost FROM workspace_resources WHERE created_at > $1
 `
 
 func (q *sqlQuerier) GetWorkspaceResourcesCreatedAfter(ctx context.Context, createdAt time.Time) ([]WorkspaceResource, error) {
@@ -4721,6 +6325,7 @@ func (q *sqlQuerier) GetWorkspaceResourcesCreatedAfter(ctx context.Context, crea
 			&i.Name,
 			&i.Hide,
 			&i.Icon,
+			&i.DailyCost,
 		); err != nil {
 			return nil, err
 		}
@@ -4737,9 +6342,9 @@ func (q *sqlQuerier) GetWorkspaceResourcesCreatedAfter(ctx context.Context, crea
 
 const insertWorkspaceResource = `-- name: InsertWorkspaceResource :one
 INSERT INTO
-	workspace_resources (id, created_at, job_id, transition, type, name, hide, icon)
+	workspace_resources (id, created_at, job_id, transition, type, name, hide, icon, daily_cost)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, created_at, job_id, transition, type, name, hide, icon
+	($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id, created_at, job_id, transition, type, name, hide, icon, daily_cost
 `
 
 type InsertWorkspaceResourceParams struct {
@@ -4751,6 +6356,7 @@ type InsertWorkspaceResourceParams struct {
 	Name       string              `db:"name" json:"name"`
 	Hide       bool                `db:"hide" json:"hide"`
 	Icon       string              `db:"icon" json:"icon"`
+	DailyCost  int32               `db:"daily_cost" json:"daily_cost"`
 }
 
 func (q *sqlQuerier) InsertWorkspaceResource(ctx context.Context, arg InsertWorkspaceResourceParams) (WorkspaceResource, error) {
@@ -4763,6 +6369,7 @@ func (q *sqlQuerier) InsertWorkspaceResource(ctx context.Context, arg InsertWork
 		arg.Name,
 		arg.Hide,
 		arg.Icon,
+		arg.DailyCost,
 	)
 	var i WorkspaceResource
 	err := row.Scan(
@@ -4774,6 +6381,7 @@ func (q *sqlQuerier) InsertWorkspaceResource(ctx context.Context, arg InsertWork
 		&i.Name,
 		&i.Hide,
 		&i.Icon,
+		&i.DailyCost,
 	)
 	return i, err
 }
@@ -4811,7 +6419,7 @@ func (q *sqlQuerier) InsertWorkspaceResourceMetadata(ctx context.Context, arg In
 
 const getWorkspaceByID = `-- name: GetWorkspaceByID :one
 SELECT
-	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at
+	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, automatic_updates
 FROM
 	workspaces
 WHERE
@@ -4835,13 +6443,14 @@ func (q *sqlQuerier) GetWorkspaceByID(ctx context.Context, id uuid.UUID) (Worksp
 		&i.AutostartSchedule,
 		&i.Ttl,
 		&i.LastUsedAt,
+		&i.AutomaticUpdates,
 	)
 	return i, err
 }
 
 const getWorkspaceByOwnerIDAndName = `-- name: GetWorkspaceByOwnerIDAndName :one
 SELECT
-	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at
+	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, automatic_updates
 FROM
 	workspaces
 WHERE
@@ -4872,6 +6481,7 @@ func (q *sqlQuerier) GetWorkspaceByOwnerIDAndName(ctx context.Context, arg GetWo
 		&i.AutostartSchedule,
 		&i.Ttl,
 		&i.LastUsedAt,
+		&i.AutomaticUpdates,
 	)
 	return i, err
 }
@@ -4920,7 +6530,7 @@ func (q *sqlQuerier) GetWorkspaceOwnerCountsByTemplateIDs(ctx context.Context, i
 
 const getWorkspaces = `-- name: GetWorkspaces :many
 SELECT
-    id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at
+    id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, automatic_updates
 FROM
     workspaces
 WHERE
@@ -4997,6 +6607,7 @@ func (q *sqlQuerier) GetWorkspaces(ctx context.Context, arg GetWorkspacesParams)
 			&i.AutostartSchedule,
 			&i.Ttl,
 			&i.LastUsedAt,
+			&i.AutomaticUpdates,
 		); err != nil {
 			return nil, err
 		}
@@ -5022,22 +6633,24 @@ INSERT INTO
 		template_id,
 		name,
 		autostart_schedule,
-		ttl
+		ttl,
+		automatic_updates
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, automatic_updates
 `
 
 type InsertWorkspaceParams struct {
-	ID                uuid.UUID      `db:"id" json:"id"`
-	CreatedAt         time.Time      `db:"created_at" json:"created_at"`
-	UpdatedAt         time.Time      `db:"updated_at" json:"updated_at"`
-	OwnerID           uuid.UUID      `db:"owner_id" json:"owner_id"`
-	OrganizationID    uuid.UUID      `db:"organization_id" json:"organization_id"`
-	TemplateID        uuid.UUID      `db:"template_id" json:"template_id"`
-	Name              string         `db:"name" json:"name"`
-	AutostartSchedule sql.NullString `db:"autostart_schedule" json:"autostart_schedule"`
-	Ttl               sql.NullInt64  `db:"ttl" json:"ttl"`
+	ID                uuid.UUID        `db:"id" json:"id"`
+	CreatedAt         time.Time        `db:"created_at" json:"created_at"`
+	UpdatedAt         time.Time        `db:"updated_at" json:"updated_at"`
+	OwnerID           uuid.UUID        `db:"owner_id" json:"owner_id"`
+	OrganizationID    uuid.UUID        `db:"organization_id" json:"organization_id"`
+	TemplateID        uuid.UUID        `db:"template_id" json:"template_id"`
+	Name              string           `db:"name" json:"name"`
+	AutostartSchedule sql.NullString   `db:"autostart_schedule" json:"autostart_schedule"`
+	Ttl               sql.NullInt64    `db:"ttl" json:"ttl"`
+	AutomaticUpdates  AutomaticUpdates `db:"automatic_updates" json:"automatic_updates"`
 }
 
 func (q *sqlQuerier) InsertWorkspace(ctx context.Context, arg InsertWorkspaceParams) (Workspace, error) {
@@ -5051,6 +6664,7 @@ func (q *sqlQuerier) InsertWorkspace(ctx context.Context, arg InsertWorkspacePar
 		arg.Name,
 		arg.AutostartSchedule,
 		arg.Ttl,
+		arg.AutomaticUpdates,
 	)
 	var i Workspace
 	err := row.Scan(
@@ -5065,6 +6679,7 @@ func (q *sqlQuerier) InsertWorkspace(ctx context.Context, arg InsertWorkspacePar
 		&i.AutostartSchedule,
 		&i.Ttl,
 		&i.LastUsedAt,
+		&i.AutomaticUpdates,
 	)
 	return i, err
 }
@@ -5077,7 +6692,7 @@ SET
 WHERE
 	id = $1
 	AND deleted = false
-RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at
+RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, automatic_updates
 `
 
 type UpdateWorkspaceParams struct {
@@ -5100,10 +6715,30 @@ func (q *sqlQuerier) UpdateWorkspace(ctx context.Context, arg UpdateWorkspacePar
 		&i.AutostartSchedule,
 		&i.Ttl,
 		&i.LastUsedAt,
+		&i.AutomaticUpdates,
 	)
 	return i, err
 }
 
+const updateWorkspaceAutomaticUpdates = `-- name: UpdateWorkspaceAutomaticUpdates :exec
+UPDATE
+	workspaces
+SET
+	automatic_updates = $2
+WHERE
+	id = $1
+`
+
+type UpdateWorkspaceAutomaticUpdatesParams struct {
+	ID               uuid.UUID        `db:"id" json:"id"`
+	AutomaticUpdates AutomaticUpdates `db:"automatic_updates" json:"automatic_updates"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceAutomaticUpdates(ctx context.Context, arg UpdateWorkspaceAutomaticUpdatesParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceAutomaticUpdates, arg.ID, arg.AutomaticUpdates)
+	return err
+}
+
 const updateWorkspaceAutostart = `-- name: UpdateWorkspaceAutostart :exec
 UPDATE
 	workspaces
@@ -7,10 +7,20 @@ INSERT INTO
 		workspace_id,
 		template_id,
 		agent_id,
-		payload
+		payload,
+		connection_count,
+		rx_bytes,
+		tx_bytes,
+		session_count_ssh,
+		session_count_vscode,
+		session_count_jetbrains,
+		session_count_reconnecting_pty,
+		session_count_port_forward,
+		session_duration_seconds,
+		latency_ms
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7) RETURNING *;
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17) RETURNING *;
 
 -- name: GetLatestAgentStat :one
 SELECT * FROM agent_stats WHERE agent_id = $1 ORDER BY created_at DESC LIMIT 1; 
@@ -27,5 +37,32 @@ group by
 order by
 	date asc;
 
+-- name: GetDailyAgentStats :many
+-- Stats are summed per day, template, and user so callers can aggregate them
+-- further. The average latency is total_latency_ms / latency_samples.
+SELECT
+	(created_at at TIME ZONE 'UTC')::date AS date,
+	template_id,
+	user_id,
+	SUM(connection_count)::bigint AS connection_count,
+	SUM(rx_bytes)::bigint AS rx_bytes,
+	SUM(tx_bytes)::bigint AS tx_bytes,
+	SUM(session_count_ssh)::bigint AS session_count_ssh,
+	SUM(session_count_vscode)::bigint AS session_count_vscode,
+	SUM(session_count_jetbrains)::bigint AS session_count_jetbrains,
+	SUM(session_count_reconnecting_pty)::bigint AS session_count_reconnecting_pty,
+	SUM(session_count_port_forward)::bigint AS session_count_port_forward,
+	SUM(session_duration_seconds)::float AS session_duration_seconds,
+	SUM(latency_ms)::float AS total_latency_ms,
+	COUNT(*) FILTER (WHERE latency_ms > 0) AS latency_samples
+FROM
+	agent_stats
+WHERE
+	created_at > @created_after
+GROUP BY
+	date, template_id, user_id
+ORDER BY
+	date ASC;
+
 -- name: DeleteOldAgentStats :exec
 DELETE FROM AGENT_STATS WHERE created_at  < now() - interval '30 days';
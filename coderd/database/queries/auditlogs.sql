@@ -50,8 +50,23 @@ WHERE
 			users.email = @email
 		ELSE true
 	END
+	-- Filter by time_after
+	AND CASE
+		WHEN @time_after :: timestamp with time zone != '0001-01-01 00:00:00Z' THEN
+			"time" >= @time_after
+		ELSE true
+	END
+	-- Filter by time_before
+	AND CASE
+		WHEN @time_before :: timestamp with time zone != '0001-01-01 00:00:00Z' THEN
+			"time" < @time_before
+		ELSE true
+	END
 ORDER BY
-    "time" DESC
+    "time" DESC,
+    -- Logs can share a time, so the ID keeps the order stable between
+    -- pages.
+    id DESC
 LIMIT
     $1
 OFFSET
@@ -98,6 +113,18 @@ WHERE
 		WHEN @email :: text != '' THEN
 			user_id = (SELECT id from users WHERE users.email = @email )
 		ELSE true
+	END
+	-- Filter by time_after
+	AND CASE
+		WHEN @time_after :: timestamp with time zone != '0001-01-01 00:00:00Z' THEN
+			"time" >= @time_after
+		ELSE true
+	END
+	-- Filter by time_before
+	AND CASE
+		WHEN @time_before :: timestamp with time zone != '0001-01-01 00:00:00Z' THEN
+			"time" < @time_before
+		ELSE true
 	END;
 
 -- name: InsertAuditLog :one
@@ -18,10 +18,17 @@ INSERT INTO
 		id,
 		created_at,
 		"name",
-		provisioners
+		provisioners,
+		tags
 	)
 VALUES
-	($1, $2, $3, $4) RETURNING *;
+	($1, $2, $3, $4, $5) RETURNING *;
+
+-- name: DeleteProvisionerDaemonByID :exec
+DELETE FROM
+	provisioner_daemons
+WHERE
+	id = $1;
 
 -- name: UpdateProvisionerDaemonByID :exec
 UPDATE
@@ -1,6 +1,13 @@
 -- Acquires the lock for a single job that isn't started, completed,
 -- canceled, and that matches an array of provisioner types.
 --
+-- Jobs are only acquired by daemons whose tags are a superset of
+-- the job's tags. Untagged daemons only acquire untagged jobs.
+--
+-- Jobs that stop a workspace aren't acquired until the agents of the
+-- previous build have run their shutdown scripts, so resources aren't
+-- destroyed while a script is running and no daemon is held waiting.
+--
 -- SKIP LOCKED is used to jump over locked rows. This prevents
 -- multiple provisioners from acquiring the same jobs. See:
 -- https://www.postgresql.org/docs/9.5/sql-select.html#SQL-FOR-UPDATE-SHARE
@@ -22,6 +29,38 @@ WHERE
 			AND nested.canceled_at IS NULL
 			AND nested.completed_at IS NULL
 			AND nested.provisioner = ANY(@types :: provisioner_type [ ])
+			AND nested.tags <@ @tags :: jsonb
+			-- Builds that stop a workspace wait for its connected agents
+			-- to run their shutdown scripts, up to the script's timeout.
+			AND NOT EXISTS (
+				SELECT
+					1
+				FROM
+					workspace_builds
+				JOIN
+					workspace_builds AS previous_build
+				ON
+					previous_build.workspace_id = workspace_builds.workspace_id
+					AND previous_build.build_number = workspace_builds.build_number - 1
+				JOIN
+					workspace_resources
+				ON
+					workspace_resources.job_id = previous_build.job_id
+				JOIN
+					workspace_agents
+				ON
+					workspace_agents.resource_id = workspace_resources.id
+				WHERE
+					workspace_builds.job_id = nested.id
+					AND workspace_builds.transition != 'start'
+					AND workspace_agents.shutdown_script IS NOT NULL
+					AND workspace_agents.lifecycle_state NOT IN ('shutdown_timeout', 'shutdown_error', 'off')
+					AND workspace_agents.last_connected_at > @agent_connected_after :: timestamptz
+					AND nested.created_at + make_interval(secs => LEAST(
+						NULLIF(workspace_agents.shutdown_script_timeout_seconds, 0),
+						@agent_shutdown_max_timeout_seconds :: integer
+					) + @agent_shutdown_grace_seconds :: integer) > @started_at
+			)
 		ORDER BY
 			nested.created_at FOR
 		UPDATE
@@ -61,10 +100,11 @@ INSERT INTO
 		storage_method,
 		storage_source,
 		"type",
-		"input"
+		"input",
+		tags
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING *;
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING *;
 
 -- name: UpdateProvisionerJobByID :exec
 UPDATE
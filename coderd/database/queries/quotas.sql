@@ -0,0 +1,46 @@
+-- name: AcquireLockForUserQuota :exec
+-- Serializes quota checks for a user, so builds acquired at the same time
+-- can't both spend the remaining quota. The lock is released when the
+-- transaction ends.
+SELECT pg_advisory_xact_lock(hashtext('quota:' || @user_id::uuid));
+
+-- name: GetQuotaAllowanceForUser :one
+SELECT
+	(
+		-- Allowances granted to the user directly.
+		(SELECT coalesce(SUM(quota_allowance), 0) FROM users WHERE id = $1)
+		+
+		(
+			SELECT
+				coalesce(SUM(quota_allowance), 0)
+			FROM
+				groups
+			WHERE
+				id IN (SELECT group_id FROM group_members WHERE user_id = $1)
+			OR
+				-- Members of an organization are implicitly members of its
+				-- "Everyone" group, which shares the organization's ID.
+				id IN (SELECT organization_id FROM organization_members WHERE user_id = $1)
+		)
+	)::BIGINT;
+
+-- name: GetQuotaConsumedForUser :one
+WITH latest_builds AS (
+SELECT
+	DISTINCT ON
+	(workspace_id) id,
+	workspace_id,
+	daily_cost
+FROM
+	workspace_builds wb
+ORDER BY
+	workspace_id,
+	created_at DESC
+)
+SELECT
+	coalesce(SUM(daily_cost), 0)::BIGINT
+FROM
+	workspaces
+JOIN latest_builds ON
+	latest_builds.workspace_id = workspaces.id
+WHERE NOT deleted AND workspaces.owner_id = $1;
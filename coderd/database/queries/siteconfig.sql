@@ -3,3 +3,11 @@ INSERT INTO site_configs (key, value) VALUES ('deployment_id', $1);
 
 -- name: GetDeploymentID :one
 SELECT value FROM site_configs WHERE key = 'deployment_id';
+
+-- name: InsertDERPMeshKey :exec
+-- Replicas may start at the same time, so the first key inserted wins.
+INSERT INTO site_configs (key, value) VALUES ('derp_mesh_key', $1)
+ON CONFLICT (key) DO NOTHING;
+
+-- name: GetDERPMeshKey :one
+SELECT value FROM site_configs WHERE key = 'derp_mesh_key';
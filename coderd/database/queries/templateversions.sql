@@ -24,6 +24,11 @@ WHERE
 		)
 		ELSE true
 	END
+	-- Archived versions are hidden unless requested.
+	AND CASE
+		WHEN @include_archived :: boolean THEN true
+		ELSE archived = false
+	END
 ORDER BY
     -- Deterministic and consistent ordering of all rows, even if they share
     -- a timestamp. This is to ensure consistent pagination.
@@ -71,10 +76,11 @@ INSERT INTO
 		"name",
 		readme,
 		job_id,
-		created_by
+		created_by,
+		message
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING *;
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING *;
 
 -- name: UpdateTemplateVersionByID :exec
 UPDATE
@@ -85,6 +91,15 @@ SET
 WHERE
 	id = $1;
 
+-- name: UpdateTemplateVersionArchivedByID :exec
+UPDATE
+	template_versions
+SET
+	archived = $2,
+	updated_at = $3
+WHERE
+	id = $1;
+
 -- name: UpdateTemplateVersionDescriptionByJobID :exec
 UPDATE
 	template_versions
@@ -62,6 +62,15 @@ SET
 WHERE
 	id = $1 RETURNING *;
 
+-- name: UpdateUserQuotaAllowance :one
+UPDATE
+	users
+SET
+	quota_allowance = $2,
+	updated_at = $3
+WHERE
+	id = $1 RETURNING *;
+
 -- name: UpdateUserRoles :one
 UPDATE
 	users
@@ -171,7 +180,17 @@ SELECT
 			array_append(users.rbac_roles, 'member'),
 		-- All org_members get the org-member role for their orgs
 			array_append(organization_members.roles, 'organization-member:'||organization_members.organization_id::text)) :: text[]
-		AS roles
+		AS roles,
+	-- All groups the user is a member of. The "Everyone" group is
+	-- implied by organization membership and is not stored here.
+	ARRAY(
+		SELECT
+			group_id :: text
+		FROM
+			group_members
+		WHERE
+			user_id = @user_id
+	) :: text[] AS groups
 FROM
 	users
 LEFT JOIN organization_members
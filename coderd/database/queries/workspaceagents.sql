@@ -26,6 +26,37 @@ WHERE
 ORDER BY
 	created_at DESC;
 
+-- name: GetWorkspaceAgentMetadata :many
+SELECT
+	*
+FROM
+	workspace_agent_metadata
+WHERE
+	workspace_agent_id = $1
+ORDER BY
+	key ASC;
+
+-- name: GetWorkspaceAgentMetadataByAgentIDs :many
+SELECT
+	*
+FROM
+	workspace_agent_metadata
+WHERE
+	workspace_agent_id = ANY(@ids :: uuid [ ])
+ORDER BY
+	key ASC;
+
+-- name: GetWorkspaceAgentStartupLogsAfter :many
+SELECT
+	*
+FROM
+	workspace_agent_startup_logs
+WHERE
+	agent_id = $1
+	AND (
+		id > @created_after
+	) ORDER BY id ASC;
+
 -- name: GetWorkspaceAgentsByResourceIDs :many
 SELECT
 	*
@@ -53,10 +84,42 @@ INSERT INTO
 		startup_script,
 		directory,
 		instance_metadata,
-		resource_metadata
+		resource_metadata,
+		startup_script_timeout_seconds,
+		shutdown_script,
+		shutdown_script_timeout_seconds,
+		session_cpu_limit,
+		session_memory_limit_mb,
+		session_pids_limit
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14) RETURNING *;
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20) RETURNING *;
+
+-- name: InsertWorkspaceAgentMetadata :exec
+INSERT INTO
+	workspace_agent_metadata (
+		workspace_agent_id,
+		display_name,
+		key,
+		script,
+		timeout,
+		interval
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6);
+
+-- name: InsertWorkspaceAgentStartupLogs :many
+WITH new_length AS (
+	UPDATE workspace_agents SET
+	startup_logs_length = startup_logs_length + @output_length WHERE workspace_agents.id = @agent_id
+)
+INSERT INTO
+	workspace_agent_startup_logs (agent_id, created_at, output)
+SELECT
+	@agent_id :: uuid,
+	unnest(@created_at :: timestamptz [ ]) AS created_at,
+	unnest(@output :: VARCHAR(1024) [ ]) AS output
+	RETURNING workspace_agent_startup_logs.*;
 
 -- name: UpdateWorkspaceAgentConnectionByID :exec
 UPDATE
@@ -69,6 +132,41 @@ SET
 WHERE
 	id = $1;
 
+-- name: UpdateWorkspaceAgentHealthWarningsByID :exec
+UPDATE
+	workspace_agents
+SET
+	health_warnings = $2
+WHERE
+	id = $1;
+
+-- name: UpdateWorkspaceAgentLifecycleStateByID :exec
+UPDATE
+	workspace_agents
+SET
+	lifecycle_state = $2
+WHERE
+	id = $1;
+
+-- name: UpdateWorkspaceAgentMetadata :exec
+UPDATE
+	workspace_agent_metadata
+SET
+	value = $3,
+	error = $4,
+	collected_at = $5
+WHERE
+	workspace_agent_id = $1
+	AND key = $2;
+
+-- name: UpdateWorkspaceAgentStartupLogOverflowByID :exec
+UPDATE
+	workspace_agents
+SET
+	startup_logs_overflowed = $2
+WHERE
+	id = $1;
+
 -- name: UpdateWorkspaceAgentVersionByID :exec
 UPDATE
 	workspace_agents
@@ -132,3 +132,11 @@ SET
 	deadline = $4
 WHERE
 	id = $1;
+
+-- name: UpdateWorkspaceBuildCostByID :exec
+UPDATE
+	workspace_builds
+SET
+	daily_cost = $2
+WHERE
+	id = $1;
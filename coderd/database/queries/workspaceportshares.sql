@@ -0,0 +1,36 @@
+-- name: GetWorkspacePortShare :one
+SELECT
+	*
+FROM
+	workspace_port_shares
+WHERE
+	workspace_id = $1
+	AND agent_name = $2
+	AND port = $3;
+
+-- name: GetWorkspacePortShares :many
+SELECT
+	*
+FROM
+	workspace_port_shares
+WHERE
+	workspace_id = $1
+ORDER BY
+	agent_name ASC, port ASC;
+
+-- name: UpsertWorkspacePortShare :one
+INSERT INTO
+	workspace_port_shares (workspace_id, agent_name, port, share_level)
+VALUES
+	($1, $2, $3, $4)
+ON CONFLICT (workspace_id, agent_name, port) DO UPDATE SET
+	share_level = $4
+RETURNING *;
+
+-- name: DeleteWorkspacePortShare :exec
+DELETE FROM
+	workspace_port_shares
+WHERE
+	workspace_id = $1
+	AND agent_name = $2
+	AND port = $3;
@@ -27,9 +27,9 @@ SELECT * FROM workspace_resources WHERE created_at > $1;
 
 -- name: InsertWorkspaceResource :one
 INSERT INTO
-	workspace_resources (id, created_at, job_id, transition, type, name, hide, icon)
+	workspace_resources (id, created_at, job_id, transition, type, name, hide, icon, daily_cost)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8) RETURNING *;
+	($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING *;
 
 -- name: GetWorkspaceResourceMetadataByResourceID :many
 SELECT
@@ -0,0 +1,62 @@
+package database
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/coderd/rbac"
+)
+
+// StringMap is a map of strings stored as a JSON object. It's used for
+// provisioner tags, which scope jobs to the daemons that can run them.
+type StringMap map[string]string
+
+func (m *StringMap) Scan(src interface{}) error {
+	if src == nil {
+		*m = StringMap{}
+		return nil
+	}
+	switch src := src.(type) {
+	case []byte:
+		return json.Unmarshal(src, m)
+	case string:
+		return json.Unmarshal([]byte(src), m)
+	default:
+		return xerrors.Errorf("unsupported Scan, storing driver.Value type %T into type %T", src, m)
+	}
+}
+
+func (m StringMap) Value() (driver.Value, error) {
+	if m == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(m)
+}
+
+// TemplateACL maps user or group IDs to the actions they are allowed
+// to perform on a template.
+type TemplateACL map[string][]rbac.Action
+
+func (t *TemplateACL) Scan(src interface{}) error {
+	if src == nil {
+		*t = TemplateACL{}
+		return nil
+	}
+	switch src := src.(type) {
+	case []byte:
+		return json.Unmarshal(src, t)
+	case string:
+		return json.Unmarshal([]byte(src), t)
+	default:
+		return xerrors.Errorf("unsupported Scan, storing driver.Value type %T into type %T", src, t)
+	}
+}
+
+func (t TemplateACL) Value() (driver.Value, error) {
+	if t == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(t)
+}
@@ -34,6 +34,13 @@ func APIKey(r *http.Request) database.APIKey {
 	return apiKey
 }
 
+// APIKeyOptional returns the API key from the ExtractAPIKey handler, if the
+// request was authenticated.
+func APIKeyOptional(r *http.Request) (database.APIKey, bool) {
+	apiKey, ok := r.Context().Value(apiKeyContextKey{}).(database.APIKey)
+	return apiKey, ok
+}
+
 // User roles are the 'subject' field of Authorize()
 type userAuthKey struct{}
 
@@ -41,6 +48,7 @@ type Authorization struct {
 	ID       uuid.UUID
 	Username string
 	Roles    []string
+	Groups   []string
 	Scope    database.APIKeyScope
 }
 
@@ -54,6 +62,13 @@ func UserAuthorization(r *http.Request) Authorization {
 	return auth
 }
 
+// UserAuthorizationOptional returns the roles and scope used for
+// authorization, if the request was authenticated.
+func UserAuthorizationOptional(r *http.Request) (Authorization, bool) {
+	auth, ok := r.Context().Value(userAuthKey{}).(Authorization)
+	return auth, ok
+}
+
 // OAuth2Configs is a collection of configurations for OAuth-based authentication.
 // This should be extended to support other authentication types in the future.
 type OAuth2Configs struct {
@@ -89,41 +104,30 @@ func UseLoginURL(loginURL *url.URL) func(http.Handler) http.Handler {
 // updating the last used time in the database.
 // nolint:revive
 func ExtractAPIKey(db database.Store, oauth *OAuth2Configs, redirectToLogin bool) func(http.Handler) http.Handler {
+	return extractAPIKey(db, oauth, redirectToLogin, false)
+}
+
+// ExtractAPIKeyOptional authenticates the request if it has a valid API key,
+// and passes it through unauthenticated otherwise. Handlers must use
+// APIKeyOptional and UserAuthorizationOptional to check for the key.
+func ExtractAPIKeyOptional(db database.Store, oauth *OAuth2Configs) func(http.Handler) http.Handler {
+	return extractAPIKey(db, oauth, false, true)
+}
+
+// nolint:revive
+func extractAPIKey(db database.Store, oauth *OAuth2Configs, redirectToLogin, optional bool) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
 			// Write wraps writing a response to redirect if the handler
 			// specified it should. This redirect is used for user-facing
 			// pages like workspace applications.
 			write := func(code int, response codersdk.Response) {
+				if optional && code == http.StatusUnauthorized {
+					next.ServeHTTP(rw, r)
+					return
+				}
 				if redirectToLogin {
-					var (
-						u = &url.URL{
-							Path: "/login",
-						}
-						redirectURL = func() string {
-							path := r.URL.Path
-							if r.URL.RawQuery != "" {
-								path += "?" + r.URL.RawQuery
-							}
-							return path
-						}()
-					)
-					if loginURL, ok := getLoginURL(r); ok {
-						u = loginURL
-						// Don't redirect to the current page, as it may be on
-						// a different domain and we have issues determining the
-						// scheme to redirect to.
-						redirectURL = ""
-					}
-
-					q := r.URL.Query()
-					q.Add("message", response.Message)
-					if redirectURL != "" {
-						q.Add("redirect", redirectURL)
-					}
-					u.RawQuery = q.Encode()
-
-					http.Redirect(rw, r, u.String(), http.StatusTemporaryRedirect)
+					RedirectToLogin(rw, r, response.Message)
 					return
 				}
 
@@ -194,7 +198,7 @@ func ExtractAPIKey(db database.Store, oauth *OAuth2Configs, redirectToLogin bool
 			changed := false
 
 			var link database.UserLink
-			if key.LoginType != database.LoginTypePassword {
+			if key.LoginType != database.LoginTypePassword && key.LoginType != database.LoginTypeToken {
 				link, err = db.GetUserLinkByUserIDLoginType(r.Context(), database.GetUserLinkByUserIDLoginTypeParams{
 					UserID:    key.UserID,
 					LoginType: key.LoginType,
@@ -270,9 +274,10 @@ func ExtractAPIKey(db database.Store, oauth *OAuth2Configs, redirectToLogin bool
 				changed = true
 			}
 			// Only update the ExpiresAt once an hour to prevent database spam.
-			// We extend the ExpiresAt to reduce re-authentication.
+			// We extend the ExpiresAt to reduce re-authentication. Tokens
+			// expire at a fixed time, so they aren't extended.
 			apiKeyLifetime := time.Duration(key.LifetimeSeconds) * time.Second
-			if key.ExpiresAt.Sub(now) <= apiKeyLifetime-time.Hour {
+			if key.LoginType != database.LoginTypeToken && key.ExpiresAt.Sub(now) <= apiKeyLifetime-time.Hour {
 				key.ExpiresAt = now.Add(apiKeyLifetime)
 				changed = true
 			}
@@ -335,6 +340,7 @@ func ExtractAPIKey(db database.Store, oauth *OAuth2Configs, redirectToLogin bool
 				ID:       key.UserID,
 				Username: roles.Username,
 				Roles:    roles.Roles,
+				Groups:   roles.Groups,
 				Scope:    key.Scope,
 			})
 
@@ -375,3 +381,36 @@ func apiTokenFromRequest(r *http.Request) string {
 
 	return ""
 }
+
+// RedirectToLogin redirects the user to the login page with the message
+// provided, and back to the current page once they've signed in.
+func RedirectToLogin(rw http.ResponseWriter, r *http.Request, message string) {
+	var (
+		u = &url.URL{
+			Path: "/login",
+		}
+		redirectURL = func() string {
+			path := r.URL.Path
+			if r.URL.RawQuery != "" {
+				path += "?" + r.URL.RawQuery
+			}
+			return path
+		}()
+	)
+	if loginURL, ok := getLoginURL(r); ok {
+		u = loginURL
+		// Don't redirect to the current page, as it may be on
+		// a different domain and we have issues determining the
+		// scheme to redirect to.
+		redirectURL = ""
+	}
+
+	q := r.URL.Query()
+	q.Add("message", message)
+	if redirectURL != "" {
+		q.Add("redirect", redirectURL)
+	}
+	u.RawQuery = q.Encode()
+
+	http.Redirect(rw, r, u.String(), http.StatusTemporaryRedirect)
+}
@@ -24,6 +24,14 @@ func TemplateParam(r *http.Request) database.Template {
 	return template
 }
 
+// TemplateParamOptional returns the template from the ExtractTemplateParam
+// or ExtractTemplateVersionParam handlers, if one was extracted.
+// Template versions that are not yet assigned to a template have none.
+func TemplateParamOptional(r *http.Request) (database.Template, bool) {
+	template, ok := r.Context().Value(templateParamContextKey{}).(database.Template)
+	return template, ok
+}
+
 // ExtractTemplateParam grabs a template from the "template" URL parameter.
 func ExtractTemplateParam(db database.Store) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
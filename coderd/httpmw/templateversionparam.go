@@ -46,6 +46,19 @@ func ExtractTemplateVersionParam(db database.Store) func(http.Handler) http.Hand
 			}
 
 			ctx := context.WithValue(r.Context(), templateVersionParamContextKey{}, templateVersion)
+			// The parent template is needed to authorize access to the
+			// version through the template's access control lists.
+			if templateVersion.TemplateID.Valid {
+				template, err := db.GetTemplateByID(r.Context(), templateVersion.TemplateID.UUID)
+				if err != nil {
+					httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+						Message: "Internal error fetching template.",
+						Detail:  err.Error(),
+					})
+					return
+				}
+				ctx = context.WithValue(ctx, templateParamContextKey{}, template)
+			}
 			chi.RouteContext(ctx).URLParams.Add("organization", templateVersion.OrganizationID.String())
 			next.ServeHTTP(rw, r.WithContext(ctx))
 		})
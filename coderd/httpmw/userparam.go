@@ -50,7 +50,14 @@ func ExtractUserParam(db database.Store) func(http.Handler) http.Handler {
 			}
 
 			if userQuery == "me" {
-				user, err = db.GetUserByID(r.Context(), APIKey(r).UserID)
+				apiKey, ok := APIKeyOptional(r)
+				if !ok {
+					httpapi.Write(rw, http.StatusUnauthorized, codersdk.Response{
+						Message: "\"me\" can only be used when signed in.",
+					})
+					return
+				}
+				user, err = db.GetUserByID(r.Context(), apiKey.UserID)
 				if xerrors.Is(err, sql.ErrNoRows) {
 					httpapi.ResourceNotFound(rw)
 					return
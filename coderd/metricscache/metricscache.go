@@ -12,12 +12,13 @@ import (
 	"github.com/google/uuid"
 
 	"cdr.dev/slog"
+	"github.com/coder/coder/agent"
 	"github.com/coder/coder/coderd/database"
 	"github.com/coder/coder/codersdk"
 	"github.com/coder/retry"
 )
 
-// Cache holds the template DAU cache.
+// Cache holds the template DAU and agent usage cache.
 // The aggregation queries responsible for these values can take up to a minute
 // on large deployments. Even in small deployments, aggregation queries can
 // take a few hundred milliseconds, which would ruin page load times and
@@ -28,6 +29,8 @@ type Cache struct {
 
 	templateDAUResponses atomic.Pointer[map[uuid.UUID]codersdk.TemplateDAUsResponse]
 	templateUniqueUsers  atomic.Pointer[map[uuid.UUID]int]
+	templateUsage        atomic.Pointer[map[uuid.UUID]codersdk.AgentUsageResponse]
+	userUsage            atomic.Pointer[map[uuid.UUID]codersdk.AgentUsageResponse]
 
 	done   chan struct{}
 	cancel func()
@@ -116,6 +119,72 @@ func countUniqueUsers(rows []database.GetTemplateDAUsRow) int {
 	return len(seen)
 }
 
+type usageEntry struct {
+	codersdk.AgentUsageEntry
+	totalLatencyMS float64
+	latencySamples int64
+}
+
+// convertUsageResponses sums agent stats into a daily series for every key,
+// e.g. template or user.
+func convertUsageResponses(rows []database.GetDailyAgentStatsRow, key func(database.GetDailyAgentStatsRow) uuid.UUID) map[uuid.UUID]codersdk.AgentUsageResponse {
+	entries := make(map[uuid.UUID]map[time.Time]*usageEntry)
+	for _, row := range rows {
+		byDate, ok := entries[key(row)]
+		if !ok {
+			byDate = make(map[time.Time]*usageEntry)
+			entries[key(row)] = byDate
+		}
+		entry, ok := byDate[row.Date]
+		if !ok {
+			entry = &usageEntry{
+				AgentUsageEntry: codersdk.AgentUsageEntry{
+					Date:         row.Date,
+					SessionCount: make(map[string]int64),
+				},
+			}
+			byDate[row.Date] = entry
+		}
+		entry.ConnectionCount += row.ConnectionCount
+		entry.RxBytes += row.RxBytes
+		entry.TxBytes += row.TxBytes
+		entry.SessionCount[string(agent.SessionTypeSSH)] += row.SessionCountSSH
+		entry.SessionCount[string(agent.SessionTypeVSCode)] += row.SessionCountVSCode
+		entry.SessionCount[string(agent.SessionTypeJetBrains)] += row.SessionCountJetBrains
+		entry.SessionCount[string(agent.SessionTypeReconnectingPTY)] += row.SessionCountReconnectingPTY
+		entry.SessionCount[string(agent.SessionTypePortForward)] += row.SessionCountPortForward
+		entry.SessionDurationSeconds += row.SessionDurationSeconds
+		entry.totalLatencyMS += row.TotalLatencyMS
+		entry.latencySamples += row.LatencySamples
+	}
+
+	responses := make(map[uuid.UUID]codersdk.AgentUsageResponse, len(entries))
+	for id, byDate := range entries {
+		dates := maps.Keys(byDate)
+		slices.SortFunc(dates, func(a, b time.Time) bool {
+			return a.Before(b)
+		})
+
+		var resp codersdk.AgentUsageResponse
+		for _, date := range fillEmptyDays(dates) {
+			entry, ok := byDate[date]
+			if !ok {
+				resp.Entries = append(resp.Entries, codersdk.AgentUsageEntry{
+					Date:         date,
+					SessionCount: map[string]int64{},
+				})
+				continue
+			}
+			if entry.latencySamples > 0 {
+				entry.AverageLatencyMS = entry.totalLatencyMS / float64(entry.latencySamples)
+			}
+			resp.Entries = append(resp.Entries, entry.AgentUsageEntry)
+		}
+		responses[id] = resp
+	}
+	return responses
+}
+
 func (c *Cache) refresh(ctx context.Context) error {
 	err := c.database.DeleteOldAgentStats(ctx)
 	if err != nil {
@@ -142,6 +211,20 @@ func (c *Cache) refresh(ctx context.Context) error {
 	c.templateDAUResponses.Store(&templateDAUs)
 	c.templateUniqueUsers.Store(&templateUniqueUsers)
 
+	// Stats older than 30 days were deleted above.
+	rows, err := c.database.GetDailyAgentStats(ctx, time.Time{})
+	if err != nil {
+		return xerrors.Errorf("get daily agent stats: %w", err)
+	}
+	templateUsage := convertUsageResponses(rows, func(row database.GetDailyAgentStatsRow) uuid.UUID {
+		return row.TemplateID
+	})
+	userUsage := convertUsageResponses(rows, func(row database.GetDailyAgentStatsRow) uuid.UUID {
+		return row.UserID
+	})
+	c.userUsage.Store(&userUsage)
+	c.templateUsage.Store(&templateUsage)
+
 	return nil
 }
 
@@ -220,3 +303,32 @@ func (c *Cache) TemplateUniqueUsers(id uuid.UUID) (int, bool) {
 	}
 	return resp, true
 }
+
+// TemplateUsage returns the daily agent usage of workspaces created from the
+// template. It returns false if the template has no usage or the cache is
+// loading for the first time.
+func (c *Cache) TemplateUsage(id uuid.UUID) (*codersdk.AgentUsageResponse, bool) {
+	return loadUsage(&c.templateUsage, id)
+}
+
+// UserUsage returns the daily agent usage of the user's workspaces. It
+// returns false if the user has no usage or the cache is loading for the
+// first time.
+func (c *Cache) UserUsage(id uuid.UUID) (*codersdk.AgentUsageResponse, bool) {
+	return loadUsage(&c.userUsage, id)
+}
+
+func loadUsage(p *atomic.Pointer[map[uuid.UUID]codersdk.AgentUsageResponse], id uuid.UUID) (*codersdk.AgentUsageResponse, bool) {
+	m := p.Load()
+	if m == nil {
+		// Data loading.
+		return nil, false
+	}
+
+	resp, ok := (*m)[id]
+	if !ok {
+		// Probably no data.
+		return nil, false
+	}
+	return &resp, true
+}
@@ -197,3 +197,98 @@ func TestCache(t *testing.T) {
 		})
 	}
 }
+
+func TestCacheUsage(t *testing.T) {
+	t.Parallel()
+
+	var (
+		zebra = uuid.UUID{1}
+		tiger = uuid.UUID{2}
+		db    = databasefake.New()
+		cache = metricscache.New(db, slogtest.Make(t, nil), testutil.IntervalFast)
+	)
+	defer cache.Close()
+
+	templateID := uuid.New()
+	_, _ = db.InsertTemplate(context.Background(), database.InsertTemplateParams{
+		ID: templateID,
+	})
+
+	today := database.Now().UTC().Truncate(24 * time.Hour)
+	yesterday := today.Add(-24 * time.Hour)
+	for _, row := range []database.InsertAgentStatParams{{
+		CreatedAt:              yesterday.Add(time.Hour),
+		UserID:                 zebra,
+		ConnectionCount:        1,
+		RxBytes:                10,
+		SessionCountSSH:        1,
+		SessionDurationSeconds: 60,
+		LatencyMS:              10,
+	}, {
+		CreatedAt:               today.Add(time.Hour),
+		UserID:                  zebra,
+		TxBytes:                 20,
+		SessionCountPortForward: 1,
+		LatencyMS:               20,
+	}, {
+		CreatedAt:             today.Add(time.Hour),
+		UserID:                tiger,
+		ConnectionCount:       2,
+		SessionCountJetBrains: 2,
+		LatencyMS:             40,
+	}} {
+		row.ID = uuid.New()
+		row.TemplateID = templateID
+		_, _ = db.InsertAgentStat(context.Background(), row)
+	}
+
+	require.Eventuallyf(t, func() bool {
+		_, ok := cache.TemplateUsage(templateID)
+		return ok
+	}, testutil.WaitShort, testutil.IntervalMedium,
+		"TemplateUsage never populated",
+	)
+
+	sessionCount := func(sessionType string, count int64) map[string]int64 {
+		counts := map[string]int64{
+			"ssh":              0,
+			"vscode":           0,
+			"jetbrains":        0,
+			"reconnecting_pty": 0,
+			"port_forward":     0,
+		}
+		counts[sessionType] = count
+		return counts
+	}
+	templateUsage, ok := cache.TemplateUsage(templateID)
+	require.True(t, ok)
+	require.Equal(t, []codersdk.AgentUsageEntry{{
+		Date:                   yesterday,
+		ConnectionCount:        1,
+		RxBytes:                10,
+		SessionCount:           sessionCount("ssh", 1),
+		SessionDurationSeconds: 60,
+		AverageLatencyMS:       10,
+	}, {
+		Date:            today,
+		ConnectionCount: 2,
+		TxBytes:         20,
+		SessionCount: map[string]int64{
+			"ssh":              0,
+			"vscode":           0,
+			"jetbrains":        2,
+			"reconnecting_pty": 0,
+			"port_forward":     1,
+		},
+		AverageLatencyMS: 30,
+	}}, templateUsage.Entries)
+
+	userUsage, ok := cache.UserUsage(tiger)
+	require.True(t, ok)
+	require.Equal(t, []codersdk.AgentUsageEntry{{
+		Date:             today,
+		ConnectionCount:  2,
+		SessionCount:     sessionCount("jetbrains", 2),
+		AverageLatencyMS: 40,
+	}}, userUsage.Entries)
+}
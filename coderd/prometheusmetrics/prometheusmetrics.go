@@ -7,6 +7,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/prometheus/client_golang/prometheus"
 
+	"github.com/coder/coder/agent"
 	"github.com/coder/coder/coderd"
 	"github.com/coder/coder/coderd/database"
 )
@@ -104,3 +105,105 @@ func Workspaces(ctx context.Context, registerer prometheus.Registerer, db databa
 	}()
 	return cancelFunc, nil
 }
+
+// AgentStats tracks the usage of workspace agents within the past hour with
+// labels on template name.
+func AgentStats(ctx context.Context, registerer prometheus.Registerer, db database.Store, duration time.Duration) (context.CancelFunc, error) {
+	if duration == 0 {
+		duration = 5 * time.Minute
+	}
+
+	newGauge := func(name, help string, labels ...string) (*prometheus.GaugeVec, error) {
+		gauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "coderd",
+			Subsystem: "agentstats",
+			Name:      name,
+			Help:      help,
+		}, append([]string{"template_name"}, labels...))
+		return gauge, registerer.Register(gauge)
+	}
+	connections, err := newGauge("connections_duration_hour", "The number of connections to agents within the last hour.")
+	if err != nil {
+		return nil, err
+	}
+	rxBytes, err := newGauge("rx_bytes_duration_hour", "The number of bytes received by agents within the last hour.")
+	if err != nil {
+		return nil, err
+	}
+	txBytes, err := newGauge("tx_bytes_duration_hour", "The number of bytes sent by agents within the last hour.")
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := newGauge("sessions_duration_hour", "The number of sessions opened to agents within the last hour.", "session_type")
+	if err != nil {
+		return nil, err
+	}
+	sessionSeconds, err := newGauge("session_seconds_duration_hour", "The time spent in agent sessions within the last hour.")
+	if err != nil {
+		return nil, err
+	}
+	latency, err := newGauge("latency_milliseconds", "The average round trip time between coderd and agents within the last hour.")
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	ticker := time.NewTicker(duration)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			rows, err := db.GetDailyAgentStats(ctx, database.Now().Add(-1*time.Hour))
+			if err != nil {
+				continue
+			}
+			templates, err := db.GetTemplates(ctx)
+			if err != nil {
+				continue
+			}
+			templateNames := make(map[uuid.UUID]string, len(templates))
+			for _, template := range templates {
+				templateNames[template.ID] = template.Name
+			}
+
+			type latencySum struct {
+				totalMS float64
+				samples int64
+			}
+			latencies := map[string]latencySum{}
+			for _, gauge := range []*prometheus.GaugeVec{connections, rxBytes, txBytes, sessions, sessionSeconds, latency} {
+				gauge.Reset()
+			}
+			for _, row := range rows {
+				name, ok := templateNames[row.TemplateID]
+				if !ok {
+					continue
+				}
+				connections.WithLabelValues(name).Add(float64(row.ConnectionCount))
+				rxBytes.WithLabelValues(name).Add(float64(row.RxBytes))
+				txBytes.WithLabelValues(name).Add(float64(row.TxBytes))
+				sessions.WithLabelValues(name, string(agent.SessionTypeSSH)).Add(float64(row.SessionCountSSH))
+				sessions.WithLabelValues(name, string(agent.SessionTypeVSCode)).Add(float64(row.SessionCountVSCode))
+				sessions.WithLabelValues(name, string(agent.SessionTypeJetBrains)).Add(float64(row.SessionCountJetBrains))
+				sessions.WithLabelValues(name, string(agent.SessionTypeReconnectingPTY)).Add(float64(row.SessionCountReconnectingPTY))
+				sessions.WithLabelValues(name, string(agent.SessionTypePortForward)).Add(float64(row.SessionCountPortForward))
+				sessionSeconds.WithLabelValues(name).Add(row.SessionDurationSeconds)
+
+				sum := latencies[name]
+				sum.totalMS += row.TotalLatencyMS
+				sum.samples += row.LatencySamples
+				latencies[name] = sum
+			}
+			for name, sum := range latencies {
+				if sum.samples > 0 {
+					latency.WithLabelValues(name).Set(sum.totalMS / float64(sum.samples))
+				}
+			}
+		}
+	}()
+	return cancelFunc, nil
+}
@@ -233,3 +233,64 @@ func TestWorkspaces(t *testing.T) {
 		})
 	}
 }
+
+func TestAgentStats(t *testing.T) {
+	t.Parallel()
+
+	db := databasefake.New()
+	template, err := db.InsertTemplate(context.Background(), database.InsertTemplateParams{
+		ID:   uuid.New(),
+		Name: "example",
+	})
+	require.NoError(t, err)
+	for _, latency := range []float64{10, 30} {
+		_, err = db.InsertAgentStat(context.Background(), database.InsertAgentStatParams{
+			ID:                 uuid.New(),
+			CreatedAt:          database.Now(),
+			UserID:             uuid.New(),
+			TemplateID:         template.ID,
+			ConnectionCount:    1,
+			RxBytes:            100,
+			SessionCountSSH:    1,
+			SessionCountVSCode: 2,
+			LatencyMS:          latency,
+		})
+		require.NoError(t, err)
+	}
+	// Stats older than an hour aren't counted.
+	_, err = db.InsertAgentStat(context.Background(), database.InsertAgentStatParams{
+		ID:              uuid.New(),
+		CreatedAt:       database.Now().Add(-2 * time.Hour),
+		UserID:          uuid.New(),
+		TemplateID:      template.ID,
+		ConnectionCount: 1,
+	})
+	require.NoError(t, err)
+
+	registry := prometheus.NewRegistry()
+	cancel, err := prometheusmetrics.AgentStats(context.Background(), registry, db, time.Millisecond)
+	require.NoError(t, err)
+	t.Cleanup(cancel)
+
+	require.Eventually(t, func() bool {
+		metrics, err := registry.Gather()
+		assert.NoError(t, err)
+		values := map[string]float64{}
+		for _, family := range metrics {
+			for _, metric := range family.Metric {
+				name := family.GetName()
+				for _, label := range metric.Label {
+					if label.GetName() == "session_type" {
+						name += ":" + label.GetValue()
+					}
+				}
+				values[name] = metric.Gauge.GetValue()
+			}
+		}
+		return values["coderd_agentstats_connections_duration_hour"] == 2 &&
+			values["coderd_agentstats_rx_bytes_duration_hour"] == 200 &&
+			values["coderd_agentstats_sessions_duration_hour:ssh"] == 2 &&
+			values["coderd_agentstats_sessions_duration_hour:vscode"] == 4 &&
+			values["coderd_agentstats_latency_milliseconds"] == 20
+	}, testutil.WaitShort, testutil.IntervalFast)
+}
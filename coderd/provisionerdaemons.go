@@ -10,13 +10,17 @@ import (
 	"net/http"
 	"net/url"
 	"reflect"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/hashicorp/yamux"
 	"github.com/moby/moby/pkg/namesgenerator"
 	"github.com/tabbed/pqtype"
 	"golang.org/x/xerrors"
 	protobuf "google.golang.org/protobuf/proto"
+	"nhooyr.io/websocket"
 	"storj.io/drpc/drpcmux"
 	"storj.io/drpc/drpcserver"
 
@@ -77,32 +81,17 @@ func (api *API) ListenProvisionerDaemon(ctx context.Context) (client proto.DRPCP
 		CreatedAt:    database.Now(),
 		Name:         name,
 		Provisioners: []database.ProvisionerType{database.ProvisionerTypeEcho, database.ProvisionerTypeTerraform},
+		// Built-in daemons are untagged, so they only acquire untagged jobs.
+		Tags: database.StringMap{},
 	})
 	if err != nil {
 		return nil, xerrors.Errorf("insert provisioner daemon %q: %w", name, err)
 	}
 
-	mux := drpcmux.New()
-	err = proto.DRPCRegisterProvisionerDaemon(mux, &provisionerdServer{
-		AccessURL:    api.AccessURL,
-		ID:           daemon.ID,
-		Database:     api.Database,
-		Pubsub:       api.Pubsub,
-		Provisioners: daemon.Provisioners,
-		Telemetry:    api.Telemetry,
-		Logger:       api.Logger.Named(fmt.Sprintf("provisionerd-%s", daemon.Name)),
-	})
+	server, err := api.newProvisionerdDRPCServer(ctx, daemon)
 	if err != nil {
 		return nil, err
 	}
-	server := drpcserver.NewWithOptions(mux, drpcserver.Options{
-		Log: func(err error) {
-			if xerrors.Is(err, io.EOF) {
-				return
-			}
-			api.Logger.Debug(ctx, "drpc server error", slog.Error(err))
-		},
-	})
 	go func() {
 		err := server.Serve(ctx, serverSession)
 		if err != nil && !xerrors.Is(err, io.EOF) {
@@ -116,6 +105,154 @@ func (api *API) ListenProvisionerDaemon(ctx context.Context) (client proto.DRPCP
 	return proto.NewDRPCProvisionerDaemonClient(provisionersdk.Conn(clientSession)), nil
 }
 
+// provisionerDaemonServe serves the provisioner daemon protocol over a
+// WebSocket. This allows provisioner daemons to run outside of coderd, for
+// example in a network zone with credentials coderd should never hold.
+func (api *API) provisionerDaemonServe(rw http.ResponseWriter, r *http.Request) {
+	if !api.Authorize(r, rbac.ActionCreate, rbac.ResourceProvisionerDaemon) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	provisioners := make([]database.ProvisionerType, 0)
+	for _, provisioner := range r.URL.Query()["provisioner"] {
+		switch provisioner {
+		case string(database.ProvisionerTypeEcho), string(database.ProvisionerTypeTerraform):
+			provisioners = append(provisioners, database.ProvisionerType(provisioner))
+		default:
+			httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+				Message: fmt.Sprintf("Unknown provisioner type %q.", provisioner),
+			})
+			return
+		}
+	}
+	if len(provisioners) == 0 {
+		httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+			Message: "At least one provisioner type must be specified.",
+		})
+		return
+	}
+
+	tags := database.StringMap{}
+	for _, tag := range r.URL.Query()["tag"] {
+		key, value, ok := strings.Cut(tag, "=")
+		if !ok || key == "" {
+			httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+				Message: fmt.Sprintf("Invalid provisioner tag %q.", tag),
+				Detail:  "Tags must be in the format \"key=value\".",
+			})
+			return
+		}
+		tags[key] = value
+	}
+
+	api.websocketWaitMutex.Lock()
+	api.websocketWaitGroup.Add(1)
+	api.websocketWaitMutex.Unlock()
+	defer api.websocketWaitGroup.Done()
+
+	conn, err := websocket.Accept(rw, r, &websocket.AcceptOptions{
+		// Need to disable compression to avoid a data-race.
+		CompressionMode: websocket.CompressionDisabled,
+	})
+	if err != nil {
+		httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Failed to accept websocket.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	// Align with the frame size of yamux.
+	conn.SetReadLimit(256 * 1024)
+
+	// The daemon is only registered once the connection is accepted, so
+	// failed upgrades don't leave daemons behind that never connected.
+	name := namesgenerator.GetRandomName(1)
+	daemon, err := api.Database.InsertProvisionerDaemon(r.Context(), database.InsertProvisionerDaemonParams{
+		ID:           uuid.New(),
+		CreatedAt:    database.Now(),
+		Name:         name,
+		Provisioners: provisioners,
+		Tags:         tags,
+	})
+	if err != nil {
+		_ = conn.Close(websocket.StatusInternalError, httpapi.WebsocketCloseSprintf("insert provisioner daemon: %s", err))
+		return
+	}
+	// Daemons are registered per connection, so the daemon is removed when
+	// it disconnects to avoid accumulating one for every reconnect. The
+	// request context may already be canceled at that point.
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		err := api.Database.DeleteProvisionerDaemonByID(ctx, daemon.ID)
+		if err != nil {
+			api.Logger.Warn(ctx, "delete disconnected provisioner daemon", slog.F("name", daemon.Name), slog.Error(err))
+		}
+	}()
+
+	// Multiplexes the incoming connection using yamux.
+	// This allows multiple function calls to occur over
+	// the same connection.
+	config := yamux.DefaultConfig()
+	config.LogOutput = io.Discard
+	session, err := yamux.Server(websocket.NetConn(r.Context(), conn, websocket.MessageBinary), config)
+	if err != nil {
+		_ = conn.Close(websocket.StatusInternalError, httpapi.WebsocketCloseSprintf("multiplex server: %s", err))
+		return
+	}
+	server, err := api.newProvisionerdDRPCServer(r.Context(), daemon)
+	if err != nil {
+		_ = conn.Close(websocket.StatusInternalError, httpapi.WebsocketCloseSprintf("drpc register provisioner daemon: %s", err))
+		return
+	}
+	err = server.Serve(r.Context(), session)
+	if err != nil && !xerrors.Is(err, io.EOF) {
+		api.Logger.Debug(r.Context(), "provisioner daemon disconnected", slog.Error(err))
+		_ = conn.Close(websocket.StatusInternalError, httpapi.WebsocketCloseSprintf("serve: %s", err))
+		return
+	}
+	_ = conn.Close(websocket.StatusGoingAway, "")
+}
+
+// newProvisionerdDRPCServer returns a dRPC server that serves the provisioner
+// daemon protocol on behalf of the daemon provided.
+func (api *API) newProvisionerdDRPCServer(ctx context.Context, daemon database.ProvisionerDaemon) (*drpcserver.Server, error) {
+	if daemon.Tags == nil {
+		// A null value would never contain any job's tags.
+		daemon.Tags = database.StringMap{}
+	}
+	tags, err := json.Marshal(daemon.Tags)
+	if err != nil {
+		return nil, xerrors.Errorf("marshal tags: %w", err)
+	}
+	mux := drpcmux.New()
+	err = proto.DRPCRegisterProvisionerDaemon(mux, &provisionerdServer{
+		AccessURL:      api.AccessURL,
+		ID:             daemon.ID,
+		Database:       api.Database,
+		Pubsub:         api.Pubsub,
+		Provisioners:   daemon.Provisioners,
+		Tags:           tags,
+		Telemetry:      api.Telemetry,
+		QuotaCommitter: &api.QuotaCommitter,
+		Logger:         api.Logger.Named(fmt.Sprintf("provisionerd-%s", daemon.Name)),
+
+		AgentInactiveDisconnectTimeout: api.AgentInactiveDisconnectTimeout,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return drpcserver.NewWithOptions(mux, drpcserver.Options{
+		Log: func(err error) {
+			if xerrors.Is(err, io.EOF) {
+				return
+			}
+			api.Logger.Debug(ctx, "drpc server error", slog.Error(err))
+		},
+	}), nil
+}
+
 // The input for a "workspace_provision" job.
 type workspaceProvisionJob struct {
 	WorkspaceBuildID uuid.UUID `json:"workspace_build_id"`
@@ -135,9 +272,16 @@ type provisionerdServer struct {
 	ID           uuid.UUID
 	Logger       slog.Logger
 	Provisioners []database.ProvisionerType
+	Tags         json.RawMessage
 	Database     database.Store
 	Pubsub       database.Pubsub
 	Telemetry    telemetry.Reporter
+	// QuotaCommitter is loaded when a workspace build is acquired to
+	// check its estimated daily cost against the owner's quota.
+	QuotaCommitter *atomic.Pointer[QuotaCommitter]
+	// AgentInactiveDisconnectTimeout is used to skip waiting for agents
+	// that haven't connected recently to run their shutdown scripts.
+	AgentInactiveDisconnectTimeout time.Duration
 }
 
 // AcquireJob queries the database to lock a job.
@@ -153,6 +297,12 @@ func (server *provisionerdServer) AcquireJob(ctx context.Context, _ *proto.Empty
 			Valid: true,
 		},
 		Types: server.Provisioners,
+		Tags:  server.Tags,
+		// Builds that stop a workspace are held back while its agents
+		// run their shutdown scripts.
+		AgentConnectedAfter:            database.Now().Add(-server.AgentInactiveDisconnectTimeout),
+		AgentShutdownMaxTimeoutSeconds: int32(agentShutdownMaxTimeout / time.Second),
+		AgentShutdownGraceSeconds:      int32(agentShutdownGracePeriod / time.Second),
 	})
 	if errors.Is(err, sql.ErrNoRows) {
 		// The provisioner daemon assumes no jobs are available if
@@ -249,6 +399,23 @@ func (server *provisionerdServer) AcquireJob(ctx context.Context, _ *proto.Empty
 		if err != nil {
 			return nil, failJob(fmt.Sprintf("convert workspace transition: %s", err))
 		}
+		if workspaceBuild.Transition == database.WorkspaceTransitionStart {
+			// Quota is checked before any resources are provisioned, so a
+			// rejected build never creates resources the owner pays for.
+			quotaError, err := server.commitQuota(ctx, workspace, workspaceBuild, templateVersion)
+			if err != nil {
+				return nil, failJob(fmt.Sprintf("commit quota: %s", err))
+			}
+			if quotaError != "" {
+				err = server.rejectWorkspaceBuild(ctx, job, workspaceBuild, quotaError)
+				if err != nil {
+					return nil, xerrors.Errorf("reject workspace build: %w", err)
+				}
+				// The provisioner daemon assumes no jobs are available if
+				// an empty struct is returned.
+				return &proto.AcquiredJob{}, nil
+			}
+		}
 
 		protoJob.Type = &proto.AcquiredJob_WorkspaceBuild_{
 			WorkspaceBuild: &proto.AcquiredJob_WorkspaceBuild{
@@ -539,14 +706,24 @@ func (server *provisionerdServer) FailJob(ctx context.Context, failJob *proto.Fa
 
 	switch jobType := failJob.Type.(type) {
 	case *proto.FailedJob_WorkspaceBuild_:
-		if jobType.WorkspaceBuild.State == nil {
-			break
-		}
 		var input workspaceProvisionJob
 		err = json.Unmarshal(job.Input, &input)
 		if err != nil {
 			return nil, xerrors.Errorf("unmarshal workspace provision input: %w", err)
 		}
+		build, err := server.Database.GetWorkspaceBuildByID(ctx, input.WorkspaceBuildID)
+		if err != nil {
+			return nil, xerrors.Errorf("get workspace build: %w", err)
+		}
+		// The cost reserved when the job was acquired is released, since
+		// the build never recorded the cost of its resources.
+		err = restorePriorBuildCost(ctx, server.Database, build)
+		if err != nil {
+			return nil, err
+		}
+		if jobType.WorkspaceBuild.State == nil {
+			break
+		}
 		err = server.Database.UpdateWorkspaceBuildByID(ctx, database.UpdateWorkspaceBuildByIDParams{
 			ID:               input.WorkspaceBuildID,
 			UpdatedAt:        database.Now(),
@@ -650,6 +827,13 @@ func (server *provisionerdServer) CompleteJob(ctx context.Context, completed *pr
 				// In any case, since this is just for the TTL, try and continue anyway.
 				server.Logger.Error(ctx, "fetch workspace for build", slog.F("workspace_build_id", workspaceBuild.ID), slog.F("workspace_id", workspaceBuild.WorkspaceID))
 			}
+
+			// The quota was checked against the estimated cost when the job
+			// was acquired, the actual cost replaces the estimate.
+			var dailyCost int32
+			for _, protoResource := range jobType.WorkspaceBuild.Resources {
+				dailyCost += protoResource.DailyCost
+			}
 			err = db.UpdateProvisionerJobWithCompleteByID(ctx, database.UpdateProvisionerJobWithCompleteByIDParams{
 				ID:        jobID,
 				UpdatedAt: database.Now(),
@@ -670,6 +854,13 @@ func (server *provisionerdServer) CompleteJob(ctx context.Context, completed *pr
 			if err != nil {
 				return xerrors.Errorf("update workspace build: %w", err)
 			}
+			err = db.UpdateWorkspaceBuildCostByID(ctx, database.UpdateWorkspaceBuildCostByIDParams{
+				ID:        workspaceBuild.ID,
+				DailyCost: dailyCost,
+			})
+			if err != nil {
+				return xerrors.Errorf("update workspace build cost: %w", err)
+			}
 			// This could be a bulk insert to improve performance.
 			for _, protoResource := range jobType.WorkspaceBuild.Resources {
 				err = insertWorkspaceResource(ctx, db, job.ID, workspaceBuild.Transition, protoResource, telemetrySnapshot)
@@ -744,6 +935,140 @@ func (server *provisionerdServer) CompleteJob(ctx context.Context, completed *pr
 	return &proto.Empty{}, nil
 }
 
+// commitQuota reserves the daily cost of a workspace build against its
+// owner's quota before the build is provisioned. The cost is estimated from
+// the resources detected for started workspaces when the template version
+// was imported. A lock is held on the owner's quota while checking it, so
+// builds acquired at the same time see each other's reservations. A message
+// for the job is returned if the owner's quota would be exceeded.
+func (server *provisionerdServer) commitQuota(ctx context.Context, workspace database.Workspace, build database.WorkspaceBuild, templateVersion database.TemplateVersion) (string, error) {
+	if server.QuotaCommitter == nil {
+		return "", nil
+	}
+	committer := server.QuotaCommitter.Load()
+	if committer == nil || *committer == nil {
+		return "", nil
+	}
+
+	resources, err := server.Database.GetWorkspaceResourcesByJobID(ctx, templateVersion.JobID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return "", xerrors.Errorf("get template version resources: %w", err)
+	}
+	var dailyCost int32
+	for _, resource := range resources {
+		if resource.Transition == database.WorkspaceTransitionStart {
+			dailyCost += resource.DailyCost
+		}
+	}
+	if dailyCost == 0 {
+		return "", nil
+	}
+
+	var message string
+	err = server.Database.InTx(func(db database.Store) error {
+		err := db.AcquireLockForUserQuota(ctx, workspace.OwnerID)
+		if err != nil {
+			return xerrors.Errorf("acquire quota lock: %w", err)
+		}
+		// The build is the workspace's latest and has no cost yet, so the
+		// workspace's previous build isn't counted against it.
+		result, err := (*committer).CommitQuota(ctx, db, workspace.OwnerID, dailyCost)
+		if err != nil {
+			return err
+		}
+		if !result.Permitted {
+			server.Logger.Info(ctx, "workspace build exceeds quota",
+				slog.F("workspace_build_id", build.ID),
+				slog.F("daily_cost", dailyCost),
+				slog.F("credits_consumed", result.CreditsConsumed),
+				slog.F("budget", result.Budget))
+			message = fmt.Sprintf("Insufficient quota: this build costs %d credits per day, but only %d of %d credits remain. Stop or delete other workspaces to free up quota.",
+				dailyCost, result.Budget-result.CreditsConsumed, result.Budget)
+			return nil
+		}
+		// Reserve the cost until the build completes and records its
+		// actual cost.
+		err = db.UpdateWorkspaceBuildCostByID(ctx, database.UpdateWorkspaceBuildCostByIDParams{
+			ID:        build.ID,
+			DailyCost: dailyCost,
+		})
+		if err != nil {
+			return xerrors.Errorf("reserve workspace build cost: %w", err)
+		}
+		return nil
+	})
+	return message, err
+}
+
+// rejectWorkspaceBuild fails a workspace build before it's provisioned. The
+// workspace's resources are unchanged, so the build keeps the cost of the
+// previous build.
+func (server *provisionerdServer) rejectWorkspaceBuild(ctx context.Context, job database.ProvisionerJob, build database.WorkspaceBuild, message string) error {
+	err := server.Database.InTx(func(db database.Store) error {
+		err := restorePriorBuildCost(ctx, db, build)
+		if err != nil {
+			return err
+		}
+		err = db.UpdateProvisionerJobWithCompleteByID(ctx, database.UpdateProvisionerJobWithCompleteByIDParams{
+			ID:        job.ID,
+			UpdatedAt: database.Now(),
+			CompletedAt: sql.NullTime{
+				Time:  database.Now(),
+				Valid: true,
+			},
+			Error: sql.NullString{
+				String: message,
+				Valid:  true,
+			},
+		})
+		if err != nil {
+			return xerrors.Errorf("update provisioner job: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(provisionerJobLogsMessage{EndOfLogs: true})
+	if err != nil {
+		return xerrors.Errorf("marshal job log: %w", err)
+	}
+	err = server.Pubsub.Publish(provisionerJobLogsChannel(job.ID), data)
+	if err != nil {
+		return xerrors.Errorf("publish end of job logs: %w", err)
+	}
+	return nil
+}
+
+// restorePriorBuildCost sets the cost of a workspace build that didn't
+// complete to the cost of the workspace's previous build, releasing the
+// quota reserved for it when the job was acquired.
+func restorePriorBuildCost(ctx context.Context, db database.Store, build database.WorkspaceBuild) error {
+	var dailyCost int32
+	if build.BuildNumber > 1 {
+		prior, err := db.GetWorkspaceBuildByWorkspaceIDAndBuildNumber(ctx, database.GetWorkspaceBuildByWorkspaceIDAndBuildNumberParams{
+			WorkspaceID: build.WorkspaceID,
+			BuildNumber: build.BuildNumber - 1,
+		})
+		if err != nil {
+			return xerrors.Errorf("get prior workspace build: %w", err)
+		}
+		dailyCost = prior.DailyCost
+	}
+	if build.DailyCost == dailyCost {
+		return nil
+	}
+	err := db.UpdateWorkspaceBuildCostByID(ctx, database.UpdateWorkspaceBuildCostByIDParams{
+		ID:        build.ID,
+		DailyCost: dailyCost,
+	})
+	if err != nil {
+		return xerrors.Errorf("update workspace build cost: %w", err)
+	}
+	return nil
+}
+
 func insertWorkspaceResource(ctx context.Context, db database.Store, jobID uuid.UUID, transition database.WorkspaceTransition, protoResource *sdkproto.Resource, snapshot *telemetry.Snapshot) error {
 	resource, err := db.InsertWorkspaceResource(ctx, database.InsertWorkspaceResourceParams{
 		ID:         uuid.New(),
@@ -754,6 +1079,7 @@ func insertWorkspaceResource(ctx context.Context, db database.Store, jobID uuid.
 		Name:       protoResource.Name,
 		Hide:       protoResource.Hide,
 		Icon:       protoResource.Icon,
+		DailyCost:  protoResource.DailyCost,
 	})
 	if err != nil {
 		return xerrors.Errorf("insert provisioner job resource %q: %w", protoResource.Name, err)
@@ -804,13 +1130,43 @@ func insertWorkspaceResource(ctx context.Context, db database.Store, jobID uuid.
 				String: prAgent.StartupScript,
 				Valid:  prAgent.StartupScript != "",
 			},
+			StartupScriptTimeoutSeconds: prAgent.GetStartupScriptTimeoutSeconds(),
+			ShutdownScript: sql.NullString{
+				String: prAgent.GetShutdownScript(),
+				Valid:  prAgent.GetShutdownScript() != "",
+			},
+			ShutdownScriptTimeoutSeconds: prAgent.GetShutdownScriptTimeoutSeconds(),
+			SessionCPULimit:              prAgent.GetSessionLimits().GetCpus(),
+			SessionMemoryLimitMB:         prAgent.GetSessionLimits().GetMemoryMb(),
+			SessionPidsLimit:             prAgent.GetSessionLimits().GetPids(),
 		})
 		if err != nil {
 			return xerrors.Errorf("insert agent: %w", err)
 		}
 		snapshot.WorkspaceAgents = append(snapshot.WorkspaceAgents, telemetry.ConvertWorkspaceAgent(dbAgent))
 
+		for _, md := range prAgent.Metadata {
+			err = db.InsertWorkspaceAgentMetadata(ctx, database.InsertWorkspaceAgentMetadataParams{
+				WorkspaceAgentID: agentID,
+				DisplayName:      md.DisplayName,
+				Key:              md.Key,
+				Script:           md.Script,
+				Timeout:          md.Timeout,
+				Interval:         md.Interval,
+			})
+			if err != nil {
+				return xerrors.Errorf("insert agent metadata %q: %w", md.Key, err)
+			}
+		}
+
 		for _, app := range prAgent.Apps {
+			// Apps with a healthcheck are initializing until the agent
+			// reports their health.
+			health := database.WorkspaceAppHealthDisabled
+			if app.GetHealthcheck().GetUrl() != "" {
+				health = database.WorkspaceAppHealthInitializing
+			}
+
 			dbApp, err := db.InsertWorkspaceApp(ctx, database.InsertWorkspaceAppParams{
 				ID:        uuid.New(),
 				CreatedAt: database.Now(),
@@ -825,7 +1181,11 @@ func insertWorkspaceResource(ctx context.Context, db database.Store, jobID uuid.
 					String: app.Url,
 					Valid:  app.Url != "",
 				},
-				RelativePath: app.RelativePath,
+				RelativePath:         app.RelativePath,
+				HealthcheckUrl:       app.GetHealthcheck().GetUrl(),
+				HealthcheckInterval:  app.GetHealthcheck().GetInterval(),
+				HealthcheckThreshold: app.GetHealthcheck().GetThreshold(),
+				Health:               health,
 			})
 			if err != nil {
 				return xerrors.Errorf("insert app: %w", err)
@@ -3,6 +3,7 @@ package coderd_test
 import (
 	"context"
 	"crypto/rand"
+	"net/http"
 	"runtime"
 	"testing"
 
@@ -11,6 +12,7 @@ import (
 
 	"github.com/coder/coder/coderd/coderdtest"
 	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/provisioner/echo"
 	"github.com/coder/coder/provisionersdk"
 	"github.com/coder/coder/testutil"
 )
@@ -74,3 +76,135 @@ func TestProvisionerDaemonsByOrganization(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+func TestProvisionerDaemonServe(t *testing.T) {
+	t.Parallel()
+	t.Run("NoAuth", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		_, err := client.ServeProvisionerDaemon(ctx, []codersdk.ProvisionerType{codersdk.ProvisionerTypeEcho}, nil)
+		require.Error(t, err)
+	})
+
+	t.Run("Forbidden", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		other := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		_, err := other.ServeProvisionerDaemon(ctx, []codersdk.ProvisionerType{codersdk.ProvisionerTypeEcho}, nil)
+		require.Error(t, err)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+
+	t.Run("UnknownProvisioner", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		_, err := client.ServeProvisionerDaemon(ctx, []codersdk.ProvisionerType{"unknown"}, nil)
+		require.Error(t, err)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("Build", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		_ = coderdtest.NewExternalProvisionerDaemon(t, client, nil)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		require.Eventually(t, func() bool {
+			daemons, err := client.ProvisionerDaemons(ctx)
+			return assert.NoError(t, err) && len(daemons) == 1
+		}, testutil.WaitShort, testutil.IntervalFast)
+
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		version = coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		require.Equal(t, codersdk.ProvisionerJobSucceeded, version.Job.Status)
+	})
+	t.Run("Disconnect", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		daemon, err := client.ServeProvisionerDaemon(ctx, []codersdk.ProvisionerType{codersdk.ProvisionerTypeEcho}, nil)
+		require.NoError(t, err)
+		require.Eventually(t, func() bool {
+			daemons, err := client.ProvisionerDaemons(ctx)
+			return assert.NoError(t, err) && len(daemons) == 1
+		}, testutil.WaitShort, testutil.IntervalFast)
+
+		// Daemons are removed when they disconnect, so reconnecting doesn't
+		// leave stale daemons behind.
+		err = daemon.DRPCConn().Close()
+		require.NoError(t, err)
+		require.Eventually(t, func() bool {
+			daemons, err := client.ProvisionerDaemons(ctx)
+			return assert.NoError(t, err) && len(daemons) == 0
+		}, testutil.WaitShort, testutil.IntervalFast)
+	})
+	t.Run("Tags", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		_ = coderdtest.NewExternalProvisionerDaemon(t, client, map[string]string{
+			"region": "us",
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		data, err := echo.Tar(nil)
+		require.NoError(t, err)
+		file, err := client.Upload(ctx, codersdk.ContentTypeTar, data)
+		require.NoError(t, err)
+		version, err := client.CreateTemplateVersion(ctx, user.OrganizationID, codersdk.CreateTemplateVersionRequest{
+			StorageSource: file.Hash,
+			StorageMethod: codersdk.ProvisionerStorageMethodFile,
+			Provisioner:   codersdk.ProvisionerTypeEcho,
+			ProvisionerTags: map[string]string{
+				"region": "eu",
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"region": "eu"}, version.Job.Tags)
+
+		// The daemon's tags don't match, so the job must stay pending.
+		require.Never(t, func() bool {
+			version, err := client.TemplateVersion(ctx, version.ID)
+			return assert.NoError(t, err) && version.Job.Status != codersdk.ProvisionerJobPending
+		}, testutil.IntervalSlow, testutil.IntervalFast)
+
+		daemons, err := client.ProvisionerDaemons(ctx)
+		require.NoError(t, err)
+		require.Len(t, daemons, 1)
+		require.Equal(t, map[string]string{"region": "us"}, daemons[0].Tags)
+
+		_ = coderdtest.NewExternalProvisionerDaemon(t, client, map[string]string{
+			"region": "eu",
+			"zone":   "a",
+		})
+		version = coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		require.Equal(t, codersdk.ProvisionerJobSucceeded, version.Job.Status)
+	})
+}
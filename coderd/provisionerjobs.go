@@ -250,6 +250,14 @@ func (api *API) provisionerJobResources(rw http.ResponseWriter, r *http.Request,
 		})
 		return
 	}
+	agentMetadata, err := api.Database.GetWorkspaceAgentMetadataByAgentIDs(r.Context(), resourceAgentIDs)
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace agent metadata.",
+			Detail:  err.Error(),
+		})
+		return
+	}
 
 	apiResources := make([]codersdk.WorkspaceResource, 0)
 	for _, resource := range resources {
@@ -264,8 +272,14 @@ func (api *API) provisionerJobResources(rw http.ResponseWriter, r *http.Request,
 					dbApps = append(dbApps, app)
 				}
 			}
+			dbMetadata := make([]database.WorkspaceAgentMetadatum, 0)
+			for _, md := range agentMetadata {
+				if md.WorkspaceAgentID == agent.ID {
+					dbMetadata = append(dbMetadata, md)
+				}
+			}
 
-			apiAgent, err := convertWorkspaceAgent(api.DERPMap, api.TailnetCoordinator, agent, convertApps(dbApps), api.AgentInactiveDisconnectTimeout)
+			apiAgent, err := convertWorkspaceAgent(api.DERPMap, *api.TailnetCoordinator.Load(), agent, convertApps(dbApps), convertWorkspaceAgentMetadata(dbMetadata), api.AgentInactiveDisconnectTimeout)
 			if err != nil {
 				httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
 					Message: "Internal error reading job agent.",
@@ -315,6 +329,7 @@ func convertProvisionerJob(provisionerJob database.ProvisionerJob) codersdk.Prov
 		CreatedAt:     provisionerJob.CreatedAt,
 		Error:         provisionerJob.Error.String,
 		StorageSource: provisionerJob.StorageSource,
+		Tags:          provisionerJob.Tags,
 	}
 	// Applying values optional to the struct.
 	if provisionerJob.StartedAt.Valid {
@@ -19,7 +19,8 @@ type subject struct {
 	// For the unit test we want to pass in the roles directly, instead of just
 	// by name. This allows us to test custom roles that do not exist in the product,
 	// but test edge cases of the implementation.
-	Roles []Role `json:"roles"`
+	Roles  []Role   `json:"roles"`
+	Groups []string `json:"groups"`
 }
 
 type fakeObject struct {
@@ -42,7 +43,7 @@ func TestFilterError(t *testing.T) {
 	auth, err := NewAuthorizer()
 	require.NoError(t, err)
 
-	_, err = Filter(context.Background(), auth, uuid.NewString(), []string{}, ScopeAll, ActionRead, []Object{ResourceUser, ResourceWorkspace})
+	_, err = Filter(context.Background(), auth, uuid.NewString(), []string{}, ScopeAll, []string{}, ActionRead, []Object{ResourceUser, ResourceWorkspace})
 	require.ErrorContains(t, err, "object types must be uniform")
 }
 
@@ -171,7 +172,7 @@ func TestFilter(t *testing.T) {
 			var allowedCount int
 			for i, obj := range localObjects {
 				obj.Type = tc.ObjectType
-				err := auth.ByRoleName(ctx, tc.SubjectID, tc.Roles, scope, ActionRead, obj.RBACObject())
+				err := auth.ByRoleName(ctx, tc.SubjectID, tc.Roles, scope, []string{}, ActionRead, obj.RBACObject())
 				obj.Allowed = err == nil
 				if err == nil {
 					allowedCount++
@@ -180,7 +181,7 @@ func TestFilter(t *testing.T) {
 			}
 
 			// Run by filter
-			list, err := Filter(ctx, auth, tc.SubjectID, tc.Roles, scope, tc.Action, localObjects)
+			list, err := Filter(ctx, auth, tc.SubjectID, tc.Roles, scope, []string{}, tc.Action, localObjects)
 			require.NoError(t, err)
 			require.Equal(t, allowedCount, len(list), "expected number of allowed")
 			for _, obj := range list {
@@ -657,6 +658,146 @@ func TestAuthorizeScope(t *testing.T) {
 	})
 }
 
+// TestAuthorizeBuiltinScopes ensures the scopes API keys can be minted with
+// only allow the actions they're meant for.
+func TestAuthorizeBuiltinScopes(t *testing.T) {
+	t.Parallel()
+
+	defOrg := uuid.New()
+	me := "me"
+	authorizer, err := NewAuthorizer()
+	require.NoError(t, err)
+	testCases := []struct {
+		name     string
+		scope    Scope
+		resource Object
+		action   Action
+		allow    bool
+	}{
+		{name: "ReadOnly/ReadWorkspace", scope: ScopeReadOnly, resource: ResourceWorkspace.InOrg(defOrg).WithOwner(me), action: ActionRead, allow: true},
+		{name: "ReadOnly/ReadTemplate", scope: ScopeReadOnly, resource: ResourceTemplate.InOrg(defOrg), action: ActionRead, allow: true},
+		{name: "ReadOnly/UpdateWorkspace", scope: ScopeReadOnly, resource: ResourceWorkspace.InOrg(defOrg).WithOwner(me), action: ActionUpdate, allow: false},
+		{name: "ReadOnly/CreateAPIKey", scope: ScopeReadOnly, resource: ResourceAPIKey.WithOwner(me), action: ActionCreate, allow: false},
+		{name: "ReadOnly/Execute", scope: ScopeReadOnly, resource: ResourceWorkspaceExecution.InOrg(defOrg).WithOwner(me), action: ActionCreate, allow: false},
+
+		{name: "WorkspaceLifecycle/CreateWorkspace", scope: ScopeWorkspaceLifecycle, resource: ResourceWorkspace.InOrg(defOrg).WithOwner(me), action: ActionCreate, allow: true},
+		{name: "WorkspaceLifecycle/DeleteWorkspace", scope: ScopeWorkspaceLifecycle, resource: ResourceWorkspace.InOrg(defOrg).WithOwner(me), action: ActionDelete, allow: true},
+		{name: "WorkspaceLifecycle/ReadTemplate", scope: ScopeWorkspaceLifecycle, resource: ResourceTemplate.InOrg(defOrg), action: ActionRead, allow: true},
+		{name: "WorkspaceLifecycle/UpdateTemplate", scope: ScopeWorkspaceLifecycle, resource: ResourceTemplate.InOrg(defOrg), action: ActionUpdate, allow: false},
+		{name: "WorkspaceLifecycle/Execute", scope: ScopeWorkspaceLifecycle, resource: ResourceWorkspaceExecution.InOrg(defOrg).WithOwner(me), action: ActionCreate, allow: false},
+
+		{name: "TemplatePush/CreateTemplate", scope: ScopeTemplatePush, resource: ResourceTemplate.InOrg(defOrg), action: ActionCreate, allow: true},
+		{name: "TemplatePush/UpdateTemplate", scope: ScopeTemplatePush, resource: ResourceTemplate.InOrg(defOrg), action: ActionUpdate, allow: true},
+		{name: "TemplatePush/UploadFile", scope: ScopeTemplatePush, resource: ResourceFile.WithOwner(me), action: ActionCreate, allow: true},
+		{name: "TemplatePush/DeleteTemplate", scope: ScopeTemplatePush, resource: ResourceTemplate.InOrg(defOrg), action: ActionDelete, allow: false},
+		{name: "TemplatePush/CreateWorkspace", scope: ScopeTemplatePush, resource: ResourceWorkspace.InOrg(defOrg).WithOwner(me), action: ActionCreate, allow: false},
+
+		{name: "AgentConnect/Execute", scope: ScopeAgentConnect, resource: ResourceWorkspaceExecution.InOrg(defOrg).WithOwner(me), action: ActionCreate, allow: true},
+		{name: "AgentConnect/ApplicationConnect", scope: ScopeAgentConnect, resource: ResourceWorkspaceApplicationConnect.InOrg(defOrg).WithOwner(me), action: ActionCreate, allow: true},
+		{name: "AgentConnect/ReadWorkspace", scope: ScopeAgentConnect, resource: ResourceWorkspace.InOrg(defOrg).WithOwner(me), action: ActionRead, allow: true},
+		{name: "AgentConnect/UpdateWorkspace", scope: ScopeAgentConnect, resource: ResourceWorkspace.InOrg(defOrg).WithOwner(me), action: ActionUpdate, allow: false},
+		{name: "AgentConnect/ReadTemplate", scope: ScopeAgentConnect, resource: ResourceTemplate.InOrg(defOrg), action: ActionRead, allow: false},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitShort)
+			defer cancel()
+
+			// Admins can do anything, so only the scope restricts them.
+			err := authorizer.ByRoleName(ctx, me, []string{RoleOwner()}, tc.scope, []string{}, tc.action, tc.resource)
+			if tc.allow {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
+
+// TestAuthorizeACL ensures access control lists on an object grant access to
+// users and groups, and that the partial authorizer agrees.
+func TestAuthorizeACL(t *testing.T) {
+	t.Parallel()
+
+	defOrg := uuid.New()
+	unusedID := uuid.New()
+	groupID := uuid.NewString()
+	user := subject{
+		UserID: "me",
+		Roles: []Role{
+			must(RoleByName(RoleMember())),
+			must(RoleByName(RoleOrgMember(defOrg))),
+		},
+		Groups: []string{groupID},
+	}
+
+	authorizer, err := NewAuthorizer()
+	require.NoError(t, err)
+	testCases := []struct {
+		name     string
+		resource Object
+		action   Action
+		allow    bool
+	}{
+		{name: "NoACL", resource: ResourceTemplate.InOrg(defOrg), action: ActionRead, allow: false},
+		{name: "UserRead", resource: ResourceTemplate.InOrg(defOrg).WithACLUserList(map[string][]Action{
+			user.UserID: {ActionRead},
+		}), action: ActionRead, allow: true},
+		{name: "UserReadNotUpdate", resource: ResourceTemplate.InOrg(defOrg).WithACLUserList(map[string][]Action{
+			user.UserID: {ActionRead},
+		}), action: ActionUpdate, allow: false},
+		{name: "UserWildcard", resource: ResourceTemplate.InOrg(defOrg).WithACLUserList(map[string][]Action{
+			user.UserID: {WildcardSymbol},
+		}), action: ActionDelete, allow: true},
+		{name: "OtherUser", resource: ResourceTemplate.InOrg(defOrg).WithACLUserList(map[string][]Action{
+			"not-me": {WildcardSymbol},
+		}), action: ActionRead, allow: false},
+		{name: "Group", resource: ResourceTemplate.InOrg(defOrg).WithGroupACL(map[string][]Action{
+			groupID: {ActionRead},
+		}), action: ActionRead, allow: true},
+		{name: "OtherGroup", resource: ResourceTemplate.InOrg(defOrg).WithGroupACL(map[string][]Action{
+			uuid.NewString(): {ActionRead},
+		}), action: ActionRead, allow: false},
+		{name: "Everyone", resource: ResourceTemplate.InOrg(defOrg).WithGroupACL(map[string][]Action{
+			defOrg.String(): {ActionRead},
+		}), action: ActionRead, allow: true},
+		{name: "OtherOrgGroup", resource: ResourceTemplate.InOrg(unusedID).WithGroupACL(map[string][]Action{
+			groupID:           {ActionRead},
+			unusedID.String(): {ActionRead},
+		}), action: ActionRead, allow: false},
+		{name: "OtherOrgUser", resource: ResourceTemplate.InOrg(unusedID).WithACLUserList(map[string][]Action{
+			user.UserID: {ActionRead},
+		}), action: ActionRead, allow: false},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitShort)
+			defer cancel()
+
+			err := authorizer.Authorize(ctx, user.UserID, user.Roles, user.Groups, tc.action, tc.resource)
+			if tc.allow {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+
+			partialAuthz, err := authorizer.Prepare(ctx, user.UserID, user.Roles, ScopeAll, user.Groups, tc.action, tc.resource.Type)
+			require.NoError(t, err)
+			require.Empty(t, partialAuthz.mainAuthorizer.partialQueries.Support, "expected 0 support rules")
+			err = partialAuthz.Authorize(ctx, tc.resource)
+			if tc.allow {
+				require.NoError(t, err, "partial error blocked valid request (false negative)")
+			} else {
+				require.Error(t, err, "partial allowed invalid request (false positive)")
+			}
+		})
+	}
+}
+
 // cases applies a given function to all test cases. This makes generalities easier to create.
 func cases(opt func(c authTestCase) authTestCase, cases []authTestCase) []authTestCase {
 	if opt == nil {
@@ -691,7 +832,7 @@ func testAuthorize(t *testing.T, name string, subject subject, sets ...[]authTes
 					ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitShort)
 					t.Cleanup(cancel)
 
-					authError := authorizer.Authorize(ctx, subject.UserID, subject.Roles, a, c.resource)
+					authError := authorizer.Authorize(ctx, subject.UserID, subject.Roles, subject.Groups, a, c.resource)
 
 					// Logging only
 					if authError != nil {
@@ -716,7 +857,7 @@ func testAuthorize(t *testing.T, name string, subject subject, sets ...[]authTes
 						assert.Error(t, authError, "expected unauthorized")
 					}
 
-					partialAuthz, err := authorizer.Prepare(ctx, subject.UserID, subject.Roles, ScopeAll, a, c.resource.Type)
+					partialAuthz, err := authorizer.Prepare(ctx, subject.UserID, subject.Roles, ScopeAll, subject.Groups, a, c.resource.Type)
 					require.NoError(t, err, "make prepared authorizer")
 
 					// Also check the rego policy can form a valid partial query result.
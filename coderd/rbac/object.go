@@ -133,6 +133,21 @@ var (
 	ResourceLicense = Object{
 		Type: "license",
 	}
+
+	// ResourceGroup is a group of users in an organization.
+	// 	create/delete = make or delete a group.
+	// 	read = view a group and its members
+	// 	update = rename a group or change its members
+	ResourceGroup = Object{
+		Type: "group",
+	}
+
+	// ResourceReplicas are the coderd replicas in a deployment.
+	// ResourceReplicas is site wide.
+	// 	read = view replicas and their health
+	ResourceReplicas = Object{
+		Type: "replicas",
+	}
 )
 
 // Object is used to create objects for authz checks when you have none in
@@ -147,7 +162,12 @@ type Object struct {
 
 	// Type is "workspace", "project", "app", etc
 	Type string `json:"type"`
-	// TODO: SharedUsers?
+
+	// ACLUserList and ACLGroupList grant actions on this specific object to
+	// users and groups, keyed by their IDs. The ID of an organization is
+	// the group containing every member of that organization.
+	ACLUserList  map[string][]Action `json:"acl_user_list"`
+	ACLGroupList map[string][]Action `json:"acl_group_list"`
 }
 
 func (z Object) RBACObject() Object {
@@ -166,17 +186,53 @@ func (z Object) All() Object {
 // InOrg adds an org OwnerID to the resource
 func (z Object) InOrg(orgID uuid.UUID) Object {
 	return Object{
-		Owner: z.Owner,
-		OrgID: orgID.String(),
-		Type:  z.Type,
+		Owner:        z.Owner,
+		OrgID:        orgID.String(),
+		Type:         z.Type,
+		ACLUserList:  z.ACLUserList,
+		ACLGroupList: z.ACLGroupList,
 	}
 }
 
 // WithOwner adds an OwnerID to the resource
 func (z Object) WithOwner(ownerID string) Object {
 	return Object{
-		Owner: ownerID,
+		Owner:        ownerID,
+		OrgID:        z.OrgID,
+		Type:         z.Type,
+		ACLUserList:  z.ACLUserList,
+		ACLGroupList: z.ACLGroupList,
+	}
+}
+
+// withoutACL removes the access control lists from the object. API key
+// scopes are checked without ACLs, so an ACL can never widen a scope.
+func (z Object) withoutACL() Object {
+	return Object{
+		Owner: z.Owner,
 		OrgID: z.OrgID,
 		Type:  z.Type,
 	}
 }
+
+// WithACLUserList adds an ACL list to a given object
+func (z Object) WithACLUserList(acl map[string][]Action) Object {
+	return Object{
+		Owner:        z.Owner,
+		OrgID:        z.OrgID,
+		Type:         z.Type,
+		ACLUserList:  acl,
+		ACLGroupList: z.ACLGroupList,
+	}
+}
+
+// WithGroupACL adds a group ACL list to a given object
+func (z Object) WithGroupACL(groups map[string][]Action) Object {
+	return Object{
+		Owner:        z.Owner,
+		OrgID:        z.OrgID,
+		Type:         z.Type,
+		ACLUserList:  z.ACLUserList,
+		ACLGroupList: groups,
+	}
+}
@@ -29,17 +29,17 @@ func (pa *PartialAuthorizer) Authorize(ctx context.Context, object Object) error
 	}
 
 	if pa.scopeAuthorizer != nil {
-		return pa.scopeAuthorizer.Authorize(ctx, object)
+		return pa.scopeAuthorizer.Authorize(ctx, object.withoutACL())
 	}
 
 	return nil
 }
 
-func newPartialAuthorizer(ctx context.Context, subjectID string, roles []Role, scope Scope, action Action, objectType string) (*PartialAuthorizer, error) {
+func newPartialAuthorizer(ctx context.Context, subjectID string, roles []Role, scope Scope, groups []string, action Action, objectType string) (*PartialAuthorizer, error) {
 	ctx, span := tracing.StartSpan(ctx)
 	defer span.End()
 
-	pAuth, err := newSubPartialAuthorizer(ctx, subjectID, roles, action, objectType)
+	pAuth, err := newSubPartialAuthorizer(ctx, subjectID, roles, groups, action, objectType)
 	if err != nil {
 		return nil, err
 	}
@@ -51,7 +51,7 @@ func newPartialAuthorizer(ctx context.Context, subjectID string, roles []Role, s
 			return nil, xerrors.Errorf("unknown scope %q", scope)
 		}
 
-		scopeAuth, err = newSubPartialAuthorizer(ctx, subjectID, []Role{scopeRole}, action, objectType)
+		scopeAuth, err = newSubPartialAuthorizer(ctx, subjectID, []Role{scopeRole}, []string{}, action, objectType)
 		if err != nil {
 			return nil, err
 		}
@@ -78,14 +78,15 @@ type subPartialAuthorizer struct {
 	alwaysTrue bool
 }
 
-func newSubPartialAuthorizer(ctx context.Context, subjectID string, roles []Role, action Action, objectType string) (*subPartialAuthorizer, error) {
+func newSubPartialAuthorizer(ctx context.Context, subjectID string, roles []Role, groups []string, action Action, objectType string) (*subPartialAuthorizer, error) {
 	ctx, span := tracing.StartSpan(ctx)
 	defer span.End()
 
 	input := map[string]interface{}{
 		"subject": authSubject{
-			ID:    subjectID,
-			Roles: roles,
+			ID:     subjectID,
+			Roles:  roles,
+			Groups: groups,
 		},
 		"object": map[string]string{
 			"type": objectType,
@@ -101,6 +102,8 @@ func newSubPartialAuthorizer(ctx context.Context, subjectID string, roles []Role
 		rego.Unknowns([]string{
 			"input.object.owner",
 			"input.object.org_owner",
+			"input.object.acl_user_list",
+			"input.object.acl_group_list",
 		}),
 		rego.Input(input),
 	).Partial(ctx)
@@ -132,6 +132,37 @@ user := num {
     num := number(allow)
 }
 
+# ACLs grant actions on a single object to users or groups. They are only
+# evaluated for objects that set them, such as templates.
+
+# ACL for users
+acl_allow {
+	# Users removed from the organization lose access granted to them.
+	org_mem
+	perms := input.object.acl_user_list[input.subject.id]
+	# Either the input action or wildcard
+	[input.action, "*"][_] in perms
+}
+
+# ACL for groups
+acl_allow {
+	# A group can only grant access to objects in the organization of the
+	# subject.
+	org_mem
+	group := input.subject.groups[_]
+	perms := input.object.acl_group_list[group]
+	# Either the input action or wildcard
+	[input.action, "*"][_] in perms
+}
+
+# ACL for the "Everyone" group. It shares the ID of the organization and
+# implicitly contains every member of it.
+acl_allow {
+	org_mem
+	perms := input.object.acl_group_list[input.object.org_owner]
+	[input.action, "*"][_] in perms
+}
+
 # The allow block is quite simple. Any set with `-1` cascades down in levels.
 # Authorization looks for any `allow` statement that is true. Multiple can be true!
 # Note that the absence of `allow` means "unauthorized".
@@ -156,3 +187,10 @@ allow {
 	org_mem
 	user = 1
 }
+
+allow {
+	# Negated site or org permissions take precedence over ACLs.
+	not site = -1
+	not org = -1
+	acl_allow
+}
@@ -11,6 +11,17 @@ type Scope string
 const (
 	ScopeAll                Scope = "all"
 	ScopeApplicationConnect Scope = "application_connect"
+	// ScopeReadOnly allows reading every resource the user can read.
+	ScopeReadOnly Scope = "read_only"
+	// ScopeWorkspaceLifecycle allows creating, starting, stopping and deleting
+	// workspaces, e.g. for a bot that manages preview environments.
+	ScopeWorkspaceLifecycle Scope = "workspace_lifecycle"
+	// ScopeTemplatePush allows creating templates and pushing new versions,
+	// e.g. for a CI pipeline.
+	ScopeTemplatePush Scope = "template_push"
+	// ScopeAgentConnect allows connecting to workspaces, e.g. over SSH or
+	// port forwarding, and to their applications.
+	ScopeAgentConnect Scope = "agent_connect"
 )
 
 var builtinScopes map[Scope]Role = map[Scope]Role{
@@ -19,8 +30,8 @@ var builtinScopes map[Scope]Role = map[Scope]Role{
 	ScopeAll: {
 		Name:        fmt.Sprintf("Scope_%s", ScopeAll),
 		DisplayName: "All operations",
-		Site: permissions(map[Object][]Action{
-			ResourceWildcard: {WildcardSymbol},
+		Site: permissions(map[string][]Action{
+			ResourceWildcard.Type: {WildcardSymbol},
 		}),
 		Org:  map[string][]Permission{},
 		User: []Permission{},
@@ -29,8 +40,65 @@ var builtinScopes map[Scope]Role = map[Scope]Role{
 	ScopeApplicationConnect: {
 		Name:        fmt.Sprintf("Scope_%s", ScopeApplicationConnect),
 		DisplayName: "Ability to connect to applications",
-		Site: permissions(map[Object][]Action{
-			ResourceWorkspaceApplicationConnect: {ActionCreate},
+		Site: permissions(map[string][]Action{
+			ResourceWorkspaceApplicationConnect.Type: {ActionCreate},
+		}),
+		Org:  map[string][]Permission{},
+		User: []Permission{},
+	},
+
+	ScopeReadOnly: {
+		Name:        fmt.Sprintf("Scope_%s", ScopeReadOnly),
+		DisplayName: "Read-only access",
+		Site: permissions(map[string][]Action{
+			ResourceWildcard.Type: {ActionRead},
+		}),
+		Org:  map[string][]Permission{},
+		User: []Permission{},
+	},
+
+	ScopeWorkspaceLifecycle: {
+		Name:        fmt.Sprintf("Scope_%s", ScopeWorkspaceLifecycle),
+		DisplayName: "Ability to manage the lifecycle of workspaces",
+		Site: permissions(map[string][]Action{
+			ResourceWorkspace.Type: {ActionCreate, ActionRead, ActionUpdate, ActionDelete},
+			// Creating a workspace requires reading its template.
+			ResourceTemplate.Type:           {ActionRead},
+			ResourceUser.Type:               {ActionRead},
+			ResourceOrganization.Type:       {ActionRead},
+			ResourceOrganizationMember.Type: {ActionRead},
+			ResourceProvisionerDaemon.Type:  {ActionRead},
+		}),
+		Org:  map[string][]Permission{},
+		User: []Permission{},
+	},
+
+	ScopeTemplatePush: {
+		Name:        fmt.Sprintf("Scope_%s", ScopeTemplatePush),
+		DisplayName: "Ability to create templates and push versions",
+		Site: permissions(map[string][]Action{
+			ResourceTemplate.Type: {ActionCreate, ActionRead, ActionUpdate},
+			// Template source code is uploaded as a file.
+			ResourceFile.Type:               {ActionCreate, ActionRead},
+			ResourceUser.Type:               {ActionRead},
+			ResourceOrganization.Type:       {ActionRead},
+			ResourceOrganizationMember.Type: {ActionRead},
+			ResourceProvisionerDaemon.Type:  {ActionRead},
+		}),
+		Org:  map[string][]Permission{},
+		User: []Permission{},
+	},
+
+	ScopeAgentConnect: {
+		Name:        fmt.Sprintf("Scope_%s", ScopeAgentConnect),
+		DisplayName: "Ability to connect to workspaces and applications",
+		Site: permissions(map[string][]Action{
+			// Connecting requires finding the workspace and its agents.
+			ResourceWorkspace.Type:                   {ActionRead},
+			ResourceWorkspaceExecution.Type:          {ActionCreate},
+			ResourceWorkspaceApplicationConnect.Type: {ActionCreate},
+			ResourceUser.Type:                        {ActionRead},
+			ResourceOrganization.Type:                {ActionRead},
 		}),
 		Org:  map[string][]Permission{},
 		User: []Permission{},
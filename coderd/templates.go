@@ -259,6 +259,9 @@ func (api *API) postTemplateByOrganization(rw http.ResponseWriter, r *http.Reque
 			MaxTtl:               int64(maxTTL),
 			MinAutostartInterval: int64(minAutostartInterval),
 			CreatedBy:            apiKey.UserID,
+			UserACL:              database.TemplateACL{},
+			GroupACL:             defaultTemplateGroupACL(organization.ID),
+			RequireActiveVersion: createTemplate.RequireActiveVersion,
 		})
 		if err != nil {
 			return xerrors.Errorf("insert template: %s", err)
@@ -499,11 +502,17 @@ func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 			count = uint32(workspaceCounts[0].Count)
 		}
 
+		requireActiveVersion := template.RequireActiveVersion
+		if req.RequireActiveVersion != nil {
+			requireActiveVersion = *req.RequireActiveVersion
+		}
+
 		if req.Name == template.Name &&
 			req.Description == template.Description &&
 			req.Icon == template.Icon &&
 			req.MaxTTLMillis == time.Duration(template.MaxTtl).Milliseconds() &&
-			req.MinAutostartIntervalMillis == time.Duration(template.MinAutostartInterval).Milliseconds() {
+			req.MinAutostartIntervalMillis == time.Duration(template.MinAutostartInterval).Milliseconds() &&
+			requireActiveVersion == template.RequireActiveVersion {
 			return nil
 		}
 
@@ -532,6 +541,7 @@ func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 			Icon:                 icon,
 			MaxTtl:               int64(maxTTL),
 			MinAutostartInterval: int64(minAutostartInterval),
+			RequireActiveVersion: requireActiveVersion,
 		})
 		if err != nil {
 			return err
@@ -580,6 +590,23 @@ func (api *API) templateDAUs(rw http.ResponseWriter, r *http.Request) {
 	httpapi.Write(rw, http.StatusOK, resp)
 }
 
+func (api *API) templateUsage(rw http.ResponseWriter, r *http.Request) {
+	template := httpmw.TemplateParam(r)
+	if !api.Authorize(r, rbac.ActionRead, template) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	resp, _ := api.metricsCache.TemplateUsage(template.ID)
+	if resp == nil || resp.Entries == nil {
+		httpapi.Write(rw, http.StatusOK, &codersdk.AgentUsageResponse{
+			Entries: []codersdk.AgentUsageEntry{},
+		})
+		return
+	}
+	httpapi.Write(rw, http.StatusOK, resp)
+}
+
 type autoImportTemplateOpts struct {
 	name    string
 	archive []byte
@@ -679,6 +706,8 @@ func (api *API) autoImportTemplate(ctx context.Context, opts autoImportTemplateO
 			MaxTtl:               int64(maxTTLDefault),
 			MinAutostartInterval: int64(minAutostartIntervalDefault),
 			CreatedBy:            opts.userID,
+			UserACL:              database.TemplateACL{},
+			GroupACL:             defaultTemplateGroupACL(opts.orgID),
 		})
 		if err != nil {
 			return xerrors.Errorf("insert template: %w", err)
@@ -720,6 +749,15 @@ func (api *API) autoImportTemplate(ctx context.Context, opts autoImportTemplateO
 	return template, err
 }
 
+// defaultTemplateGroupACL grants every member of the organization
+// permission to use a newly created template through the "Everyone" group,
+// which shares the organization's ID.
+func defaultTemplateGroupACL(organizationID uuid.UUID) database.TemplateACL {
+	return database.TemplateACL{
+		organizationID.String(): []rbac.Action{rbac.ActionRead},
+	}
+}
+
 func getCreatedByNamesByTemplateIDs(ctx context.Context, db database.Store, templates []database.Template) (map[string]string, error) {
 	creators := make(map[string]string, len(templates))
 	for _, template := range templates {
@@ -778,5 +816,6 @@ func (api *API) convertTemplate(
 		MinAutostartIntervalMillis: time.Duration(template.MinAutostartInterval).Milliseconds(),
 		CreatedByID:                template.CreatedBy,
 		CreatedByName:              createdByName,
+		RequireActiveVersion:       template.RequireActiveVersion,
 	}
 }
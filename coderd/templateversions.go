@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -24,7 +25,7 @@ import (
 
 func (api *API) templateVersion(rw http.ResponseWriter, r *http.Request) {
 	templateVersion := httpmw.TemplateVersionParam(r)
-	if !api.Authorize(r, rbac.ActionRead, templateVersion) {
+	if !api.Authorize(r, rbac.ActionRead, templateVersionRBAC(r, templateVersion)) {
 		httpapi.ResourceNotFound(rw)
 		return
 	}
@@ -52,7 +53,7 @@ func (api *API) templateVersion(rw http.ResponseWriter, r *http.Request) {
 
 func (api *API) patchCancelTemplateVersion(rw http.ResponseWriter, r *http.Request) {
 	templateVersion := httpmw.TemplateVersionParam(r)
-	if !api.Authorize(r, rbac.ActionUpdate, templateVersion) {
+	if !api.Authorize(r, rbac.ActionUpdate, templateVersionRBAC(r, templateVersion)) {
 		httpapi.ResourceNotFound(rw)
 		return
 	}
@@ -96,9 +97,89 @@ func (api *API) patchCancelTemplateVersion(rw http.ResponseWriter, r *http.Reque
 	})
 }
 
+// postArchiveTemplateVersion hides a template version from version lists and
+// prevents new workspace builds from using it.
+func (api *API) postArchiveTemplateVersion(rw http.ResponseWriter, r *http.Request) {
+	api.setTemplateVersionArchived(rw, r, true)
+}
+
+// postUnarchiveTemplateVersion restores an archived template version.
+func (api *API) postUnarchiveTemplateVersion(rw http.ResponseWriter, r *http.Request) {
+	api.setTemplateVersionArchived(rw, r, false)
+}
+
+func (api *API) setTemplateVersionArchived(rw http.ResponseWriter, r *http.Request, archived bool) {
+	var (
+		templateVersion   = httpmw.TemplateVersionParam(r)
+		auditor           = *api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.TemplateVersion](rw, &audit.RequestParams{
+			Audit:   auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionWrite,
+		})
+	)
+	defer commitAudit()
+	aReq.Old = templateVersion
+
+	if !api.Authorize(r, rbac.ActionUpdate, templateVersionRBAC(r, templateVersion)) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if !templateVersion.TemplateID.Valid {
+		httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Only template versions that belong to a template can be archived.",
+		})
+		return
+	}
+
+	if archived {
+		template, err := api.Database.GetTemplateByID(r.Context(), templateVersion.TemplateID.UUID)
+		if err != nil {
+			httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching template.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		if template.ActiveVersionID == templateVersion.ID {
+			httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+				Message: "The active template version can't be archived. Promote another version first.",
+			})
+			return
+		}
+	}
+
+	updatedAt := database.Now()
+	err := api.Database.UpdateTemplateVersionArchivedByID(r.Context(), database.UpdateTemplateVersionArchivedByIDParams{
+		ID:        templateVersion.ID,
+		Archived:  archived,
+		UpdatedAt: updatedAt,
+	})
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating template version.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	newTemplateVersion := templateVersion
+	newTemplateVersion.Archived = archived
+	newTemplateVersion.UpdatedAt = updatedAt
+	aReq.New = newTemplateVersion
+
+	message := "Template version has been archived."
+	if !archived {
+		message = "Template version has been unarchived."
+	}
+	httpapi.Write(rw, http.StatusOK, codersdk.Response{
+		Message: message,
+	})
+}
+
 func (api *API) templateVersionSchema(rw http.ResponseWriter, r *http.Request) {
 	templateVersion := httpmw.TemplateVersionParam(r)
-	if !api.Authorize(r, rbac.ActionRead, templateVersion) {
+	if !api.Authorize(r, rbac.ActionRead, templateVersionRBAC(r, templateVersion)) {
 		httpapi.ResourceNotFound(rw)
 		return
 	}
@@ -146,7 +227,7 @@ func (api *API) templateVersionSchema(rw http.ResponseWriter, r *http.Request) {
 func (api *API) templateVersionParameters(rw http.ResponseWriter, r *http.Request) {
 	apiKey := httpmw.APIKey(r)
 	templateVersion := httpmw.TemplateVersionParam(r)
-	if !api.Authorize(r, rbac.ActionRead, templateVersion) {
+	if !api.Authorize(r, rbac.ActionRead, templateVersionRBAC(r, templateVersion)) {
 		httpapi.ResourceNotFound(rw)
 		return
 	}
@@ -190,7 +271,7 @@ func (api *API) templateVersionParameters(rw http.ResponseWriter, r *http.Reques
 func (api *API) postTemplateVersionDryRun(rw http.ResponseWriter, r *http.Request) {
 	apiKey := httpmw.APIKey(r)
 	templateVersion := httpmw.TemplateVersionParam(r)
-	if !api.Authorize(r, rbac.ActionRead, templateVersion) {
+	if !api.Authorize(r, rbac.ActionRead, templateVersionRBAC(r, templateVersion)) {
 		httpapi.ResourceNotFound(rw)
 		return
 	}
@@ -264,6 +345,7 @@ func (api *API) postTemplateVersionDryRun(rw http.ResponseWriter, r *http.Reques
 		StorageSource:  job.StorageSource,
 		Type:           database.ProvisionerJobTypeTemplateVersionDryRun,
 		Input:          input,
+		Tags:           job.Tags,
 	})
 	if err != nil {
 		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
@@ -354,7 +436,7 @@ func (api *API) fetchTemplateVersionDryRunJob(rw http.ResponseWriter, r *http.Re
 		templateVersion = httpmw.TemplateVersionParam(r)
 		jobID           = chi.URLParam(r, "jobID")
 	)
-	if !api.Authorize(r, rbac.ActionRead, templateVersion) {
+	if !api.Authorize(r, rbac.ActionRead, templateVersionRBAC(r, templateVersion)) {
 		httpapi.ResourceNotFound(rw)
 		return database.ProvisionerJob{}, false
 	}
@@ -423,6 +505,21 @@ func (api *API) templateVersionsByTemplate(rw http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	includeArchived := false
+	if s := r.URL.Query().Get("include_archived"); s != "" {
+		var err error
+		includeArchived, err = strconv.ParseBool(s)
+		if err != nil {
+			httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+				Message: fmt.Sprintf("Invalid boolean value %q for \"include_archived\" query param.", s),
+				Validations: []codersdk.ValidationError{
+					{Field: "include_archived", Detail: "Must be a valid boolean"},
+				},
+			})
+			return
+		}
+	}
+
 	var err error
 	apiVersions := []codersdk.TemplateVersion{}
 	err = api.Database.InTx(func(store database.Store) error {
@@ -445,10 +542,11 @@ func (api *API) templateVersionsByTemplate(rw http.ResponseWriter, r *http.Reque
 		}
 
 		versions, err := store.GetTemplateVersionsByTemplateID(r.Context(), database.GetTemplateVersionsByTemplateIDParams{
-			TemplateID: template.ID,
-			AfterID:    paginationParams.AfterID,
-			LimitOpt:   int32(paginationParams.Limit),
-			OffsetOpt:  int32(paginationParams.Offset),
+			TemplateID:      template.ID,
+			AfterID:         paginationParams.AfterID,
+			IncludeArchived: includeArchived,
+			LimitOpt:        int32(paginationParams.Limit),
+			OffsetOpt:       int32(paginationParams.Offset),
 		})
 		if errors.Is(err, sql.ErrNoRows) {
 			httpapi.Write(rw, http.StatusOK, apiVersions)
@@ -599,6 +697,12 @@ func (api *API) patchActiveTemplateVersion(rw http.ResponseWriter, r *http.Reque
 		})
 		return
 	}
+	if version.Archived {
+		httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+			Message: "The provided template version is archived. Unarchive it to make it active.",
+		})
+		return
+	}
 
 	err = api.Database.InTx(func(store database.Store) error {
 		err = store.UpdateTemplateActiveVersionByID(r.Context(), database.UpdateTemplateActiveVersionByIDParams{
@@ -769,6 +873,7 @@ func (api *API) postTemplateVersionsByOrganization(rw http.ResponseWriter, r *ht
 			StorageSource:  file.Hash,
 			Type:           database.ProvisionerJobTypeTemplateVersionImport,
 			Input:          []byte{'{', '}'},
+			Tags:           req.ProvisionerTags,
 		})
 		if err != nil {
 			return xerrors.Errorf("insert provisioner job: %w", err)
@@ -795,6 +900,7 @@ func (api *API) postTemplateVersionsByOrganization(rw http.ResponseWriter, r *ht
 				UUID:  apiKey.UserID,
 				Valid: true,
 			},
+			Message: req.Message,
 		})
 		if err != nil {
 			return xerrors.Errorf("insert template version: %w", err)
@@ -828,7 +934,7 @@ func (api *API) postTemplateVersionsByOrganization(rw http.ResponseWriter, r *ht
 // return agents associated with any particular workspace.
 func (api *API) templateVersionResources(rw http.ResponseWriter, r *http.Request) {
 	templateVersion := httpmw.TemplateVersionParam(r)
-	if !api.Authorize(r, rbac.ActionRead, templateVersion) {
+	if !api.Authorize(r, rbac.ActionRead, templateVersionRBAC(r, templateVersion)) {
 		httpapi.ResourceNotFound(rw)
 		return
 	}
@@ -850,7 +956,7 @@ func (api *API) templateVersionResources(rw http.ResponseWriter, r *http.Request
 // Eg: Logs returned from 'terraform plan' when uploading a new terraform file.
 func (api *API) templateVersionLogs(rw http.ResponseWriter, r *http.Request) {
 	templateVersion := httpmw.TemplateVersionParam(r)
-	if !api.Authorize(r, rbac.ActionRead, templateVersion) {
+	if !api.Authorize(r, rbac.ActionRead, templateVersionRBAC(r, templateVersion)) {
 		httpapi.ResourceNotFound(rw)
 		return
 	}
@@ -877,6 +983,16 @@ func getUsernameByUserID(ctx context.Context, db database.Store, userID uuid.Nul
 	return user.Username, nil
 }
 
+// templateVersionRBAC returns the RBAC object for a template version
+// extracted by httpmw.ExtractTemplateVersionParam.
+func templateVersionRBAC(r *http.Request, version database.TemplateVersion) rbac.Object {
+	template, ok := httpmw.TemplateParamOptional(r)
+	if !ok {
+		return version.RBACObjectNoTemplate()
+	}
+	return version.RBACObject(template)
+}
+
 func convertTemplateVersion(version database.TemplateVersion, job codersdk.ProvisionerJob, createdByName string) codersdk.TemplateVersion {
 	return codersdk.TemplateVersion{
 		ID:             version.ID,
@@ -889,5 +1005,7 @@ func convertTemplateVersion(version database.TemplateVersion, job codersdk.Provi
 		Readme:         version.Readme,
 		CreatedByID:    version.CreatedBy.UUID,
 		CreatedByName:  createdByName,
+		Message:        version.Message,
+		Archived:       version.Archived,
 	}
 }
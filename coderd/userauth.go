@@ -15,6 +15,7 @@ import (
 	"golang.org/x/oauth2"
 	"golang.org/x/xerrors"
 
+	"github.com/coder/coder/coderd/audit"
 	"github.com/coder/coder/coderd/database"
 	"github.com/coder/coder/coderd/httpapi"
 	"github.com/coder/coder/coderd/httpmw"
@@ -50,9 +51,17 @@ func (api *API) userAuthMethods(rw http.ResponseWriter, _ *http.Request) {
 
 func (api *API) userOAuth2Github(rw http.ResponseWriter, r *http.Request) {
 	var (
-		ctx   = r.Context()
-		state = httpmw.OAuth2(r)
+		ctx               = r.Context()
+		state             = httpmw.OAuth2(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.User](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionLogin,
+		})
 	)
+	defer commitAudit()
 
 	oauthClient := oauth2.NewClient(ctx, oauth2.StaticTokenSource(state.Token))
 	memberships, err := api.GithubOAuth2Config.ListOrganizationMemberships(ctx, oauthClient)
@@ -88,6 +97,8 @@ func (api *API) userOAuth2Github(rw http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
+	aReq.Old = database.User{Username: ghUser.GetLogin()}
+	aReq.New = aReq.Old
 
 	// The default if no teams are specified is to allow all.
 	if len(api.GithubOAuth2Config.AllowTeams) > 0 {
@@ -137,7 +148,7 @@ func (api *API) userOAuth2Github(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	cookie, err := api.oauthLogin(r, oauthLoginParams{
+	cookie, user, err := api.oauthLogin(r, oauthLoginParams{
 		State:        state,
 		LinkedID:     githubLinkedID(ghUser),
 		LoginType:    database.LoginTypeGithub,
@@ -146,6 +157,11 @@ func (api *API) userOAuth2Github(rw http.ResponseWriter, r *http.Request) {
 		Username:     ghUser.GetLogin(),
 		AvatarURL:    ghUser.GetAvatarURL(),
 	})
+	if user.ID != uuid.Nil {
+		aReq.Old = user
+		aReq.New = user
+		aReq.UserID = user.ID
+	}
 	var httpErr httpError
 	if xerrors.As(err, &httpErr) {
 		httpapi.Write(rw, httpErr.code, codersdk.Response{
@@ -182,9 +198,17 @@ type OIDCConfig struct {
 
 func (api *API) userOIDC(rw http.ResponseWriter, r *http.Request) {
 	var (
-		ctx   = r.Context()
-		state = httpmw.OAuth2(r)
+		ctx               = r.Context()
+		state             = httpmw.OAuth2(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.User](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionLogin,
+		})
 	)
+	defer commitAudit()
 
 	// See the example here: https://github.com/coreos/go-oidc
 	rawIDToken, ok := state.Token.Extra("id_token").(string)
@@ -230,6 +254,8 @@ func (api *API) userOIDC(rw http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
+	aReq.Old = database.User{Username: email}
+	aReq.New = aReq.Old
 	verifiedRaw, ok := claims["email_verified"]
 	if ok {
 		verified, ok := verifiedRaw.(bool)
@@ -271,7 +297,7 @@ func (api *API) userOIDC(rw http.ResponseWriter, r *http.Request) {
 		picture, _ = pictureRaw.(string)
 	}
 
-	cookie, err := api.oauthLogin(r, oauthLoginParams{
+	cookie, user, err := api.oauthLogin(r, oauthLoginParams{
 		State:        state,
 		LinkedID:     oidcLinkedID(idToken),
 		LoginType:    database.LoginTypeOIDC,
@@ -280,6 +306,11 @@ func (api *API) userOIDC(rw http.ResponseWriter, r *http.Request) {
 		Username:     username,
 		AvatarURL:    picture,
 	})
+	if user.ID != uuid.Nil {
+		aReq.Old = user
+		aReq.New = user
+		aReq.UserID = user.ID
+	}
 	var httpErr httpError
 	if xerrors.As(err, &httpErr) {
 		httpapi.Write(rw, httpErr.code, codersdk.Response{
@@ -332,7 +363,9 @@ func (e httpError) Error() string {
 	return e.msg
 }
 
-func (api *API) oauthLogin(r *http.Request, params oauthLoginParams) (*http.Cookie, error) {
+// oauthLogin returns the user that was found or created, even if logging in
+// failed, so the attempt can be audited.
+func (api *API) oauthLogin(r *http.Request, params oauthLoginParams) (*http.Cookie, database.User, error) {
 	var (
 		ctx  = r.Context()
 		user database.User
@@ -476,7 +509,7 @@ func (api *API) oauthLogin(r *http.Request, params oauthLoginParams) (*http.Cook
 		return nil
 	})
 	if err != nil {
-		return nil, xerrors.Errorf("in tx: %w", err)
+		return nil, user, xerrors.Errorf("in tx: %w", err)
 	}
 
 	cookie, err := api.createAPIKey(r, createAPIKeyParams{
@@ -484,10 +517,10 @@ func (api *API) oauthLogin(r *http.Request, params oauthLoginParams) (*http.Cook
 		LoginType: params.LoginType,
 	})
 	if err != nil {
-		return nil, xerrors.Errorf("create API key: %w", err)
+		return nil, user, xerrors.Errorf("create API key: %w", err)
 	}
 
-	return cookie, nil
+	return cookie, user, nil
 }
 
 // githubLinkedID returns the unique ID for a GitHub user.
@@ -336,7 +336,7 @@ func (api *API) postUser(rw http.ResponseWriter, r *http.Request) {
 		Users: []telemetry.User{telemetry.ConvertUser(user)},
 	})
 
-	httpapi.Write(rw, http.StatusCreated, convertUser(user, []uuid.UUID{req.OrganizationID}))
+	httpapi.Write(rw, http.StatusCreated, ConvertUser(user, []uuid.UUID{req.OrganizationID}))
 }
 
 func (api *API) deleteUser(rw http.ResponseWriter, r *http.Request) {
@@ -410,7 +410,7 @@ func (api *API) userByName(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	httpapi.Write(rw, http.StatusOK, convertUser(user, organizationIDs))
+	httpapi.Write(rw, http.StatusOK, ConvertUser(user, organizationIDs))
 }
 
 func (api *API) putUserProfile(rw http.ResponseWriter, r *http.Request) {
@@ -489,7 +489,7 @@ func (api *API) putUserProfile(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	httpapi.Write(rw, http.StatusOK, convertUser(updatedUserProfile, organizationIDs))
+	httpapi.Write(rw, http.StatusOK, ConvertUser(updatedUserProfile, organizationIDs))
 }
 
 func (api *API) putUserStatus(status database.UserStatus) func(rw http.ResponseWriter, r *http.Request) {
@@ -556,7 +556,7 @@ func (api *API) putUserStatus(status database.UserStatus) func(rw http.ResponseW
 			return
 		}
 
-		httpapi.Write(rw, http.StatusOK, convertUser(suspendedUser, organizations))
+		httpapi.Write(rw, http.StatusOK, ConvertUser(suspendedUser, organizations))
 	}
 }
 
@@ -655,6 +655,24 @@ func (api *API) putUserPassword(rw http.ResponseWriter, r *http.Request) {
 	httpapi.Write(rw, http.StatusNoContent, nil)
 }
 
+func (api *API) userUsage(rw http.ResponseWriter, r *http.Request) {
+	user := httpmw.UserParam(r)
+
+	if !api.Authorize(r, rbac.ActionRead, rbac.ResourceUserData.WithOwner(user.ID.String())) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	resp, _ := api.metricsCache.UserUsage(user.ID)
+	if resp == nil || resp.Entries == nil {
+		httpapi.Write(rw, http.StatusOK, &codersdk.AgentUsageResponse{
+			Entries: []codersdk.AgentUsageEntry{},
+		})
+		return
+	}
+	httpapi.Write(rw, http.StatusOK, resp)
+}
+
 func (api *API) userRoles(rw http.ResponseWriter, r *http.Request) {
 	user := httpmw.UserParam(r)
 
@@ -776,7 +794,7 @@ func (api *API) putUserRoles(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	httpapi.Write(rw, http.StatusOK, convertUser(updatedUser, organizationIDs))
+	httpapi.Write(rw, http.StatusOK, ConvertUser(updatedUser, organizationIDs))
 }
 
 // updateSiteUserRoles will ensure only site wide roles are passed in as arguments.
@@ -862,6 +880,17 @@ func (api *API) organizationByUserAndName(rw http.ResponseWriter, r *http.Reques
 
 // Authenticates the user with an email and password.
 func (api *API) postLogin(rw http.ResponseWriter, r *http.Request) {
+	var (
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.User](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionLogin,
+		})
+	)
+	defer commitAudit()
+
 	var loginWithPassword codersdk.LoginWithPasswordRequest
 	if !httpapi.Read(rw, r, &loginWithPassword) {
 		return
@@ -876,6 +905,14 @@ func (api *API) postLogin(rw http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
+	if user.ID == uuid.Nil {
+		// Failed logins for unknown users are still audited, with the
+		// attempted email as the target.
+		user.Username = loginWithPassword.Email
+	}
+	aReq.Old = user
+	aReq.New = user
+	aReq.UserID = user.ID
 
 	// If the user doesn't exist, it will be a default struct.
 	equal, err := userpassword.Compare(string(user.HashedPassword), loginWithPassword.Password)
@@ -974,7 +1011,9 @@ func (api *API) apiKey(rw http.ResponseWriter, r *http.Request) {
 
 	keyID := chi.URLParam(r, "keyid")
 	key, err := api.Database.GetAPIKeyByID(ctx, keyID)
-	if errors.Is(err, sql.ErrNoRows) {
+	// The key must belong to the user, since authorization was checked for
+	// the keys of the user.
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && key.UserID != user.ID) {
 		httpapi.ResourceNotFound(rw)
 		return
 	}
@@ -991,6 +1030,29 @@ func (api *API) apiKey(rw http.ResponseWriter, r *http.Request) {
 
 // Clear the user's session cookie.
 func (api *API) postLogout(rw http.ResponseWriter, r *http.Request) {
+	var (
+		apiKey            = httpmw.APIKey(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.User](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionLogout,
+		})
+	)
+	defer commitAudit()
+
+	user, err := api.Database.GetUserByID(r.Context(), apiKey.UserID)
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching user.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.Old = user
+	aReq.New = user
+
 	// Get a blank token cookie.
 	cookie := &http.Cookie{
 		// MaxAge < 0 means to delete the cookie now.
@@ -1001,8 +1063,7 @@ func (api *API) postLogout(rw http.ResponseWriter, r *http.Request) {
 	api.setAuthCookie(rw, cookie)
 
 	// Delete the session token from database.
-	apiKey := httpmw.APIKey(r)
-	err := api.Database.DeleteAPIKeyByID(r.Context(), apiKey.ID)
+	err = api.Database.DeleteAPIKeyByID(r.Context(), apiKey.ID)
 	if err != nil {
 		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
 			Message: "Internal error deleting API key.",
@@ -1038,6 +1099,9 @@ type createAPIKeyParams struct {
 	// Optional.
 	ExpiresAt       time.Time
 	LifetimeSeconds int64
+	TokenName       string
+	// Scope defaults to "all".
+	Scope database.APIKeyScope
 }
 
 func (api *API) createAPIKey(r *http.Request, params createAPIKeyParams) (*http.Cookie, error) {
@@ -1047,6 +1111,10 @@ func (api *API) createAPIKey(r *http.Request, params createAPIKeyParams) (*http.
 	}
 	hashed := sha256.Sum256([]byte(keySecret))
 
+	if params.Scope == "" {
+		params.Scope = database.APIKeyScopeAll
+	}
+
 	// Default expires at to now+lifetime, or just 24hrs if not set
 	if params.ExpiresAt.IsZero() {
 		if params.LifetimeSeconds != 0 {
@@ -1079,7 +1147,8 @@ func (api *API) createAPIKey(r *http.Request, params createAPIKeyParams) (*http.
 		UpdatedAt:    database.Now(),
 		HashedSecret: hashed[:],
 		LoginType:    params.LoginType,
-		Scope:        database.APIKeyScopeAll,
+		Scope:        params.Scope,
+		TokenName:    params.TokenName,
 	})
 	if err != nil {
 		return nil, xerrors.Errorf("insert API key: %w", err)
@@ -1121,6 +1190,10 @@ func (api *API) CreateUser(ctx context.Context, store database.Store, req Create
 			if err != nil {
 				return xerrors.Errorf("create organization: %w", err)
 			}
+			_, err = tx.InsertAllUsersGroup(ctx, organization.ID)
+			if err != nil {
+				return xerrors.Errorf("create %q group: %w", database.AllUsersGroup, err)
+			}
 			req.OrganizationID = organization.ID
 			orgRoles = append(orgRoles, rbac.RoleOrgAdmin(req.OrganizationID))
 		}
@@ -1188,7 +1261,8 @@ func (api *API) setAuthCookie(rw http.ResponseWriter, cookie *http.Cookie) {
 	}
 }
 
-func convertUser(user database.User, organizationIDs []uuid.UUID) codersdk.User {
+// ConvertUser converts a database user to the API representation.
+func ConvertUser(user database.User, organizationIDs []uuid.UUID) codersdk.User {
 	convertedUser := codersdk.User{
 		ID:              user.ID,
 		Email:           user.Email,
@@ -1212,7 +1286,7 @@ func convertUsers(users []database.User, organizationIDsByUserID map[uuid.UUID][
 	converted := make([]codersdk.User, 0, len(users))
 	for _, u := range users {
 		userOrganizationIDs := organizationIDsByUserID[u.ID]
-		converted = append(converted, convertUser(u, userOrganizationIDs))
+		converted = append(converted, ConvertUser(u, userOrganizationIDs))
 	}
 	return converted
 }
@@ -1302,5 +1376,7 @@ func convertAPIKey(k database.APIKey) codersdk.APIKey {
 		UpdatedAt:       k.UpdatedAt,
 		LoginType:       codersdk.LoginType(k.LoginType),
 		LifetimeSeconds: k.LifetimeSeconds,
+		TokenName:       k.TokenName,
+		Scope:           codersdk.APIKeyScope(k.Scope),
 	}
 }
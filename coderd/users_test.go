@@ -259,6 +259,41 @@ func TestPostLogin(t *testing.T) {
 		require.True(t, apiKey.ExpiresAt.After(key.ExpiresAt.Add(time.Hour)), "api key should be longer expires")
 		require.Greater(t, apiKey.LifetimeSeconds, key.LifetimeSeconds, "api key should have longer lifetime")
 	})
+	t.Run("Audit", func(t *testing.T) {
+		t.Parallel()
+		auditor := audit.NewMock()
+		client := coderdtest.New(t, &coderdtest.Options{Auditor: auditor})
+		admin := coderdtest.CreateFirstUser(t, client)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		require.Len(t, auditor.AuditLogs, 1)
+		assert.Equal(t, database.AuditActionLogin, auditor.AuditLogs[0].Action)
+		assert.Equal(t, admin.UserID, auditor.AuditLogs[0].UserID)
+		assert.Equal(t, int32(http.StatusCreated), auditor.AuditLogs[0].StatusCode)
+
+		_, err := client.LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
+			Email:    coderdtest.FirstUserParams.Email,
+			Password: "badpass",
+		})
+		require.Error(t, err)
+		require.Len(t, auditor.AuditLogs, 2)
+		assert.Equal(t, database.AuditActionLogin, auditor.AuditLogs[1].Action)
+		assert.Equal(t, admin.UserID, auditor.AuditLogs[1].UserID)
+		assert.Equal(t, int32(http.StatusUnauthorized), auditor.AuditLogs[1].StatusCode)
+
+		// Unknown users are audited with the attempted email as the target.
+		_, err = client.LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
+			Email:    "unknown@coder.com",
+			Password: "badpass",
+		})
+		require.Error(t, err)
+		require.Len(t, auditor.AuditLogs, 3)
+		assert.Equal(t, uuid.Nil, auditor.AuditLogs[2].UserID)
+		assert.Equal(t, "unknown@coder.com", auditor.AuditLogs[2].ResourceTarget)
+		assert.Equal(t, int32(http.StatusUnauthorized), auditor.AuditLogs[2].StatusCode)
+	})
 }
 
 func TestDeleteUser(t *testing.T) {
@@ -350,6 +385,23 @@ func TestPostLogout(t *testing.T) {
 		require.ErrorAs(t, err, &sdkErr)
 		require.Equal(t, http.StatusUnauthorized, sdkErr.StatusCode(), "Expecting 401")
 	})
+
+	t.Run("Audit", func(t *testing.T) {
+		t.Parallel()
+		auditor := audit.NewMock()
+		client := coderdtest.New(t, &coderdtest.Options{Auditor: auditor})
+		admin := coderdtest.CreateFirstUser(t, client)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		err := client.Logout(ctx)
+		require.NoError(t, err)
+		require.Len(t, auditor.AuditLogs, 2)
+		assert.Equal(t, database.AuditActionLogout, auditor.AuditLogs[1].Action)
+		assert.Equal(t, admin.UserID, auditor.AuditLogs[1].UserID)
+		assert.Equal(t, admin.UserID, auditor.AuditLogs[1].ResourceID)
+	})
 }
 
 func TestPostUsers(t *testing.T) {
@@ -448,8 +500,8 @@ func TestPostUsers(t *testing.T) {
 		})
 		require.NoError(t, err)
 
-		require.Len(t, auditor.AuditLogs, 1)
-		assert.Equal(t, database.AuditActionCreate, auditor.AuditLogs[0].Action)
+		require.Len(t, auditor.AuditLogs, 2)
+		assert.Equal(t, database.AuditActionCreate, auditor.AuditLogs[1].Action)
 	})
 }
 
@@ -511,8 +563,8 @@ func TestUpdateUserProfile(t *testing.T) {
 		})
 		require.NoError(t, err)
 		require.Equal(t, userProfile.Username, "newusername")
-		assert.Len(t, auditor.AuditLogs, 1)
-		assert.Equal(t, database.AuditActionWrite, auditor.AuditLogs[0].Action)
+		assert.Len(t, auditor.AuditLogs, 2)
+		assert.Equal(t, database.AuditActionWrite, auditor.AuditLogs[1].Action)
 	})
 }
 
@@ -575,8 +627,8 @@ func TestUpdateUserPassword(t *testing.T) {
 			Password:    "newpassword",
 		})
 		require.NoError(t, err, "member should be able to update own password")
-		assert.Len(t, auditor.AuditLogs, 2)
-		assert.Equal(t, database.AuditActionWrite, auditor.AuditLogs[1].Action)
+		assert.Len(t, auditor.AuditLogs, 4)
+		assert.Equal(t, database.AuditActionWrite, auditor.AuditLogs[3].Action)
 	})
 	t.Run("MemberCantUpdateOwnPasswordWithoutOldPassword", func(t *testing.T) {
 		t.Parallel()
@@ -605,8 +657,8 @@ func TestUpdateUserPassword(t *testing.T) {
 			Password: "newpassword",
 		})
 		require.NoError(t, err, "admin should be able to update own password without providing old password")
-		assert.Len(t, auditor.AuditLogs, 1)
-		assert.Equal(t, database.AuditActionWrite, auditor.AuditLogs[0].Action)
+		assert.Len(t, auditor.AuditLogs, 2)
+		assert.Equal(t, database.AuditActionWrite, auditor.AuditLogs[1].Action)
 	})
 }
 
@@ -835,8 +887,8 @@ func TestPutUserSuspend(t *testing.T) {
 		user, err := client.UpdateUserStatus(ctx, user.Username, codersdk.UserStatusSuspended)
 		require.NoError(t, err)
 		require.Equal(t, user.Status, codersdk.UserStatusSuspended)
-		assert.Len(t, auditor.AuditLogs, 2)
-		assert.Equal(t, database.AuditActionWrite, auditor.AuditLogs[1].Action)
+		assert.Len(t, auditor.AuditLogs, 4)
+		assert.Equal(t, database.AuditActionWrite, auditor.AuditLogs[3].Action)
 	})
 
 	t.Run("SuspendItSelf", func(t *testing.T) {
@@ -0,0 +1,163 @@
+package coderd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"strconv"
+
+	"github.com/coder/coder/agent"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/coderd/wsconncache"
+	"github.com/coder/coder/codersdk"
+)
+
+// maxWorkspaceAgentFileUpload is the largest file that can be uploaded to a
+// workspace through coderd. Larger files should be copied with `coder cp`,
+// which connects to the agent directly.
+const maxWorkspaceAgentFileUpload = 100 << 20
+
+func (api *API) workspaceAgentListFiles(rw http.ResponseWriter, r *http.Request) {
+	conn, release, ok := api.workspaceAgentFilesConn(rw, r)
+	if !ok {
+		return
+	}
+	defer release()
+
+	resp, err := conn.ListFiles(r.Context(), r.URL.Query().Get("path"))
+	if err != nil {
+		writeWorkspaceAgentFileError(rw, "Internal error listing files.", err)
+		return
+	}
+	files := make([]codersdk.WorkspaceAgentFile, 0, len(resp.Files))
+	for _, file := range resp.Files {
+		files = append(files, convertWorkspaceAgentFile(file))
+	}
+	httpapi.Write(rw, http.StatusOK, codersdk.WorkspaceAgentListFilesResponse{
+		Path:  resp.Path,
+		Files: files,
+	})
+}
+
+func (api *API) workspaceAgentReadFile(rw http.ResponseWriter, r *http.Request) {
+	conn, release, ok := api.workspaceAgentFilesConn(rw, r)
+	if !ok {
+		return
+	}
+	defer release()
+
+	content, size, err := conn.ReadFile(r.Context(), r.URL.Query().Get("path"))
+	if err != nil {
+		writeWorkspaceAgentFileError(rw, "Internal error reading file.", err)
+		return
+	}
+	defer content.Close()
+	rw.Header().Set("Content-Type", "application/octet-stream")
+	if size >= 0 {
+		rw.Header().Set("Content-Length", strconv.FormatInt(size, 10))
+	}
+	rw.WriteHeader(http.StatusOK)
+	_, _ = io.Copy(rw, content)
+}
+
+func (api *API) workspaceAgentWriteFile(rw http.ResponseWriter, r *http.Request) {
+	conn, release, ok := api.workspaceAgentFilesConn(rw, r)
+	if !ok {
+		return
+	}
+	defer release()
+
+	mode := fs.FileMode(0o644)
+	if rawMode := r.URL.Query().Get("mode"); rawMode != "" {
+		parsed, err := strconv.ParseUint(rawMode, 8, 32)
+		if err != nil {
+			httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Query param 'mode' must be an octal file mode.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		mode = fs.FileMode(parsed)
+	}
+
+	r.Body = http.MaxBytesReader(rw, r.Body, maxWorkspaceAgentFileUpload)
+	info, err := conn.WriteFile(r.Context(), r.URL.Query().Get("path"), mode, r.Body)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			httpapi.Write(rw, http.StatusRequestEntityTooLarge, codersdk.Response{
+				Message: fmt.Sprintf("File must be smaller than %d bytes.", maxWorkspaceAgentFileUpload),
+			})
+			return
+		}
+		writeWorkspaceAgentFileError(rw, "Internal error writing file.", err)
+		return
+	}
+	httpapi.Write(rw, http.StatusOK, convertWorkspaceAgentFile(info))
+}
+
+// workspaceAgentFilesConn authorizes access to the files of a workspace and
+// acquires a connection to its agent. Reading and writing files is
+// equivalent to running commands, so the same permission is required.
+func (api *API) workspaceAgentFilesConn(rw http.ResponseWriter, r *http.Request) (*wsconncache.Conn, func(), bool) {
+	workspace := httpmw.WorkspaceParam(r)
+	workspaceAgent := httpmw.WorkspaceAgentParam(r)
+	if !api.Authorize(r, rbac.ActionCreate, workspace.ExecutionRBAC()) {
+		httpapi.ResourceNotFound(rw)
+		return nil, nil, false
+	}
+	apiAgent, err := convertWorkspaceAgent(api.DERPMap, *api.TailnetCoordinator.Load(), workspaceAgent, nil, nil, api.AgentInactiveDisconnectTimeout)
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error reading workspace agent.",
+			Detail:  err.Error(),
+		})
+		return nil, nil, false
+	}
+	if apiAgent.Status != codersdk.WorkspaceAgentConnected {
+		httpapi.Write(rw, http.StatusPreconditionRequired, codersdk.Response{
+			Message: fmt.Sprintf("Agent state is %q, it must be in the %q state.", apiAgent.Status, codersdk.WorkspaceAgentConnected),
+		})
+		return nil, nil, false
+	}
+
+	conn, release, err := api.workspaceAgentCache.Acquire(r, workspaceAgent.ID)
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error dialing workspace agent.",
+			Detail:  err.Error(),
+		})
+		return nil, nil, false
+	}
+	return conn, release, true
+}
+
+// writeWorkspaceAgentFileError responds with the status code returned by the
+// agent if it rejected the request, e.g. because the file doesn't exist.
+func writeWorkspaceAgentFileError(rw http.ResponseWriter, message string, err error) {
+	var apiErr *agent.APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode < http.StatusInternalServerError {
+		httpapi.Write(rw, apiErr.StatusCode, codersdk.Response{
+			Message: apiErr.Message,
+		})
+		return
+	}
+	httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+		Message: message,
+		Detail:  err.Error(),
+	})
+}
+
+func convertWorkspaceAgentFile(file agent.FileInfo) codersdk.WorkspaceAgentFile {
+	return codersdk.WorkspaceAgentFile{
+		Name:    file.Name,
+		Size:    file.Size,
+		Mode:    fmt.Sprintf("%04o", file.Mode.Perm()),
+		ModTime: file.ModTime,
+		IsDir:   file.IsDir,
+	}
+}
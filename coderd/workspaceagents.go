@@ -4,18 +4,21 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net"
 	"net/http"
 	"net/netip"
-	"reflect"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/exp/maps"
 	"golang.org/x/mod/semver"
 	"golang.org/x/xerrors"
 	"nhooyr.io/websocket"
@@ -24,6 +27,9 @@ import (
 
 	"cdr.dev/slog"
 	"github.com/coder/coder/agent"
+	"github.com/coder/coder/buildinfo"
+	"github.com/coder/coder/coderd/agentshutdown"
+	"github.com/coder/coder/coderd/audit"
 	"github.com/coder/coder/coderd/database"
 	"github.com/coder/coder/coderd/httpapi"
 	"github.com/coder/coder/coderd/httpmw"
@@ -48,7 +54,15 @@ func (api *API) workspaceAgent(rw http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
-	apiAgent, err := convertWorkspaceAgent(api.DERPMap, api.TailnetCoordinator, workspaceAgent, convertApps(dbApps), api.AgentInactiveDisconnectTimeout)
+	dbMetadata, err := api.Database.GetWorkspaceAgentMetadata(r.Context(), workspaceAgent.ID)
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace agent metadata.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	apiAgent, err := convertWorkspaceAgent(api.DERPMap, *api.TailnetCoordinator.Load(), workspaceAgent, convertApps(dbApps), convertWorkspaceAgentMetadata(dbMetadata), api.AgentInactiveDisconnectTimeout)
 	if err != nil {
 		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
 			Message: "Internal error reading workspace agent.",
@@ -62,7 +76,7 @@ func (api *API) workspaceAgent(rw http.ResponseWriter, r *http.Request) {
 
 func (api *API) workspaceAgentMetadata(rw http.ResponseWriter, r *http.Request) {
 	workspaceAgent := httpmw.WorkspaceAgent(r)
-	apiAgent, err := convertWorkspaceAgent(api.DERPMap, api.TailnetCoordinator, workspaceAgent, nil, api.AgentInactiveDisconnectTimeout)
+	apiAgent, err := convertWorkspaceAgent(api.DERPMap, *api.TailnetCoordinator.Load(), workspaceAgent, nil, nil, api.AgentInactiveDisconnectTimeout)
 	if err != nil {
 		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
 			Message: "Internal error reading workspace agent.",
@@ -70,18 +84,68 @@ func (api *API) workspaceAgentMetadata(rw http.ResponseWriter, r *http.Request)
 		})
 		return
 	}
+	dbMetadata, err := api.Database.GetWorkspaceAgentMetadata(r.Context(), workspaceAgent.ID)
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace agent metadata.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	metadataDescriptions := make([]agent.MetadataDescription, 0, len(dbMetadata))
+	for _, md := range dbMetadata {
+		metadataDescriptions = append(metadataDescriptions, agent.MetadataDescription{
+			DisplayName: md.DisplayName,
+			Key:         md.Key,
+			Script:      md.Script,
+			Interval:    md.Interval,
+			Timeout:     md.Timeout,
+		})
+	}
+	dbApps, err := api.Database.GetWorkspaceAppsByAgentID(r.Context(), workspaceAgent.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace agent applications.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	appHealthchecks := make([]agent.AppHealthcheck, 0)
+	for _, app := range dbApps {
+		if app.HealthcheckUrl == "" {
+			continue
+		}
+		appHealthchecks = append(appHealthchecks, agent.AppHealthcheck{
+			ID:        app.ID,
+			URL:       app.HealthcheckUrl,
+			Interval:  app.HealthcheckInterval,
+			Threshold: app.HealthcheckThreshold,
+		})
+	}
 
 	httpapi.Write(rw, http.StatusOK, agent.Metadata{
 		DERPMap:              api.DERPMap,
 		EnvironmentVariables: apiAgent.EnvironmentVariables,
 		StartupScript:        apiAgent.StartupScript,
+		StartupScriptTimeout: time.Duration(apiAgent.StartupScriptTimeoutSeconds) * time.Second,
 		Directory:            apiAgent.Directory,
+
+		ShutdownScript:        apiAgent.ShutdownScript,
+		ShutdownScriptTimeout: time.Duration(apiAgent.ShutdownScriptTimeoutSeconds) * time.Second,
+		Metadata:              metadataDescriptions,
+		AppHealthchecks:       appHealthchecks,
+		AgentVersion:          buildinfo.Version(),
+		SessionLimits: agent.SessionLimits{
+			CPUs:     workspaceAgent.SessionCPULimit,
+			MemoryMB: workspaceAgent.SessionMemoryLimitMB,
+			Pids:     workspaceAgent.SessionPidsLimit,
+		},
 	})
 }
 
 func (api *API) postWorkspaceAgentVersion(rw http.ResponseWriter, r *http.Request) {
 	workspaceAgent := httpmw.WorkspaceAgent(r)
-	apiAgent, err := convertWorkspaceAgent(api.DERPMap, api.TailnetCoordinator, workspaceAgent, nil, api.AgentInactiveDisconnectTimeout)
+	apiAgent, err := convertWorkspaceAgent(api.DERPMap, *api.TailnetCoordinator.Load(), workspaceAgent, nil, nil, api.AgentInactiveDisconnectTimeout)
 	if err != nil {
 		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
 			Message: "Internal error reading workspace agent.",
@@ -133,7 +197,7 @@ func (api *API) workspaceAgentPTY(rw http.ResponseWriter, r *http.Request) {
 		httpapi.ResourceNotFound(rw)
 		return
 	}
-	apiAgent, err := convertWorkspaceAgent(api.DERPMap, api.TailnetCoordinator, workspaceAgent, nil, api.AgentInactiveDisconnectTimeout)
+	apiAgent, err := convertWorkspaceAgent(api.DERPMap, *api.TailnetCoordinator.Load(), workspaceAgent, nil, nil, api.AgentInactiveDisconnectTimeout)
 	if err != nil {
 		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
 			Message: "Internal error reading workspace agent.",
@@ -181,6 +245,9 @@ func (api *API) workspaceAgentPTY(rw http.ResponseWriter, r *http.Request) {
 	_, wsNetConn := websocketNetConn(r.Context(), conn, websocket.MessageBinary)
 	defer wsNetConn.Close() // Also closes conn.
 
+	api.auditAgentConnection(r, workspace, workspaceAgent, database.AuditActionConnect, "reconnecting_pty")
+	defer api.auditAgentConnection(r, workspace, workspaceAgent, database.AuditActionDisconnect, "reconnecting_pty")
+
 	agentConn, release, err := api.workspaceAgentCache.Acquire(r, workspaceAgent.ID)
 	if err != nil {
 		_ = conn.Close(websocket.StatusInternalError, httpapi.WebsocketCloseSprintf("dial workspace agent: %s", err))
@@ -200,6 +267,63 @@ func (api *API) workspaceAgentPTY(rw http.ResponseWriter, r *http.Request) {
 	_, _ = io.Copy(ptNetConn, wsNetConn)
 }
 
+func (api *API) workspaceAgentListeningPorts(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+	workspaceAgent := httpmw.WorkspaceAgentParam(r)
+	if !api.Authorize(r, rbac.ActionRead, workspace) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	apiAgent, err := convertWorkspaceAgent(api.DERPMap, *api.TailnetCoordinator.Load(), workspaceAgent, nil, nil, api.AgentInactiveDisconnectTimeout)
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error reading workspace agent.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if apiAgent.Status != codersdk.WorkspaceAgentConnected {
+		httpapi.Write(rw, http.StatusPreconditionRequired, codersdk.Response{
+			Message: fmt.Sprintf("Agent state is %q, it must be in the %q state.", apiAgent.Status, codersdk.WorkspaceAgentConnected),
+		})
+		return
+	}
+
+	agentConn, release, err := api.workspaceAgentCache.Acquire(r, workspaceAgent.ID)
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error dialing workspace agent.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	portsResponse, err := agentConn.ListeningPorts(ctx)
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching listening ports.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	resp := codersdk.WorkspaceAgentListeningPortsResponse{
+		Ports: make([]codersdk.WorkspaceAgentListeningPort, 0, len(portsResponse.Ports)),
+	}
+	for _, port := range portsResponse.Ports {
+		resp.Ports = append(resp.Ports, codersdk.WorkspaceAgentListeningPort{
+			ProcessName: port.ProcessName,
+			Network:     port.Network,
+			Port:        port.Port,
+		})
+	}
+	httpapi.Write(rw, http.StatusOK, resp)
+}
+
 func (api *API) dialWorkspaceAgentTailnet(r *http.Request, agentID uuid.UUID) (*agent.Conn, error) {
 	clientConn, serverConn := net.Pipe()
 	go func() {
@@ -222,7 +346,7 @@ func (api *API) dialWorkspaceAgentTailnet(r *http.Request, agentID uuid.UUID) (*
 	})
 	conn.SetNodeCallback(sendNodes)
 	go func() {
-		err := api.TailnetCoordinator.ServeClient(serverConn, uuid.New(), agentID)
+		err := (*api.TailnetCoordinator.Load()).ServeClient(serverConn, uuid.New(), agentID)
 		if err != nil {
 			_ = conn.Close()
 		}
@@ -352,7 +476,7 @@ func (api *API) workspaceAgentCoordinate(rw http.ResponseWriter, r *http.Request
 	closeChan := make(chan struct{})
 	go func() {
 		defer close(closeChan)
-		err := api.TailnetCoordinator.ServeAgent(wsNetConn, workspaceAgent.ID)
+		err := (*api.TailnetCoordinator.Load()).ServeAgent(wsNetConn, workspaceAgent.ID)
 		if err != nil {
 			_ = conn.Close(websocket.StatusInternalError, err.Error())
 			return
@@ -409,13 +533,43 @@ func (api *API) workspaceAgentClientCoordinate(rw http.ResponseWriter, r *http.R
 		return
 	}
 	defer conn.Close(websocket.StatusNormalClosure, "")
-	err = api.TailnetCoordinator.ServeClient(websocket.NetConn(r.Context(), conn, websocket.MessageBinary), uuid.New(), workspaceAgent.ID)
+
+	api.auditAgentConnection(r, workspace, workspaceAgent, database.AuditActionConnect, "tailnet")
+	defer api.auditAgentConnection(r, workspace, workspaceAgent, database.AuditActionDisconnect, "tailnet")
+
+	err = (*api.TailnetCoordinator.Load()).ServeClient(websocket.NetConn(r.Context(), conn, websocket.MessageBinary), uuid.New(), workspaceAgent.ID)
 	if err != nil {
 		_ = conn.Close(websocket.StatusInternalError, err.Error())
 		return
 	}
 }
 
+// auditAgentConnection creates an audit log when a user connects to or
+// disconnects from a workspace agent. Connections outlive the handler's
+// request context, so the audit log is created in the background.
+func (api *API) auditAgentConnection(r *http.Request, workspace database.Workspace, workspaceAgent database.WorkspaceAgent, action database.AuditAction, connectionType string) {
+	fields, _ := json.Marshal(map[string]string{
+		"agent_id":        workspaceAgent.ID.String(),
+		"agent_name":      workspaceAgent.Name,
+		"connection_type": connectionType,
+	})
+	ip, _, _ := net.SplitHostPort(r.RemoteAddr)
+	auditor := api.Auditor.Load()
+	audit.BackgroundAudit(context.Background(), &audit.BackgroundAuditParams[database.Workspace]{
+		Audit:            *auditor,
+		Log:              api.Logger,
+		UserID:           httpmw.APIKey(r).UserID,
+		RequestID:        httpmw.RequestID(r),
+		IP:               ip,
+		UserAgent:        r.UserAgent(),
+		Status:           http.StatusSwitchingProtocols,
+		Action:           action,
+		AdditionalFields: fields,
+		Old:              workspace,
+		New:              workspace,
+	})
+}
+
 func convertApps(dbApps []database.WorkspaceApp) []codersdk.WorkspaceApp {
 	apps := make([]codersdk.WorkspaceApp, 0)
 	for _, dbApp := range dbApps {
@@ -424,12 +578,18 @@ func convertApps(dbApps []database.WorkspaceApp) []codersdk.WorkspaceApp {
 			Name:    dbApp.Name,
 			Command: dbApp.Command.String,
 			Icon:    dbApp.Icon,
+			Healthcheck: codersdk.Healthcheck{
+				URL:       dbApp.HealthcheckUrl,
+				Interval:  dbApp.HealthcheckInterval,
+				Threshold: dbApp.HealthcheckThreshold,
+			},
+			Health: codersdk.WorkspaceAppHealth(dbApp.Health),
 		})
 	}
 	return apps
 }
 
-func convertWorkspaceAgent(derpMap *tailcfg.DERPMap, coordinator *tailnet.Coordinator, dbAgent database.WorkspaceAgent, apps []codersdk.WorkspaceApp, agentInactiveDisconnectTimeout time.Duration) (codersdk.WorkspaceAgent, error) {
+func convertWorkspaceAgent(derpMap *tailcfg.DERPMap, coordinator tailnet.Coordinator, dbAgent database.WorkspaceAgent, apps []codersdk.WorkspaceApp, metadata []codersdk.WorkspaceAgentMetadata, agentInactiveDisconnectTimeout time.Duration) (codersdk.WorkspaceAgent, error) {
 	var envs map[string]string
 	if dbAgent.EnvironmentVariables.Valid {
 		err := json.Unmarshal(dbAgent.EnvironmentVariables.RawMessage, &envs)
@@ -451,6 +611,16 @@ func convertWorkspaceAgent(derpMap *tailcfg.DERPMap, coordinator *tailnet.Coordi
 		EnvironmentVariables: envs,
 		Directory:            dbAgent.Directory,
 		Apps:                 apps,
+
+		LifecycleState:              codersdk.WorkspaceAgentLifecycle(dbAgent.LifecycleState),
+		StartupScriptTimeoutSeconds: dbAgent.StartupScriptTimeoutSeconds,
+		StartupLogsLength:           dbAgent.StartupLogsLength,
+		StartupLogsOverflowed:       dbAgent.StartupLogsOverflowed,
+
+		ShutdownScript:               dbAgent.ShutdownScript.String,
+		ShutdownScriptTimeoutSeconds: dbAgent.ShutdownScriptTimeoutSeconds,
+		Metadata:                     metadata,
+		HealthWarnings:               dbAgent.HealthWarnings,
 	}
 	node := coordinator.Node(dbAgent.ID)
 	if node != nil {
@@ -570,6 +740,7 @@ func (api *API) workspaceAgentReportStats(rw http.ResponseWriter, r *http.Reques
 	ctx := r.Context()
 	timer := time.NewTicker(api.AgentStatsRefreshInterval)
 	for {
+		requestedAt := time.Now()
 		err := wsjson.Write(ctx, conn, codersdk.AgentStatsReportRequest{})
 		if err != nil {
 			httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
@@ -588,6 +759,7 @@ func (api *API) workspaceAgentReportStats(rw http.ResponseWriter, r *http.Reques
 			})
 			return
 		}
+		latency := time.Since(requestedAt)
 
 		repJSON, err := json.Marshal(rep)
 		if err != nil {
@@ -603,8 +775,9 @@ func (api *API) workspaceAgentReportStats(rw http.ResponseWriter, r *http.Reques
 		// (e.g. web terminal left open) or when there are no connections at
 		// all.
 		// We also don't want to update the workspace last used at on duplicate
-		// reports.
-		var updateDB = !reflect.DeepEqual(lastReport, rep)
+		// reports. Session time grows while sessions are idle, so it isn't
+		// considered.
+		var updateDB = agentStatsChanged(lastReport, rep)
 
 		api.Logger.Debug(ctx, "read stats report",
 			slog.F("interval", api.AgentStatsRefreshInterval),
@@ -618,16 +791,27 @@ func (api *API) workspaceAgentReportStats(rw http.ResponseWriter, r *http.Reques
 		if updateDB {
 			go activityBumpWorkspace(api.Logger.Named("activity_bump"), api.Database, workspace)
 
+			delta := agentStatsDelta(lastReport, rep)
 			lastReport = rep
 
 			_, err = api.Database.InsertAgentStat(ctx, database.InsertAgentStatParams{
-				ID:          uuid.New(),
-				CreatedAt:   database.Now(),
-				AgentID:     workspaceAgent.ID,
-				WorkspaceID: build.WorkspaceID,
-				UserID:      workspace.OwnerID,
-				TemplateID:  workspace.TemplateID,
-				Payload:     json.RawMessage(repJSON),
+				ID:                          uuid.New(),
+				CreatedAt:                   database.Now(),
+				AgentID:                     workspaceAgent.ID,
+				WorkspaceID:                 build.WorkspaceID,
+				UserID:                      workspace.OwnerID,
+				TemplateID:                  workspace.TemplateID,
+				Payload:                     json.RawMessage(repJSON),
+				ConnectionCount:             delta.NumConns,
+				RxBytes:                     delta.RxBytes,
+				TxBytes:                     delta.TxBytes,
+				SessionCountSSH:             delta.SessionCount[string(agent.SessionTypeSSH)],
+				SessionCountVSCode:          delta.SessionCount[string(agent.SessionTypeVSCode)],
+				SessionCountJetBrains:       delta.SessionCount[string(agent.SessionTypeJetBrains)],
+				SessionCountReconnectingPTY: delta.SessionCount[string(agent.SessionTypeReconnectingPTY)],
+				SessionCountPortForward:     delta.SessionCount[string(agent.SessionTypePortForward)],
+				SessionDurationSeconds:      delta.SessionSeconds,
+				LatencyMS:                   float64(latency.Microseconds()) / 1000,
 			})
 			if err != nil {
 				httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
@@ -660,6 +844,40 @@ func (api *API) workspaceAgentReportStats(rw http.ResponseWriter, r *http.Reques
 	}
 }
 
+// agentStatsChanged returns whether the agent's connections or traffic
+// changed between reports.
+func agentStatsChanged(last, current codersdk.AgentStatsReportResponse) bool {
+	return last.NumConns != current.NumConns ||
+		last.RxBytes != current.RxBytes ||
+		last.TxBytes != current.TxBytes ||
+		!maps.Equal(last.SessionCount, current.SessionCount)
+}
+
+// agentStatsDelta returns the change in an agent's cumulative stats since
+// the previous report. The stats reset when the agent restarts, in which
+// case the whole report is the change.
+func agentStatsDelta(last, current codersdk.AgentStatsReportResponse) codersdk.AgentStatsReportResponse {
+	if current.NumConns < last.NumConns ||
+		current.RxBytes < last.RxBytes ||
+		current.TxBytes < last.TxBytes ||
+		current.SessionSeconds < last.SessionSeconds {
+		last = codersdk.AgentStatsReportResponse{}
+	}
+	delta := codersdk.AgentStatsReportResponse{
+		NumConns:       current.NumConns - last.NumConns,
+		RxBytes:        current.RxBytes - last.RxBytes,
+		TxBytes:        current.TxBytes - last.TxBytes,
+		SessionCount:   make(map[string]int64, len(current.SessionCount)),
+		SessionSeconds: current.SessionSeconds - last.SessionSeconds,
+	}
+	for sessionType, count := range current.SessionCount {
+		if count > last.SessionCount[sessionType] {
+			delta.SessionCount[sessionType] = count - last.SessionCount[sessionType]
+		}
+	}
+	return delta
+}
+
 // wsNetConn wraps net.Conn created by websocket.NetConn(). Cancel func
 // is called if a read or write error is encountered.
 type wsNetConn struct {
@@ -700,3 +918,686 @@ func websocketNetConn(ctx context.Context, conn *websocket.Conn, msgType websock
 		Conn:   nc,
 	}
 }
+
+// startupLogsMaxLength is the maximum total length of startup script output
+// stored for an agent.
+const startupLogsMaxLength = 1 << 20
+
+func (api *API) workspaceAgentReportLifecycle(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceAgent := httpmw.WorkspaceAgent(r)
+
+	var req codersdk.PostWorkspaceAgentLifecycleRequest
+	if !httpapi.Read(rw, r, &req) {
+		return
+	}
+
+	lifecycleState := database.WorkspaceAgentLifecycleState(req.State)
+	switch lifecycleState {
+	case database.WorkspaceAgentLifecycleStateCreated,
+		database.WorkspaceAgentLifecycleStateStarting,
+		database.WorkspaceAgentLifecycleStateStartTimeout,
+		database.WorkspaceAgentLifecycleStateStartError,
+		database.WorkspaceAgentLifecycleStateReady,
+		database.WorkspaceAgentLifecycleStateShuttingDown,
+		database.WorkspaceAgentLifecycleStateShutdownTimeout,
+		database.WorkspaceAgentLifecycleStateShutdownError,
+		database.WorkspaceAgentLifecycleStateOff:
+	default:
+		httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid lifecycle state.",
+			Detail:  fmt.Sprintf("invalid lifecycle state: %q", req.State),
+		})
+		return
+	}
+
+	err := api.Database.UpdateWorkspaceAgentLifecycleStateByID(ctx, database.UpdateWorkspaceAgentLifecycleStateByIDParams{
+		ID:             workspaceAgent.ID,
+		LifecycleState: lifecycleState,
+	})
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating workspace agent lifecycle state.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	if !codersdk.WorkspaceAgentLifecycle(lifecycleState).Starting() {
+		// Followers stop streaming once the agent is done starting.
+		api.publishWorkspaceAgentStartupLogs(ctx, workspaceAgent.ID, workspaceAgentStartupLogsMessage{
+			EndOfLogs: true,
+		})
+	}
+
+	httpapi.Write(rw, http.StatusNoContent, nil)
+}
+
+func (api *API) patchWorkspaceAgentStartupLogs(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceAgent := httpmw.WorkspaceAgent(r)
+
+	var req codersdk.PatchWorkspaceAgentStartupLogs
+	if !httpapi.Read(rw, r, &req) {
+		return
+	}
+	if len(req.Logs) == 0 {
+		httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+			Message: "No logs provided.",
+		})
+		return
+	}
+
+	createdAt := make([]time.Time, 0, len(req.Logs))
+	output := make([]string, 0, len(req.Logs))
+	outputLength := 0
+	for _, log := range req.Logs {
+		createdAt = append(createdAt, log.CreatedAt)
+		output = append(output, log.Output)
+		outputLength += len(log.Output)
+	}
+
+	if workspaceAgent.StartupLogsOverflowed || int(workspaceAgent.StartupLogsLength)+outputLength > startupLogsMaxLength {
+		if !workspaceAgent.StartupLogsOverflowed {
+			err := api.Database.UpdateWorkspaceAgentStartupLogOverflowByID(ctx, database.UpdateWorkspaceAgentStartupLogOverflowByIDParams{
+				ID:                    workspaceAgent.ID,
+				StartupLogsOverflowed: true,
+			})
+			if err != nil {
+				httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+					Message: "Internal error marking startup logs as overflowed.",
+					Detail:  err.Error(),
+				})
+				return
+			}
+		}
+		httpapi.Write(rw, http.StatusRequestEntityTooLarge, codersdk.Response{
+			Message: "Startup logs limit exceeded.",
+			Detail:  fmt.Sprintf("startup logs are limited to %d bytes", startupLogsMaxLength),
+		})
+		return
+	}
+
+	logs, err := api.Database.InsertWorkspaceAgentStartupLogs(ctx, database.InsertWorkspaceAgentStartupLogsParams{
+		AgentID:      workspaceAgent.ID,
+		CreatedAt:    createdAt,
+		Output:       output,
+		OutputLength: int32(outputLength),
+	})
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error inserting startup logs.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	if len(logs) > 0 {
+		api.publishWorkspaceAgentStartupLogs(ctx, workspaceAgent.ID, workspaceAgentStartupLogsMessage{
+			LatestLogID: logs[len(logs)-1].ID,
+		})
+	}
+
+	httpapi.Write(rw, http.StatusOK, nil)
+}
+
+// workspaceAgentStartupLogs returns the output of the agent's startup script.
+// With the "follow" query param, output is streamed over a WebSocket until
+// the agent finishes starting.
+func (api *API) workspaceAgentStartupLogs(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+	workspaceAgent := httpmw.WorkspaceAgentParam(r)
+	if !api.Authorize(r, rbac.ActionRead, workspace) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	follow := r.URL.Query().Has("follow")
+	var after int64
+	if afterRaw := r.URL.Query().Get("after"); afterRaw != "" {
+		var err error
+		after, err = strconv.ParseInt(afterRaw, 10, 64)
+		if err != nil {
+			httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Query param \"after\" must be an integer.",
+				Validations: []codersdk.ValidationError{
+					{Field: "after", Detail: "Must be an integer"},
+				},
+			})
+			return
+		}
+	}
+
+	// Subscribe before querying the database so no output is missed
+	// between the query and the subscription.
+	var (
+		newLogs   <-chan struct{}
+		endOfLogs <-chan struct{}
+	)
+	if follow {
+		nl, eol, closeFollow, err := api.followWorkspaceAgentStartupLogs(workspaceAgent.ID)
+		if err != nil {
+			httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error watching startup logs.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		defer closeFollow()
+		newLogs, endOfLogs = nl, eol
+
+		workspaceAgent, err = api.Database.GetWorkspaceAgentByID(ctx, workspaceAgent.ID)
+		if err != nil {
+			httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching workspace agent.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+	}
+
+	logs, err := api.Database.GetWorkspaceAgentStartupLogsAfter(ctx, database.GetWorkspaceAgentStartupLogsAfterParams{
+		AgentID:      workspaceAgent.ID,
+		CreatedAfter: after,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		err = nil
+	}
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching startup logs.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	if !follow {
+		httpapi.Write(rw, http.StatusOK, convertWorkspaceAgentStartupLogs(logs))
+		return
+	}
+
+	api.websocketWaitMutex.Lock()
+	api.websocketWaitGroup.Add(1)
+	api.websocketWaitMutex.Unlock()
+	defer api.websocketWaitGroup.Done()
+	conn, err := websocket.Accept(rw, r, nil)
+	if err != nil {
+		httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Failed to accept websocket.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	ctx, wsNetConn := websocketNetConn(ctx, conn, websocket.MessageText)
+	defer wsNetConn.Close() // Also closes conn.
+
+	// The Go stdlib JSON encoder appends a newline character after message write.
+	encoder := json.NewEncoder(wsNetConn)
+	sendLogs := func(logs []database.WorkspaceAgentStartupLog) error {
+		for _, log := range convertWorkspaceAgentStartupLogs(logs) {
+			err := encoder.Encode(log)
+			if err != nil {
+				return err
+			}
+			after = log.ID
+		}
+		return nil
+	}
+	// sendNewLogs reads the output inserted since the last log sent. The
+	// output isn't published itself, so followers never miss any.
+	sendNewLogs := func() error {
+		logs, err := api.Database.GetWorkspaceAgentStartupLogsAfter(ctx, database.GetWorkspaceAgentStartupLogsAfterParams{
+			AgentID:      workspaceAgent.ID,
+			CreatedAfter: after,
+		})
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return xerrors.Errorf("get startup logs: %w", err)
+		}
+		return sendLogs(logs)
+	}
+
+	err = sendLogs(logs)
+	if err != nil {
+		return
+	}
+	if !codersdk.WorkspaceAgentLifecycle(workspaceAgent.LifecycleState).Starting() {
+		// The agent finished starting before the query, so all output
+		// has been sent.
+		return
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-endOfLogs:
+			// Output may have been inserted since the last notification.
+			_ = sendNewLogs()
+			return
+		case <-newLogs:
+			err = sendNewLogs()
+			if err != nil {
+				api.Logger.Warn(ctx, "send startup logs", slog.F("agent_id", workspaceAgent.ID), slog.Error(err))
+				return
+			}
+		}
+	}
+}
+
+func workspaceAgentStartupLogsChannel(agentID uuid.UUID) string {
+	return fmt.Sprintf("workspace-agent-startup-logs:%s", agentID)
+}
+
+// workspaceAgentStartupLogsMessage is published on the
+// workspaceAgentStartupLogsChannel() channel. It only notifies followers of
+// new output, which they read from the database, since the output could
+// exceed the payload limit of the pubsub.
+type workspaceAgentStartupLogsMessage struct {
+	EndOfLogs bool `json:"end_of_logs,omitempty"`
+	// LatestLogID is the ID of the newest output inserted.
+	LatestLogID int64 `json:"latest_log_id,omitempty"`
+}
+
+func (api *API) publishWorkspaceAgentStartupLogs(ctx context.Context, agentID uuid.UUID, msg workspaceAgentStartupLogsMessage) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		api.Logger.Warn(ctx, "marshal startup logs message", slog.Error(err))
+		return
+	}
+	err = api.Pubsub.Publish(workspaceAgentStartupLogsChannel(agentID), data)
+	if err != nil {
+		api.Logger.Warn(ctx, "publish startup logs", slog.F("agent_id", agentID), slog.Error(err))
+	}
+}
+
+// followWorkspaceAgentStartupLogs returns a channel that receives a value
+// when the agent has new output, and a channel that's closed once the agent
+// finishes starting. Notifications are merged while the follower is busy,
+// since it reads all new output at once.
+func (api *API) followWorkspaceAgentStartupLogs(agentID uuid.UUID) (<-chan struct{}, <-chan struct{}, func(), error) {
+	logger := api.Logger.With(slog.F("agent_id", agentID))
+	newLogs := make(chan struct{}, 1)
+	endOfLogs := make(chan struct{})
+	var closeOnce sync.Once
+	closeSubscribe, err := api.Pubsub.Subscribe(workspaceAgentStartupLogsChannel(agentID),
+		func(ctx context.Context, message []byte) {
+			msg := workspaceAgentStartupLogsMessage{}
+			err := json.Unmarshal(message, &msg)
+			if err != nil {
+				logger.Warn(ctx, "invalid startup logs on channel", slog.Error(err))
+				return
+			}
+			if msg.LatestLogID > 0 {
+				select {
+				case newLogs <- struct{}{}:
+				default:
+					// The follower hasn't read the pending notification
+					// yet, which covers this output too.
+				}
+			}
+			if msg.EndOfLogs {
+				closeOnce.Do(func() {
+					close(endOfLogs)
+				})
+			}
+		})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return newLogs, endOfLogs, closeSubscribe, nil
+}
+
+func convertWorkspaceAgentStartupLogs(logs []database.WorkspaceAgentStartupLog) []codersdk.WorkspaceAgentStartupLog {
+	sdk := make([]codersdk.WorkspaceAgentStartupLog, 0, len(logs))
+	for _, log := range logs {
+		sdk = append(sdk, codersdk.WorkspaceAgentStartupLog{
+			ID:        log.ID,
+			CreatedAt: log.CreatedAt,
+			Output:    log.Output,
+		})
+	}
+	return sdk
+}
+
+const (
+	// agentShutdownMaxTimeout bounds how long a build waits for an agent to
+	// run its shutdown script.
+	agentShutdownMaxTimeout = 5 * time.Minute
+	// agentShutdownPollInterval is how often agents waiting for a shutdown
+	// request check for a build that stops their workspace, in case a
+	// notification was missed.
+	agentShutdownPollInterval = 10 * time.Second
+	// agentShutdownGracePeriod is added to an agent's shutdown script
+	// timeout to allow for it to notice the request and report the outcome.
+	agentShutdownGracePeriod = agentShutdownPollInterval + 5*time.Second
+)
+
+// workspaceAgentAwaitShutdown blocks until a build that stops the agent's
+// workspace is created, so the agent can run its shutdown script before the
+// build's job is acquired.
+func (api *API) workspaceAgentAwaitShutdown(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceAgent := httpmw.WorkspaceAgent(r)
+
+	// Subscribe before checking the latest build so a request isn't
+	// missed in between.
+	shutdown := make(chan struct{}, 1)
+	cancel, err := api.Pubsub.Subscribe(agentshutdown.Channel(workspaceAgent.ID), func(_ context.Context, _ []byte) {
+		select {
+		case shutdown <- struct{}{}:
+		default:
+		}
+	})
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error subscribing to shutdown requests.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	defer cancel()
+
+	resource, err := api.Database.GetWorkspaceResourceByID(ctx, workspaceAgent.ResourceID)
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace resource.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	build, err := api.Database.GetWorkspaceBuildByJobID(ctx, resource.JobID)
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace build.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	latestBuild, err := api.Database.GetLatestWorkspaceBuildByWorkspaceID(ctx, build.WorkspaceID)
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching latest workspace build.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	ticker := time.NewTicker(agentShutdownPollInterval)
+	defer ticker.Stop()
+	for latestBuild.ID == build.ID || latestBuild.Transition == database.WorkspaceTransitionStart {
+		select {
+		case <-ctx.Done():
+			return
+		case <-shutdown:
+		case <-ticker.C:
+		}
+		latestBuild, err = api.Database.GetLatestWorkspaceBuildByWorkspaceID(ctx, build.WorkspaceID)
+		if err != nil {
+			httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching latest workspace build.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+	}
+
+	httpapi.Write(rw, http.StatusOK, codersdk.Response{
+		Message: "Shutdown requested.",
+	})
+}
+
+func (api *API) postWorkspaceAgentAppHealth(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceAgent := httpmw.WorkspaceAgent(r)
+	var req codersdk.PostWorkspaceAppHealthsRequest
+	if !httpapi.Read(rw, r, &req) {
+		return
+	}
+
+	if len(req.Healths) == 0 {
+		httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Health field is empty.",
+		})
+		return
+	}
+
+	apps, err := api.Database.GetWorkspaceAppsByAgentID(ctx, workspaceAgent.ID)
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace applications.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	var newApps []database.WorkspaceApp
+	for id, newHealth := range req.Healths {
+		var found *database.WorkspaceApp
+		for i := range apps {
+			if apps[i].ID == id {
+				found = &apps[i]
+				break
+			}
+		}
+		if found == nil {
+			httpapi.Write(rw, http.StatusNotFound, codersdk.Response{
+				Message: "Error setting workspace application health.",
+				Detail:  xerrors.Errorf("workspace app %s not found", id).Error(),
+			})
+			return
+		}
+
+		if found.HealthcheckUrl == "" {
+			httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Error setting workspace application health.",
+				Detail:  xerrors.Errorf("health checking is disabled for workspace app %s", id).Error(),
+			})
+			return
+		}
+
+		switch newHealth {
+		case codersdk.WorkspaceAppHealthInitializing:
+		case codersdk.WorkspaceAppHealthHealthy:
+		case codersdk.WorkspaceAppHealthUnhealthy:
+		default:
+			httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Error setting workspace application health.",
+				Detail:  xerrors.Errorf("workspace app health %q is not a valid value", newHealth).Error(),
+			})
+			return
+		}
+
+		// Don't save if the value hasn't changed.
+		if found.Health == database.WorkspaceAppHealth(newHealth) {
+			continue
+		}
+		found.Health = database.WorkspaceAppHealth(newHealth)
+		newApps = append(newApps, *found)
+	}
+
+	for _, app := range newApps {
+		err = api.Database.UpdateWorkspaceAppHealthByID(ctx, database.UpdateWorkspaceAppHealthByIDParams{
+			ID:     app.ID,
+			Health: app.Health,
+		})
+		if err != nil {
+			httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error updating workspace application health.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+	}
+
+	httpapi.Write(rw, http.StatusNoContent, nil)
+}
+
+func (api *API) postWorkspaceAgentHealth(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceAgent := httpmw.WorkspaceAgent(r)
+	var req codersdk.PostWorkspaceAgentHealthRequest
+	if !httpapi.Read(rw, r, &req) {
+		return
+	}
+	if req.Warnings == nil {
+		req.Warnings = []string{}
+	}
+
+	err := api.Database.UpdateWorkspaceAgentHealthWarningsByID(ctx, database.UpdateWorkspaceAgentHealthWarningsByIDParams{
+		ID:             workspaceAgent.ID,
+		HealthWarnings: req.Warnings,
+	})
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating workspace agent health.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(rw, http.StatusNoContent, nil)
+}
+
+func workspaceAgentMetadataChannel(agentID uuid.UUID) string {
+	return fmt.Sprintf("workspace-agent-metadata:%s", agentID)
+}
+
+func (api *API) workspaceAgentPostMetadata(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceAgent := httpmw.WorkspaceAgent(r)
+	key := chi.URLParam(r, "key")
+
+	var req codersdk.WorkspaceAgentMetadataResult
+	if !httpapi.Read(rw, r, &req) {
+		return
+	}
+
+	err := api.Database.UpdateWorkspaceAgentMetadata(ctx, database.UpdateWorkspaceAgentMetadataParams{
+		WorkspaceAgentID: workspaceAgent.ID,
+		Key:              key,
+		Value:            truncateWorkspaceAgentMetadata(req.Value),
+		Error:            truncateWorkspaceAgentMetadata(req.Error),
+		CollectedAt:      req.CollectedAt,
+	})
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating workspace agent metadata.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	err = api.Pubsub.Publish(workspaceAgentMetadataChannel(workspaceAgent.ID), []byte(key))
+	if err != nil {
+		api.Logger.Warn(ctx, "publish workspace agent metadata", slog.F("agent_id", workspaceAgent.ID), slog.Error(err))
+	}
+
+	httpapi.Write(rw, http.StatusNoContent, nil)
+}
+
+// watchWorkspaceAgentMetadata sends the latest values of every metadata
+// script as server-sent events whenever the agent reports a new value.
+func (api *API) watchWorkspaceAgentMetadata(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceAgent := httpmw.WorkspaceAgentParam(r)
+	workspace := httpmw.WorkspaceParam(r)
+	if !api.Authorize(r, rbac.ActionRead, workspace) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	// Updates are coalesced, since every event contains all values.
+	update := make(chan struct{}, 1)
+	cancel, err := api.Pubsub.Subscribe(workspaceAgentMetadataChannel(workspaceAgent.ID), func(_ context.Context, _ []byte) {
+		select {
+		case update <- struct{}{}:
+		default:
+		}
+	})
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error subscribing to workspace agent metadata.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	defer cancel()
+
+	sendEvent, err := httpapi.ServerSentEventSender(rw, r)
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error setting up server-sent events.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	for {
+		dbMetadata, err := api.Database.GetWorkspaceAgentMetadata(ctx, workspaceAgent.ID)
+		if err != nil {
+			_ = sendEvent(ctx, codersdk.ServerSentEvent{
+				Type: codersdk.ServerSentEventTypeError,
+				Data: codersdk.Response{
+					Message: "Internal error fetching workspace agent metadata.",
+					Detail:  err.Error(),
+				},
+			})
+			return
+		}
+		err = sendEvent(ctx, codersdk.ServerSentEvent{
+			Type: codersdk.ServerSentEventTypeData,
+			Data: convertWorkspaceAgentMetadata(dbMetadata),
+		})
+		if err != nil {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-update:
+		}
+	}
+}
+
+func truncateWorkspaceAgentMetadata(value string) string {
+	// Agents truncate values themselves, this protects the database from
+	// agents that don't.
+	const maxLength = 10 << 10
+	if len(value) > maxLength {
+		return value[:maxLength]
+	}
+	return value
+}
+
+func convertWorkspaceAgentMetadata(dbMetadata []database.WorkspaceAgentMetadatum) []codersdk.WorkspaceAgentMetadata {
+	metadata := make([]codersdk.WorkspaceAgentMetadata, 0, len(dbMetadata))
+	for _, md := range dbMetadata {
+		var age int64
+		if !md.CollectedAt.IsZero() {
+			age = int64(database.Now().Sub(md.CollectedAt).Seconds())
+			if age < 0 {
+				// The agent's clock may be ahead of ours.
+				age = 0
+			}
+		}
+		metadata = append(metadata, codersdk.WorkspaceAgentMetadata{
+			Description: codersdk.WorkspaceAgentMetadataDescription{
+				DisplayName: md.DisplayName,
+				Key:         md.Key,
+				Script:      md.Script,
+				Interval:    md.Interval,
+				Timeout:     md.Timeout,
+			},
+			Result: codersdk.WorkspaceAgentMetadataResult{
+				CollectedAt: md.CollectedAt,
+				Age:         age,
+				Value:       md.Value,
+				Error:       md.Error,
+			},
+		})
+	}
+	return metadata
+}
@@ -4,18 +4,26 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
 
 	"cdr.dev/slog"
 	"cdr.dev/slog/sloggers/slogtest"
 	"github.com/coder/coder/agent"
 	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/coderd/database"
 	"github.com/coder/coder/codersdk"
 	"github.com/coder/coder/provisioner/echo"
 	"github.com/coder/coder/provisionersdk/proto"
@@ -363,3 +371,577 @@ func TestWorkspaceAgentPTY(t *testing.T) {
 	expectLine(matchEchoCommand)
 	expectLine(matchEchoOutput)
 }
+
+func TestWorkspaceAgentListeningPorts(t *testing.T) {
+	t.Parallel()
+	client := coderdtest.New(t, &coderdtest.Options{
+		IncludeProvisionerDaemon: true,
+	})
+	user := coderdtest.CreateFirstUser(t, client)
+	authToken := uuid.NewString()
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse:           echo.ParseComplete,
+		ProvisionDryRun: echo.ProvisionComplete,
+		Provision: []*proto.Provision_Response{{
+			Type: &proto.Provision_Response_Complete{
+				Complete: &proto.Provision_Complete{
+					Resources: []*proto.Resource{{
+						Name: "example",
+						Type: "aws_instance",
+						Agents: []*proto.Agent{{
+							Id: uuid.NewString(),
+							Auth: &proto.Agent_Token{
+								Token: authToken,
+							},
+						}},
+					}},
+				},
+			},
+		}},
+	})
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	resources, err := client.WorkspaceResourcesByBuild(ctx, workspace.LatestBuild.ID)
+	require.NoError(t, err)
+	agentID := resources[0].Agents[0].ID
+	_, err = client.WorkspaceAgentListeningPorts(ctx, agentID)
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusPreconditionRequired, apiErr.StatusCode(), "agent isn't connected")
+
+	agentClient := codersdk.New(client.URL)
+	agentClient.SessionToken = authToken
+	agentCloser := agent.New(agent.Options{
+		FetchMetadata:     agentClient.WorkspaceAgentMetadata,
+		CoordinatorDialer: agentClient.ListenWorkspaceAgentTailnet,
+		Logger:            slogtest.Make(t, nil).Named("agent").Leveled(slog.LevelDebug),
+	})
+	defer func() {
+		_ = agentCloser.Close()
+	}()
+	coderdtest.AwaitWorkspaceAgents(t, client, workspace.LatestBuild.ID)
+
+	// The agent runs in the test process, so the ports of coderd and
+	// the agent itself are excluded.
+	resp, err := client.WorkspaceAgentListeningPorts(ctx, agentID)
+	require.NoError(t, err)
+	_, rawPort, err := net.SplitHostPort(client.URL.Host)
+	require.NoError(t, err)
+	for _, port := range resp.Ports {
+		require.NotEqual(t, rawPort, strconv.Itoa(int(port.Port)))
+	}
+}
+
+func TestWorkspaceAgentStartupLogs(t *testing.T) {
+	t.Parallel()
+	setup := func(t *testing.T) (*codersdk.Client, *codersdk.Client, codersdk.WorkspaceAgent) {
+		client := coderdtest.New(t, &coderdtest.Options{
+			IncludeProvisionerDaemon: true,
+			Pubsub:                   limitedPubsub{Pubsub: database.NewPubsubInMemory()},
+		})
+		user := coderdtest.CreateFirstUser(t, client)
+		authToken := uuid.NewString()
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+			Parse:           echo.ParseComplete,
+			ProvisionDryRun: echo.ProvisionComplete,
+			Provision: []*proto.Provision_Response{{
+				Type: &proto.Provision_Response_Complete{
+					Complete: &proto.Provision_Complete{
+						Resources: []*proto.Resource{{
+							Name: "example",
+							Type: "aws_instance",
+							Agents: []*proto.Agent{{
+								Id: uuid.NewString(),
+								Auth: &proto.Agent_Token{
+									Token: authToken,
+								},
+								StartupScriptTimeoutSeconds: 60,
+							}},
+						}},
+					},
+				},
+			}},
+		})
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+		resources, err := client.WorkspaceResourcesByBuild(ctx, workspace.LatestBuild.ID)
+		require.NoError(t, err)
+
+		agentClient := codersdk.New(client.URL)
+		agentClient.SessionToken = authToken
+		return client, agentClient, resources[0].Agents[0]
+	}
+
+	t.Run("Follow", func(t *testing.T) {
+		t.Parallel()
+		client, agentClient, workspaceAgent := setup(t)
+		require.Equal(t, codersdk.WorkspaceAgentLifecycleCreated, workspaceAgent.LifecycleState)
+		require.EqualValues(t, 60, workspaceAgent.StartupScriptTimeoutSeconds)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		err := agentClient.PostWorkspaceAgentLifecycle(ctx, agent.LifecycleStateStarting)
+		require.NoError(t, err)
+		err = agentClient.PatchWorkspaceAgentStartupLogs(ctx, []agent.StartupLog{{
+			CreatedAt: time.Now(),
+			Output:    "first",
+		}})
+		require.NoError(t, err)
+
+		logs, closer, err := client.WorkspaceAgentStartupLogsAfter(ctx, workspaceAgent.ID, 0)
+		require.NoError(t, err)
+		defer closer.Close()
+
+		log := <-logs
+		require.Equal(t, "first", log.Output)
+
+		err = agentClient.PatchWorkspaceAgentStartupLogs(ctx, []agent.StartupLog{{
+			CreatedAt: time.Now(),
+			Output:    "second",
+		}})
+		require.NoError(t, err)
+		log = <-logs
+		require.Equal(t, "second", log.Output)
+
+		err = agentClient.PostWorkspaceAgentLifecycle(ctx, agent.LifecycleStateReady)
+		require.NoError(t, err)
+		_, ok := <-logs
+		require.False(t, ok, "logs should close once the agent is ready")
+
+		workspaceAgent, err = client.WorkspaceAgent(ctx, workspaceAgent.ID)
+		require.NoError(t, err)
+		require.Equal(t, codersdk.WorkspaceAgentLifecycleReady, workspaceAgent.LifecycleState)
+		require.EqualValues(t, len("first")+len("second"), workspaceAgent.StartupLogsLength)
+	})
+
+	t.Run("FollowLargeBatch", func(t *testing.T) {
+		t.Parallel()
+		client, agentClient, workspaceAgent := setup(t)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		err := agentClient.PostWorkspaceAgentLifecycle(ctx, agent.LifecycleStateStarting)
+		require.NoError(t, err)
+
+		logs, closer, err := client.WorkspaceAgentStartupLogsAfter(ctx, workspaceAgent.ID, 0)
+		require.NoError(t, err)
+		defer closer.Close()
+
+		// The batch is far larger than a pubsub message may be.
+		batch := make([]agent.StartupLog, 0, 100)
+		for i := 0; i < cap(batch); i++ {
+			batch = append(batch, agent.StartupLog{
+				CreatedAt: time.Now(),
+				Output:    strings.Repeat(strconv.Itoa(i%10), 1024),
+			})
+		}
+		err = agentClient.PatchWorkspaceAgentStartupLogs(ctx, batch)
+		require.NoError(t, err)
+		for _, want := range batch {
+			log := <-logs
+			require.Equal(t, want.Output, log.Output)
+		}
+
+		err = agentClient.PostWorkspaceAgentLifecycle(ctx, agent.LifecycleStateReady)
+		require.NoError(t, err)
+		_, ok := <-logs
+		require.False(t, ok, "logs should close once the agent is ready")
+	})
+
+	t.Run("Overflow", func(t *testing.T) {
+		t.Parallel()
+		client, agentClient, workspaceAgent := setup(t)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		err := agentClient.PatchWorkspaceAgentStartupLogs(ctx, []agent.StartupLog{{
+			CreatedAt: time.Now(),
+			Output:    strings.Repeat("a", (1<<20)+1),
+		}})
+		var apiError *codersdk.Error
+		require.ErrorAs(t, err, &apiError)
+		require.Equal(t, http.StatusRequestEntityTooLarge, apiError.StatusCode())
+
+		workspaceAgent, err = client.WorkspaceAgent(ctx, workspaceAgent.ID)
+		require.NoError(t, err)
+		require.True(t, workspaceAgent.StartupLogsOverflowed)
+		require.Zero(t, workspaceAgent.StartupLogsLength)
+	})
+}
+
+func TestWorkspaceAgentShutdownScript(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("the shutdown script uses a POSIX shell")
+	}
+
+	client := coderdtest.New(t, &coderdtest.Options{
+		IncludeProvisionerDaemon: true,
+	})
+	user := coderdtest.CreateFirstUser(t, client)
+	authToken := uuid.NewString()
+	tempPath := filepath.Join(t.TempDir(), "shutdown.txt")
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse:           echo.ParseComplete,
+		ProvisionDryRun: echo.ProvisionComplete,
+		Provision: []*proto.Provision_Response{{
+			Type: &proto.Provision_Response_Complete{
+				Complete: &proto.Provision_Complete{
+					Resources: []*proto.Resource{{
+						Name: "example",
+						Type: "aws_instance",
+						Agents: []*proto.Agent{{
+							Id: uuid.NewString(),
+							Auth: &proto.Agent_Token{
+								Token: authToken,
+							},
+							ShutdownScript:               "sleep 1 && echo stopped > " + tempPath,
+							ShutdownScriptTimeoutSeconds: 30,
+						}},
+					}},
+				},
+			},
+		}},
+	})
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+	agentClient := codersdk.New(client.URL)
+	agentClient.SessionToken = authToken
+	agentCloser := agent.New(agent.Options{
+		FetchMetadata:     agentClient.WorkspaceAgentMetadata,
+		CoordinatorDialer: agentClient.ListenWorkspaceAgentTailnet,
+		ReportLifecycle:   agentClient.PostWorkspaceAgentLifecycle,
+		AwaitShutdown:     agentClient.WorkspaceAgentAwaitShutdown,
+		Logger:            slogtest.Make(t, nil).Named("agent").Leveled(slog.LevelDebug),
+	})
+	defer func() {
+		_ = agentCloser.Close()
+	}()
+	resources := coderdtest.AwaitWorkspaceAgents(t, client, workspace.LatestBuild.ID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	// The stop build waits for the shutdown script before it's applied.
+	build := coderdtest.CreateWorkspaceBuild(t, client, workspace, database.WorkspaceTransitionStop)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, build.ID)
+
+	content, err := os.ReadFile(tempPath)
+	require.NoError(t, err)
+	require.Equal(t, "stopped", strings.TrimSpace(string(content)))
+
+	workspaceAgent, err := client.WorkspaceAgent(ctx, resources[0].Agents[0].ID)
+	require.NoError(t, err)
+	require.Equal(t, codersdk.WorkspaceAgentLifecycleOff, workspaceAgent.LifecycleState)
+}
+
+func TestWorkspaceAgentMetadata(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("the metadata scripts use a POSIX shell")
+	}
+
+	client := coderdtest.New(t, &coderdtest.Options{
+		IncludeProvisionerDaemon: true,
+	})
+	user := coderdtest.CreateFirstUser(t, client)
+	authToken := uuid.NewString()
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse:           echo.ParseComplete,
+		ProvisionDryRun: echo.ProvisionComplete,
+		Provision: []*proto.Provision_Response{{
+			Type: &proto.Provision_Response_Complete{
+				Complete: &proto.Provision_Complete{
+					Resources: []*proto.Resource{{
+						Name: "example",
+						Type: "aws_instance",
+						Agents: []*proto.Agent{{
+							Id: uuid.NewString(),
+							Auth: &proto.Agent_Token{
+								Token: authToken,
+							},
+							Metadata: []*proto.Agent_Metadata{{
+								Key:         "greeting",
+								DisplayName: "Greeting",
+								Script:      "echo hello",
+								Interval:    1,
+							}, {
+								Key:         "fail",
+								DisplayName: "Fail",
+								Script:      "exit 1",
+							}},
+						}},
+					}},
+				},
+			},
+		}},
+	})
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	resources, err := client.WorkspaceResourcesByBuild(ctx, workspace.LatestBuild.ID)
+	require.NoError(t, err)
+	agentID := resources[0].Agents[0].ID
+	require.Len(t, resources[0].Agents[0].Metadata, 2)
+
+	updates, err := client.WatchWorkspaceAgentMetadata(ctx, agentID)
+	require.NoError(t, err)
+	// Values are empty until the agent reports them.
+	metadata := <-updates
+	require.Len(t, metadata, 2)
+	require.True(t, metadata[0].Result.CollectedAt.IsZero())
+
+	agentClient := codersdk.New(client.URL)
+	agentClient.SessionToken = authToken
+	agentCloser := agent.New(agent.Options{
+		FetchMetadata:     agentClient.WorkspaceAgentMetadata,
+		CoordinatorDialer: agentClient.ListenWorkspaceAgentTailnet,
+		PostMetadata:      agentClient.PostWorkspaceAgentMetadata,
+		Logger:            slogtest.Make(t, nil).Named("agent").Leveled(slog.LevelDebug),
+	})
+	defer func() {
+		_ = agentCloser.Close()
+	}()
+
+	byKey := map[string]codersdk.WorkspaceAgentMetadata{}
+	for byKey["greeting"].Result.Value == "" || byKey["fail"].Result.Error == "" {
+		select {
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for metadata")
+		case metadata = <-updates:
+		}
+		for _, md := range metadata {
+			byKey[md.Description.Key] = md
+		}
+	}
+	require.Equal(t, "Greeting", byKey["greeting"].Description.DisplayName)
+	require.Equal(t, "hello", byKey["greeting"].Result.Value)
+	require.Empty(t, byKey["greeting"].Result.Error)
+
+	workspaceAgent, err := client.WorkspaceAgent(ctx, agentID)
+	require.NoError(t, err)
+	require.Len(t, workspaceAgent.Metadata, 2)
+	require.Equal(t, "fail", workspaceAgent.Metadata[0].Description.Key)
+	require.NotEmpty(t, workspaceAgent.Metadata[0].Result.Error)
+}
+
+func TestWorkspaceAgentAppHealth(t *testing.T) {
+	t.Parallel()
+	client := coderdtest.New(t, &coderdtest.Options{
+		IncludeProvisionerDaemon: true,
+	})
+	user := coderdtest.CreateFirstUser(t, client)
+	authToken := uuid.NewString()
+
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer healthy.Close()
+	unhealthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer unhealthy.Close()
+
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse:           echo.ParseComplete,
+		ProvisionDryRun: echo.ProvisionComplete,
+		Provision: []*proto.Provision_Response{{
+			Type: &proto.Provision_Response_Complete{
+				Complete: &proto.Provision_Complete{
+					Resources: []*proto.Resource{{
+						Name: "example",
+						Type: "aws_instance",
+						Agents: []*proto.Agent{{
+							Id: uuid.NewString(),
+							Auth: &proto.Agent_Token{
+								Token: authToken,
+							},
+							Apps: []*proto.App{{
+								Name: "disabled",
+								Url:  healthy.URL,
+							}, {
+								Name: "healthy",
+								Url:  healthy.URL,
+								Healthcheck: &proto.Healthcheck{
+									Url:       healthy.URL,
+									Interval:  1,
+									Threshold: 1,
+								},
+							}, {
+								Name: "unhealthy",
+								Url:  unhealthy.URL,
+								Healthcheck: &proto.Healthcheck{
+									Url:       unhealthy.URL,
+									Interval:  1,
+									Threshold: 1,
+								},
+							}},
+						}},
+					}},
+				},
+			},
+		}},
+	})
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	appsByName := func() (map[string]codersdk.WorkspaceApp, error) {
+		resources, err := client.WorkspaceResourcesByBuild(ctx, workspace.LatestBuild.ID)
+		if err != nil {
+			return nil, err
+		}
+		apps := map[string]codersdk.WorkspaceApp{}
+		for _, app := range resources[0].Agents[0].Apps {
+			apps[app.Name] = app
+		}
+		return apps, nil
+	}
+	apps, err := appsByName()
+	require.NoError(t, err)
+	require.Equal(t, codersdk.WorkspaceAppHealthDisabled, apps["disabled"].Health)
+	require.Equal(t, codersdk.WorkspaceAppHealthInitializing, apps["healthy"].Health)
+	require.Equal(t, codersdk.WorkspaceAppHealthInitializing, apps["unhealthy"].Health)
+	require.Equal(t, healthy.URL, apps["healthy"].Healthcheck.URL)
+
+	agentClient := codersdk.New(client.URL)
+	agentClient.SessionToken = authToken
+
+	// Apps without a healthcheck, or that don't exist, can't have their
+	// health set.
+	err = agentClient.PostWorkspaceAgentAppHealth(ctx, map[uuid.UUID]agent.AppHealth{
+		apps["disabled"].ID: agent.AppHealthHealthy,
+	})
+	require.Error(t, err)
+	err = agentClient.PostWorkspaceAgentAppHealth(ctx, map[uuid.UUID]agent.AppHealth{
+		uuid.New(): agent.AppHealthHealthy,
+	})
+	require.Error(t, err)
+
+	agentCloser := agent.New(agent.Options{
+		FetchMetadata:     agentClient.WorkspaceAgentMetadata,
+		CoordinatorDialer: agentClient.ListenWorkspaceAgentTailnet,
+		PostAppHealth:     agentClient.PostWorkspaceAgentAppHealth,
+		Logger:            slogtest.Make(t, nil).Named("agent").Leveled(slog.LevelDebug),
+	})
+	defer func() {
+		_ = agentCloser.Close()
+	}()
+
+	require.Eventually(t, func() bool {
+		apps, err = appsByName()
+		if err != nil {
+			return false
+		}
+		return apps["healthy"].Health == codersdk.WorkspaceAppHealthHealthy &&
+			apps["unhealthy"].Health == codersdk.WorkspaceAppHealthUnhealthy
+	}, testutil.WaitLong, testutil.IntervalMedium)
+	require.Equal(t, codersdk.WorkspaceAppHealthDisabled, apps["disabled"].Health)
+}
+
+func TestWorkspaceAgentHealth(t *testing.T) {
+	t.Parallel()
+	client := coderdtest.New(t, &coderdtest.Options{
+		IncludeProvisionerDaemon: true,
+	})
+	user := coderdtest.CreateFirstUser(t, client)
+	authToken := uuid.NewString()
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse:           echo.ParseComplete,
+		ProvisionDryRun: echo.ProvisionComplete,
+		Provision: []*proto.Provision_Response{{
+			Type: &proto.Provision_Response_Complete{
+				Complete: &proto.Provision_Complete{
+					Resources: []*proto.Resource{{
+						Name: "example",
+						Type: "aws_instance",
+						Agents: []*proto.Agent{{
+							Id: uuid.NewString(),
+							Auth: &proto.Agent_Token{
+								Token: authToken,
+							},
+							SessionLimits: &proto.Agent_SessionLimits{
+								Cpus:     1.5,
+								MemoryMb: 2048,
+								Pids:     1024,
+							},
+						}},
+					}},
+				},
+			},
+		}},
+	})
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	agentClient := codersdk.New(client.URL)
+	agentClient.SessionToken = authToken
+	metadata, err := agentClient.WorkspaceAgentMetadata(ctx)
+	require.NoError(t, err)
+	require.Equal(t, agent.SessionLimits{
+		CPUs:     1.5,
+		MemoryMB: 2048,
+		Pids:     1024,
+	}, metadata.SessionLimits)
+
+	resources, err := client.WorkspaceResourcesByBuild(ctx, workspace.LatestBuild.ID)
+	require.NoError(t, err)
+	require.Empty(t, resources[0].Agents[0].HealthWarnings)
+
+	err = agentClient.PostWorkspaceAgentHealth(ctx, []string{"Session limits aren't applied."})
+	require.NoError(t, err)
+	resources, err = client.WorkspaceResourcesByBuild(ctx, workspace.LatestBuild.ID)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Session limits aren't applied."}, resources[0].Agents[0].HealthWarnings)
+
+	// Reporting no warnings clears the previous ones.
+	err = agentClient.PostWorkspaceAgentHealth(ctx, []string{})
+	require.NoError(t, err)
+	resources, err = client.WorkspaceResourcesByBuild(ctx, workspace.LatestBuild.ID)
+	require.NoError(t, err)
+	require.Empty(t, resources[0].Agents[0].HealthWarnings)
+}
+
+// limitedPubsub rejects messages larger than Postgres allows for
+// notifications.
+type limitedPubsub struct {
+	database.Pubsub
+}
+
+func (p limitedPubsub) Publish(event string, message []byte) error {
+	if len(message) >= 8000 {
+		return xerrors.New("payload string too long")
+	}
+	return p.Pubsub.Publish(event, message)
+}
@@ -1,6 +1,8 @@
 package coderd
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
@@ -26,11 +28,6 @@ func (api *API) workspaceAppsProxyPath(rw http.ResponseWriter, r *http.Request)
 	workspace := httpmw.WorkspaceParam(r)
 	agent := httpmw.WorkspaceAgentParam(r)
 
-	if !api.Authorize(r, rbac.ActionCreate, workspace.ApplicationConnectRBAC()) {
-		httpapi.ResourceNotFound(rw)
-		return
-	}
-
 	// Determine the real path that was hit. The * URL parameter in Chi will not
 	// include the leading slash if it was present, so we need to add it back.
 	chiPath := chi.URLParam(r, "*")
@@ -39,17 +36,73 @@ func (api *API) workspaceAppsProxyPath(rw http.ResponseWriter, r *http.Request)
 		chiPath = "/" + chiPath
 	}
 
+	// Path-based applications are served from the dashboard origin with the
+	// user's session, so ports are redirected to their subdomain URL, where
+	// their share level applies. Without subdomains, ports are only
+	// accessible by users who can connect to the workspace's applications.
+	appName, port := httpapi.AppNameOrPort(chi.URLParam(r, "workspaceapp"))
+	if port != 0 && api.AppHostname != "" {
+		appURL := api.subdomainAppURL(httpapi.ApplicationURL{
+			Port:          port,
+			AgentName:     agent.Name,
+			WorkspaceName: workspace.Name,
+			Username:      httpmw.UserParam(r).Username,
+		})
+		appURL.Path = chiPath
+		appURL.RawQuery = r.URL.RawQuery
+		http.Redirect(rw, r, appURL.String(), http.StatusTemporaryRedirect)
+		return
+	}
+
+	if _, ok := httpmw.APIKeyOptional(r); !ok {
+		httpmw.RedirectToLogin(rw, r, "You must be signed in to access this application.")
+		return
+	}
+	if !api.Authorize(r, rbac.ActionCreate, workspace.ApplicationConnectRBAC()) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
 	api.proxyWorkspaceApplication(proxyApplication{
-		Workspace: workspace,
-		Agent:     agent,
-		// We do not support port proxying for paths.
-		AppName:          chi.URLParam(r, "workspaceapp"),
-		Port:             0,
+		Workspace:        workspace,
+		Agent:            agent,
+		AppName:          appName,
+		Port:             port,
 		Path:             chiPath,
 		DashboardOnError: true,
 	}, rw, r)
 }
 
+// subdomainAppURL returns the URL of an application served from a subdomain
+// of the wildcard AppHostname. The base hostname of the application is
+// ignored.
+func (api *API) subdomainAppURL(app httpapi.ApplicationURL) *url.URL {
+	app.BaseHostname = strings.TrimPrefix(api.AppHostname, "*.")
+	host := app.String()
+	// Applications are served by the same listener as the access URL.
+	if port := api.AccessURL.Port(); port != "" {
+		host = net.JoinHostPort(host, port)
+	}
+	return &url.URL{
+		Scheme: api.AccessURL.Scheme,
+		Host:   host,
+		Path:   "/",
+	}
+}
+
+// isAppHostname returns true if the hostname, without its application
+// subdomain, is the wildcard AppHostname. Applications aren't served from
+// other hostnames.
+func (api *API) isAppHostname(baseHostname string) bool {
+	if api.AppHostname == "" {
+		return false
+	}
+	if host, _, err := net.SplitHostPort(baseHostname); err == nil {
+		baseHostname = host
+	}
+	return strings.EqualFold(baseHostname, strings.TrimPrefix(api.AppHostname, "*."))
+}
+
 func (api *API) handleSubdomainApplications(middlewares ...func(http.Handler) http.Handler) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
@@ -73,13 +126,9 @@ func (api *API) handleSubdomainApplications(middlewares ...func(http.Handler) ht
 			}
 
 			app, err := httpapi.ParseSubdomainAppURL(host)
-			if err != nil {
+			if err != nil || !api.isAppHostname(app.BaseHostname) {
 				// Subdomain is not a valid application url. Pass through to the
 				// rest of the app.
-				// TODO: @emyrk we should probably catch invalid subdomains. Meaning
-				// 	an invalid application should not route to the coderd.
-				//	To do this we would need to know the list of valid access urls
-				//	though?
 				next.ServeHTTP(rw, r)
 				return
 			}
@@ -108,6 +157,18 @@ func (api *API) handleSubdomainApplications(middlewares ...func(http.Handler) ht
 	}
 }
 
+// redirectAnonymousMe redirects requests for the "me" user to the login page
+// if they aren't authenticated, as there's no user to resolve it to.
+func redirectAnonymousMe(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if _, ok := httpmw.APIKeyOptional(r); !ok && chi.URLParam(r, "user") == "me" {
+			httpmw.RedirectToLogin(rw, r, "You must be signed in to access this application.")
+			return
+		}
+		next.ServeHTTP(rw, r)
+	})
+}
+
 // proxyApplication are the required fields to proxy a workspace application.
 type proxyApplication struct {
 	Workspace database.Workspace
@@ -127,8 +188,7 @@ type proxyApplication struct {
 
 func (api *API) proxyWorkspaceApplication(proxyApp proxyApplication, rw http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	if !api.Authorize(r, rbac.ActionCreate, proxyApp.Workspace.ApplicationConnectRBAC()) {
-		httpapi.ResourceNotFound(rw)
+	if !api.authorizeWorkspaceApp(rw, r, proxyApp) {
 		return
 	}
 
@@ -136,6 +196,7 @@ func (api *API) proxyWorkspaceApplication(proxyApp proxyApplication, rw http.Res
 	// route to the port as an "anonymous app". We only support HTTP for
 	// port-based URLs.
 	internalURL := fmt.Sprintf("http://127.0.0.1:%d", proxyApp.Port)
+	appHealth := database.WorkspaceAppHealthDisabled
 
 	// If the app name was used instead, fetch the app from the database so we
 	// can get the internal URL.
@@ -159,6 +220,7 @@ func (api *API) proxyWorkspaceApplication(proxyApp proxyApplication, rw http.Res
 			return
 		}
 		internalURL = app.Url.String
+		appHealth = app.Health
 	}
 
 	appURL, err := url.Parse(internalURL)
@@ -197,16 +259,24 @@ func (api *API) proxyWorkspaceApplication(proxyApp proxyApplication, rw http.Res
 		if proxyApp.DashboardOnError {
 			// To pass friendly errors to the frontend, special meta tags are
 			// overridden in the index.html with the content passed here.
+			message := err.Error()
+			if healthMessage := appHealthMessage(appHealth); healthMessage != "" {
+				message = fmt.Sprintf("%s %s", healthMessage, message)
+			}
 			r = r.WithContext(site.WithAPIResponse(ctx, site.APIResponse{
 				StatusCode: http.StatusBadGateway,
-				Message:    err.Error(),
+				Message:    message,
 			}))
 			api.siteHandler.ServeHTTP(w, r)
 			return
 		}
 
+		message := "Failed to proxy request to application."
+		if healthMessage := appHealthMessage(appHealth); healthMessage != "" {
+			message = healthMessage
+		}
 		httpapi.Write(w, http.StatusBadGateway, codersdk.Response{
-			Message: "Failed to proxy request to application.",
+			Message: message,
 			Detail:  err.Error(),
 		})
 	}
@@ -235,6 +305,62 @@ func (api *API) proxyWorkspaceApplication(proxyApp proxyApplication, rw http.Res
 	proxy.ServeHTTP(rw, r)
 }
 
+// appHealthMessage explains why an application with the given health may
+// have failed to respond. An empty string is returned if the health doesn't
+// explain the failure.
+func appHealthMessage(health database.WorkspaceAppHealth) string {
+	switch health {
+	case database.WorkspaceAppHealthInitializing:
+		return "Application is still initializing."
+	case database.WorkspaceAppHealthUnhealthy:
+		return "Application is unhealthy."
+	default:
+		return ""
+	}
+}
+
+// authorizeWorkspaceApp returns true if the user can access the application.
+// Applications are only accessible by users permitted to connect to the
+// owner's applications, while ports can be shared with other users through
+// their share level. Requests that are denied write a response.
+func (api *API) authorizeWorkspaceApp(rw http.ResponseWriter, r *http.Request, proxyApp proxyApplication) bool {
+	ctx := r.Context()
+	_, authenticated := httpmw.APIKeyOptional(r)
+	if authenticated && api.Authorize(r, rbac.ActionCreate, proxyApp.Workspace.ApplicationConnectRBAC()) {
+		return true
+	}
+
+	if proxyApp.Port != 0 {
+		share, err := api.Database.GetWorkspacePortShare(ctx, database.GetWorkspacePortShareParams{
+			WorkspaceID: proxyApp.Workspace.ID,
+			AgentName:   proxyApp.Agent.Name,
+			Port:        int32(proxyApp.Port),
+		})
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching workspace port share.",
+				Detail:  err.Error(),
+			})
+			return false
+		}
+		switch share.ShareLevel {
+		case database.WorkspacePortShareLevelPublic:
+			return true
+		case database.WorkspacePortShareLevelAuthenticated:
+			if authenticated {
+				return true
+			}
+		}
+	}
+
+	if !authenticated {
+		httpmw.RedirectToLogin(rw, r, "You must be signed in to access this application.")
+		return false
+	}
+	httpapi.ResourceNotFound(rw)
+	return false
+}
+
 // applicationCookie is a helper function to copy the auth cookie to also
 // support subdomains. Until we support creating authentication cookies that can
 // only do application authentication, we will just reuse the original token.
@@ -7,6 +7,7 @@ import (
 	"net"
 	"net/http"
 	"net/url"
+	"strings"
 	"testing"
 	"time"
 
@@ -58,6 +59,7 @@ func setupProxyTest(t *testing.T, workspaceMutators ...func(*codersdk.CreateWork
 	require.True(t, ok)
 
 	client := coderdtest.New(t, &coderdtest.Options{
+		AppHostname:                 "*.test.coder.com",
 		IncludeProvisionerDaemon:    true,
 		AgentStatsRefreshInterval:   time.Millisecond * 100,
 		MetricsCacheRefreshInterval: time.Millisecond * 100,
@@ -315,6 +317,24 @@ func TestWorkspaceAppsProxySubdomain(t *testing.T) {
 		require.Equal(t, http.StatusNotFound, resp.StatusCode)
 	})
 
+	t.Run("OtherHostname", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		// Applications are only served from the wildcard access URL.
+		appURL, err := url.Parse(proxyURL(t, port))
+		require.NoError(t, err)
+		appURL.Host = strings.Replace(appURL.Host, "test.coder.com", "other.coder.com", 1)
+		resp, err := client.Request(ctx, http.MethodGet, appURL.String(), nil)
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		require.NoError(t, err)
+		require.NotEqual(t, proxyTestAppBody, string(body))
+	})
+
 	t.Run("RedirectsWithSlash", func(t *testing.T) {
 		t.Parallel()
 
@@ -6,17 +6,23 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"golang.org/x/exp/slices"
+	"golang.org/x/sync/errgroup"
 	"golang.org/x/xerrors"
 
+	"github.com/coder/coder/coderd/agentshutdown"
+	"github.com/coder/coder/coderd/audit"
 	"github.com/coder/coder/coderd/database"
 	"github.com/coder/coder/coderd/httpapi"
 	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/coderd/parameter"
 	"github.com/coder/coder/coderd/rbac"
 	"github.com/coder/coder/codersdk"
 )
@@ -48,6 +54,7 @@ func (api *API) workspaceBuild(rw http.ResponseWriter, r *http.Request) {
 		data.metadata,
 		data.agents,
 		data.apps,
+		data.agentMetadata,
 	)
 	if err != nil {
 		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
@@ -137,6 +144,7 @@ func (api *API) workspaceBuilds(rw http.ResponseWriter, r *http.Request) {
 		data.metadata,
 		data.agents,
 		data.apps,
+		data.agentMetadata,
 	)
 	if err != nil {
 		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
@@ -218,6 +226,7 @@ func (api *API) workspaceBuildByBuildNumber(rw http.ResponseWriter, r *http.Requ
 		data.metadata,
 		data.agents,
 		data.apps,
+		data.agentMetadata,
 	)
 	if err != nil {
 		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
@@ -231,7 +240,6 @@ func (api *API) workspaceBuildByBuildNumber(rw http.ResponseWriter, r *http.Requ
 }
 
 func (api *API) postWorkspaceBuilds(rw http.ResponseWriter, r *http.Request) {
-	apiKey := httpmw.APIKey(r)
 	workspace := httpmw.WorkspaceParam(r)
 	var createBuild codersdk.CreateWorkspaceBuildRequest
 	if !httpapi.Read(rw, r, &createBuild) {
@@ -239,119 +247,549 @@ func (api *API) postWorkspaceBuilds(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	// Rbac action depends on the transition
-	var action rbac.Action
-	switch createBuild.Transition {
-	case codersdk.WorkspaceTransitionDelete:
-		action = rbac.ActionDelete
-	case codersdk.WorkspaceTransitionStart, codersdk.WorkspaceTransitionStop:
-		action = rbac.ActionUpdate
-	default:
+	action, ok := workspaceTransitionAction(createBuild.Transition)
+	if !ok {
 		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
 			Message: fmt.Sprintf("Transition %q not supported.", createBuild.Transition),
 		})
 		return
 	}
+
+	auditor := api.Auditor.Load()
+	aReq, commitAudit := audit.InitRequest[database.Workspace](rw, &audit.RequestParams{
+		Audit:   *auditor,
+		Log:     api.Logger,
+		Request: r,
+		Action:  audit.WorkspaceBuildAction(database.WorkspaceTransition(createBuild.Transition)),
+	})
+	defer commitAudit()
+	aReq.Old = workspace
+	aReq.New = workspace
+
 	if !api.Authorize(r, action, workspace) {
 		httpapi.ResourceNotFound(rw)
 		return
 	}
 
-	if createBuild.TemplateVersionID == uuid.Nil {
-		latestBuild, err := api.Database.GetLatestWorkspaceBuildByWorkspaceID(r.Context(), workspace.ID)
-		if err != nil {
-			httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
-				Message: "Internal error fetching the latest workspace build.",
-				Detail:  err.Error(),
-			})
-			return
-		}
-		createBuild.TemplateVersionID = latestBuild.TemplateVersionID
+	workspaceBuild, provisionerJob, err := api.createWorkspaceBuild(r, workspace, createBuild)
+	var buildErr buildError
+	if xerrors.As(err, &buildErr) {
+		httpapi.Write(rw, buildErr.status, buildErr.response)
+		return
+	}
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error inserting workspace build.",
+			Detail:  err.Error(),
+		})
+		return
 	}
+	aReq.AdditionalFields = audit.WorkspaceBuildFields(workspaceBuild)
 
-	templateVersion, err := api.Database.GetTemplateVersionByID(r.Context(), createBuild.TemplateVersionID)
-	if errors.Is(err, sql.ErrNoRows) {
+	users, err := api.Database.GetUsersByIDs(r.Context(), database.GetUsersByIDsParams{
+		IDs: []uuid.UUID{
+			workspace.OwnerID,
+			workspaceBuild.InitiatorID,
+		},
+	})
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error getting user.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	apiBuild, err := api.convertWorkspaceBuild(
+		workspaceBuild,
+		workspace,
+		provisionerJob,
+		users,
+		[]database.WorkspaceResource{},
+		[]database.WorkspaceResourceMetadatum{},
+		[]database.WorkspaceAgent{},
+		[]database.WorkspaceApp{},
+		[]database.WorkspaceAgentMetadatum{},
+	)
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error converting workspace build.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(rw, http.StatusCreated, apiBuild)
+}
+
+// bulkWorkspaceBuildConcurrency limits how many builds a bulk request
+// creates at once to avoid overloading the database.
+const bulkWorkspaceBuildConcurrency = 10
+
+// Creates a build for every workspace matching a search query, e.g. to stop
+// all workspaces of a deprecated template. The result of each workspace is
+// reported separately, so one failing build doesn't fail the request.
+func (api *API) postBulkWorkspaceBuilds(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		apiKey = httpmw.APIKey(r)
+	)
+	var req codersdk.BulkWorkspaceBuildRequest
+	if !httpapi.Read(rw, r, &req) {
+		return
+	}
+
+	action, ok := workspaceTransitionAction(req.Transition)
+	if !ok {
+		httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Transition %q not supported.", req.Transition),
+		})
+		return
+	}
+	if req.ActiveVersion && req.Transition != codersdk.WorkspaceTransitionStart {
+		httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Only starts can update workspaces to the active version.",
+			Validations: []codersdk.ValidationError{{
+				Field:  "active_version",
+				Detail: "requires the start transition",
+			}},
+		})
+		return
+	}
+	if req.IncludeStopped && !req.ActiveVersion {
 		httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
-			Message: "Template version not found.",
+			Message: "Only updates to the active version can include stopped workspaces.",
 			Validations: []codersdk.ValidationError{{
-				Field:  "template_version_id",
-				Detail: "template version not found",
+				Field:  "include_stopped",
+				Detail: "requires active_version",
 			}},
 		})
 		return
 	}
+
+	filter, errs := workspaceSearchQuery(req.Query)
+	if len(errs) > 0 {
+		httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid workspace search query.",
+			Validations: errs,
+		})
+		return
+	}
+	if filter.OwnerUsername == "me" {
+		filter.OwnerID = apiKey.UserID
+		filter.OwnerUsername = ""
+	}
+
+	workspaces, err := api.Database.GetWorkspaces(ctx, filter)
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspaces.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	// Workspaces the user can't read aren't mentioned in the results at all.
+	workspaces, err = AuthorizeFilter(api.HTTPAuth, r, rbac.ActionRead, workspaces)
 	if err != nil {
 		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
-			Message: "Internal error fetching template version.",
+			Message: "Internal error fetching workspaces.",
 			Detail:  err.Error(),
 		})
 		return
 	}
+	// Only build the workspaces that were asked for, so the workspaces a
+	// user confirmed after a dry run are the only ones built even if more
+	// match the query by now.
+	if req.WorkspaceIDs != nil {
+		requested := make(map[uuid.UUID]struct{}, len(req.WorkspaceIDs))
+		for _, id := range req.WorkspaceIDs {
+			requested[id] = struct{}{}
+		}
+		filtered := make([]database.Workspace, 0, len(req.WorkspaceIDs))
+		for _, workspace := range workspaces {
+			if _, ok := requested[workspace.ID]; ok {
+				filtered = append(filtered, workspace)
+			}
+		}
+		workspaces = filtered
+	}
 
-	template, err := api.Database.GetTemplateByID(r.Context(), templateVersion.TemplateID.UUID)
+	data, err := api.workspaceData(ctx, workspaces)
 	if err != nil {
 		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
-			Message: "Failed to get template",
+			Message: "Internal error fetching workspace resources.",
 			Detail:  err.Error(),
 		})
 		return
 	}
+	buildsByWorkspaceID := map[uuid.UUID]codersdk.WorkspaceBuild{}
+	for _, build := range data.builds {
+		buildsByWorkspaceID[build.WorkspaceID] = build
+	}
+	templatesByID := map[uuid.UUID]database.Template{}
+	for _, template := range data.templates {
+		templatesByID[template.ID] = template
+	}
+
+	// Active versions are fetched as needed, since many workspaces usually
+	// share a template.
+	activeVersionsByID := map[uuid.UUID]database.TemplateVersion{}
+
+	auditor := api.Auditor.Load()
+	ip, _, _ := net.SplitHostPort(r.RemoteAddr)
+	results := make([]codersdk.BulkWorkspaceBuildResult, len(workspaces))
+
+	// We only use errgroup here for convenience of API, not for early
+	// cancellation. This means we only return nil errors in the eg.Go.
+	eg := errgroup.Group{}
+	eg.SetLimit(bulkWorkspaceBuildConcurrency)
+	for i, workspace := range workspaces {
+		workspace := workspace
+		result := &results[i]
+		result.WorkspaceID = workspace.ID
+		result.WorkspaceName = workspace.Name
+		result.OwnerName = buildsByWorkspaceID[workspace.ID].WorkspaceOwnerName
+
+		if !api.Authorize(r, action, workspace) {
+			result.Skipped = fmt.Sprintf("You don't have permission to %s this workspace.", req.Transition)
+			continue
+		}
+		latestBuild := buildsByWorkspaceID[workspace.ID]
+		switch {
+		// A build can't be created while another is in progress.
+		case latestBuild.Job.Status == codersdk.ProvisionerJobPending || latestBuild.Job.Status == codersdk.ProvisionerJobRunning:
+			result.Skipped = "The workspace has a build in progress."
+			continue
+		case req.Transition == codersdk.WorkspaceTransitionStop &&
+			latestBuild.Transition == codersdk.WorkspaceTransitionStop &&
+			latestBuild.Job.Status == codersdk.ProvisionerJobSucceeded:
+			result.Skipped = "The workspace is already stopped."
+			continue
+		}
+		createBuild := codersdk.CreateWorkspaceBuildRequest{
+			Transition: req.Transition,
+		}
+		if req.ActiveVersion {
+			template := templatesByID[workspace.TemplateID]
+			if latestBuild.TemplateVersionID == template.ActiveVersionID {
+				result.Skipped = "The workspace is already on the active version."
+				continue
+			}
+			// Updating a workspace starts it, so workspaces that aren't
+			// running are left alone unless asked for.
+			if !req.IncludeStopped &&
+				(latestBuild.Transition != codersdk.WorkspaceTransitionStart || latestBuild.Job.Status != codersdk.ProvisionerJobSucceeded) {
+				result.Skipped = "The workspace isn't running."
+				continue
+			}
+			activeVersion, ok := activeVersionsByID[template.ActiveVersionID]
+			if !ok {
+				activeVersion, err = api.Database.GetTemplateVersionByID(ctx, template.ActiveVersionID)
+				if err != nil {
+					result.Error = fmt.Sprintf("Internal error fetching the active template version: %s", err)
+					continue
+				}
+				activeVersionsByID[activeVersion.ID] = activeVersion
+			}
+			// Parameters can't be prompted for, so the build would fail.
+			missing, err := api.missingWorkspaceParameters(ctx, workspace, activeVersion)
+			if err != nil {
+				result.Error = fmt.Sprintf("Internal error computing parameters: %s", err)
+				continue
+			}
+			if len(missing) > 0 {
+				result.Error = fmt.Sprintf("The active version requires values for new parameters: %s. Update the workspace on its own to set them.", strings.Join(missing, ", "))
+				continue
+			}
+			createBuild.TemplateVersionID = template.ActiveVersionID
+		}
+		if req.DryRun {
+			continue
+		}
+
+		eg.Go(func() error {
+			workspaceBuild, _, err := api.createWorkspaceBuild(r, workspace, createBuild)
+			status := http.StatusCreated
+			var (
+				fields   json.RawMessage
+				buildErr buildError
+			)
+			switch {
+			case xerrors.As(err, &buildErr):
+				status = buildErr.status
+				result.Error = buildErr.response.Message
+			case err != nil:
+				status = http.StatusInternalServerError
+				result.Error = fmt.Sprintf("Internal error inserting workspace build: %s", err)
+			default:
+				result.BuildID = &workspaceBuild.ID
+				fields = audit.WorkspaceBuildFields(workspaceBuild)
+			}
+
+			audit.BackgroundAudit(context.Background(), &audit.BackgroundAuditParams[database.Workspace]{
+				Audit:            *auditor,
+				Log:              api.Logger,
+				UserID:           apiKey.UserID,
+				RequestID:        httpmw.RequestID(r),
+				IP:               ip,
+				UserAgent:        r.UserAgent(),
+				Status:           status,
+				Action:           audit.WorkspaceBuildAction(database.WorkspaceTransition(req.Transition)),
+				AdditionalFields: fields,
+				Old:              workspace,
+				New:              workspace,
+			})
+			return nil
+		})
+	}
+	_ = eg.Wait()
+
+	httpapi.Write(rw, http.StatusOK, codersdk.BulkWorkspaceBuildResponse{
+		DryRun:  req.DryRun,
+		Results: results,
+	})
+}
+
+// workspaceTransitionAction returns the RBAC action required to build a
+// workspace with the transition.
+func workspaceTransitionAction(transition codersdk.WorkspaceTransition) (rbac.Action, bool) {
+	switch transition {
+	case codersdk.WorkspaceTransitionDelete:
+		return rbac.ActionDelete, true
+	case codersdk.WorkspaceTransitionStart, codersdk.WorkspaceTransitionStop:
+		return rbac.ActionUpdate, true
+	default:
+		return "", false
+	}
+}
+
+// missingWorkspaceParameters returns the names of the parameters of the
+// template version that have no value for the workspace, e.g. parameters
+// without a default that were added since the workspace was last built.
+func (api *API) missingWorkspaceParameters(ctx context.Context, workspace database.Workspace, templateVersion database.TemplateVersion) ([]string, error) {
+	schemas, err := api.Database.GetParameterSchemasByJobID(ctx, templateVersion.JobID)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = nil
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("get parameter schemas: %w", err)
+	}
+	values, err := parameter.Compute(ctx, api.Database, parameter.ComputeScope{
+		TemplateImportJobID: templateVersion.JobID,
+		OrganizationID:      workspace.OrganizationID,
+		UserID:              workspace.OwnerID,
+		TemplateID: uuid.NullUUID{
+			UUID:  workspace.TemplateID,
+			Valid: true,
+		},
+		WorkspaceID: uuid.NullUUID{
+			UUID:  workspace.ID,
+			Valid: true,
+		},
+	}, nil)
+	if err != nil {
+		return nil, xerrors.Errorf("compute parameters: %w", err)
+	}
+
+	hasValue := make(map[string]bool, len(values))
+	for _, value := range values {
+		hasValue[value.Name] = true
+	}
+	var missing []string
+	for _, schema := range schemas {
+		if !hasValue[schema.Name] {
+			missing = append(missing, schema.Name)
+		}
+	}
+	return missing, nil
+}
+
+// buildError is an error creating a workspace build that should be returned
+// to the client as is.
+type buildError struct {
+	status   int
+	response codersdk.Response
+}
+
+func (e buildError) Error() string {
+	if e.response.Detail != "" {
+		return e.response.Detail
+	}
+	return e.response.Message
+}
+
+// createWorkspaceBuild validates a build request and inserts the build and
+// its provisioner job. The caller must have authorized the transition on the
+// workspace. Errors that should be returned to the client are buildErrors.
+func (api *API) createWorkspaceBuild(r *http.Request, workspace database.Workspace, createBuild codersdk.CreateWorkspaceBuildRequest) (database.WorkspaceBuild, database.ProvisionerJob, error) {
+	apiKey := httpmw.APIKey(r)
+
+	if createBuild.TemplateVersionID == uuid.Nil {
+		latestBuild, err := api.Database.GetLatestWorkspaceBuildByWorkspaceID(r.Context(), workspace.ID)
+		if err != nil {
+			return database.WorkspaceBuild{}, database.ProvisionerJob{}, buildError{
+				status: http.StatusInternalServerError,
+				response: codersdk.Response{
+					Message: "Internal error fetching the latest workspace build.",
+					Detail:  err.Error(),
+				},
+			}
+		}
+		createBuild.TemplateVersionID = latestBuild.TemplateVersionID
+
+		// Starts without an explicit version are moved to the active version
+		// if the template or the workspace asks for it.
+		if createBuild.Transition == codersdk.WorkspaceTransitionStart {
+			template, err := api.Database.GetTemplateByID(r.Context(), workspace.TemplateID)
+			if err != nil {
+				return database.WorkspaceBuild{}, database.ProvisionerJob{}, buildError{
+					status: http.StatusInternalServerError,
+					response: codersdk.Response{
+						Message: "Internal error fetching template.",
+						Detail:  err.Error(),
+					},
+				}
+			}
+			if template.RequireActiveVersion || workspace.AutomaticUpdates == database.AutomaticUpdatesAlways {
+				createBuild.TemplateVersionID = template.ActiveVersionID
+			}
+		}
+	}
+
+	templateVersion, err := api.Database.GetTemplateVersionByID(r.Context(), createBuild.TemplateVersionID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return database.WorkspaceBuild{}, database.ProvisionerJob{}, buildError{
+			status: http.StatusBadRequest,
+			response: codersdk.Response{
+				Message: "Template version not found.",
+				Validations: []codersdk.ValidationError{{
+					Field:  "template_version_id",
+					Detail: "template version not found",
+				}},
+			},
+		}
+	}
+	if err != nil {
+		return database.WorkspaceBuild{}, database.ProvisionerJob{}, buildError{
+			status: http.StatusInternalServerError,
+			response: codersdk.Response{
+				Message: "Internal error fetching template version.",
+				Detail:  err.Error(),
+			},
+		}
+	}
+	// Workspaces on an archived version can still be stopped or deleted.
+	if templateVersion.Archived && createBuild.Transition == codersdk.WorkspaceTransitionStart {
+		return database.WorkspaceBuild{}, database.ProvisionerJob{}, buildError{
+			status: http.StatusBadRequest,
+			response: codersdk.Response{
+				Message: fmt.Sprintf("The provided template version %q is archived. You cannot start workspaces with it!", templateVersion.Name),
+				Validations: []codersdk.ValidationError{{
+					Field:  "template_version_id",
+					Detail: "template version is archived",
+				}},
+			},
+		}
+	}
+
+	template, err := api.Database.GetTemplateByID(r.Context(), templateVersion.TemplateID.UUID)
+	if err != nil {
+		return database.WorkspaceBuild{}, database.ProvisionerJob{}, buildError{
+			status: http.StatusInternalServerError,
+			response: codersdk.Response{
+				Message: "Failed to get template",
+				Detail:  err.Error(),
+			},
+		}
+	}
+
+	// Template admins may still start workspaces with other versions, e.g. to
+	// test a version before promoting it.
+	if template.RequireActiveVersion &&
+		createBuild.Transition == codersdk.WorkspaceTransitionStart &&
+		templateVersion.ID != template.ActiveVersionID &&
+		!api.Authorize(r, rbac.ActionUpdate, template.RBACObject()) {
+		return database.WorkspaceBuild{}, database.ProvisionerJob{}, buildError{
+			status: http.StatusForbidden,
+			response: codersdk.Response{
+				Message: "The template requires workspaces to be started with the active version.",
+				Validations: []codersdk.ValidationError{{
+					Field:  "template_version_id",
+					Detail: "must be the active version of the template",
+				}},
+			},
+		}
+	}
 
 	var state []byte
 	// If custom state, deny request since user could be corrupting or leaking
 	// cloud state.
 	if createBuild.ProvisionerState != nil || createBuild.Orphan {
 		if !api.Authorize(r, rbac.ActionUpdate, template.RBACObject()) {
-			httpapi.Write(rw, http.StatusForbidden, codersdk.Response{
-				Message: "Only template managers may provide custom state",
-			})
-			return
+			return database.WorkspaceBuild{}, database.ProvisionerJob{}, buildError{
+				status: http.StatusForbidden,
+				response: codersdk.Response{
+					Message: "Only template managers may provide custom state",
+				},
+			}
 		}
 		state = createBuild.ProvisionerState
 	}
 
 	if createBuild.Orphan {
 		if createBuild.Transition != codersdk.WorkspaceTransitionDelete {
-			httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
-				Message: "Orphan is only permitted when deleting a workspace.",
-				Detail:  err.Error(),
-			})
-			return
+			return database.WorkspaceBuild{}, database.ProvisionerJob{}, buildError{
+				status: http.StatusBadRequest,
+				response: codersdk.Response{
+					Message: "Orphan is only permitted when deleting a workspace.",
+				},
+			}
 		}
 
 		if createBuild.ProvisionerState != nil && createBuild.Orphan {
-			httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
-				Message: "ProvisionerState cannot be set alongside Orphan since state intent is unclear.",
-			})
-			return
+			return database.WorkspaceBuild{}, database.ProvisionerJob{}, buildError{
+				status: http.StatusBadRequest,
+				response: codersdk.Response{
+					Message: "ProvisionerState cannot be set alongside Orphan since state intent is unclear.",
+				},
+			}
 		}
 		state = []byte{}
 	}
 
 	templateVersionJob, err := api.Database.GetProvisionerJobByID(r.Context(), templateVersion.JobID)
 	if err != nil {
-		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
-			Message: "Internal error fetching provisioner job.",
-			Detail:  err.Error(),
-		})
-		return
+		return database.WorkspaceBuild{}, database.ProvisionerJob{}, buildError{
+			status: http.StatusInternalServerError,
+			response: codersdk.Response{
+				Message: "Internal error fetching provisioner job.",
+				Detail:  err.Error(),
+			},
+		}
 	}
 	templateVersionJobStatus := convertProvisionerJob(templateVersionJob).Status
 	switch templateVersionJobStatus {
 	case codersdk.ProvisionerJobPending, codersdk.ProvisionerJobRunning:
-		httpapi.Write(rw, http.StatusNotAcceptable, codersdk.Response{
-			Message: fmt.Sprintf("The provided template version is %s. Wait for it to complete importing!", templateVersionJobStatus),
-		})
-		return
+		return database.WorkspaceBuild{}, database.ProvisionerJob{}, buildError{
+			status: http.StatusNotAcceptable,
+			response: codersdk.Response{
+				Message: fmt.Sprintf("The provided template version is %s. Wait for it to complete importing!", templateVersionJobStatus),
+			},
+		}
 	case codersdk.ProvisionerJobFailed:
-		httpapi.Write(rw, http.StatusPreconditionFailed, codersdk.Response{
-			Message: fmt.Sprintf("The provided template version %q has failed to import: %q. You cannot build workspaces with it!", templateVersion.Name, templateVersionJob.Error.String),
-		})
-		return
+		return database.WorkspaceBuild{}, database.ProvisionerJob{}, buildError{
+			status: http.StatusPreconditionFailed,
+			response: codersdk.Response{
+				Message: fmt.Sprintf("The provided template version %q has failed to import: %q. You cannot build workspaces with it!", templateVersion.Name, templateVersionJob.Error.String),
+			},
+		}
 	case codersdk.ProvisionerJobCanceled:
-		httpapi.Write(rw, http.StatusPreconditionFailed, codersdk.Response{
-			Message: "The provided template version was canceled during import. You cannot builds workspaces with it!",
-		})
-		return
+		return database.WorkspaceBuild{}, database.ProvisionerJob{}, buildError{
+			status: http.StatusPreconditionFailed,
+			response: codersdk.Response{
+				Message: "The provided template version was canceled during import. You cannot builds workspaces with it!",
+			},
+		}
 	}
 
 	// Store prior build number to compute new build number
@@ -360,19 +798,23 @@ func (api *API) postWorkspaceBuilds(rw http.ResponseWriter, r *http.Request) {
 	if err == nil {
 		priorJob, err := api.Database.GetProvisionerJobByID(r.Context(), priorHistory.JobID)
 		if err == nil && convertProvisionerJob(priorJob).Status.Active() {
-			httpapi.Write(rw, http.StatusConflict, codersdk.Response{
-				Message: "A workspace build is already active.",
-			})
-			return
+			return database.WorkspaceBuild{}, database.ProvisionerJob{}, buildError{
+				status: http.StatusConflict,
+				response: codersdk.Response{
+					Message: "A workspace build is already active.",
+				},
+			}
 		}
 
 		priorBuildNum = priorHistory.BuildNumber
 	} else if !errors.Is(err, sql.ErrNoRows) {
-		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
-			Message: "Internal error fetching prior workspace build.",
-			Detail:  err.Error(),
-		})
-		return
+		return database.WorkspaceBuild{}, database.ProvisionerJob{}, buildError{
+			status: http.StatusInternalServerError,
+			response: codersdk.Response{
+				Message: "Internal error fetching prior workspace build.",
+				Detail:  err.Error(),
+			},
+		}
 	}
 
 	if state == nil {
@@ -439,6 +881,7 @@ func (api *API) postWorkspaceBuilds(rw http.ResponseWriter, r *http.Request) {
 			StorageMethod:  templateVersionJob.StorageMethod,
 			StorageSource:  templateVersionJob.StorageSource,
 			Input:          input,
+			Tags:           templateVersionJob.Tags,
 		})
 		if err != nil {
 			return xerrors.Errorf("insert provisioner job: %w", err)
@@ -464,46 +907,11 @@ func (api *API) postWorkspaceBuilds(rw http.ResponseWriter, r *http.Request) {
 		return nil
 	})
 	if err != nil {
-		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
-			Message: "Internal error inserting workspace build.",
-			Detail:  err.Error(),
-		})
-		return
+		return database.WorkspaceBuild{}, database.ProvisionerJob{}, err
 	}
+	agentshutdown.Request(r.Context(), api.Database, api.Pubsub, api.Logger, workspaceBuild)
 
-	users, err := api.Database.GetUsersByIDs(r.Context(), database.GetUsersByIDsParams{
-		IDs: []uuid.UUID{
-			workspace.OwnerID,
-			workspaceBuild.InitiatorID,
-		},
-	})
-	if err != nil {
-		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
-			Message: "Internal error getting user.",
-			Detail:  err.Error(),
-		})
-		return
-	}
-
-	apiBuild, err := api.convertWorkspaceBuild(
-		workspaceBuild,
-		workspace,
-		provisionerJob,
-		users,
-		[]database.WorkspaceResource{},
-		[]database.WorkspaceResourceMetadatum{},
-		[]database.WorkspaceAgent{},
-		[]database.WorkspaceApp{},
-	)
-	if err != nil {
-		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
-			Message: "Internal error converting workspace build.",
-			Detail:  err.Error(),
-		})
-		return
-	}
-
-	httpapi.Write(rw, http.StatusCreated, apiBuild)
+	return workspaceBuild, provisionerJob, nil
 }
 
 func (api *API) patchCancelWorkspaceBuild(rw http.ResponseWriter, r *http.Request) {
@@ -639,6 +1047,9 @@ type workspaceBuildsData struct {
 	metadata  []database.WorkspaceResourceMetadatum
 	agents    []database.WorkspaceAgent
 	apps      []database.WorkspaceApp
+	// agentMetadata contains the latest values of each agent's metadata
+	// scripts.
+	agentMetadata []database.WorkspaceAgentMetadatum
 }
 
 func (api *API) workspaceBuildsData(ctx context.Context, workspaces []database.Workspace, workspaceBuilds []database.WorkspaceBuild) (workspaceBuildsData, error) {
@@ -711,13 +1122,19 @@ func (api *API) workspaceBuildsData(ctx context.Context, workspaces []database.W
 		return workspaceBuildsData{}, xerrors.Errorf("fetching workspace apps: %w", err)
 	}
 
+	agentMetadata, err := api.Database.GetWorkspaceAgentMetadataByAgentIDs(ctx, agentIDs)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return workspaceBuildsData{}, xerrors.Errorf("fetching workspace agent metadata: %w", err)
+	}
+
 	return workspaceBuildsData{
-		users:     users,
-		jobs:      jobs,
-		resources: resources,
-		metadata:  metadata,
-		agents:    agents,
-		apps:      apps,
+		users:         users,
+		jobs:          jobs,
+		resources:     resources,
+		metadata:      metadata,
+		agents:        agents,
+		apps:          apps,
+		agentMetadata: agentMetadata,
 	}, nil
 }
 
@@ -730,6 +1147,7 @@ func (api *API) convertWorkspaceBuilds(
 	resourceMetadata []database.WorkspaceResourceMetadatum,
 	resourceAgents []database.WorkspaceAgent,
 	agentApps []database.WorkspaceApp,
+	agentMetadata []database.WorkspaceAgentMetadatum,
 ) ([]codersdk.WorkspaceBuild, error) {
 	workspaceByID := map[uuid.UUID]database.Workspace{}
 	for _, workspace := range workspaces {
@@ -760,6 +1178,7 @@ func (api *API) convertWorkspaceBuilds(
 			resourceMetadata,
 			resourceAgents,
 			agentApps,
+			agentMetadata,
 		)
 		if err != nil {
 			return nil, xerrors.Errorf("converting workspace build: %w", err)
@@ -780,6 +1199,7 @@ func (api *API) convertWorkspaceBuild(
 	resourceMetadata []database.WorkspaceResourceMetadatum,
 	resourceAgents []database.WorkspaceAgent,
 	agentApps []database.WorkspaceApp,
+	agentMetadata []database.WorkspaceAgentMetadatum,
 ) (codersdk.WorkspaceBuild, error) {
 	userByID := map[uuid.UUID]database.User{}
 	for _, user := range users {
@@ -801,6 +1221,10 @@ func (api *API) convertWorkspaceBuild(
 	for _, app := range agentApps {
 		appsByAgentID[app.AgentID] = append(appsByAgentID[app.AgentID], app)
 	}
+	metadataByAgentID := map[uuid.UUID][]database.WorkspaceAgentMetadatum{}
+	for _, md := range agentMetadata {
+		metadataByAgentID[md.WorkspaceAgentID] = append(metadataByAgentID[md.WorkspaceAgentID], md)
+	}
 
 	owner, exists := userByID[workspace.OwnerID]
 	if !exists {
@@ -818,7 +1242,7 @@ func (api *API) convertWorkspaceBuild(
 		apiAgents := make([]codersdk.WorkspaceAgent, 0)
 		for _, agent := range agents {
 			apps := appsByAgentID[agent.ID]
-			apiAgent, err := convertWorkspaceAgent(api.DERPMap, api.TailnetCoordinator, agent, convertApps(apps), api.AgentInactiveDisconnectTimeout)
+			apiAgent, err := convertWorkspaceAgent(api.DERPMap, *api.TailnetCoordinator.Load(), agent, convertApps(apps), convertWorkspaceAgentMetadata(metadataByAgentID[agent.ID]), api.AgentInactiveDisconnectTimeout)
 			if err != nil {
 				return codersdk.WorkspaceBuild{}, xerrors.Errorf("converting workspace agent: %w", err)
 			}
@@ -845,6 +1269,7 @@ func (api *API) convertWorkspaceBuild(
 		Deadline:           codersdk.NewNullTime(build.Deadline, !build.Deadline.IsZero()),
 		Reason:             codersdk.BuildReason(build.Reason),
 		Resources:          apiResources,
+		DailyCost:          build.DailyCost,
 	}, nil
 }
 
@@ -888,5 +1313,6 @@ func convertWorkspaceResource(resource database.WorkspaceResource, agents []code
 		Icon:       resource.Icon,
 		Agents:     agents,
 		Metadata:   convertedMetadata,
+		DailyCost:  resource.DailyCost,
 	}
 }
@@ -320,6 +320,257 @@ func TestWorkspaceBuildsProvisionerState(t *testing.T) {
 	})
 }
 
+func TestBulkWorkspaceBuild(t *testing.T) {
+	t.Parallel()
+	t.Run("Stop", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		legacy := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		other := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		workspaces := []codersdk.Workspace{
+			coderdtest.CreateWorkspace(t, client, user.OrganizationID, legacy.ID),
+			coderdtest.CreateWorkspace(t, client, user.OrganizationID, legacy.ID),
+			coderdtest.CreateWorkspace(t, client, user.OrganizationID, other.ID),
+		}
+		for _, workspace := range workspaces {
+			coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		req := codersdk.BulkWorkspaceBuildRequest{
+			Query:      "template:" + legacy.Name,
+			Transition: codersdk.WorkspaceTransitionStop,
+			DryRun:     true,
+		}
+		res, err := client.BulkWorkspaceBuild(ctx, req)
+		require.NoError(t, err)
+		require.True(t, res.DryRun)
+		require.Len(t, res.Results, 2)
+		for _, result := range res.Results {
+			require.Nil(t, result.BuildID)
+			require.Empty(t, result.Error)
+			workspace, err := client.Workspace(ctx, result.WorkspaceID)
+			require.NoError(t, err)
+			require.Equal(t, codersdk.WorkspaceTransitionStart, workspace.LatestBuild.Transition)
+		}
+
+		req.DryRun = false
+		res, err = client.BulkWorkspaceBuild(ctx, req)
+		require.NoError(t, err)
+		require.Len(t, res.Results, 2)
+		for _, result := range res.Results {
+			require.Empty(t, result.Error)
+			require.NotNil(t, result.BuildID)
+			build := coderdtest.AwaitWorkspaceBuildJob(t, client, *result.BuildID)
+			require.Equal(t, codersdk.WorkspaceTransitionStop, build.Transition)
+		}
+
+		// Workspaces of other templates aren't touched.
+		workspace, err := client.Workspace(ctx, workspaces[2].ID)
+		require.NoError(t, err)
+		require.Equal(t, codersdk.WorkspaceTransitionStart, workspace.LatestBuild.Transition)
+
+		// Workspaces that are already stopped are skipped.
+		res, err = client.BulkWorkspaceBuild(ctx, req)
+		require.NoError(t, err)
+		require.Len(t, res.Results, 2)
+		for _, result := range res.Results {
+			require.Nil(t, result.BuildID)
+			require.Equal(t, "The workspace is already stopped.", result.Skipped)
+		}
+	})
+
+	t.Run("BuildInProgress", func(t *testing.T) {
+		t.Parallel()
+		client, closeDaemon := coderdtest.NewWithProvisionerCloser(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		// The build stays pending without a daemon to acquire it.
+		_ = closeDaemon.Close()
+		_ = coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		for _, transition := range []codersdk.WorkspaceTransition{codersdk.WorkspaceTransitionStop, codersdk.WorkspaceTransitionDelete} {
+			for _, dryRun := range []bool{true, false} {
+				res, err := client.BulkWorkspaceBuild(ctx, codersdk.BulkWorkspaceBuildRequest{
+					Transition: transition,
+					DryRun:     dryRun,
+				})
+				require.NoError(t, err)
+				require.Len(t, res.Results, 1)
+				require.Nil(t, res.Results[0].BuildID)
+				require.Empty(t, res.Results[0].Error)
+				require.Equal(t, "The workspace has a build in progress.", res.Results[0].Skipped)
+			}
+		}
+	})
+
+	t.Run("ActiveVersion", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		outdated := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, outdated.LatestBuild.ID)
+		stopped := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, stopped.LatestBuild.ID)
+		_ = coderdtest.MustTransitionWorkspace(t, client, stopped.ID, database.WorkspaceTransitionStart, database.WorkspaceTransitionStop)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		newVersion := coderdtest.UpdateTemplateVersion(t, client, user.OrganizationID, nil, template.ID)
+		coderdtest.AwaitTemplateVersionJob(t, client, newVersion.ID)
+		err := client.UpdateActiveTemplateVersion(ctx, template.ID, codersdk.UpdateActiveTemplateVersion{
+			ID: newVersion.ID,
+		})
+		require.NoError(t, err)
+		current := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, current.LatestBuild.ID)
+
+		res, err := client.BulkWorkspaceBuild(ctx, codersdk.BulkWorkspaceBuildRequest{
+			Transition:    codersdk.WorkspaceTransitionStart,
+			ActiveVersion: true,
+		})
+		require.NoError(t, err)
+		require.Len(t, res.Results, 3)
+		for _, result := range res.Results {
+			require.Empty(t, result.Error)
+			switch result.WorkspaceID {
+			case outdated.ID:
+				require.NotNil(t, result.BuildID)
+				build := coderdtest.AwaitWorkspaceBuildJob(t, client, *result.BuildID)
+				require.Equal(t, newVersion.ID, build.TemplateVersionID)
+			case current.ID, stopped.ID:
+				require.Nil(t, result.BuildID)
+				require.NotEmpty(t, result.Skipped)
+			default:
+				t.Fatalf("unexpected workspace %s", result.WorkspaceID)
+			}
+		}
+
+		// Stopped workspaces are started with the active version when
+		// asked for.
+		res, err = client.BulkWorkspaceBuild(ctx, codersdk.BulkWorkspaceBuildRequest{
+			Transition:     codersdk.WorkspaceTransitionStart,
+			ActiveVersion:  true,
+			IncludeStopped: true,
+			WorkspaceIDs:   []uuid.UUID{stopped.ID},
+		})
+		require.NoError(t, err)
+		require.Len(t, res.Results, 1)
+		require.Empty(t, res.Results[0].Error)
+		require.NotNil(t, res.Results[0].BuildID)
+		build := coderdtest.AwaitWorkspaceBuildJob(t, client, *res.Results[0].BuildID)
+		require.Equal(t, newVersion.ID, build.TemplateVersionID)
+	})
+
+	t.Run("ActiveVersionMissingParameters", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		// The new version adds a parameter without a default.
+		newVersion := coderdtest.UpdateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+			Parse: []*proto.Parse_Response{{
+				Type: &proto.Parse_Response_Complete{
+					Complete: &proto.Parse_Complete{
+						ParameterSchemas: []*proto.ParameterSchema{{
+							Name: "region",
+							DefaultDestination: &proto.ParameterDestination{
+								Scheme: proto.ParameterDestination_PROVISIONER_VARIABLE,
+							},
+						}},
+					},
+				},
+			}},
+			Provision: echo.ProvisionComplete,
+		}, template.ID)
+		coderdtest.AwaitTemplateVersionJob(t, client, newVersion.ID)
+		err := client.UpdateActiveTemplateVersion(ctx, template.ID, codersdk.UpdateActiveTemplateVersion{
+			ID: newVersion.ID,
+		})
+		require.NoError(t, err)
+
+		res, err := client.BulkWorkspaceBuild(ctx, codersdk.BulkWorkspaceBuildRequest{
+			Transition:    codersdk.WorkspaceTransitionStart,
+			ActiveVersion: true,
+			DryRun:        true,
+		})
+		require.NoError(t, err)
+		require.Len(t, res.Results, 1)
+		require.Contains(t, res.Results[0].Error, "region")
+	})
+
+	t.Run("WorkspaceIDs", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		confirmed := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, confirmed.LatestBuild.ID)
+		// Matches the query too, but wasn't asked for.
+		other := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, other.LatestBuild.ID)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		res, err := client.BulkWorkspaceBuild(ctx, codersdk.BulkWorkspaceBuildRequest{
+			Query:        "template:" + template.Name,
+			Transition:   codersdk.WorkspaceTransitionStop,
+			WorkspaceIDs: []uuid.UUID{confirmed.ID},
+		})
+		require.NoError(t, err)
+		require.Len(t, res.Results, 1)
+		require.Equal(t, confirmed.ID, res.Results[0].WorkspaceID)
+		require.NotNil(t, res.Results[0].BuildID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, *res.Results[0].BuildID)
+
+		workspace, err := client.Workspace(ctx, other.ID)
+		require.NoError(t, err)
+		require.Equal(t, codersdk.WorkspaceTransitionStart, workspace.LatestBuild.Transition)
+	})
+
+	t.Run("InvalidQuery", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		_, err := client.BulkWorkspaceBuild(ctx, codersdk.BulkWorkspaceBuildRequest{
+			Query:      "template:a:b",
+			Transition: codersdk.WorkspaceTransitionStop,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+}
+
 func TestPatchCancelWorkspaceBuild(t *testing.T) {
 	t.Parallel()
 	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
@@ -0,0 +1,191 @@
+package coderd
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/codersdk"
+)
+
+func (api *API) workspacePortShares(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+	if !api.Authorize(r, rbac.ActionRead, workspace) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	shares, err := api.Database.GetWorkspacePortShares(ctx, workspace.ID)
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace port shares.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	owner, err := api.Database.GetUserByID(ctx, workspace.OwnerID)
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace owner.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	converted := make([]codersdk.WorkspacePortShare, 0, len(shares))
+	for _, share := range shares {
+		converted = append(converted, api.convertWorkspacePortShare(owner, workspace, share))
+	}
+	httpapi.Write(rw, http.StatusOK, converted)
+}
+
+func (api *API) postWorkspacePortShare(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+	if !api.Authorize(r, rbac.ActionUpdate, workspace) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	var req codersdk.UpsertWorkspacePortShareRequest
+	if !httpapi.Read(rw, r, &req) {
+		return
+	}
+	if req.ShareLevel != codersdk.WorkspacePortShareLevelOwner && api.AppHostname == "" {
+		// Shared ports are only served from subdomains, which need a
+		// wildcard hostname.
+		httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Ports can only be shared when a wildcard access URL is configured.",
+			Validations: []codersdk.ValidationError{{
+				Field:  "share_level",
+				Detail: fmt.Sprintf("Share level %q requires a wildcard access URL.", req.ShareLevel),
+			}},
+		})
+		return
+	}
+	if !api.workspaceHasAgent(rw, r, workspace.ID, req.AgentName) {
+		return
+	}
+
+	share, err := api.Database.UpsertWorkspacePortShare(ctx, database.UpsertWorkspacePortShareParams{
+		WorkspaceID: workspace.ID,
+		AgentName:   req.AgentName,
+		Port:        req.Port,
+		ShareLevel:  database.WorkspacePortShareLevel(req.ShareLevel),
+	})
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating workspace port share.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	owner, err := api.Database.GetUserByID(ctx, workspace.OwnerID)
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace owner.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(rw, http.StatusOK, api.convertWorkspacePortShare(owner, workspace, share))
+}
+
+func (api *API) deleteWorkspacePortShare(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+	if !api.Authorize(r, rbac.ActionUpdate, workspace) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	var req codersdk.DeleteWorkspacePortShareRequest
+	if !httpapi.Read(rw, r, &req) {
+		return
+	}
+
+	err := api.Database.DeleteWorkspacePortShare(ctx, database.DeleteWorkspacePortShareParams{
+		WorkspaceID: workspace.ID,
+		AgentName:   req.AgentName,
+		Port:        req.Port,
+	})
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting workspace port share.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// workspaceHasAgent writes a validation error and returns false if the
+// latest build of the workspace doesn't have an agent with the name provided.
+func (api *API) workspaceHasAgent(rw http.ResponseWriter, r *http.Request, workspaceID uuid.UUID, agentName string) bool {
+	ctx := r.Context()
+	build, err := api.Database.GetLatestWorkspaceBuildByWorkspaceID(ctx, workspaceID)
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching latest workspace build.",
+			Detail:  err.Error(),
+		})
+		return false
+	}
+	resources, err := api.Database.GetWorkspaceResourcesByJobID(ctx, build.JobID)
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace resources.",
+			Detail:  err.Error(),
+		})
+		return false
+	}
+	resourceIDs := make([]uuid.UUID, 0, len(resources))
+	for _, resource := range resources {
+		resourceIDs = append(resourceIDs, resource.ID)
+	}
+	agents, err := api.Database.GetWorkspaceAgentsByResourceIDs(ctx, resourceIDs)
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace agents.",
+			Detail:  err.Error(),
+		})
+		return false
+	}
+	for _, agent := range agents {
+		if agent.Name == agentName {
+			return true
+		}
+	}
+	httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+		Message: fmt.Sprintf("Workspace doesn't have an agent named %q.", agentName),
+		Validations: []codersdk.ValidationError{
+			{Field: "agent_name", Detail: "agent not found"},
+		},
+	})
+	return false
+}
+
+func (api *API) convertWorkspacePortShare(owner database.User, workspace database.Workspace, share database.WorkspacePortShare) codersdk.WorkspacePortShare {
+	converted := codersdk.WorkspacePortShare{
+		WorkspaceID: share.WorkspaceID,
+		AgentName:   share.AgentName,
+		Port:        share.Port,
+		ShareLevel:  codersdk.WorkspacePortShareLevel(share.ShareLevel),
+	}
+	if api.AppHostname != "" {
+		converted.URL = api.subdomainAppURL(httpapi.ApplicationURL{
+			Port:          uint16(share.Port),
+			AgentName:     share.AgentName,
+			WorkspaceName: workspace.Name,
+			Username:      owner.Username,
+		}).String()
+	}
+	return converted
+}
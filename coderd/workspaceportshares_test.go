@@ -0,0 +1,194 @@
+package coderd_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/testutil"
+)
+
+func TestWorkspacePortShares(t *testing.T) {
+	t.Parallel()
+
+	t.Run("CRUD", func(t *testing.T) {
+		t.Parallel()
+		client, orgID, workspace, port := setupProxyTest(t)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		_, err := client.UpsertWorkspacePortShare(ctx, workspace.ID, codersdk.UpsertWorkspacePortShareRequest{
+			AgentName:  "unknown",
+			Port:       int32(port),
+			ShareLevel: codersdk.WorkspacePortShareLevelPublic,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		share, err := client.UpsertWorkspacePortShare(ctx, workspace.ID, codersdk.UpsertWorkspacePortShareRequest{
+			AgentName:  proxyTestAgentName,
+			Port:       int32(port),
+			ShareLevel: codersdk.WorkspacePortShareLevelAuthenticated,
+		})
+		require.NoError(t, err)
+		require.Equal(t, codersdk.WorkspacePortShareLevelAuthenticated, share.ShareLevel)
+
+		share, err = client.UpsertWorkspacePortShare(ctx, workspace.ID, codersdk.UpsertWorkspacePortShareRequest{
+			AgentName:  proxyTestAgentName,
+			Port:       int32(port),
+			ShareLevel: codersdk.WorkspacePortShareLevelPublic,
+		})
+		require.NoError(t, err)
+		require.Equal(t, codersdk.WorkspacePortShareLevelPublic, share.ShareLevel)
+
+		shares, err := client.WorkspacePortShares(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Equal(t, []codersdk.WorkspacePortShare{share}, shares)
+
+		// Other users can't change the shares of a workspace.
+		userClient := coderdtest.CreateAnotherUser(t, client, orgID, rbac.RoleMember())
+		err = userClient.DeleteWorkspacePortShare(ctx, workspace.ID, codersdk.DeleteWorkspacePortShareRequest{
+			AgentName: proxyTestAgentName,
+			Port:      int32(port),
+		})
+		require.Error(t, err)
+
+		err = client.DeleteWorkspacePortShare(ctx, workspace.ID, codersdk.DeleteWorkspacePortShareRequest{
+			AgentName: proxyTestAgentName,
+			Port:      int32(port),
+		})
+		require.NoError(t, err)
+		shares, err = client.WorkspacePortShares(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Empty(t, shares)
+	})
+
+	t.Run("NoWildcardAccessURL", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		// Without subdomains, shared ports would only be reachable from
+		// the dashboard origin.
+		_, err := client.UpsertWorkspacePortShare(ctx, workspace.ID, codersdk.UpsertWorkspacePortShareRequest{
+			AgentName:  "dev",
+			Port:       8080,
+			ShareLevel: codersdk.WorkspacePortShareLevelPublic,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+		require.Len(t, apiErr.Validations, 1)
+		require.Equal(t, "share_level", apiErr.Validations[0].Field)
+	})
+
+	t.Run("ShareLevels", func(t *testing.T) {
+		t.Parallel()
+		client, orgID, workspace, port := setupProxyTest(t)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		me, err := client.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+
+		userClient := coderdtest.CreateAnotherUser(t, client, orgID, rbac.RoleMember())
+		userClient.HTTPClient.CheckRedirect = client.HTTPClient.CheckRedirect
+		userClient.HTTPClient.Transport = client.HTTPClient.Transport
+		anonymousClient := codersdk.New(client.URL)
+		anonymousClient.HTTPClient.CheckRedirect = client.HTTPClient.CheckRedirect
+		anonymousClient.HTTPClient.Transport = client.HTTPClient.Transport
+
+		pathURL := fmt.Sprintf("/@%s/%s.%s/apps/%d/", me.Username, workspace.Name, proxyTestAgentName, port)
+		subdomainURL := (&url.URL{
+			Scheme: "http",
+			Host: httpapi.ApplicationURL{
+				Port:          port,
+				AgentName:     proxyTestAgentName,
+				WorkspaceName: workspace.Name,
+				Username:      me.Username,
+				BaseHostname:  "test.coder.com",
+			}.String(),
+			Path: "/",
+		}).String()
+
+		requestStatus := func(t *testing.T, client *codersdk.Client, rawURL string) (int, string) {
+			t.Helper()
+			resp, err := client.Request(ctx, http.MethodGet, rawURL, nil)
+			require.NoError(t, err)
+			body, err := io.ReadAll(resp.Body)
+			_ = resp.Body.Close()
+			require.NoError(t, err)
+			return resp.StatusCode, string(body)
+		}
+		requireStatus := func(t *testing.T, client *codersdk.Client, status int) {
+			t.Helper()
+			got, body := requestStatus(t, client, subdomainURL)
+			require.Equal(t, status, got)
+			if status == http.StatusOK {
+				require.Equal(t, proxyTestAppBody, body)
+			}
+		}
+		share := func(t *testing.T, level codersdk.WorkspacePortShareLevel) {
+			t.Helper()
+			_, err := client.UpsertWorkspacePortShare(ctx, workspace.ID, codersdk.UpsertWorkspacePortShareRequest{
+				AgentName:  proxyTestAgentName,
+				Port:       int32(port),
+				ShareLevel: level,
+			})
+			require.NoError(t, err)
+		}
+
+		// Ports are only accessible by the owner by default.
+		requireStatus(t, client, http.StatusOK)
+		requireStatus(t, userClient, http.StatusNotFound)
+		requireStatus(t, anonymousClient, http.StatusTemporaryRedirect)
+
+		share(t, codersdk.WorkspacePortShareLevelAuthenticated)
+		requireStatus(t, userClient, http.StatusOK)
+		requireStatus(t, anonymousClient, http.StatusTemporaryRedirect)
+
+		share(t, codersdk.WorkspacePortShareLevelPublic)
+		requireStatus(t, userClient, http.StatusOK)
+		requireStatus(t, anonymousClient, http.StatusOK)
+
+		// Path-based URLs are served from the dashboard origin, so ports
+		// are redirected to their subdomain URL.
+		for _, client := range []*codersdk.Client{userClient, anonymousClient} {
+			resp, err := client.Request(ctx, http.MethodGet, pathURL+"path?query=true", nil)
+			require.NoError(t, err)
+			_ = resp.Body.Close()
+			require.Equal(t, http.StatusTemporaryRedirect, resp.StatusCode)
+			location, err := resp.Location()
+			require.NoError(t, err)
+			require.Equal(t, strings.TrimSuffix(subdomainURL, "/"), location.Scheme+"://"+location.Hostname())
+			require.Equal(t, "/path", location.Path)
+			require.Equal(t, "query=true", location.RawQuery)
+		}
+
+		share(t, codersdk.WorkspacePortShareLevelOwner)
+		requireStatus(t, client, http.StatusOK)
+		requireStatus(t, userClient, http.StatusNotFound)
+		requireStatus(t, anonymousClient, http.StatusTemporaryRedirect)
+	})
+}
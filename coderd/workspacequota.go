@@ -0,0 +1,27 @@
+package coderd
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/coderd/database"
+)
+
+// QuotaCommitter decides whether a workspace build may consume its daily
+// cost. The AGPL version of Coder does not enforce quotas; Enterprise
+// swaps in an implementation that enforces group quota allowances.
+type QuotaCommitter interface {
+	// CommitQuota is called before a build is provisioned, within a
+	// transaction that holds a lock on the owner's quota, so the store
+	// provided should be used for all queries.
+	CommitQuota(ctx context.Context, db database.Store, ownerID uuid.UUID, dailyCost int32) (QuotaCommitResult, error)
+}
+
+// QuotaCommitResult is the outcome of committing a build's daily cost
+// against its owner's quota.
+type QuotaCommitResult struct {
+	Permitted       bool
+	CreditsConsumed int64
+	Budget          int64
+}
@@ -61,6 +61,14 @@ func (api *API) workspaceResource(rw http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
+	agentMetadata, err := api.Database.GetWorkspaceAgentMetadataByAgentIDs(r.Context(), agentIDs)
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace agent metadata.",
+			Detail:  err.Error(),
+		})
+		return
+	}
 	apiAgents := make([]codersdk.WorkspaceAgent, 0)
 	for _, agent := range agents {
 		dbApps := make([]database.WorkspaceApp, 0)
@@ -69,8 +77,14 @@ func (api *API) workspaceResource(rw http.ResponseWriter, r *http.Request) {
 				dbApps = append(dbApps, app)
 			}
 		}
+		dbMetadata := make([]database.WorkspaceAgentMetadatum, 0)
+		for _, md := range agentMetadata {
+			if md.WorkspaceAgentID == agent.ID {
+				dbMetadata = append(dbMetadata, md)
+			}
+		}
 
-		convertedAgent, err := convertWorkspaceAgent(api.DERPMap, api.TailnetCoordinator, agent, convertApps(dbApps), api.AgentInactiveDisconnectTimeout)
+		convertedAgent, err := convertWorkspaceAgent(api.DERPMap, *api.TailnetCoordinator.Load(), agent, convertApps(dbApps), convertWorkspaceAgentMetadata(dbMetadata), api.AgentInactiveDisconnectTimeout)
 		if err != nil {
 			httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
 				Message: "Internal error reading workspace agent.",
@@ -0,0 +1,83 @@
+package codersdk
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1" //#nosec // Matches the checksums published by coderd.
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// AgentBinaryName returns the name of the binary that coderd serves for the
+// operating system and architecture provided.
+func AgentBinaryName(goos, goarch string) string {
+	name := fmt.Sprintf("coder-%s-%s", goos, goarch)
+	if goos == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+// DownloadAgentBinary writes the binary for the operating system and
+// architecture provided to w. The binary is verified against the checksums
+// published by coderd, and an error is returned if it doesn't match.
+func (c *Client) DownloadAgentBinary(ctx context.Context, goos, goarch string, w io.Writer) error {
+	name := AgentBinaryName(goos, goarch)
+	checksums, err := c.agentBinaryChecksums(ctx)
+	if err != nil {
+		return xerrors.Errorf("get checksums: %w", err)
+	}
+	expected, ok := checksums[name]
+	if !ok {
+		return xerrors.Errorf("no checksum published for %q", name)
+	}
+
+	res, err := c.Request(ctx, http.MethodGet, "/bin/"+name, nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return readBodyAsError(res)
+	}
+	//#nosec // Not used for cryptography.
+	hash := sha1.New()
+	_, err = io.Copy(io.MultiWriter(w, hash), res.Body)
+	if err != nil {
+		return xerrors.Errorf("download %q: %w", name, err)
+	}
+	actual := hex.EncodeToString(hash.Sum(nil))
+	if actual != expected {
+		return xerrors.Errorf("checksum mismatch for %q: expected %s, got %s", name, expected, actual)
+	}
+	return nil
+}
+
+// agentBinaryChecksums returns the SHA1 checksum of each binary served by
+// coderd, keyed by name.
+func (c *Client) agentBinaryChecksums(ctx context.Context) (map[string]string, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/bin/coder.sha1", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, readBodyAsError(res)
+	}
+	// Lines are in the format "<checksum> *<name>".
+	checksums := map[string]string{}
+	scanner := bufio.NewScanner(res.Body)
+	for scanner.Scan() {
+		parts := strings.Fields(scanner.Text())
+		if len(parts) != 2 {
+			continue
+		}
+		checksums[strings.TrimPrefix(parts[1], "*")] = strings.ToLower(parts[0])
+	}
+	return checksums, scanner.Err()
+}
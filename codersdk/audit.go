@@ -3,6 +3,7 @@ package codersdk
 import (
 	"context"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/netip"
 	"strings"
@@ -47,9 +48,15 @@ func (r ResourceType) FriendlyString() string {
 type AuditAction string
 
 const (
-	AuditActionCreate AuditAction = "create"
-	AuditActionWrite  AuditAction = "write"
-	AuditActionDelete AuditAction = "delete"
+	AuditActionCreate     AuditAction = "create"
+	AuditActionWrite      AuditAction = "write"
+	AuditActionDelete     AuditAction = "delete"
+	AuditActionStart      AuditAction = "start"
+	AuditActionStop       AuditAction = "stop"
+	AuditActionLogin      AuditAction = "login"
+	AuditActionLogout     AuditAction = "logout"
+	AuditActionConnect    AuditAction = "connect"
+	AuditActionDisconnect AuditAction = "disconnect"
 )
 
 func (a AuditAction) FriendlyString() string {
@@ -60,6 +67,18 @@ func (a AuditAction) FriendlyString() string {
 		return "updated"
 	case AuditActionDelete:
 		return "deleted"
+	case AuditActionStart:
+		return "started"
+	case AuditActionStop:
+		return "stopped"
+	case AuditActionLogin:
+		return "logged in"
+	case AuditActionLogout:
+		return "logged out"
+	case AuditActionConnect:
+		return "connected to"
+	case AuditActionDisconnect:
+		return "disconnected from"
 	default:
 		return "unknown"
 	}
@@ -188,3 +207,38 @@ func (c *Client) CreateTestAuditLog(ctx context.Context, req CreateTestAuditLogR
 
 	return nil
 }
+
+type AuditLogExportFormat string
+
+const (
+	AuditLogExportFormatJSON AuditLogExportFormat = "json"
+	AuditLogExportFormatCSV  AuditLogExportFormat = "csv"
+)
+
+type AuditLogExportRequest struct {
+	SearchQuery string               `json:"q,omitempty"`
+	Format      AuditLogExportFormat `json:"format,omitempty"`
+}
+
+// ExportAuditLogs streams every audit log matching the search query in
+// the requested format. The caller must close the returned reader.
+func (c *Client) ExportAuditLogs(ctx context.Context, req AuditLogExportRequest) (io.ReadCloser, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/audit/export", nil, func(r *http.Request) {
+		q := r.URL.Query()
+		if req.SearchQuery != "" {
+			q.Set("q", req.SearchQuery)
+		}
+		if req.Format != "" {
+			q.Set("format", string(req.Format))
+		}
+		r.URL.RawQuery = q.Encode()
+	})
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
+		return nil, readBodyAsError(res)
+	}
+	return res.Body, nil
+}
@@ -15,12 +15,15 @@ const (
 )
 
 const (
-	FeatureUserLimit = "user_limit"
-	FeatureAuditLog  = "audit_log"
-	FeatureSCIM      = "scim"
+	FeatureUserLimit        = "user_limit"
+	FeatureAuditLog         = "audit_log"
+	FeatureSCIM             = "scim"
+	FeatureHighAvailability = "high_availability"
+	FeatureTemplateRBAC     = "template_rbac"
+	FeatureWorkspaceQuota   = "workspace_quota"
 )
 
-var FeatureNames = []string{FeatureUserLimit, FeatureAuditLog, FeatureSCIM}
+var FeatureNames = []string{FeatureUserLimit, FeatureAuditLog, FeatureSCIM, FeatureHighAvailability, FeatureTemplateRBAC, FeatureWorkspaceQuota}
 
 type Feature struct {
 	Entitlement Entitlement `json:"entitlement"`
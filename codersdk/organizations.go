@@ -43,6 +43,12 @@ type CreateTemplateVersionRequest struct {
 	// ParameterValues allows for additional parameters to be provided
 	// during the dry-run provision stage.
 	ParameterValues []CreateParameterRequest `json:"parameter_values,omitempty"`
+	// ProvisionerTags restricts the version's jobs to provisioner daemons
+	// with all of the tags provided. Workspace builds of the version inherit
+	// these tags.
+	ProvisionerTags map[string]string `json:"provisioner_tags,omitempty"`
+	// Message describes the changes of the version, e.g. release notes.
+	Message string `json:"message,omitempty" validate:"lt=1048576"`
 }
 
 // CreateTemplateRequest provides options when creating a template.
@@ -73,6 +79,10 @@ type CreateTemplateRequest struct {
 	// allowable duration between autostarts for all workspaces created from
 	// this template.
 	MinAutostartIntervalMillis *int64 `json:"min_autostart_interval_ms,omitempty"`
+
+	// RequireActiveVersion prevents workspaces from being started with any
+	// version other than the active one.
+	RequireActiveVersion bool `json:"require_active_version,omitempty"`
 }
 
 // CreateWorkspaceRequest provides options for creating a new workspace.
@@ -84,6 +94,8 @@ type CreateWorkspaceRequest struct {
 	// ParameterValues allows for additional parameters to be provided
 	// during the initial provision.
 	ParameterValues []CreateParameterRequest `json:"parameter_values,omitempty"`
+	// AutomaticUpdates defaults to AutomaticUpdatesNever.
+	AutomaticUpdates AutomaticUpdates `json:"automatic_updates,omitempty" validate:"omitempty,oneof=always never"`
 }
 
 func (c *Client) Organization(ctx context.Context, id uuid.UUID) (Organization, error) {
@@ -30,6 +30,41 @@ type Template struct {
 	MinAutostartIntervalMillis int64     `json:"min_autostart_interval_ms"`
 	CreatedByID                uuid.UUID `json:"created_by_id"`
 	CreatedByName              string    `json:"created_by_name"`
+	// RequireActiveVersion prevents workspaces from being started with any
+	// version other than the active one.
+	RequireActiveVersion bool `json:"require_active_version"`
+}
+
+type TemplateRole string
+
+const (
+	TemplateRoleAdmin   TemplateRole = "admin"
+	TemplateRoleUse     TemplateRole = "use"
+	TemplateRoleDeleted TemplateRole = ""
+)
+
+// TemplateACL lists the users and groups that have been granted access
+// to a template.
+type TemplateACL struct {
+	Users  []TemplateUser  `json:"users"`
+	Groups []TemplateGroup `json:"group"`
+}
+
+type TemplateGroup struct {
+	Group
+	Role TemplateRole `json:"role"`
+}
+
+type TemplateUser struct {
+	User
+	Role TemplateRole `json:"role"`
+}
+
+// UpdateTemplateACL maps user and group IDs to the role they should have
+// on a template. Assigning TemplateRoleDeleted removes their access.
+type UpdateTemplateACL struct {
+	UserPerms  map[string]TemplateRole `json:"user_perms,omitempty"`
+	GroupPerms map[string]TemplateRole `json:"group_perms,omitempty"`
 }
 
 type UpdateActiveTemplateVersion struct {
@@ -42,6 +77,8 @@ type UpdateTemplateMeta struct {
 	Icon                       string `json:"icon,omitempty"`
 	MaxTTLMillis               int64  `json:"max_ttl_ms,omitempty"`
 	MinAutostartIntervalMillis int64  `json:"min_autostart_interval_ms,omitempty"`
+	// RequireActiveVersion is only updated if set.
+	RequireActiveVersion *bool `json:"require_active_version,omitempty"`
 }
 
 // Template returns a single template.
@@ -86,6 +123,31 @@ func (c *Client) UpdateTemplateMeta(ctx context.Context, templateID uuid.UUID, r
 	return updated, json.NewDecoder(res.Body).Decode(&updated)
 }
 
+func (c *Client) UpdateTemplateACL(ctx context.Context, templateID uuid.UUID, req UpdateTemplateACL) error {
+	res, err := c.Request(ctx, http.MethodPatch, fmt.Sprintf("/api/v2/templates/%s/acl", templateID), req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return readBodyAsError(res)
+	}
+	return nil
+}
+
+func (c *Client) TemplateACL(ctx context.Context, templateID uuid.UUID) (TemplateACL, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templates/%s/acl", templateID), nil)
+	if err != nil {
+		return TemplateACL{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return TemplateACL{}, readBodyAsError(res)
+	}
+	var acl TemplateACL
+	return acl, json.NewDecoder(res.Body).Decode(&acl)
+}
+
 // UpdateActiveTemplateVersion updates the active template version to the ID provided.
 // The template version must be attached to the template.
 func (c *Client) UpdateActiveTemplateVersion(ctx context.Context, template uuid.UUID, req UpdateActiveTemplateVersion) error {
@@ -104,12 +166,27 @@ func (c *Client) UpdateActiveTemplateVersion(ctx context.Context, template uuid.
 // TemplateVersionsByTemplate.
 type TemplateVersionsByTemplateRequest struct {
 	TemplateID uuid.UUID `json:"template_id" validate:"required"`
+	// IncludeArchived includes archived versions, which are hidden by
+	// default.
+	IncludeArchived bool `json:"include_archived"`
 	Pagination
 }
 
+// asRequestOption returns a function that can be used in (*Client).Request.
+// It modifies the request query parameters.
+func (r TemplateVersionsByTemplateRequest) asRequestOption() requestOption {
+	return func(req *http.Request) {
+		q := req.URL.Query()
+		if r.IncludeArchived {
+			q.Set("include_archived", "true")
+		}
+		req.URL.RawQuery = q.Encode()
+	}
+}
+
 // TemplateVersionsByTemplate lists versions associated with a template.
 func (c *Client) TemplateVersionsByTemplate(ctx context.Context, req TemplateVersionsByTemplateRequest) ([]TemplateVersion, error) {
-	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templates/%s/versions", req.TemplateID), nil, req.Pagination.asRequestOption())
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templates/%s/versions", req.TemplateID), nil, req.Pagination.asRequestOption(), req.asRequestOption())
 	if err != nil {
 		return nil, err
 	}
@@ -160,6 +237,42 @@ func (c *Client) TemplateDAUs(ctx context.Context, templateID uuid.UUID) (*Templ
 	return &resp, json.NewDecoder(res.Body).Decode(&resp)
 }
 
+// AgentUsageEntry is the usage of workspace agents on a single day.
+type AgentUsageEntry struct {
+	Date            time.Time `json:"date"`
+	ConnectionCount int64     `json:"connection_count"`
+	RxBytes         int64     `json:"rx_bytes"`
+	TxBytes         int64     `json:"tx_bytes"`
+	// SessionCount is the number of sessions opened, by type. Types are
+	// "ssh", "vscode", "jetbrains", "reconnecting_pty", and "port_forward".
+	SessionCount           map[string]int64 `json:"session_count"`
+	SessionDurationSeconds float64          `json:"session_duration_seconds"`
+	// AverageLatencyMS is the average round trip time between coderd and
+	// agents. It's zero when no latency was measured.
+	AverageLatencyMS float64 `json:"average_latency_ms"`
+}
+
+type AgentUsageResponse struct {
+	Entries []AgentUsageEntry `json:"entries"`
+}
+
+// TemplateUsage returns the daily usage of workspaces created from the
+// template.
+func (c *Client) TemplateUsage(ctx context.Context, templateID uuid.UUID) (*AgentUsageResponse, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templates/%s/usage", templateID), nil)
+	if err != nil {
+		return nil, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, readBodyAsError(res)
+	}
+
+	var resp AgentUsageResponse
+	return &resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
 // AgentStatsReportRequest is a WebSocket request by coderd
 // to the agent for stats.
 // @typescript-ignore AgentStatsReportRequest
@@ -174,4 +287,9 @@ type AgentStatsReportResponse struct {
 	RxBytes int64 `json:"rx_bytes"`
 	// TxBytes is the number of received bytes.
 	TxBytes int64 `json:"tx_bytes"`
+	// SessionCount is the number of sessions opened, by type.
+	SessionCount map[string]int64 `json:"session_count"`
+	// SessionSeconds is the time spent in sessions, including sessions
+	// that are still open.
+	SessionSeconds float64 `json:"session_seconds"`
 }
@@ -22,6 +22,11 @@ type TemplateVersion struct {
 	Readme         string         `json:"readme"`
 	CreatedByID    uuid.UUID      `json:"created_by_id"`
 	CreatedByName  string         `json:"created_by_name"`
+	// Message describes the changes of the version, e.g. release notes.
+	Message string `json:"message"`
+	// Archived versions are hidden from version lists and can't be used by
+	// new workspace builds.
+	Archived bool `json:"archived"`
 }
 
 // TemplateVersion returns a template version by ID.
@@ -51,6 +56,29 @@ func (c *Client) CancelTemplateVersion(ctx context.Context, version uuid.UUID) e
 	return nil
 }
 
+// ArchiveTemplateVersion hides a template version and prevents new
+// workspace builds from using it. The active version can't be archived.
+func (c *Client) ArchiveTemplateVersion(ctx context.Context, version uuid.UUID) error {
+	return c.setTemplateVersionArchived(ctx, version, "archive")
+}
+
+// UnarchiveTemplateVersion restores an archived template version.
+func (c *Client) UnarchiveTemplateVersion(ctx context.Context, version uuid.UUID) error {
+	return c.setTemplateVersionArchived(ctx, version, "unarchive")
+}
+
+func (c *Client) setTemplateVersionArchived(ctx context.Context, version uuid.UUID, action string) error {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/templateversions/%s/%s", version, action), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return readBodyAsError(res)
+	}
+	return nil
+}
+
 // TemplateVersionSchema returns schemas for a template version by ID.
 func (c *Client) TemplateVersionSchema(ctx context.Context, version uuid.UUID) ([]ParameterSchema, error) {
 	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templateversions/%s/schema", version), nil)
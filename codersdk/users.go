@@ -28,6 +28,26 @@ const (
 	LoginTypePassword LoginType = "password"
 	LoginTypeGithub   LoginType = "github"
 	LoginTypeOIDC     LoginType = "oidc"
+	LoginTypeToken    LoginType = "token"
+)
+
+// APIKeyScope restricts what an API key can be used for, in addition to the
+// roles of its user.
+type APIKeyScope string
+
+const (
+	APIKeyScopeAll                APIKeyScope = "all"
+	APIKeyScopeApplicationConnect APIKeyScope = "application_connect"
+	// APIKeyScopeReadOnly allows reading every resource the user can read.
+	APIKeyScopeReadOnly APIKeyScope = "read_only"
+	// APIKeyScopeWorkspaceLifecycle allows creating, starting, stopping and
+	// deleting workspaces.
+	APIKeyScopeWorkspaceLifecycle APIKeyScope = "workspace_lifecycle"
+	// APIKeyScopeTemplatePush allows creating templates and pushing versions.
+	APIKeyScopeTemplatePush APIKeyScope = "template_push"
+	// APIKeyScopeAgentConnect allows connecting to workspaces and their
+	// applications.
+	APIKeyScopeAgentConnect APIKeyScope = "agent_connect"
 )
 
 type UsersRequest struct {
@@ -62,6 +82,9 @@ type APIKey struct {
 	UpdatedAt       time.Time `json:"updated_at" validate:"required"`
 	LoginType       LoginType `json:"login_type" validate:"required"`
 	LifetimeSeconds int64     `json:"lifetime_seconds" validate:"required"`
+	// TokenName is only set for long-lived tokens created by the user.
+	TokenName string      `json:"token_name"`
+	Scope     APIKeyScope `json:"scope"`
 }
 
 type CreateFirstUserRequest struct {
@@ -168,6 +191,16 @@ type GenerateAPIKeyResponse struct {
 	Key string `json:"key"`
 }
 
+// CreateTokenRequest creates a long-lived token for a user, e.g. for a CI
+// pipeline.
+type CreateTokenRequest struct {
+	TokenName string `json:"token_name" validate:"required,token_name"`
+	// LifetimeSeconds defaults to 30 days if unset.
+	LifetimeSeconds int64 `json:"lifetime_seconds,omitempty"`
+	// Scope defaults to "all" if unset.
+	Scope APIKeyScope `json:"scope,omitempty" validate:"omitempty,oneof=all application_connect read_only workspace_lifecycle template_push agent_connect"`
+}
+
 type CreateOrganizationRequest struct {
 	Name string `json:"name" validate:"required,username"`
 }
@@ -334,6 +367,20 @@ func (c *Client) GetUserRoles(ctx context.Context, user string) (UserRoles, erro
 	return roles, json.NewDecoder(res.Body).Decode(&roles)
 }
 
+// UserUsage returns the daily usage of the user's workspaces.
+func (c *Client) UserUsage(ctx context.Context, user string) (*AgentUsageResponse, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/users/%s/usage", user), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, readBodyAsError(res)
+	}
+	var resp AgentUsageResponse
+	return &resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
 // CreateAPIKey generates an API key for the user ID provided.
 func (c *Client) CreateAPIKey(ctx context.Context, user string) (*GenerateAPIKeyResponse, error) {
 	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/users/%s/keys", user), nil)
@@ -361,6 +408,47 @@ func (c *Client) GetAPIKey(ctx context.Context, user string, id string) (*APIKey
 	return apiKey, json.NewDecoder(res.Body).Decode(apiKey)
 }
 
+// CreateToken generates a long-lived token for the user provided.
+func (c *Client) CreateToken(ctx context.Context, user string, req CreateTokenRequest) (*GenerateAPIKeyResponse, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/users/%s/keys/tokens", user), req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return nil, readBodyAsError(res)
+	}
+	apiKey := &GenerateAPIKeyResponse{}
+	return apiKey, json.NewDecoder(res.Body).Decode(apiKey)
+}
+
+// Tokens returns the long-lived tokens of the user provided.
+func (c *Client) Tokens(ctx context.Context, user string) ([]APIKey, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/users/%s/keys/tokens", user), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, readBodyAsError(res)
+	}
+	var apiKeys []APIKey
+	return apiKeys, json.NewDecoder(res.Body).Decode(&apiKeys)
+}
+
+// DeleteAPIKey revokes an API key of the user provided.
+func (c *Client) DeleteAPIKey(ctx context.Context, user string, id string) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/users/%s/keys/%s", user, id), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return readBodyAsError(res)
+	}
+	return nil
+}
+
 // LoginWithPassword creates a session token authenticating with an email and password.
 // Call `SetSessionToken()` to apply the newly acquired token to the client.
 func (c *Client) LoginWithPassword(ctx context.Context, req LoginWithPasswordRequest) (LoginWithPasswordResponse, error) {
@@ -0,0 +1,82 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WorkspaceAgentFile describes a file or directory in a workspace.
+type WorkspaceAgentFile struct {
+	Name string `json:"name"`
+	Size int64  `json:"size"`
+	// Mode is the permission bits of the file, e.g. "0644".
+	Mode    string    `json:"mode"`
+	ModTime time.Time `json:"mod_time"`
+	IsDir   bool      `json:"is_dir"`
+}
+
+type WorkspaceAgentListFilesResponse struct {
+	// Path is the absolute path of the directory.
+	Path string `json:"path"`
+	// Files are sorted by name.
+	Files []WorkspaceAgentFile `json:"files"`
+}
+
+// WorkspaceAgentListFiles returns the files in a directory of the workspace.
+// Relative paths are relative to the home directory of the agent user.
+func (c *Client) WorkspaceAgentListFiles(ctx context.Context, agentID uuid.UUID, path string) (WorkspaceAgentListFilesResponse, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaceagents/%s/files?%s", agentID, url.Values{"path": {path}}.Encode()), nil)
+	if err != nil {
+		return WorkspaceAgentListFilesResponse{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceAgentListFilesResponse{}, readBodyAsError(res)
+	}
+	var resp WorkspaceAgentListFilesResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// WorkspaceAgentReadFile returns the contents of a file in the workspace. The
+// caller must close the reader.
+func (c *Client) WorkspaceAgentReadFile(ctx context.Context, agentID uuid.UUID, path string) (io.ReadCloser, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaceagents/%s/files/read?%s", agentID, url.Values{"path": {path}}.Encode()), nil)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
+		return nil, readBodyAsError(res)
+	}
+	return res.Body, nil
+}
+
+// WorkspaceAgentWriteFile creates or replaces a file in the workspace. Parent
+// directories are created if they don't exist.
+func (c *Client) WorkspaceAgentWriteFile(ctx context.Context, agentID uuid.UUID, path string, mode fs.FileMode, content []byte) (WorkspaceAgentFile, error) {
+	query := url.Values{
+		"path": {path},
+		"mode": {strconv.FormatUint(uint64(mode.Perm()), 8)},
+	}
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspaceagents/%s/files/write?%s", agentID, query.Encode()), content, func(r *http.Request) {
+		r.Header.Set("Content-Type", "application/octet-stream")
+	})
+	if err != nil {
+		return WorkspaceAgentFile{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceAgentFile{}, readBodyAsError(res)
+	}
+	var file WorkspaceAgentFile
+	return file, json.NewDecoder(res.Body).Decode(&file)
+}
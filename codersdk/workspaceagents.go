@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"net/http/cookiejar"
 	"net/netip"
+	"net/url"
 	"time"
 
 	"cloud.google.com/go/compute/metadata"
@@ -56,6 +57,60 @@ type PostWorkspaceAgentVersionRequest struct {
 	Version string `json:"version"`
 }
 
+// PostWorkspaceAgentHealthRequest replaces the health warnings of an agent.
+type PostWorkspaceAgentHealthRequest struct {
+	Warnings []string `json:"warnings"`
+}
+
+type PostWorkspaceAgentLifecycleRequest struct {
+	State WorkspaceAgentLifecycle `json:"state"`
+}
+
+type PatchWorkspaceAgentStartupLogs struct {
+	Logs []StartupLog `json:"logs"`
+}
+
+// StartupLog is a line of startup script output sent by the agent.
+type StartupLog struct {
+	CreatedAt time.Time `json:"created_at"`
+	Output    string    `json:"output"`
+}
+
+// WorkspaceAgentStartupLog is a line of startup script output stored by
+// coderd.
+type WorkspaceAgentStartupLog struct {
+	ID        int64     `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	Output    string    `json:"output"`
+}
+
+// WorkspaceAgentMetadataDescription describes a script declared on the agent
+// that is run periodically to collect a value.
+type WorkspaceAgentMetadataDescription struct {
+	DisplayName string `json:"display_name"`
+	Key         string `json:"key"`
+	Script      string `json:"script"`
+	// Interval is the number of seconds between runs. If zero, the script
+	// only runs once.
+	Interval int64 `json:"interval"`
+	// Timeout is the number of seconds the script may run for.
+	Timeout int64 `json:"timeout"`
+}
+
+type WorkspaceAgentMetadataResult struct {
+	CollectedAt time.Time `json:"collected_at"`
+	// Age is the number of seconds since the value was collected.
+	Age   int64  `json:"age"`
+	Value string `json:"value"`
+	Error string `json:"error"`
+}
+
+// WorkspaceAgentMetadata is the latest value collected by a metadata script.
+type WorkspaceAgentMetadata struct {
+	Description WorkspaceAgentMetadataDescription `json:"description"`
+	Result      WorkspaceAgentMetadataResult      `json:"result"`
+}
+
 // AuthWorkspaceGoogleInstanceIdentity uses the Google Compute Engine Metadata API to
 // fetch a signed JWT, and exchange it for a session token for a workspace agent.
 //
@@ -328,6 +383,36 @@ func (c *Client) WorkspaceAgent(ctx context.Context, id uuid.UUID) (WorkspaceAge
 	return workspaceAgent, json.NewDecoder(res.Body).Decode(&workspaceAgent)
 }
 
+// WorkspaceAgentListeningPort is a TCP port that a process in the workspace
+// listens on.
+type WorkspaceAgentListeningPort struct {
+	ProcessName string `json:"process_name"`
+	Network     string `json:"network"`
+	Port        uint16 `json:"port"`
+}
+
+type WorkspaceAgentListeningPortsResponse struct {
+	// Ports are sorted by number. Ports the agent listens on itself are
+	// excluded.
+	Ports []WorkspaceAgentListeningPort `json:"ports"`
+}
+
+// WorkspaceAgentListeningPorts returns the TCP ports that processes in the
+// workspace listen on. Listing ports is only supported on Linux; other
+// agents return no ports.
+func (c *Client) WorkspaceAgentListeningPorts(ctx context.Context, agentID uuid.UUID) (WorkspaceAgentListeningPortsResponse, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaceagents/%s/listening-ports", agentID), nil)
+	if err != nil {
+		return WorkspaceAgentListeningPortsResponse{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceAgentListeningPortsResponse{}, readBodyAsError(res)
+	}
+	var resp WorkspaceAgentListeningPortsResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
 func (c *Client) PostWorkspaceAgentVersion(ctx context.Context, version string) error {
 	// Phone home and tell the mothership what version we're on.
 	versionReq := PostWorkspaceAgentVersionRequest{Version: version}
@@ -341,6 +426,218 @@ func (c *Client) PostWorkspaceAgentVersion(ctx context.Context, version string)
 	return nil
 }
 
+// PostWorkspaceAgentLifecycle reports the lifecycle state of the agent.
+func (c *Client) PostWorkspaceAgentLifecycle(ctx context.Context, state agent.LifecycleState) error {
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/workspaceagents/me/report-lifecycle", PostWorkspaceAgentLifecycleRequest{
+		State: WorkspaceAgentLifecycle(state),
+	})
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return readBodyAsError(res)
+	}
+	return nil
+}
+
+// PatchWorkspaceAgentStartupLogs appends output of the startup script.
+func (c *Client) PatchWorkspaceAgentStartupLogs(ctx context.Context, logs []agent.StartupLog) error {
+	req := PatchWorkspaceAgentStartupLogs{
+		Logs: make([]StartupLog, 0, len(logs)),
+	}
+	for _, log := range logs {
+		req.Logs = append(req.Logs, StartupLog{
+			CreatedAt: log.CreatedAt,
+			Output:    log.Output,
+		})
+	}
+	res, err := c.Request(ctx, http.MethodPatch, "/api/v2/workspaceagents/me/startup-logs", req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return readBodyAsError(res)
+	}
+	return nil
+}
+
+// WorkspaceAgentAwaitShutdown blocks until coderd requests the agent to shut
+// down, e.g. before its workspace is stopped.
+func (c *Client) WorkspaceAgentAwaitShutdown(ctx context.Context) error {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/workspaceagents/me/await-shutdown", nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return readBodyAsError(res)
+	}
+	return nil
+}
+
+// PostWorkspaceAgentMetadata reports the result of the metadata script
+// with the given key.
+func (c *Client) PostWorkspaceAgentMetadata(ctx context.Context, key string, result agent.MetadataResult) error {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspaceagents/me/metadata/%s", url.PathEscape(key)), WorkspaceAgentMetadataResult{
+		CollectedAt: result.CollectedAt,
+		Value:       result.Value,
+		Error:       result.Error,
+	})
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return readBodyAsError(res)
+	}
+	return nil
+}
+
+// PostWorkspaceAgentAppHealth reports the health of workspace applications.
+func (c *Client) PostWorkspaceAgentAppHealth(ctx context.Context, healths map[uuid.UUID]agent.AppHealth) error {
+	req := PostWorkspaceAppHealthsRequest{
+		Healths: make(map[uuid.UUID]WorkspaceAppHealth, len(healths)),
+	}
+	for id, health := range healths {
+		req.Healths[id] = WorkspaceAppHealth(health)
+	}
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/workspaceagents/me/app-health", req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return readBodyAsError(res)
+	}
+	return nil
+}
+
+// PostWorkspaceAgentHealth reports problems the agent encountered, e.g.
+// session limits that could not be applied. Previous warnings are replaced.
+func (c *Client) PostWorkspaceAgentHealth(ctx context.Context, warnings []string) error {
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/workspaceagents/me/health", PostWorkspaceAgentHealthRequest{
+		Warnings: warnings,
+	})
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return readBodyAsError(res)
+	}
+	return nil
+}
+
+// WatchWorkspaceAgentMetadata streams the latest metadata of an agent. The
+// channel receives every value whenever any of them changes, and is closed
+// when the context is canceled or the connection is lost.
+func (c *Client) WatchWorkspaceAgentMetadata(ctx context.Context, agentID uuid.UUID) (<-chan []WorkspaceAgentMetadata, error) {
+	//nolint:bodyclose
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaceagents/%s/watch-metadata", agentID), nil)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, readBodyAsError(res)
+	}
+	nextEvent := ServerSentEventReader(res.Body)
+
+	metadata := make(chan []WorkspaceAgentMetadata, 256)
+	go func() {
+		defer close(metadata)
+		defer res.Body.Close()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			default:
+				sse, err := nextEvent()
+				if err != nil {
+					return
+				}
+				if sse.Type != ServerSentEventTypeData {
+					continue
+				}
+				b, ok := sse.Data.([]byte)
+				if !ok {
+					return
+				}
+				var md []WorkspaceAgentMetadata
+				err = json.Unmarshal(b, &md)
+				if err != nil {
+					return
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case metadata <- md:
+				}
+			}
+		}
+	}()
+
+	return metadata, nil
+}
+
+// WorkspaceAgentStartupLogsAfter streams startup script output with an ID
+// greater than after. The channel is closed once the agent finishes
+// starting and all output has been sent.
+func (c *Client) WorkspaceAgentStartupLogsAfter(ctx context.Context, agentID uuid.UUID, after int64) (<-chan WorkspaceAgentStartupLog, io.Closer, error) {
+	followURL, err := c.URL.Parse(fmt.Sprintf("/api/v2/workspaceagents/%s/startup-logs?follow&after=%d", agentID, after))
+	if err != nil {
+		return nil, nil, err
+	}
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, nil, xerrors.Errorf("create cookie jar: %w", err)
+	}
+	jar.SetCookies(followURL, []*http.Cookie{{
+		Name:  SessionTokenKey,
+		Value: c.SessionToken,
+	}})
+	httpClient := &http.Client{
+		Jar: jar,
+	}
+	conn, res, err := websocket.Dial(ctx, followURL.String(), &websocket.DialOptions{
+		HTTPClient:      httpClient,
+		CompressionMode: websocket.CompressionDisabled,
+	})
+	if err != nil {
+		if res == nil {
+			return nil, nil, err
+		}
+		return nil, nil, readBodyAsError(res)
+	}
+	logs := make(chan WorkspaceAgentStartupLog)
+	closed := make(chan struct{})
+	decoder := json.NewDecoder(websocket.NetConn(ctx, conn, websocket.MessageText))
+	go func() {
+		defer close(closed)
+		defer close(logs)
+		defer conn.Close(websocket.StatusGoingAway, "")
+		var log WorkspaceAgentStartupLog
+		for {
+			err = decoder.Decode(&log)
+			if err != nil {
+				return
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case logs <- log:
+			}
+		}
+	}()
+	return logs, closeFunc(func() error {
+		_ = conn.Close(websocket.StatusGoingAway, "")
+		<-closed
+		return nil
+	}), nil
+}
+
 // WorkspaceAgentReconnectingPTY spawns a PTY that reconnects using the token provided.
 // It communicates using `agent.ReconnectingPTYRequest` marshaled as JSON.
 // Responses are PTY output that can be rendered.
@@ -432,10 +729,16 @@ func (c *Client) AgentReportStats(
 
 					s := stats()
 
+					sessionCount := make(map[string]int64, len(s.SessionCount))
+					for sessionType, count := range s.SessionCount {
+						sessionCount[string(sessionType)] = count
+					}
 					resp := AgentStatsReportResponse{
-						NumConns: s.NumConns,
-						RxBytes:  s.RxBytes,
-						TxBytes:  s.TxBytes,
+						NumConns:       s.NumConns,
+						RxBytes:        s.RxBytes,
+						TxBytes:        s.TxBytes,
+						SessionCount:   sessionCount,
+						SessionSeconds: s.SessionSeconds,
 					}
 
 					err = wsjson.Write(ctx, conn, resp)
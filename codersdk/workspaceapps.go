@@ -4,6 +4,15 @@ import (
 	"github.com/google/uuid"
 )
 
+type WorkspaceAppHealth string
+
+const (
+	WorkspaceAppHealthDisabled     WorkspaceAppHealth = "disabled"
+	WorkspaceAppHealthInitializing WorkspaceAppHealth = "initializing"
+	WorkspaceAppHealthHealthy      WorkspaceAppHealth = "healthy"
+	WorkspaceAppHealthUnhealthy    WorkspaceAppHealth = "unhealthy"
+)
+
 type WorkspaceApp struct {
 	ID uuid.UUID `json:"id"`
 	// Name is a unique identifier attached to an agent.
@@ -12,4 +21,23 @@ type WorkspaceApp struct {
 	// Icon is a relative path or external URL that specifies
 	// an icon to be displayed in the dashboard.
 	Icon string `json:"icon,omitempty"`
+	// Healthcheck specifies the configuration for checking app health.
+	Healthcheck Healthcheck        `json:"healthcheck"`
+	Health      WorkspaceAppHealth `json:"health"`
+}
+
+type Healthcheck struct {
+	// URL specifies the url to check for the app health.
+	URL string `json:"url"`
+	// Interval specifies the seconds between each health check.
+	Interval int32 `json:"interval"`
+	// Threshold specifies the number of consecutive failed health checks
+	// before returning "unhealthy".
+	Threshold int32 `json:"threshold"`
+}
+
+// PostWorkspaceAppHealthsRequest is a request to update the health of
+// workspace applications, keyed by application ID.
+type PostWorkspaceAppHealthsRequest struct {
+	Healths map[uuid.UUID]WorkspaceAppHealth `json:"healths"`
 }
@@ -52,6 +52,7 @@ type WorkspaceBuild struct {
 	Reason             BuildReason         `db:"reason" json:"reason"`
 	Resources          []WorkspaceResource `json:"resources"`
 	Deadline           NullTime            `json:"deadline,omitempty"`
+	DailyCost          int32               `json:"daily_cost"`
 }
 
 // WorkspaceBuild returns a single workspace build for a workspace.
@@ -0,0 +1,89 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// WorkspacePortShareLevel determines who can access a port of a workspace
+// agent through the application proxy.
+type WorkspacePortShareLevel string
+
+const (
+	// WorkspacePortShareLevelOwner only allows the workspace owner, and users
+	// permitted to connect to the owner's applications.
+	WorkspacePortShareLevelOwner WorkspacePortShareLevel = "owner"
+	// WorkspacePortShareLevelAuthenticated allows any signed in user.
+	WorkspacePortShareLevelAuthenticated WorkspacePortShareLevel = "authenticated"
+	// WorkspacePortShareLevelPublic allows anyone, without signing in.
+	WorkspacePortShareLevelPublic WorkspacePortShareLevel = "public"
+)
+
+// WorkspacePortShare is the share level of a port of a workspace agent.
+type WorkspacePortShare struct {
+	WorkspaceID uuid.UUID               `json:"workspace_id"`
+	AgentName   string                  `json:"agent_name"`
+	Port        int32                   `json:"port"`
+	ShareLevel  WorkspacePortShareLevel `json:"share_level"`
+	// URL is the subdomain application URL the port is served from. It's
+	// empty if the deployment doesn't have a wildcard access URL.
+	URL string `json:"url"`
+}
+
+type UpsertWorkspacePortShareRequest struct {
+	AgentName  string                  `json:"agent_name" validate:"required"`
+	Port       int32                   `json:"port" validate:"required,min=1,max=65535"`
+	ShareLevel WorkspacePortShareLevel `json:"share_level" validate:"oneof=owner authenticated public,required"`
+}
+
+type DeleteWorkspacePortShareRequest struct {
+	AgentName string `json:"agent_name" validate:"required"`
+	Port      int32  `json:"port" validate:"required,min=1,max=65535"`
+}
+
+// WorkspacePortShares returns the shared ports of a workspace.
+func (c *Client) WorkspacePortShares(ctx context.Context, workspaceID uuid.UUID) ([]WorkspacePortShare, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaces/%s/port-share", workspaceID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, readBodyAsError(res)
+	}
+	var shares []WorkspacePortShare
+	return shares, json.NewDecoder(res.Body).Decode(&shares)
+}
+
+// UpsertWorkspacePortShare sets the share level of a port of a workspace
+// agent.
+func (c *Client) UpsertWorkspacePortShare(ctx context.Context, workspaceID uuid.UUID, req UpsertWorkspacePortShareRequest) (WorkspacePortShare, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspaces/%s/port-share", workspaceID), req)
+	if err != nil {
+		return WorkspacePortShare{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspacePortShare{}, readBodyAsError(res)
+	}
+	var share WorkspacePortShare
+	return share, json.NewDecoder(res.Body).Decode(&share)
+}
+
+// DeleteWorkspacePortShare stops sharing a port of a workspace agent. The
+// port is only accessible by the workspace owner afterwards.
+func (c *Client) DeleteWorkspacePortShare(ctx context.Context, workspaceID uuid.UUID, req DeleteWorkspacePortShareRequest) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/workspaces/%s/port-share", workspaceID), req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return readBodyAsError(res)
+	}
+	return nil
+}
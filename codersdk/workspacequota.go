@@ -0,0 +1,53 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// WorkspaceQuota is a user's daily workspace credit usage. Each running
+// workspace consumes the daily cost declared by its template, and users are
+// granted a budget through their own quota allowance and those of their
+// groups.
+type WorkspaceQuota struct {
+	CreditsConsumed int `json:"credits_consumed"`
+	Budget          int `json:"budget"`
+}
+
+// UpdateWorkspaceQuotaRequest sets the quota allowance granted to a user
+// directly, in addition to the allowances of their groups.
+type UpdateWorkspaceQuotaRequest struct {
+	QuotaAllowance int `json:"quota_allowance"`
+}
+
+// WorkspaceQuota returns the quota usage of the user provided.
+// The user can be a username, user ID, or "me".
+func (c *Client) WorkspaceQuota(ctx context.Context, user string) (WorkspaceQuota, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspace-quota/%s", user), nil)
+	if err != nil {
+		return WorkspaceQuota{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceQuota{}, readBodyAsError(res)
+	}
+	var quota WorkspaceQuota
+	return quota, json.NewDecoder(res.Body).Decode(&quota)
+}
+
+// UpdateWorkspaceQuota sets the quota allowance of the user provided and
+// returns their updated quota usage.
+func (c *Client) UpdateWorkspaceQuota(ctx context.Context, user string, req UpdateWorkspaceQuotaRequest) (WorkspaceQuota, error) {
+	res, err := c.Request(ctx, http.MethodPatch, fmt.Sprintf("/api/v2/workspace-quota/%s", user), req)
+	if err != nil {
+		return WorkspaceQuota{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceQuota{}, readBodyAsError(res)
+	}
+	var quota WorkspaceQuota
+	return quota, json.NewDecoder(res.Body).Decode(&quota)
+}
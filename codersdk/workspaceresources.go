@@ -18,6 +18,28 @@ const (
 	WorkspaceAgentDisconnected WorkspaceAgentStatus = "disconnected"
 )
 
+// WorkspaceAgentLifecycle represents the progress of the agent's startup
+// script.
+type WorkspaceAgentLifecycle string
+
+const (
+	WorkspaceAgentLifecycleCreated         WorkspaceAgentLifecycle = "created"
+	WorkspaceAgentLifecycleStarting        WorkspaceAgentLifecycle = "starting"
+	WorkspaceAgentLifecycleStartTimeout    WorkspaceAgentLifecycle = "start_timeout"
+	WorkspaceAgentLifecycleStartError      WorkspaceAgentLifecycle = "start_error"
+	WorkspaceAgentLifecycleReady           WorkspaceAgentLifecycle = "ready"
+	WorkspaceAgentLifecycleShuttingDown    WorkspaceAgentLifecycle = "shutting_down"
+	WorkspaceAgentLifecycleShutdownTimeout WorkspaceAgentLifecycle = "shutdown_timeout"
+	WorkspaceAgentLifecycleShutdownError   WorkspaceAgentLifecycle = "shutdown_error"
+	WorkspaceAgentLifecycleOff             WorkspaceAgentLifecycle = "off"
+)
+
+// Starting returns true if the agent hasn't finished running its startup
+// script.
+func (l WorkspaceAgentLifecycle) Starting() bool {
+	return l == WorkspaceAgentLifecycleCreated || l == WorkspaceAgentLifecycleStarting
+}
+
 type WorkspaceResource struct {
 	ID         uuid.UUID                   `json:"id"`
 	CreatedAt  time.Time                   `json:"created_at"`
@@ -29,6 +51,7 @@ type WorkspaceResource struct {
 	Icon       string                      `json:"icon"`
 	Agents     []WorkspaceAgent            `json:"agents,omitempty"`
 	Metadata   []WorkspaceResourceMetadata `json:"metadata,omitempty"`
+	DailyCost  int32                       `json:"daily_cost"`
 }
 
 type WorkspaceResourceMetadata struct {
@@ -60,6 +83,21 @@ type WorkspaceAgent struct {
 	Directory            string               `json:"directory,omitempty"`
 	Version              string               `json:"version"`
 	Apps                 []WorkspaceApp       `json:"apps"`
+	// LifecycleState is reported by the agent as its startup script runs.
+	LifecycleState WorkspaceAgentLifecycle `json:"lifecycle_state"`
+	// StartupScriptTimeoutSeconds is zero if the script can run forever.
+	StartupScriptTimeoutSeconds int32  `json:"startup_script_timeout_seconds"`
+	StartupLogsLength           int32  `json:"startup_logs_length"`
+	StartupLogsOverflowed       bool   `json:"startup_logs_overflowed"`
+	ShutdownScript              string `json:"shutdown_script,omitempty"`
+	// ShutdownScriptTimeoutSeconds is zero if the script can run forever.
+	ShutdownScriptTimeoutSeconds int32 `json:"shutdown_script_timeout_seconds"`
+	// Metadata contains the latest values collected by the agent's
+	// metadata scripts.
+	Metadata []WorkspaceAgentMetadata `json:"metadata,omitempty"`
+	// HealthWarnings are problems reported by the agent, e.g. session limits
+	// that could not be applied.
+	HealthWarnings []string `json:"health_warnings"`
 	// DERPLatency is mapped by region name (e.g. "New York City", "Seattle").
 	DERPLatency map[string]DERPRegion `json:"latency,omitempty"`
 }
@@ -15,22 +15,32 @@ import (
 // Workspace is a deployment of a template. It references a specific
 // version and can be updated.
 type Workspace struct {
-	ID                uuid.UUID      `json:"id"`
-	CreatedAt         time.Time      `json:"created_at"`
-	UpdatedAt         time.Time      `json:"updated_at"`
-	OwnerID           uuid.UUID      `json:"owner_id"`
-	OwnerName         string         `json:"owner_name"`
-	TemplateID        uuid.UUID      `json:"template_id"`
-	TemplateName      string         `json:"template_name"`
-	TemplateIcon      string         `json:"template_icon"`
-	LatestBuild       WorkspaceBuild `json:"latest_build"`
-	Outdated          bool           `json:"outdated"`
-	Name              string         `json:"name"`
-	AutostartSchedule *string        `json:"autostart_schedule,omitempty"`
-	TTLMillis         *int64         `json:"ttl_ms,omitempty"`
-	LastUsedAt        time.Time      `json:"last_used_at"`
+	ID                uuid.UUID        `json:"id"`
+	CreatedAt         time.Time        `json:"created_at"`
+	UpdatedAt         time.Time        `json:"updated_at"`
+	OwnerID           uuid.UUID        `json:"owner_id"`
+	OwnerName         string           `json:"owner_name"`
+	TemplateID        uuid.UUID        `json:"template_id"`
+	TemplateName      string           `json:"template_name"`
+	TemplateIcon      string           `json:"template_icon"`
+	LatestBuild       WorkspaceBuild   `json:"latest_build"`
+	Outdated          bool             `json:"outdated"`
+	Name              string           `json:"name"`
+	AutostartSchedule *string          `json:"autostart_schedule,omitempty"`
+	TTLMillis         *int64           `json:"ttl_ms,omitempty"`
+	LastUsedAt        time.Time        `json:"last_used_at"`
+	AutomaticUpdates  AutomaticUpdates `json:"automatic_updates"`
 }
 
+// AutomaticUpdates determines whether a workspace is updated to the active
+// template version when it's started.
+type AutomaticUpdates string
+
+const (
+	AutomaticUpdatesAlways AutomaticUpdates = "always"
+	AutomaticUpdatesNever  AutomaticUpdates = "never"
+)
+
 // CreateWorkspaceBuildRequest provides options to update the latest workspace build.
 type CreateWorkspaceBuildRequest struct {
 	TemplateVersionID uuid.UUID           `json:"template_version_id,omitempty"`
@@ -120,6 +130,68 @@ func (c *Client) CreateWorkspaceBuild(ctx context.Context, workspace uuid.UUID,
 	return workspaceBuild, json.NewDecoder(res.Body).Decode(&workspaceBuild)
 }
 
+// BulkWorkspaceBuildRequest creates a build for every workspace matching a
+// search query.
+type BulkWorkspaceBuildRequest struct {
+	// Query uses the same syntax as the workspaces list, e.g.
+	// "owner:bob template:legacy". An empty query matches every workspace the
+	// user can read.
+	Query string `json:"q"`
+	// Transition is applied to every matching workspace. Workspaces with a
+	// build in progress, and workspaces that are already stopped when
+	// stopping, are skipped.
+	Transition WorkspaceTransition `json:"transition" validate:"oneof=start stop delete,required"`
+	// ActiveVersion starts workspaces with the active version of their
+	// template. Workspaces already on the active version or that aren't
+	// running are skipped, and workspaces missing values for parameters of
+	// the active version fail.
+	ActiveVersion bool `json:"active_version,omitempty"`
+	// IncludeStopped also starts workspaces that aren't running with the
+	// active version. Requires ActiveVersion.
+	IncludeStopped bool `json:"include_stopped,omitempty"`
+	// DryRun reports the workspaces that would be built without building them.
+	DryRun bool `json:"dry_run,omitempty"`
+	// WorkspaceIDs restricts the build to these workspaces, e.g. the ones a
+	// dry run reported. Workspaces that no longer match the query are left
+	// out.
+	WorkspaceIDs []uuid.UUID `json:"workspace_ids,omitempty"`
+}
+
+// BulkWorkspaceBuildResult is the outcome for a single workspace of a bulk
+// build.
+type BulkWorkspaceBuildResult struct {
+	WorkspaceID   uuid.UUID `json:"workspace_id"`
+	WorkspaceName string    `json:"workspace_name"`
+	OwnerName     string    `json:"owner_name"`
+	// BuildID is unset for dry runs and workspaces that weren't built.
+	BuildID *uuid.UUID `json:"build_id,omitempty"`
+	// Skipped is why no build was needed for the workspace.
+	Skipped string `json:"skipped,omitempty"`
+	// Error is why the build couldn't be created.
+	Error string `json:"error,omitempty"`
+}
+
+type BulkWorkspaceBuildResponse struct {
+	DryRun  bool                       `json:"dry_run"`
+	Results []BulkWorkspaceBuildResult `json:"results"`
+}
+
+// BulkWorkspaceBuild creates builds for all workspaces matching the query.
+// Failing to build one workspace doesn't fail the request, check the result
+// of each workspace instead.
+func (c *Client) BulkWorkspaceBuild(ctx context.Context, request BulkWorkspaceBuildRequest) (BulkWorkspaceBuildResponse, error) {
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/workspaces/builds", request)
+	if err != nil {
+		return BulkWorkspaceBuildResponse{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return BulkWorkspaceBuildResponse{}, readBodyAsError(res)
+	}
+	var resp BulkWorkspaceBuildResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
 func (c *Client) WatchWorkspace(ctx context.Context, id uuid.UUID) (<-chan Workspace, error) {
 	//nolint:bodyclose
 	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaces/%s/watch", id), nil)
@@ -221,6 +293,27 @@ func (c *Client) UpdateWorkspaceTTL(ctx context.Context, id uuid.UUID, req Updat
 	return nil
 }
 
+// UpdateWorkspaceAutomaticUpdatesRequest is a request to update whether a
+// workspace is updated to the active template version when it's started.
+type UpdateWorkspaceAutomaticUpdatesRequest struct {
+	AutomaticUpdates AutomaticUpdates `json:"automatic_updates" validate:"required,oneof=always never"`
+}
+
+// UpdateWorkspaceAutomaticUpdates sets the automatic updates policy for a
+// workspace by id.
+func (c *Client) UpdateWorkspaceAutomaticUpdates(ctx context.Context, id uuid.UUID, req UpdateWorkspaceAutomaticUpdatesRequest) error {
+	path := fmt.Sprintf("/api/v2/workspaces/%s/autoupdates", id.String())
+	res, err := c.Request(ctx, http.MethodPut, path, req)
+	if err != nil {
+		return xerrors.Errorf("update workspace automatic updates: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return readBodyAsError(res)
+	}
+	return nil
+}
+
 // PutExtendWorkspaceRequest is a request to extend the deadline of
 // the active workspace build.
 type PutExtendWorkspaceRequest struct {
@@ -0,0 +1,64 @@
+package backends
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/coderd/database"
+)
+
+// exportedLog is the JSON representation of an audit log sent to
+// external backends. It differs from database.AuditLog by encoding the
+// IP as a plain string.
+type exportedLog struct {
+	ID               uuid.UUID       `json:"id"`
+	Time             time.Time       `json:"time"`
+	UserID           uuid.UUID       `json:"user_id"`
+	OrganizationID   uuid.UUID       `json:"organization_id"`
+	IP               string          `json:"ip"`
+	UserAgent        string          `json:"user_agent"`
+	ResourceType     string          `json:"resource_type"`
+	ResourceID       uuid.UUID       `json:"resource_id"`
+	ResourceTarget   string          `json:"resource_target"`
+	ResourceIcon     string          `json:"resource_icon"`
+	Action           string          `json:"action"`
+	Diff             json.RawMessage `json:"diff"`
+	StatusCode       int32           `json:"status_code"`
+	AdditionalFields json.RawMessage `json:"additional_fields"`
+	RequestID        uuid.UUID       `json:"request_id"`
+}
+
+func convertLog(alog database.AuditLog) exportedLog {
+	ip := ""
+	if alog.Ip.Valid {
+		ip = alog.Ip.IPNet.IP.String()
+	}
+	return exportedLog{
+		ID:               alog.ID,
+		Time:             alog.Time,
+		UserID:           alog.UserID,
+		OrganizationID:   alog.OrganizationID,
+		IP:               ip,
+		UserAgent:        alog.UserAgent,
+		ResourceType:     string(alog.ResourceType),
+		ResourceID:       alog.ResourceID,
+		ResourceTarget:   alog.ResourceTarget,
+		ResourceIcon:     alog.ResourceIcon,
+		Action:           string(alog.Action),
+		Diff:             rawOrEmpty(alog.Diff),
+		StatusCode:       alog.StatusCode,
+		AdditionalFields: rawOrEmpty(alog.AdditionalFields),
+		RequestID:        alog.RequestID,
+	}
+}
+
+// rawOrEmpty ensures empty raw messages are encoded as an empty object,
+// since a nil json.RawMessage fails to marshal.
+func rawOrEmpty(raw json.RawMessage) json.RawMessage {
+	if len(raw) == 0 {
+		return json.RawMessage("{}")
+	}
+	return raw
+}
@@ -0,0 +1,69 @@
+package backends
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"golang.org/x/xerrors"
+	"gopkg.in/natefinch/lumberjack.v2"
+
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/enterprise/audit"
+)
+
+type FileOptions struct {
+	Path string
+	// MaxSize is the size in megabytes a file can reach before it's
+	// rotated. Defaults to 100.
+	MaxSize int
+	// MaxBackups is the number of rotated files to keep. Zero keeps all
+	// of them.
+	MaxBackups int
+}
+
+// NewFile creates a backend that writes audit logs to a file as JSON
+// lines, rotating it once it grows too large.
+func NewFile(options FileOptions) audit.Backend {
+	if options.MaxSize <= 0 {
+		options.MaxSize = 100
+	}
+	return &fileBackend{
+		writer: &lumberjack.Logger{
+			Filename:   options.Path,
+			MaxSize:    options.MaxSize,
+			MaxBackups: options.MaxBackups,
+		},
+	}
+}
+
+type fileBackend struct {
+	mutex  sync.Mutex
+	writer *lumberjack.Logger
+}
+
+func (*fileBackend) Decision() audit.FilterDecision {
+	return audit.FilterDecisionExport
+}
+
+func (b *fileBackend) Export(_ context.Context, alog database.AuditLog) error {
+	data, err := json.Marshal(convertLog(alog))
+	if err != nil {
+		return xerrors.Errorf("marshal audit log: %w", err)
+	}
+	data = append(data, '\n')
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	_, err = b.writer.Write(data)
+	if err != nil {
+		return xerrors.Errorf("write audit log: %w", err)
+	}
+	return nil
+}
+
+func (b *fileBackend) Close() error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.writer.Close()
+}
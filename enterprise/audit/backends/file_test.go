@@ -0,0 +1,47 @@
+package backends_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/enterprise/audit/audittest"
+	"github.com/coder/coder/enterprise/audit/backends"
+)
+
+func TestFileBackend(t *testing.T) {
+	t.Parallel()
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "audit.log")
+		backend := backends.NewFile(backends.FileOptions{
+			Path: path,
+		})
+
+		first, second := audittest.RandomLog(), audittest.RandomLog()
+		require.NoError(t, backend.Export(context.Background(), first))
+		require.NoError(t, backend.Export(context.Background(), second))
+		require.NoError(t, backend.(interface{ Close() error }).Close())
+
+		file, err := os.Open(path)
+		require.NoError(t, err)
+		defer file.Close()
+		ids := make([]string, 0)
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			var alog struct {
+				ID string `json:"id"`
+			}
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &alog))
+			ids = append(ids, alog.ID)
+		}
+		require.NoError(t, scanner.Err())
+		require.Equal(t, []string{first.ID.String(), second.ID.String()}, ids)
+	})
+}
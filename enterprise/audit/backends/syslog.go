@@ -0,0 +1,262 @@
+package backends
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/enterprise/audit"
+)
+
+const (
+	// syslogFacility is local0, which is conventionally used for
+	// application-defined messages.
+	syslogFacility = 16
+	syslogInfo     = 6
+	syslogWarning  = 4
+)
+
+type SyslogOptions struct {
+	// Network is "udp", "tcp", or "unix".
+	Network string
+	Address string
+	// Hostname is sent in the HOSTNAME field. Defaults to os.Hostname.
+	Hostname string
+	// AppName is sent in the APP-NAME field. Defaults to "coder".
+	AppName string
+	// DialTimeout defaults to 5 seconds.
+	DialTimeout time.Duration
+	// WriteTimeout is how long a message may take to send before the
+	// connection is considered broken. Defaults to 5 seconds.
+	WriteTimeout time.Duration
+	// QueueSize is the number of audit logs that can be buffered while
+	// waiting to be sent. Defaults to 10000.
+	QueueSize int
+}
+
+// ParseSyslogAddress parses an address in the form "udp://host:514",
+// "tcp://host:601", or "unix:///dev/log".
+func ParseSyslogAddress(raw string) (network string, address string, err error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", "", xerrors.Errorf("parse syslog address: %w", err)
+	}
+	switch u.Scheme {
+	case "udp", "tcp":
+		if u.Host == "" {
+			return "", "", xerrors.Errorf("syslog address %q is missing a host", raw)
+		}
+		return u.Scheme, u.Host, nil
+	case "unix":
+		if u.Path == "" {
+			return "", "", xerrors.Errorf("syslog address %q is missing a path", raw)
+		}
+		return u.Scheme, u.Path, nil
+	default:
+		return "", "", xerrors.Errorf("unsupported syslog scheme %q, expected udp, tcp, or unix", u.Scheme)
+	}
+}
+
+// NewSyslog creates a backend that sends each audit log as an RFC 5424
+// message. Messages are sent in the background, so Export never blocks on
+// the network; logs are dropped with an error if the queue is full. The
+// connection is established lazily and re-established if a write fails.
+func NewSyslog(logger slog.Logger, options SyslogOptions) (audit.Backend, error) {
+	switch options.Network {
+	case "udp", "tcp", "unix":
+	default:
+		return nil, xerrors.Errorf("unsupported syslog network %q", options.Network)
+	}
+	if options.Hostname == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil, xerrors.Errorf("get hostname: %w", err)
+		}
+		options.Hostname = hostname
+	}
+	if options.AppName == "" {
+		options.AppName = "coder"
+	}
+	if options.DialTimeout <= 0 {
+		options.DialTimeout = 5 * time.Second
+	}
+	if options.WriteTimeout <= 0 {
+		options.WriteTimeout = 5 * time.Second
+	}
+	if options.QueueSize <= 0 {
+		options.QueueSize = 10000
+	}
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	b := &syslogBackend{
+		logger:     logger,
+		options:    options,
+		pid:        os.Getpid(),
+		queue:      make(chan []byte, options.QueueSize),
+		closed:     make(chan struct{}),
+		done:       make(chan struct{}),
+		ctx:        ctx,
+		cancelFunc: cancelFunc,
+	}
+	go b.loop()
+	return b, nil
+}
+
+type syslogBackend struct {
+	logger  slog.Logger
+	options SyslogOptions
+	pid     int
+
+	queue      chan []byte
+	closed     chan struct{}
+	done       chan struct{}
+	ctx        context.Context
+	cancelFunc context.CancelFunc
+	closeOnce  sync.Once
+
+	// conn is only used by the loop.
+	conn net.Conn
+}
+
+func (*syslogBackend) Decision() audit.FilterDecision {
+	return audit.FilterDecisionExport
+}
+
+func (b *syslogBackend) Export(_ context.Context, alog database.AuditLog) error {
+	msg, err := b.format(alog)
+	if err != nil {
+		return err
+	}
+	select {
+	case <-b.closed:
+		return xerrors.New("syslog backend is closed")
+	default:
+	}
+	select {
+	case b.queue <- msg:
+		return nil
+	default:
+		return xerrors.Errorf("syslog queue is full, dropping audit log %s", alog.ID)
+	}
+}
+
+// Close sends any queued messages and stops the backend.
+func (b *syslogBackend) Close() error {
+	b.closeOnce.Do(func() {
+		close(b.closed)
+		// Give the final messages a bounded amount of time to be sent.
+		timer := time.AfterFunc(10*time.Second, b.cancelFunc)
+		<-b.done
+		timer.Stop()
+		b.cancelFunc()
+	})
+	return nil
+}
+
+func (b *syslogBackend) loop() {
+	defer close(b.done)
+	defer func() {
+		if b.conn != nil {
+			_ = b.conn.Close()
+		}
+	}()
+	for {
+		select {
+		case <-b.closed:
+			// Drain whatever was queued before closing.
+			for {
+				select {
+				case msg := <-b.queue:
+					b.send(msg)
+				default:
+					return
+				}
+			}
+		case msg := <-b.queue:
+			b.send(msg)
+		}
+	}
+}
+
+// send writes a message, retrying once with a fresh connection since the
+// server may have closed the previous one. The message is dropped if it
+// can't be sent.
+func (b *syslogBackend) send(msg []byte) {
+	if b.ctx.Err() != nil {
+		return
+	}
+	var err error
+	for attempt := 0; attempt < 2; attempt++ {
+		if b.conn == nil {
+			dialer := net.Dialer{Timeout: b.options.DialTimeout}
+			b.conn, err = dialer.DialContext(b.ctx, b.options.Network, b.options.Address)
+			if err != nil {
+				err = xerrors.Errorf("dial syslog: %w", err)
+				break
+			}
+		}
+		// A slow or unresponsive server mustn't hold up the queue.
+		_ = b.conn.SetWriteDeadline(time.Now().Add(b.options.WriteTimeout))
+		_, err = b.conn.Write(msg)
+		if err == nil {
+			return
+		}
+		err = xerrors.Errorf("write syslog message: %w", err)
+		_ = b.conn.Close()
+		b.conn = nil
+	}
+	b.logger.Error(b.ctx, "dropping audit log after failing to send it to syslog", slog.Error(err))
+}
+
+// format encodes an audit log as an RFC 5424 message with the JSON
+// encoded log as the MSG. Stream transports are framed with octet
+// counting as described in RFC 6587.
+func (b *syslogBackend) format(alog database.AuditLog) ([]byte, error) {
+	data, err := json.Marshal(convertLog(alog))
+	if err != nil {
+		return nil, xerrors.Errorf("marshal audit log: %w", err)
+	}
+	severity := syslogInfo
+	if alog.StatusCode >= 400 {
+		severity = syslogWarning
+	}
+	msg := fmt.Sprintf("<%d>1 %s %s %s %d %s - %s",
+		syslogFacility*8+severity,
+		alog.Time.UTC().Format("2006-01-02T15:04:05.000000Z07:00"),
+		syslogField(b.options.Hostname, 255),
+		syslogField(b.options.AppName, 48),
+		b.pid,
+		"audit",
+		data,
+	)
+	if b.options.Network == "tcp" {
+		msg = fmt.Sprintf("%d %s", len(msg), msg)
+	}
+	return []byte(msg), nil
+}
+
+// syslogField makes a header field valid by replacing characters that
+// aren't printable US-ASCII and truncating it to the maximum length.
+func syslogField(value string, maxLength int) string {
+	if value == "" {
+		return "-"
+	}
+	field := []byte(value)
+	for i, c := range field {
+		if c < 33 || c > 126 {
+			field[i] = '_'
+		}
+	}
+	if len(field) > maxLength {
+		field = field[:maxLength]
+	}
+	return string(field)
+}
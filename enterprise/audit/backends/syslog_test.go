@@ -0,0 +1,112 @@
+package backends_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/enterprise/audit/audittest"
+	"github.com/coder/coder/enterprise/audit/backends"
+)
+
+func TestSyslogBackend(t *testing.T) {
+	t.Parallel()
+	t.Run("UDP", func(t *testing.T) {
+		t.Parallel()
+
+		conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer conn.Close()
+
+		backend, err := backends.NewSyslog(slogtest.Make(t, nil), backends.SyslogOptions{
+			Network:  "udp",
+			Address:  conn.LocalAddr().String(),
+			Hostname: "coder host",
+		})
+		require.NoError(t, err)
+		defer backend.(interface{ Close() error }).Close()
+
+		alog := audittest.RandomLog()
+		err = backend.Export(context.Background(), alog)
+		require.NoError(t, err)
+
+		buf := make([]byte, 64*1024)
+		n, _, err := conn.ReadFrom(buf)
+		require.NoError(t, err)
+		assertSyslogMessage(t, string(buf[:n]), alog.ID.String())
+	})
+	t.Run("TCP", func(t *testing.T) {
+		t.Parallel()
+
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer listener.Close()
+		received := make(chan string, 1)
+		go func() {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+			// Messages are framed with their length in octets.
+			reader := bufio.NewReader(conn)
+			length, err := reader.ReadString(' ')
+			if err != nil {
+				return
+			}
+			size, err := strconv.Atoi(strings.TrimSpace(length))
+			if err != nil {
+				return
+			}
+			msg := make([]byte, size)
+			_, err = reader.Read(msg)
+			if err != nil {
+				return
+			}
+			received <- string(msg)
+		}()
+
+		network, address, err := backends.ParseSyslogAddress("tcp://" + listener.Addr().String())
+		require.NoError(t, err)
+		backend, err := backends.NewSyslog(slogtest.Make(t, nil), backends.SyslogOptions{
+			Network: network,
+			Address: address,
+		})
+		require.NoError(t, err)
+		defer backend.(interface{ Close() error }).Close()
+
+		alog := audittest.RandomLog()
+		err = backend.Export(context.Background(), alog)
+		require.NoError(t, err)
+		assertSyslogMessage(t, <-received, alog.ID.String())
+	})
+	t.Run("InvalidAddress", func(t *testing.T) {
+		t.Parallel()
+		_, _, err := backends.ParseSyslogAddress("http://localhost:514")
+		require.Error(t, err)
+		_, _, err = backends.ParseSyslogAddress("udp://")
+		require.Error(t, err)
+	})
+}
+
+var syslogHeader = regexp.MustCompile(`^<(\d+)>1 (\S+) (\S+) coder (\d+) audit - (.*)$`)
+
+func assertSyslogMessage(t *testing.T, msg string, id string) {
+	t.Helper()
+	match := syslogHeader.FindStringSubmatch(msg)
+	require.NotNil(t, match, "message %q doesn't match the RFC 5424 format", msg)
+	// local0.info
+	require.Equal(t, "134", match[1])
+	require.NotContains(t, match[3], " ")
+	var alog map[string]any
+	require.NoError(t, json.Unmarshal([]byte(match[5]), &alog))
+	require.Equal(t, id, alog["id"])
+}
@@ -0,0 +1,223 @@
+package backends
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/enterprise/audit"
+	"github.com/coder/retry"
+)
+
+type WebhookOptions struct {
+	// URL receives a POST with a JSON array of audit logs.
+	URL string
+	// Headers are added to every request, e.g. for authentication.
+	Headers http.Header
+	// BatchSize is the maximum number of audit logs sent in a single
+	// request. Defaults to 100.
+	BatchSize int
+	// FlushInterval is how often pending audit logs are sent if a batch
+	// hasn't filled up. Defaults to 5 seconds.
+	FlushInterval time.Duration
+	// MaxRetries is how many times a failed request is retried before the
+	// batch is dropped. Defaults to 5.
+	MaxRetries int
+	// QueueSize is the number of audit logs that can be buffered while
+	// waiting to be sent. Defaults to 10000.
+	QueueSize  int
+	HTTPClient *http.Client
+}
+
+// NewWebhook creates a backend that batches audit logs and sends them to
+// an HTTP endpoint. Export never blocks on the network; logs are dropped
+// with an error if the queue is full.
+func NewWebhook(logger slog.Logger, options WebhookOptions) audit.Backend {
+	if options.BatchSize <= 0 {
+		options.BatchSize = 100
+	}
+	if options.FlushInterval <= 0 {
+		options.FlushInterval = 5 * time.Second
+	}
+	if options.MaxRetries <= 0 {
+		options.MaxRetries = 5
+	}
+	if options.QueueSize <= 0 {
+		options.QueueSize = 10000
+	}
+	if options.HTTPClient == nil {
+		options.HTTPClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	b := &webhookBackend{
+		logger:     logger,
+		options:    options,
+		queue:      make(chan database.AuditLog, options.QueueSize),
+		closed:     make(chan struct{}),
+		done:       make(chan struct{}),
+		ctx:        ctx,
+		cancelFunc: cancelFunc,
+	}
+	go b.loop()
+	return b
+}
+
+type webhookBackend struct {
+	logger  slog.Logger
+	options WebhookOptions
+
+	queue      chan database.AuditLog
+	closed     chan struct{}
+	done       chan struct{}
+	ctx        context.Context
+	cancelFunc context.CancelFunc
+	closeOnce  sync.Once
+}
+
+func (*webhookBackend) Decision() audit.FilterDecision {
+	return audit.FilterDecisionExport
+}
+
+func (b *webhookBackend) Export(_ context.Context, alog database.AuditLog) error {
+	select {
+	case <-b.closed:
+		return xerrors.New("webhook backend is closed")
+	default:
+	}
+	select {
+	case b.queue <- alog:
+		return nil
+	default:
+		return xerrors.Errorf("webhook queue is full, dropping audit log %s", alog.ID)
+	}
+}
+
+// Close sends any queued audit logs and stops the backend.
+func (b *webhookBackend) Close() error {
+	b.closeOnce.Do(func() {
+		close(b.closed)
+		// Give the final flush a bounded amount of time to complete.
+		timer := time.AfterFunc(10*time.Second, b.cancelFunc)
+		<-b.done
+		timer.Stop()
+		b.cancelFunc()
+	})
+	return nil
+}
+
+func (b *webhookBackend) loop() {
+	defer close(b.done)
+	ticker := time.NewTicker(b.options.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]database.AuditLog, 0, b.options.BatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		b.send(batch)
+		batch = make([]database.AuditLog, 0, b.options.BatchSize)
+	}
+	for {
+		select {
+		case <-b.closed:
+			// Drain whatever was queued before closing.
+			for {
+				select {
+				case alog := <-b.queue:
+					batch = append(batch, alog)
+					if len(batch) >= b.options.BatchSize {
+						flush()
+					}
+				default:
+					flush()
+					return
+				}
+			}
+		case alog := <-b.queue:
+			batch = append(batch, alog)
+			if len(batch) >= b.options.BatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// send posts a batch of audit logs, retrying on failure. The batch is
+// dropped once retries are exhausted.
+func (b *webhookBackend) send(batch []database.AuditLog) {
+	logs := make([]exportedLog, 0, len(batch))
+	for _, alog := range batch {
+		logs = append(logs, convertLog(alog))
+	}
+	body, err := json.Marshal(logs)
+	if err != nil {
+		b.logger.Error(b.ctx, "marshal audit logs", slog.Error(err))
+		return
+	}
+
+	retrier := retry.New(250*time.Millisecond, 30*time.Second)
+	for attempt := 1; ; attempt++ {
+		err = b.post(body)
+		if err == nil {
+			return
+		}
+		var perm permanentError
+		if xerrors.As(err, &perm) || attempt > b.options.MaxRetries {
+			break
+		}
+		b.logger.Warn(b.ctx, "send audit logs to webhook", slog.F("attempt", attempt), slog.Error(err))
+		if !retrier.Wait(b.ctx) {
+			break
+		}
+	}
+	b.logger.Error(b.ctx, "dropping audit logs after failing to send them to webhook",
+		slog.F("count", len(batch)), slog.Error(err))
+}
+
+func (b *webhookBackend) post(body []byte) error {
+	req, err := http.NewRequestWithContext(b.ctx, http.MethodPost, b.options.URL, bytes.NewReader(body))
+	if err != nil {
+		return permanentError{xerrors.Errorf("create request: %w", err)}
+	}
+	for key, values := range b.options.Headers {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := b.options.HTTPClient.Do(req)
+	if err != nil {
+		return xerrors.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(io.Discard, res.Body)
+	if res.StatusCode >= 200 && res.StatusCode < 300 {
+		return nil
+	}
+	err = xerrors.Errorf("unexpected status code %d", res.StatusCode)
+	// Client errors won't succeed on retry, except when rate limited.
+	if res.StatusCode >= 400 && res.StatusCode < 500 && res.StatusCode != http.StatusTooManyRequests {
+		return permanentError{err}
+	}
+	return err
+}
+
+// permanentError indicates a request shouldn't be retried.
+type permanentError struct {
+	error
+}
+
+func (e permanentError) Unwrap() error {
+	return e.error
+}
@@ -0,0 +1,117 @@
+package backends_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/enterprise/audit/audittest"
+	"github.com/coder/coder/enterprise/audit/backends"
+	"github.com/coder/coder/testutil"
+)
+
+func TestWebhookBackend(t *testing.T) {
+	t.Parallel()
+	t.Run("Batch", func(t *testing.T) {
+		t.Parallel()
+
+		received := make(chan []map[string]any, 10)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "secret", r.Header.Get("Authorization"))
+			var logs []map[string]any
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&logs))
+			received <- logs
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer srv.Close()
+
+		backend := backends.NewWebhook(slogtest.Make(t, nil), backends.WebhookOptions{
+			URL:           srv.URL,
+			Headers:       http.Header{"Authorization": []string{"secret"}},
+			BatchSize:     2,
+			FlushInterval: time.Hour,
+		})
+		defer backend.(interface{ Close() error }).Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitShort)
+		defer cancel()
+		first, second := audittest.RandomLog(), audittest.RandomLog()
+		require.NoError(t, backend.Export(ctx, first))
+		require.NoError(t, backend.Export(ctx, second))
+
+		select {
+		case logs := <-received:
+			require.Len(t, logs, 2)
+			require.Equal(t, first.ID.String(), logs[0]["id"])
+			require.Equal(t, "127.0.0.1", logs[0]["ip"])
+			require.Equal(t, second.ID.String(), logs[1]["id"])
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for batch")
+		}
+	})
+	t.Run("Retry", func(t *testing.T) {
+		t.Parallel()
+
+		var attempts atomic.Int32
+		received := make(chan uuid.UUID, 1)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if attempts.Add(1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			var logs []struct {
+				ID uuid.UUID `json:"id"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&logs))
+			received <- logs[0].ID
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer srv.Close()
+
+		backend := backends.NewWebhook(slogtest.Make(t, nil), backends.WebhookOptions{
+			URL:           srv.URL,
+			FlushInterval: testutil.IntervalFast,
+		})
+		defer backend.(interface{ Close() error }).Close()
+
+		alog := audittest.RandomLog()
+		require.NoError(t, backend.Export(context.Background(), alog))
+		select {
+		case id := <-received:
+			require.Equal(t, alog.ID, id)
+			require.EqualValues(t, 3, attempts.Load())
+		case <-time.After(testutil.WaitShort):
+			t.Fatal("timed out waiting for retry")
+		}
+	})
+	t.Run("FlushOnClose", func(t *testing.T) {
+		t.Parallel()
+
+		received := make(chan int, 1)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var logs []map[string]any
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&logs))
+			received <- len(logs)
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer srv.Close()
+
+		backend := backends.NewWebhook(slogtest.Make(t, nil), backends.WebhookOptions{
+			URL:           srv.URL,
+			FlushInterval: time.Hour,
+		})
+		require.NoError(t, backend.Export(context.Background(), audittest.RandomLog()))
+		require.NoError(t, backend.(interface{ Close() error }).Close())
+		require.Equal(t, 1, <-received)
+
+		require.Error(t, backend.Export(context.Background(), audittest.RandomLog()))
+	})
+}
@@ -0,0 +1,221 @@
+package audit
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/util/slice"
+)
+
+// FilterRule applies a decision to the audit logs it matches. Empty fields
+// match every audit log.
+type FilterRule struct {
+	ResourceTypes   []database.ResourceType
+	Actions         []database.AuditAction
+	UserIDs         []uuid.UUID
+	OrganizationIDs []uuid.UUID
+	StatusCodes     []int32
+	Decision        FilterDecision
+}
+
+// Matches returns true if the audit log matches every field of the rule.
+func (r FilterRule) Matches(alog database.AuditLog) bool {
+	if len(r.ResourceTypes) > 0 && !slice.Contains(r.ResourceTypes, alog.ResourceType) {
+		return false
+	}
+	if len(r.Actions) > 0 && !slice.Contains(r.Actions, alog.Action) {
+		return false
+	}
+	if len(r.UserIDs) > 0 && !slice.Contains(r.UserIDs, alog.UserID) {
+		return false
+	}
+	if len(r.OrganizationIDs) > 0 && !slice.Contains(r.OrganizationIDs, alog.OrganizationID) {
+		return false
+	}
+	if len(r.StatusCodes) > 0 && !slice.Contains(r.StatusCodes, alog.StatusCode) {
+		return false
+	}
+	return true
+}
+
+// NewRuleFilter returns a filter that applies the decision of the first
+// rule matching an audit log. Audit logs that don't match any rule are
+// stored and exported.
+func NewRuleFilter(rules []FilterRule) Filter {
+	return FilterFunc(func(_ context.Context, alog database.AuditLog) (FilterDecision, error) {
+		for _, rule := range rules {
+			if rule.Matches(alog) {
+				return rule.Decision, nil
+			}
+		}
+		return FilterDecisionStore | FilterDecisionExport, nil
+	})
+}
+
+// ParseFilterDecision parses "drop", or a comma-separated list of "store"
+// and "export".
+func ParseFilterDecision(raw string) (FilterDecision, error) {
+	var decision FilterDecision
+	for _, part := range strings.Split(raw, ",") {
+		switch strings.TrimSpace(part) {
+		case "drop":
+			if strings.TrimSpace(raw) != "drop" {
+				return 0, xerrors.Errorf("decision %q can't combine drop with other decisions", raw)
+			}
+			return FilterDecisionDrop, nil
+		case "store":
+			decision |= FilterDecisionStore
+		case "export":
+			decision |= FilterDecisionExport
+		default:
+			return 0, xerrors.Errorf("invalid decision %q, expected drop, store, or export", part)
+		}
+	}
+	return decision, nil
+}
+
+type filterRulesConfig struct {
+	Rules []filterRuleConfig `yaml:"rules"`
+}
+
+type filterRuleConfig struct {
+	ResourceTypes   []string `yaml:"resource_types"`
+	Actions         []string `yaml:"actions"`
+	UserIDs         []string `yaml:"user_ids"`
+	OrganizationIDs []string `yaml:"organization_ids"`
+	StatusCodes     []int32  `yaml:"status_codes"`
+	Decision        string   `yaml:"decision"`
+}
+
+// ParseFilterRules parses rules from a YAML or JSON document, e.g.
+//
+//	rules:
+//	  - resource_types: [workspace]
+//	    actions: [write]
+//	    decision: export
+func ParseFilterRules(data []byte) ([]FilterRule, error) {
+	var config filterRulesConfig
+	err := yaml.Unmarshal(data, &config)
+	if err != nil {
+		return nil, xerrors.Errorf("unmarshal filter rules: %w", err)
+	}
+	rules := make([]FilterRule, 0, len(config.Rules))
+	for i, raw := range config.Rules {
+		rule, err := raw.convert()
+		if err != nil {
+			return nil, xerrors.Errorf("rule %d: %w", i, err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// ParseFilterRule parses a rule from space-separated key=value pairs,
+// where values are comma-separated lists. The keys match the fields of
+// ParseFilterRules, e.g.
+//
+//	resource_types=workspace,template actions=write decision=export
+func ParseFilterRule(raw string) (FilterRule, error) {
+	var config filterRuleConfig
+	for _, field := range strings.Fields(raw) {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok || value == "" {
+			return FilterRule{}, xerrors.Errorf("invalid field %q, expected key=value", field)
+		}
+		values := strings.Split(value, ",")
+		switch key {
+		case "resource_types":
+			config.ResourceTypes = append(config.ResourceTypes, values...)
+		case "actions":
+			config.Actions = append(config.Actions, values...)
+		case "user_ids":
+			config.UserIDs = append(config.UserIDs, values...)
+		case "organization_ids":
+			config.OrganizationIDs = append(config.OrganizationIDs, values...)
+		case "status_codes":
+			for _, value := range values {
+				code, err := strconv.ParseInt(value, 10, 32)
+				if err != nil {
+					return FilterRule{}, xerrors.Errorf("invalid status code %q: %w", value, err)
+				}
+				config.StatusCodes = append(config.StatusCodes, int32(code))
+			}
+		case "decision":
+			config.Decision = value
+		default:
+			return FilterRule{}, xerrors.Errorf("unknown field %q", key)
+		}
+	}
+	return config.convert()
+}
+
+func (c filterRuleConfig) convert() (FilterRule, error) {
+	if c.Decision == "" {
+		return FilterRule{}, xerrors.New("decision is required")
+	}
+	decision, err := ParseFilterDecision(c.Decision)
+	if err != nil {
+		return FilterRule{}, err
+	}
+	rule := FilterRule{
+		StatusCodes: c.StatusCodes,
+		Decision:    decision,
+	}
+	for _, raw := range c.ResourceTypes {
+		resourceType := database.ResourceType(raw)
+		if !slice.Contains(filterResourceTypes, resourceType) {
+			return FilterRule{}, xerrors.Errorf("unknown resource type %q", raw)
+		}
+		rule.ResourceTypes = append(rule.ResourceTypes, resourceType)
+	}
+	for _, raw := range c.Actions {
+		action := database.AuditAction(raw)
+		if !slice.Contains(filterActions, action) {
+			return FilterRule{}, xerrors.Errorf("unknown action %q", raw)
+		}
+		rule.Actions = append(rule.Actions, action)
+	}
+	for _, raw := range c.UserIDs {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return FilterRule{}, xerrors.Errorf("invalid user id %q: %w", raw, err)
+		}
+		rule.UserIDs = append(rule.UserIDs, id)
+	}
+	for _, raw := range c.OrganizationIDs {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return FilterRule{}, xerrors.Errorf("invalid organization id %q: %w", raw, err)
+		}
+		rule.OrganizationIDs = append(rule.OrganizationIDs, id)
+	}
+	return rule, nil
+}
+
+var filterResourceTypes = []database.ResourceType{
+	database.ResourceTypeOrganization,
+	database.ResourceTypeTemplate,
+	database.ResourceTypeTemplateVersion,
+	database.ResourceTypeUser,
+	database.ResourceTypeWorkspace,
+	database.ResourceTypeGitSshKey,
+	database.ResourceTypeApiKey,
+}
+
+var filterActions = []database.AuditAction{
+	database.AuditActionCreate,
+	database.AuditActionWrite,
+	database.AuditActionDelete,
+	database.AuditActionStart,
+	database.AuditActionStop,
+	database.AuditActionLogin,
+	database.AuditActionLogout,
+	database.AuditActionConnect,
+	database.AuditActionDisconnect,
+}
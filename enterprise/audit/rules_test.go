@@ -0,0 +1,95 @@
+package audit_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/enterprise/audit"
+	"github.com/coder/coder/enterprise/audit/audittest"
+)
+
+func TestRuleFilter(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	rules, err := audit.ParseFilterRules([]byte(`
+rules:
+  - resource_types: [workspace]
+    actions: [write]
+    decision: export
+  - user_ids: [` + userID.String() + `]
+    decision: drop
+  - status_codes: [401, 403]
+    decision: store
+`))
+	require.NoError(t, err)
+	filter := audit.NewRuleFilter(rules)
+
+	check := func(mutate func(alog *database.AuditLog)) audit.FilterDecision {
+		alog := audittest.RandomLog()
+		mutate(&alog)
+		decision, err := filter.Check(context.Background(), alog)
+		require.NoError(t, err)
+		return decision
+	}
+
+	require.Equal(t, audit.FilterDecisionExport, check(func(alog *database.AuditLog) {
+		alog.ResourceType = database.ResourceTypeWorkspace
+		alog.Action = database.AuditActionWrite
+	}))
+	require.Equal(t, audit.FilterDecisionDrop, check(func(alog *database.AuditLog) {
+		alog.UserID = userID
+	}))
+	require.Equal(t, audit.FilterDecisionStore, check(func(alog *database.AuditLog) {
+		alog.StatusCode = http.StatusForbidden
+	}))
+	// The first matching rule wins.
+	require.Equal(t, audit.FilterDecisionExport, check(func(alog *database.AuditLog) {
+		alog.ResourceType = database.ResourceTypeWorkspace
+		alog.Action = database.AuditActionWrite
+		alog.UserID = userID
+	}))
+	require.Equal(t, audit.FilterDecisionStore|audit.FilterDecisionExport, check(func(alog *database.AuditLog) {
+		alog.ResourceType = database.ResourceTypeWorkspace
+		alog.Action = database.AuditActionDelete
+	}))
+}
+
+func TestParseFilterRule(t *testing.T) {
+	t.Parallel()
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		orgID := uuid.New()
+		rule, err := audit.ParseFilterRule("resource_types=workspace,template actions=write organization_ids=" + orgID.String() + " status_codes=200 decision=store,export")
+		require.NoError(t, err)
+		require.Equal(t, audit.FilterRule{
+			ResourceTypes:   []database.ResourceType{database.ResourceTypeWorkspace, database.ResourceTypeTemplate},
+			Actions:         []database.AuditAction{database.AuditActionWrite},
+			OrganizationIDs: []uuid.UUID{orgID},
+			StatusCodes:     []int32{200},
+			Decision:        audit.FilterDecisionStore | audit.FilterDecisionExport,
+		}, rule)
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+		for _, raw := range []string{
+			"actions=write",
+			"decision=maybe",
+			"decision=drop,store",
+			"resource_types=spaceship decision=drop",
+			"actions=launch decision=drop",
+			"user_ids=me decision=drop",
+			"status_codes=ok decision=drop",
+			"colors=red decision=drop",
+			"decision",
+		} {
+			_, err := audit.ParseFilterRule(raw)
+			require.Error(t, err, raw)
+		}
+	})
+}
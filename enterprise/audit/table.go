@@ -61,6 +61,9 @@ var AuditableResources = auditMap(map[any]map[string]Action{
 		"max_ttl":                ActionTrack,
 		"min_autostart_interval": ActionTrack,
 		"created_by":             ActionTrack,
+		"user_acl":               ActionTrack,
+		"group_acl":              ActionTrack,
+		"require_active_version": ActionTrack,
 	},
 	&database.TemplateVersion{}: {
 		"id":              ActionTrack,
@@ -72,6 +75,8 @@ var AuditableResources = auditMap(map[any]map[string]Action{
 		"readme":          ActionTrack,
 		"job_id":          ActionIgnore, // Not helpful in a diff because jobs aren't tracked in audit logs.
 		"created_by":      ActionTrack,
+		"message":         ActionTrack,
+		"archived":        ActionTrack,
 	},
 	&database.User{}: {
 		"id":              ActionTrack,
@@ -85,6 +90,7 @@ var AuditableResources = auditMap(map[any]map[string]Action{
 		"login_type":      ActionIgnore,
 		"avatar_url":      ActionIgnore,
 		"deleted":         ActionTrack,
+		"quota_allowance": ActionTrack,
 	},
 	&database.Workspace{}: {
 		"id":                 ActionTrack,
@@ -98,6 +104,7 @@ var AuditableResources = auditMap(map[any]map[string]Action{
 		"autostart_schedule": ActionTrack,
 		"ttl":                ActionTrack,
 		"last_used_at":       ActionIgnore,
+		"automatic_updates":  ActionTrack,
 	},
 })
 
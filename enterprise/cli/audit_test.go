@@ -0,0 +1,80 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/cli/clitest"
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/enterprise/cli"
+	"github.com/coder/coder/enterprise/coderd/coderdenttest"
+	"github.com/coder/coder/testutil"
+)
+
+func TestAuditExport(t *testing.T) {
+	t.Parallel()
+
+	setup := func(t *testing.T) *codersdk.Client {
+		client := coderdenttest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+		for _, action := range []codersdk.AuditAction{codersdk.AuditActionCreate, codersdk.AuditActionDelete} {
+			err := client.CreateTestAuditLog(ctx, codersdk.CreateTestAuditLogRequest{
+				Action: action,
+			})
+			require.NoError(t, err)
+		}
+		return client
+	}
+
+	t.Run("CSV", func(t *testing.T) {
+		t.Parallel()
+
+		client := setup(t)
+		cmd, root := clitest.NewWithSubcommands(t, cli.EnterpriseSubcommands(), "audit", "export", "--format", "csv", "--search", "action:delete")
+		clitest.SetupConfig(t, client, root)
+		buf := new(bytes.Buffer)
+		cmd.SetOut(buf)
+		err := cmd.Execute()
+		require.NoError(t, err)
+
+		records, err := csv.NewReader(buf).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 2)
+		require.Equal(t, string(codersdk.AuditActionDelete), records[1][9])
+	})
+
+	t.Run("File", func(t *testing.T) {
+		t.Parallel()
+
+		client := setup(t)
+		path := filepath.Join(t.TempDir(), "audit.json")
+		cmd, root := clitest.NewWithSubcommands(t, cli.EnterpriseSubcommands(), "audit", "export", "-f", path)
+		clitest.SetupConfig(t, client, root)
+		err := cmd.Execute()
+		require.NoError(t, err)
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		require.Contains(t, string(data), `"action":"create"`)
+		require.Contains(t, string(data), `"action":"delete"`)
+	})
+
+	t.Run("InvalidFormat", func(t *testing.T) {
+		t.Parallel()
+
+		client := setup(t)
+		cmd, root := clitest.NewWithSubcommands(t, cli.EnterpriseSubcommands(), "audit", "export", "--format", "xml")
+		clitest.SetupConfig(t, client, root)
+		err := cmd.Execute()
+		require.ErrorContains(t, err, "invalid format")
+	})
+}
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+
+	agpl "github.com/coder/coder/cli"
+	"github.com/coder/coder/codersdk"
+)
+
+func auditExport() *cobra.Command {
+	var (
+		format string
+		search string
+		file   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export every audit log matching a search query",
+		Args:  cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			exportFormat := codersdk.AuditLogExportFormat(format)
+			switch exportFormat {
+			case codersdk.AuditLogExportFormatJSON, codersdk.AuditLogExportFormatCSV:
+			default:
+				return xerrors.Errorf("invalid format %q, expected json or csv", format)
+			}
+
+			client, err := agpl.CreateClient(cmd)
+			if err != nil {
+				return xerrors.Errorf("create client: %w", err)
+			}
+
+			logs, err := client.ExportAuditLogs(cmd.Context(), codersdk.AuditLogExportRequest{
+				SearchQuery: search,
+				Format:      exportFormat,
+			})
+			if err != nil {
+				return xerrors.Errorf("export audit logs: %w", err)
+			}
+			defer logs.Close()
+
+			out := cmd.OutOrStdout()
+			if file != "" {
+				f, err := os.Create(file)
+				if err != nil {
+					return xerrors.Errorf("create file: %w", err)
+				}
+				defer f.Close()
+				out = f
+			}
+			_, err = io.Copy(out, logs)
+			if err != nil {
+				return xerrors.Errorf("write audit logs: %w", err)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", string(codersdk.AuditLogExportFormatJSON), "Export format. Available formats are: json, csv.")
+	cmd.Flags().StringVarP(&search, "search", "s", "", "Search query, e.g. \"resource_type:workspace action:delete username:alice time_after:2022-09-01\".")
+	cmd.Flags().StringVarP(&file, "file", "f", "", "Write the export to a file instead of stdout.")
+	return cmd
+}
@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+
+	agpl "github.com/coder/coder/cli"
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/codersdk"
+)
+
+func groupCreate() *cobra.Command {
+	var (
+		users          []string
+		quotaAllowance int
+	)
+	cmd := &cobra.Command{
+		Use:   "create <name>",
+		Short: "Create a user group",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var (
+				ctx = cmd.Context()
+			)
+
+			client, err := agpl.CreateClient(cmd)
+			if err != nil {
+				return xerrors.Errorf("create client: %w", err)
+			}
+
+			org, err := agpl.CurrentOrganization(cmd, client)
+			if err != nil {
+				return xerrors.Errorf("current organization: %w", err)
+			}
+
+			// Resolve users before creating the group so a typo doesn't
+			// leave behind an empty group.
+			userIDs, err := groupUserIDs(cmd, client, users)
+			if err != nil {
+				return err
+			}
+
+			group, err := client.CreateGroup(ctx, org.ID, codersdk.CreateGroupRequest{
+				Name:           args[0],
+				QuotaAllowance: quotaAllowance,
+			})
+			if err != nil {
+				return xerrors.Errorf("create group: %w", err)
+			}
+
+			if len(userIDs) > 0 {
+				group, err = client.PatchGroup(ctx, group.ID, codersdk.PatchGroupRequest{
+					AddUsers: userIDs,
+				})
+				if err != nil {
+					return xerrors.Errorf("add users to group: %w", err)
+				}
+			}
+
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Successfully created group %s with %d members!\n",
+				cliui.Styles.Keyword.Render(group.Name), len(group.Members))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSliceVarP(&users, "users", "u", []string{}, "Users to add to the group, specified by username or ID.")
+	cmd.Flags().IntVarP(&quotaAllowance, "quota-allowance", "q", 0, "The workspace credits granted to each member of the group per day.")
+	return cmd
+}
+
+// groupUserIDs resolves usernames or IDs to user IDs.
+func groupUserIDs(cmd *cobra.Command, client *codersdk.Client, users []string) ([]string, error) {
+	ids := make([]string, 0, len(users))
+	for _, ident := range users {
+		user, err := client.User(cmd.Context(), ident)
+		if err != nil {
+			return nil, xerrors.Errorf("get user %q: %w", ident, err)
+		}
+		ids = append(ids, user.ID.String())
+	}
+	return ids, nil
+}
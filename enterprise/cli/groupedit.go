@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+
+	agpl "github.com/coder/coder/cli"
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/codersdk"
+)
+
+func groupEdit() *cobra.Command {
+	var (
+		name           string
+		addUsers       []string
+		removeUsers    []string
+		quotaAllowance int
+	)
+	cmd := &cobra.Command{
+		Use:   "edit <name>",
+		Short: "Edit a user group",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var (
+				ctx       = cmd.Context()
+				groupName = args[0]
+			)
+
+			client, err := agpl.CreateClient(cmd)
+			if err != nil {
+				return xerrors.Errorf("create client: %w", err)
+			}
+
+			org, err := agpl.CurrentOrganization(cmd, client)
+			if err != nil {
+				return xerrors.Errorf("current organization: %w", err)
+			}
+
+			group, err := client.GroupByOrgAndName(ctx, org.ID, groupName)
+			if err != nil {
+				return xerrors.Errorf("get group: %w", err)
+			}
+
+			req := codersdk.PatchGroupRequest{
+				Name: name,
+			}
+			if cmd.Flags().Changed("quota-allowance") {
+				req.QuotaAllowance = &quotaAllowance
+			}
+			req.AddUsers, err = groupUserIDs(cmd, client, addUsers)
+			if err != nil {
+				return err
+			}
+			req.RemoveUsers, err = groupUserIDs(cmd, client, removeUsers)
+			if err != nil {
+				return err
+			}
+
+			group, err = client.PatchGroup(ctx, group.ID, req)
+			if err != nil {
+				return xerrors.Errorf("patch group: %w", err)
+			}
+
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Successfully patched group %s!\n", cliui.Styles.Keyword.Render(group.Name))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&name, "name", "n", "", "Update the group name")
+	cmd.Flags().StringSliceVarP(&addUsers, "add-users", "a", []string{}, "Add users to the group, specified by username or ID.")
+	cmd.Flags().StringSliceVarP(&removeUsers, "rm-users", "r", []string{}, "Remove users from the group, specified by username or ID.")
+	cmd.Flags().IntVarP(&quotaAllowance, "quota-allowance", "q", 0, "Update the workspace credits granted to each member of the group per day.")
+	return cmd
+}
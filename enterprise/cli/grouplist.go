@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+
+	agpl "github.com/coder/coder/cli"
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/codersdk"
+)
+
+func groupList() *cobra.Command {
+	var (
+		groupColumns = []string{"Name", "Organization ID", "Members"}
+		columns      []string
+		outputFormat string
+	)
+
+	cmd := &cobra.Command{
+		Use:     "list",
+		Short:   "List user groups",
+		Aliases: []string{"ls"},
+		Args:    cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var (
+				ctx = cmd.Context()
+			)
+
+			client, err := agpl.CreateClient(cmd)
+			if err != nil {
+				return xerrors.Errorf("create client: %w", err)
+			}
+
+			org, err := agpl.CurrentOrganization(cmd, client)
+			if err != nil {
+				return xerrors.Errorf("current organization: %w", err)
+			}
+
+			groups, err := client.GroupsByOrganization(ctx, org.ID)
+			if err != nil {
+				return xerrors.Errorf("get groups: %w", err)
+			}
+
+			if len(groups) == 0 {
+				_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "%s No groups found in %s! Create one:\n\n", cliui.Styles.Prompt.String(), color.HiWhiteString(org.Name))
+				_, _ = fmt.Fprintln(cmd.ErrOrStderr(), color.HiMagentaString("  $ coder groups create <name>\n"))
+				return nil
+			}
+
+			out := ""
+			switch outputFormat {
+			case "table", "":
+				out, err = displayGroups(columns, groups)
+				if err != nil {
+					return xerrors.Errorf("render table: %w", err)
+				}
+			case "json":
+				buf := new(bytes.Buffer)
+				enc := json.NewEncoder(buf)
+				enc.SetIndent("", "  ")
+				err = enc.Encode(groups)
+				if err != nil {
+					return xerrors.Errorf("marshal groups to JSON: %w", err)
+				}
+				out = buf.String()
+			default:
+				return xerrors.Errorf(`unknown output format %q, only "table" and "json" are supported`, outputFormat)
+			}
+
+			_, err = fmt.Fprintln(cmd.OutOrStdout(), out)
+			return err
+		},
+	}
+
+	cmd.Flags().StringArrayVarP(&columns, "column", "c", groupColumns,
+		fmt.Sprintf("Specify a column to filter in the table. Available columns are: %s",
+			strings.Join(groupColumns, ", ")))
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "table", "Output format. Available formats are: table, json.")
+	return cmd
+}
+
+type groupRow struct {
+	Name           string    `table:"name"`
+	OrganizationID uuid.UUID `table:"organization_id"`
+	Members        string    `table:"members"`
+}
+
+// displayGroups will return a table displaying all groups passed in.
+// filterColumns must be a subset of the group fields and will determine
+// which columns to display.
+func displayGroups(filterColumns []string, groups []codersdk.Group) (string, error) {
+	rows := make([]groupRow, 0, len(groups))
+	for _, group := range groups {
+		members := make([]string, 0, len(group.Members))
+		for _, member := range group.Members {
+			members = append(members, member.Username)
+		}
+		rows = append(rows, groupRow{
+			Name:           group.Name,
+			OrganizationID: group.OrganizationID,
+			Members:        strings.Join(members, ", "),
+		})
+	}
+
+	return cliui.DisplayTable(rows, "name", filterColumns)
+}
@@ -0,0 +1,132 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/cli/clitest"
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/enterprise/cli"
+	"github.com/coder/coder/enterprise/coderd/coderdenttest"
+	"github.com/coder/coder/pty/ptytest"
+	"github.com/coder/coder/testutil"
+)
+
+func TestGroups(t *testing.T) {
+	t.Parallel()
+
+	setup := func(t *testing.T) (*codersdk.Client, codersdk.CreateFirstUserResponse) {
+		client := coderdenttest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		_ = coderdenttest.AddLicense(t, client, coderdenttest.LicenseOptions{
+			TemplateRBAC: true,
+		})
+		return client, user
+	}
+
+	t.Run("Create", func(t *testing.T) {
+		t.Parallel()
+
+		client, user := setup(t)
+		_, other := coderdtest.CreateAnotherUserWithUser(t, client, user.OrganizationID)
+
+		cmd, root := clitest.NewWithSubcommands(t, cli.EnterpriseSubcommands(), "groups", "create", "engineering", "-u", other.Username)
+		clitest.SetupConfig(t, client, root)
+		pty := ptytest.New(t)
+		cmd.SetOut(pty.Output())
+		err := cmd.Execute()
+		require.NoError(t, err)
+		pty.ExpectMatch("Successfully created group")
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+		group, err := client.GroupByOrgAndName(ctx, user.OrganizationID, "engineering")
+		require.NoError(t, err)
+		require.Len(t, group.Members, 1)
+		require.Equal(t, other.ID, group.Members[0].ID)
+	})
+
+	t.Run("Edit", func(t *testing.T) {
+		t.Parallel()
+
+		client, user := setup(t)
+		_, other := coderdtest.CreateAnotherUserWithUser(t, client, user.OrganizationID)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+		_, err := client.CreateGroup(ctx, user.OrganizationID, codersdk.CreateGroupRequest{
+			Name: "engineering",
+		})
+		require.NoError(t, err)
+
+		cmd, root := clitest.NewWithSubcommands(t, cli.EnterpriseSubcommands(), "groups", "edit", "engineering", "--name", "product", "-a", other.ID.String(), "--quota-allowance", "10")
+		clitest.SetupConfig(t, client, root)
+		pty := ptytest.New(t)
+		cmd.SetOut(pty.Output())
+		err = cmd.Execute()
+		require.NoError(t, err)
+		pty.ExpectMatch("Successfully patched group")
+
+		group, err := client.GroupByOrgAndName(ctx, user.OrganizationID, "product")
+		require.NoError(t, err)
+		require.Len(t, group.Members, 1)
+		require.Equal(t, other.ID, group.Members[0].ID)
+		require.Equal(t, 10, group.QuotaAllowance)
+	})
+
+	t.Run("List", func(t *testing.T) {
+		t.Parallel()
+
+		client, user := setup(t)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+		_, err := client.CreateGroup(ctx, user.OrganizationID, codersdk.CreateGroupRequest{
+			Name: "engineering",
+		})
+		require.NoError(t, err)
+
+		cmd, root := clitest.NewWithSubcommands(t, cli.EnterpriseSubcommands(), "groups", "list", "-o", "json")
+		clitest.SetupConfig(t, client, root)
+		buf := bytes.NewBuffer(nil)
+		cmd.SetOut(buf)
+		err = cmd.Execute()
+		require.NoError(t, err)
+
+		var groups []codersdk.Group
+		err = json.Unmarshal(buf.Bytes(), &groups)
+		require.NoError(t, err)
+		require.Len(t, groups, 1)
+		require.Equal(t, "engineering", groups[0].Name)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		t.Parallel()
+
+		client, user := setup(t)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+		_, err := client.CreateGroup(ctx, user.OrganizationID, codersdk.CreateGroupRequest{
+			Name: "engineering",
+		})
+		require.NoError(t, err)
+
+		cmd, root := clitest.NewWithSubcommands(t, cli.EnterpriseSubcommands(), "groups", "delete", "engineering")
+		clitest.SetupConfig(t, client, root)
+		pty := ptytest.New(t)
+		cmd.SetOut(pty.Output())
+		err = cmd.Execute()
+		require.NoError(t, err)
+		pty.ExpectMatch("Successfully deleted group")
+
+		groups, err := client.GroupsByOrganization(ctx, user.OrganizationID)
+		require.NoError(t, err)
+		require.Len(t, groups, 0)
+	})
+}
@@ -11,6 +11,8 @@ func enterpriseOnly() []*cobra.Command {
 		server(),
 		features(),
 		licenses(),
+		groups(),
+		auditCmd(),
 	}
 }
 
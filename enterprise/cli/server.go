@@ -2,10 +2,18 @@ package cli
 
 import (
 	"context"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
 
+	"cdr.dev/slog"
 	"github.com/coder/coder/cli/cliflag"
+	"github.com/coder/coder/enterprise/audit"
+	"github.com/coder/coder/enterprise/audit/backends"
 	"github.com/coder/coder/enterprise/coderd"
 
 	agpl "github.com/coder/coder/cli"
@@ -14,14 +22,35 @@ import (
 
 func server() *cobra.Command {
 	var (
-		auditLogging   bool
-		scimAuthHeader string
+		auditLogging        bool
+		auditWebhookURL     string
+		auditWebhookHeaders []string
+		auditSyslogAddress  string
+		auditFilePath       string
+		auditFileMaxSize    int
+		auditFileMaxBackups int
+		auditFilterFile     string
+		auditFilterRules    []string
+		derpServerRelayURL  string
+		scimAuthHeader      string
 	)
 	cmd := agpl.Server(func(ctx context.Context, options *agplcoderd.Options) (*agplcoderd.API, error) {
+		auditFilter, err := newAuditFilter(auditFilterFile, auditFilterRules)
+		if err != nil {
+			return nil, err
+		}
+		auditBackends, err := newAuditBackends(options.Logger, auditWebhookURL, auditWebhookHeaders,
+			auditSyslogAddress, auditFilePath, auditFileMaxSize, auditFileMaxBackups)
+		if err != nil {
+			return nil, err
+		}
 		api, err := coderd.New(ctx, &coderd.Options{
-			AuditLogging: auditLogging,
-			SCIMAPIKey:   []byte(scimAuthHeader),
-			Options:      options,
+			AuditLogging:           auditLogging,
+			AuditBackends:          auditBackends,
+			AuditFilter:            auditFilter,
+			DERPServerRelayAddress: derpServerRelayURL,
+			SCIMAPIKey:             []byte(scimAuthHeader),
+			Options:                options,
 		})
 		if err != nil {
 			return nil, err
@@ -30,7 +59,106 @@ func server() *cobra.Command {
 	})
 	cliflag.BoolVarP(cmd.Flags(), &auditLogging, "audit-logging", "", "CODER_AUDIT_LOGGING", true,
 		"Specifies whether audit logging is enabled.")
+	cliflag.StringVarP(cmd.Flags(), &auditWebhookURL, "audit-webhook-url", "", "CODER_AUDIT_WEBHOOK_URL", "",
+		"Sends batches of audit logs as JSON to this URL.")
+	cliflag.StringArrayVarP(cmd.Flags(), &auditWebhookHeaders, "audit-webhook-header", "", "CODER_AUDIT_WEBHOOK_HEADERS", nil,
+		"Headers added to audit webhook requests in the form \"Name: value\".")
+	cliflag.StringVarP(cmd.Flags(), &auditSyslogAddress, "audit-syslog-address", "", "CODER_AUDIT_SYSLOG_ADDRESS", "",
+		"Sends audit logs as RFC 5424 messages to a syslog server, e.g. udp://localhost:514, tcp://localhost:601, or unix:///dev/log.")
+	cliflag.StringVarP(cmd.Flags(), &auditFilePath, "audit-file-path", "", "CODER_AUDIT_FILE_PATH", "",
+		"Writes audit logs as JSON lines to this file.")
+	cliflag.IntVarP(cmd.Flags(), &auditFileMaxSize, "audit-file-max-size", "", "CODER_AUDIT_FILE_MAX_SIZE", 100,
+		"The size in megabytes an audit log file can reach before it's rotated.")
+	cliflag.IntVarP(cmd.Flags(), &auditFileMaxBackups, "audit-file-max-backups", "", "CODER_AUDIT_FILE_MAX_BACKUPS", 5,
+		"The number of rotated audit log files to keep. Zero keeps all of them.")
+	cliflag.StringVarP(cmd.Flags(), &auditFilterFile, "audit-filter-file", "", "CODER_AUDIT_FILTER_FILE", "",
+		"A YAML or JSON file of rules deciding whether audit logs are stored, exported, or dropped.")
+	cliflag.StringArrayVarP(cmd.Flags(), &auditFilterRules, "audit-filter-rule", "", "CODER_AUDIT_FILTER_RULES", nil,
+		"A rule deciding whether audit logs are stored, exported, or dropped, e.g. \"resource_types=workspace actions=write decision=export\". Evaluated before rules from --audit-filter-file.")
+	cliflag.StringVarP(cmd.Flags(), &derpServerRelayURL, "derp-server-relay-url", "", "CODER_DERP_SERVER_RELAY_URL", "",
+		"An HTTP URL that is accessible by other replicas to relay DERP traffic. Required for high availability.")
 	cliflag.StringVarP(cmd.Flags(), &scimAuthHeader, "scim-auth-header", "", "CODER_SCIM_API_KEY", "", "Enables SCIM and sets the authentication header for the built-in SCIM server. New users are automatically created with OIDC authentication.")
 
 	return cmd
 }
+
+// newAuditBackends constructs the audit backends enabled by flags.
+func newAuditBackends(logger slog.Logger, webhookURL string, webhookHeaders []string, syslogAddress, filePath string, fileMaxSize, fileMaxBackups int) ([]audit.Backend, error) {
+	// Options are validated before any backend is created, so no
+	// background work is started when returning an error.
+	headers := http.Header{}
+	if webhookURL != "" {
+		if _, err := url.Parse(webhookURL); err != nil {
+			return nil, xerrors.Errorf("parse audit webhook url: %w", err)
+		}
+		for _, header := range webhookHeaders {
+			name, value, ok := strings.Cut(header, ":")
+			if !ok {
+				return nil, xerrors.Errorf("audit webhook header %q must be in the form \"Name: value\"", header)
+			}
+			headers.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+		}
+	}
+	var syslogOptions backends.SyslogOptions
+	if syslogAddress != "" {
+		network, address, err := backends.ParseSyslogAddress(syslogAddress)
+		if err != nil {
+			return nil, err
+		}
+		syslogOptions = backends.SyslogOptions{
+			Network: network,
+			Address: address,
+		}
+	}
+
+	auditBackends := make([]audit.Backend, 0)
+	if syslogAddress != "" {
+		backend, err := backends.NewSyslog(logger.Named("audit_syslog"), syslogOptions)
+		if err != nil {
+			return nil, xerrors.Errorf("create syslog audit backend: %w", err)
+		}
+		auditBackends = append(auditBackends, backend)
+	}
+	if webhookURL != "" {
+		auditBackends = append(auditBackends, backends.NewWebhook(logger.Named("audit_webhook"), backends.WebhookOptions{
+			URL:     webhookURL,
+			Headers: headers,
+		}))
+	}
+	if filePath != "" {
+		auditBackends = append(auditBackends, backends.NewFile(backends.FileOptions{
+			Path:       filePath,
+			MaxSize:    fileMaxSize,
+			MaxBackups: fileMaxBackups,
+		}))
+	}
+	return auditBackends, nil
+}
+
+// newAuditFilter constructs a filter from rules passed by flags, followed
+// by rules in the filter file. It returns nil if there are no rules.
+func newAuditFilter(filterFile string, filterRules []string) (audit.Filter, error) {
+	rules := make([]audit.FilterRule, 0, len(filterRules))
+	for _, raw := range filterRules {
+		rule, err := audit.ParseFilterRule(raw)
+		if err != nil {
+			return nil, xerrors.Errorf("parse audit filter rule %q: %w", raw, err)
+		}
+		rules = append(rules, rule)
+	}
+	if filterFile != "" {
+		data, err := os.ReadFile(filterFile)
+		if err != nil {
+			return nil, xerrors.Errorf("read audit filter file: %w", err)
+		}
+		fileRules, err := audit.ParseFilterRules(data)
+		if err != nil {
+			return nil, xerrors.Errorf("parse audit filter file %q: %w", filterFile, err)
+		}
+		rules = append(rules, fileRules...)
+	}
+	if len(rules) == 0 {
+		return nil, nil
+	}
+	return audit.NewRuleFilter(rules), nil
+}
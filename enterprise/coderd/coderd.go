@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/ed25519"
 	"fmt"
+	"io"
 	"net/http"
 	"sync"
 	"time"
@@ -21,6 +22,10 @@ import (
 	"github.com/coder/coder/codersdk"
 	"github.com/coder/coder/enterprise/audit"
 	"github.com/coder/coder/enterprise/audit/backends"
+	"github.com/coder/coder/enterprise/derpmesh"
+	"github.com/coder/coder/enterprise/replicasync"
+	"github.com/coder/coder/enterprise/tailnet"
+	agpltailnet "github.com/coder/coder/tailnet"
 )
 
 // New constructs an Enterprise coderd API instance.
@@ -43,7 +48,10 @@ func New(ctx context.Context, options *Options) (*API, error) {
 				Entitlement: codersdk.EntitlementNotEntitled,
 				Enabled:     false,
 			},
-			auditLogs: codersdk.EntitlementNotEntitled,
+			auditLogs:        codersdk.EntitlementNotEntitled,
+			highAvailability: codersdk.EntitlementNotEntitled,
+			templateRBAC:     codersdk.EntitlementNotEntitled,
+			workspaceQuota:   codersdk.EntitlementNotEntitled,
 		},
 		cancelEntitlementsLoop: cancelFunc,
 	}
@@ -55,12 +63,54 @@ func New(ctx context.Context, options *Options) (*API, error) {
 
 	api.AGPL.APIHandler.Group(func(r chi.Router) {
 		r.Get("/entitlements", api.serveEntitlements)
+		r.Route("/replicas", func(r chi.Router) {
+			r.Use(apiKeyMiddleware)
+			r.Get("/", api.replicas)
+		})
 		r.Route("/licenses", func(r chi.Router) {
 			r.Use(apiKeyMiddleware)
 			r.Post("/", api.postLicense)
 			r.Get("/", api.licenses)
 			r.Delete("/{id}", api.deleteLicense)
 		})
+		r.Route("/organizations/{organization}/groups", func(r chi.Router) {
+			r.Use(
+				apiKeyMiddleware,
+				api.templateRBACEnabledMW,
+				httpmw.ExtractOrganizationParam(api.Database),
+			)
+			r.Post("/", api.postGroupByOrganization)
+			r.Get("/", api.groups)
+			r.Get("/{groupName}", api.groupByOrgAndName)
+		})
+		r.Route("/groups/{group}", func(r chi.Router) {
+			r.Use(
+				apiKeyMiddleware,
+				api.templateRBACEnabledMW,
+				httpmw.ExtractGroupParam(api.Database),
+			)
+			r.Get("/", api.group)
+			r.Patch("/", api.patchGroup)
+			r.Delete("/", api.deleteGroup)
+		})
+		r.Route("/templates/{template}/acl", func(r chi.Router) {
+			r.Use(
+				apiKeyMiddleware,
+				api.templateRBACEnabledMW,
+				httpmw.ExtractTemplateParam(api.Database),
+			)
+			r.Get("/", api.templateACL)
+			r.Patch("/", api.patchTemplateACL)
+		})
+		r.Route("/workspace-quota/{user}", func(r chi.Router) {
+			r.Use(
+				apiKeyMiddleware,
+				api.workspaceQuotaEnabledMW,
+				httpmw.ExtractUserParam(api.Database),
+			)
+			r.Get("/", api.workspaceQuota)
+			r.Patch("/", api.patchWorkspaceQuota)
+		})
 	})
 
 	if len(options.SCIMAPIKey) != 0 {
@@ -76,7 +126,16 @@ func New(ctx context.Context, options *Options) (*API, error) {
 		})
 	}
 
-	err := api.updateEntitlements(ctx)
+	var err error
+	api.replicaManager, err = replicasync.New(ctx, options.Logger, options.Database, options.Pubsub, &replicasync.Options{
+		RelayAddress: options.DERPServerRelayAddress,
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("initialize replica: %w", err)
+	}
+	api.derpMesh = derpmesh.New(options.Logger.Named("derpmesh"), api.DERPServer, nil)
+
+	err = api.updateEntitlements(ctx)
 	if err != nil {
 		return nil, xerrors.Errorf("update entitlements: %w", err)
 	}
@@ -88,7 +147,16 @@ func New(ctx context.Context, options *Options) (*API, error) {
 type Options struct {
 	*coderd.Options
 
-	AuditLogging               bool
+	AuditLogging bool
+	// AuditBackends receive audit logs in addition to the database. They
+	// are closed with the API if they implement io.Closer.
+	AuditBackends []audit.Backend
+	// AuditFilter decides where audit logs are sent. Defaults to
+	// audit.DefaultFilter.
+	AuditFilter audit.Filter
+	// DERPServerRelayAddress is the address other replicas use to mesh
+	// with this replica's DERP server.
+	DERPServerRelayAddress     string
 	SCIMAPIKey                 []byte
 	EntitlementsUpdateInterval time.Duration
 	Keys                       map[string]ed25519.PublicKey
@@ -98,20 +166,35 @@ type API struct {
 	AGPL *coderd.API
 	*Options
 
+	// replicaManager keeps this replica registered and tracks its peers.
+	replicaManager *replicasync.Manager
+	// derpMesh forwards DERP packets to the relays of peer replicas.
+	derpMesh *derpmesh.Mesh
+
 	cancelEntitlementsLoop func()
 	entitlementsMu         sync.RWMutex
 	entitlements           entitlements
 }
 
 type entitlements struct {
-	hasLicense  bool
-	activeUsers codersdk.Feature
-	auditLogs   codersdk.Entitlement
-	scim        codersdk.Entitlement
+	hasLicense       bool
+	activeUsers      codersdk.Feature
+	auditLogs        codersdk.Entitlement
+	scim             codersdk.Entitlement
+	highAvailability codersdk.Entitlement
+	templateRBAC     codersdk.Entitlement
+	workspaceQuota   codersdk.Entitlement
 }
 
 func (api *API) Close() error {
 	api.cancelEntitlementsLoop()
+	_ = api.replicaManager.Close()
+	_ = api.derpMesh.Close()
+	for _, backend := range api.AuditBackends {
+		if closer, ok := backend.(io.Closer); ok {
+			_ = closer.Close()
+		}
+	}
 	return api.AGPL.Close()
 }
 
@@ -131,8 +214,11 @@ func (api *API) updateEntitlements(ctx context.Context) error {
 			Enabled:     false,
 			Entitlement: codersdk.EntitlementNotEntitled,
 		},
-		auditLogs: codersdk.EntitlementNotEntitled,
-		scim:      codersdk.EntitlementNotEntitled,
+		auditLogs:        codersdk.EntitlementNotEntitled,
+		scim:             codersdk.EntitlementNotEntitled,
+		highAvailability: codersdk.EntitlementNotEntitled,
+		templateRBAC:     codersdk.EntitlementNotEntitled,
+		workspaceQuota:   codersdk.EntitlementNotEntitled,
 	}
 
 	// Here we loop through licenses to detect enabled features.
@@ -168,6 +254,15 @@ func (api *API) updateEntitlements(ctx context.Context) error {
 		if claims.Features.SCIM > 0 {
 			entitlements.scim = entitlement
 		}
+		if claims.Features.HighAvailability > 0 {
+			entitlements.highAvailability = entitlement
+		}
+		if claims.Features.TemplateRBAC > 0 {
+			entitlements.templateRBAC = entitlement
+		}
+		if claims.Features.WorkspaceQuota > 0 {
+			entitlements.workspaceQuota = entitlement
+		}
 	}
 
 	if entitlements.auditLogs != api.entitlements.auditLogs {
@@ -175,15 +270,69 @@ func (api *API) updateEntitlements(ctx context.Context) error {
 		// A flag could be added to the options that would allow disabling
 		// enhanced audit logging here!
 		if entitlements.auditLogs == codersdk.EntitlementEntitled && api.AuditLogging {
-			auditor = audit.NewAuditor(
-				audit.DefaultFilter,
+			auditBackends := []audit.Backend{
 				backends.NewPostgres(api.Database, true),
 				backends.NewSlog(api.Logger),
-			)
+			}
+			auditBackends = append(auditBackends, api.AuditBackends...)
+			filter := audit.DefaultFilter
+			if api.AuditFilter != nil {
+				filter = api.AuditFilter
+			}
+			auditor = audit.NewAuditor(filter, auditBackends...)
 		}
 		api.AGPL.Auditor.Store(&auditor)
 	}
 
+	if entitlements.workspaceQuota != api.entitlements.workspaceQuota {
+		// Quotas are enforced during the grace period so workspace
+		// usage doesn't suddenly balloon when a license expires.
+		if entitlements.workspaceQuota == codersdk.EntitlementNotEntitled {
+			api.AGPL.QuotaCommitter.Store(nil)
+		} else {
+			var committer coderd.QuotaCommitter = quotaCommitter{}
+			api.AGPL.QuotaCommitter.Store(&committer)
+		}
+	}
+
+	// High availability is kept during the grace period, since falling back
+	// to the in-memory coordinator would break multi-replica deployments.
+	enabled := entitlements.highAvailability != codersdk.EntitlementNotEntitled
+	wasEnabled := api.entitlements.highAvailability != codersdk.EntitlementNotEntitled
+	if enabled != wasEnabled {
+		coordinator := agpltailnet.NewCoordinator()
+		if enabled {
+			coordinator, err = tailnet.NewCoordinator(api.Logger, api.Pubsub)
+		}
+		if err != nil {
+			api.Logger.Error(ctx, "unable to set up high availability coordinator", slog.Error(err))
+			// Keep the current coordinator and try again on the next update.
+			entitlements.highAvailability = api.entitlements.highAvailability
+		} else {
+			oldCoordinator := api.AGPL.TailnetCoordinator.Swap(&coordinator)
+			if oldCoordinator != nil {
+				_ = (*oldCoordinator).Close()
+			}
+			if enabled {
+				api.replicaManager.SetCallback(func() {
+					addresses := make([]string, 0)
+					for _, replica := range api.replicaManager.Peers() {
+						if replica.RelayAddress == "" {
+							continue
+						}
+						addresses = append(addresses, replica.RelayAddress)
+					}
+					api.derpMesh.SetAddresses(addresses)
+				})
+			} else {
+				// Stop meshing with peers when the license no longer
+				// includes high availability.
+				api.replicaManager.SetCallback(func() {})
+				api.derpMesh.SetAddresses([]string{})
+			}
+		}
+	}
+
 	api.entitlements = entitlements
 
 	return nil
@@ -229,6 +378,37 @@ func (api *API) serveEntitlements(rw http.ResponseWriter, r *http.Request) {
 			"Audit logging is enabled but your license for this feature is expired.")
 	}
 
+	resp.Features[codersdk.FeatureHighAvailability] = codersdk.Feature{
+		Entitlement: entitlements.highAvailability,
+		Enabled:     entitlements.highAvailability != codersdk.EntitlementNotEntitled,
+	}
+	if entitlements.highAvailability == codersdk.EntitlementGracePeriod {
+		resp.Warnings = append(resp.Warnings,
+			"High availability is enabled but your license for this feature is expired.")
+	}
+	if entitlements.highAvailability == codersdk.EntitlementNotEntitled && len(api.replicaManager.Peers()) > 0 {
+		resp.Warnings = append(resp.Warnings,
+			"You have multiple replicas but your license is not entitled to high availability. You will experience connectivity issues.")
+	}
+
+	resp.Features[codersdk.FeatureTemplateRBAC] = codersdk.Feature{
+		Entitlement: entitlements.templateRBAC,
+		Enabled:     entitlements.templateRBAC != codersdk.EntitlementNotEntitled,
+	}
+	if entitlements.templateRBAC == codersdk.EntitlementGracePeriod {
+		resp.Warnings = append(resp.Warnings,
+			"Template RBAC is enabled but your license for this feature is expired.")
+	}
+
+	resp.Features[codersdk.FeatureWorkspaceQuota] = codersdk.Feature{
+		Entitlement: entitlements.workspaceQuota,
+		Enabled:     entitlements.workspaceQuota != codersdk.EntitlementNotEntitled,
+	}
+	if entitlements.workspaceQuota == codersdk.EntitlementGracePeriod {
+		resp.Warnings = append(resp.Warnings,
+			"Workspace quotas are enabled but your license for this feature is expired.")
+	}
+
 	httpapi.Write(rw, http.StatusOK, resp)
 }
 
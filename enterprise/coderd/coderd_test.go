@@ -17,6 +17,8 @@ import (
 	"github.com/coder/coder/enterprise/audit"
 	"github.com/coder/coder/enterprise/coderd"
 	"github.com/coder/coder/enterprise/coderd/coderdenttest"
+	"github.com/coder/coder/enterprise/tailnet"
+	agpltailnet "github.com/coder/coder/tailnet"
 	"github.com/coder/coder/testutil"
 )
 
@@ -202,3 +204,37 @@ func TestAuditLogging(t *testing.T) {
 		assert.Equal(t, reflect.ValueOf(ea).Type(), reflect.ValueOf(auditor).Type())
 	})
 }
+
+func TestHighAvailability(t *testing.T) {
+	t.Parallel()
+	t.Run("Enabled", func(t *testing.T) {
+		t.Parallel()
+		client, _, api := coderdenttest.NewWithAPI(t, nil)
+		coderdtest.CreateFirstUser(t, client)
+		coderdenttest.AddLicense(t, client, coderdenttest.LicenseOptions{
+			HighAvailability: true,
+		})
+		entitlements, err := client.Entitlements(context.Background())
+		require.NoError(t, err)
+		ha := entitlements.Features[codersdk.FeatureHighAvailability]
+		assert.Equal(t, codersdk.EntitlementEntitled, ha.Entitlement)
+		assert.True(t, ha.Enabled)
+
+		coordinator := *api.AGPL.TailnetCoordinator.Load()
+		haCoordinator, err := tailnet.NewCoordinator(api.Logger, api.Pubsub)
+		require.NoError(t, err)
+		defer haCoordinator.Close()
+		t.Logf("%T = %T", coordinator, haCoordinator)
+		assert.Equal(t, reflect.ValueOf(haCoordinator).Type(), reflect.ValueOf(coordinator).Type())
+	})
+	t.Run("Disabled", func(t *testing.T) {
+		t.Parallel()
+		client, _, api := coderdenttest.NewWithAPI(t, nil)
+		coderdtest.CreateFirstUser(t, client)
+		coordinator := *api.AGPL.TailnetCoordinator.Load()
+		agplCoordinator := agpltailnet.NewCoordinator()
+		defer agplCoordinator.Close()
+		t.Logf("%T = %T", coordinator, agplCoordinator)
+		assert.Equal(t, reflect.ValueOf(agplCoordinator).Type(), reflect.ValueOf(coordinator).Type())
+	})
+}
@@ -53,9 +53,10 @@ func NewWithAPI(t *testing.T, options *Options) (*codersdk.Client, io.Closer, *c
 	if options.Options == nil {
 		options.Options = &coderdtest.Options{}
 	}
-	srv, cancelFunc, oop := coderdtest.NewOptions(t, options.Options)
+	setHandler, cancelFunc, oop := coderdtest.NewOptions(t, options.Options)
 	coderAPI, err := coderd.New(context.Background(), &coderd.Options{
 		AuditLogging:               true,
+		DERPServerRelayAddress:     oop.AccessURL.String(),
 		SCIMAPIKey:                 options.SCIMAPIKey,
 		Options:                    oop,
 		EntitlementsUpdateInterval: options.EntitlementsUpdateInterval,
@@ -64,7 +65,7 @@ func NewWithAPI(t *testing.T, options *Options) (*codersdk.Client, io.Closer, *c
 		},
 	})
 	assert.NoError(t, err)
-	srv.Config.Handler = coderAPI.AGPL.RootHandler
+	setHandler(coderAPI.AGPL.RootHandler)
 	var provisionerCloser io.Closer = nopcloser{}
 	if options.IncludeProvisionerDaemon {
 		provisionerCloser = coderdtest.NewProvisionerDaemon(t, coderAPI.AGPL)
@@ -78,13 +79,16 @@ func NewWithAPI(t *testing.T, options *Options) (*codersdk.Client, io.Closer, *c
 }
 
 type LicenseOptions struct {
-	AccountType string
-	AccountID   string
-	GraceAt     time.Time
-	ExpiresAt   time.Time
-	UserLimit   int64
-	AuditLog    bool
-	SCIM        bool
+	AccountType      string
+	AccountID        string
+	GraceAt          time.Time
+	ExpiresAt        time.Time
+	UserLimit        int64
+	AuditLog         bool
+	SCIM             bool
+	HighAvailability bool
+	TemplateRBAC     bool
+	WorkspaceQuota   bool
 }
 
 // AddLicense generates a new license with the options provided and inserts it.
@@ -112,6 +116,18 @@ func GenerateLicense(t *testing.T, options LicenseOptions) string {
 	if options.SCIM {
 		scim = 1
 	}
+	highAvailability := int64(0)
+	if options.HighAvailability {
+		highAvailability = 1
+	}
+	rbacEnabled := int64(0)
+	if options.TemplateRBAC {
+		rbacEnabled = 1
+	}
+	workspaceQuota := int64(0)
+	if options.WorkspaceQuota {
+		workspaceQuota = 1
+	}
 
 	c := &coderd.Claims{
 		RegisteredClaims: jwt.RegisteredClaims{
@@ -125,9 +141,12 @@ func GenerateLicense(t *testing.T, options LicenseOptions) string {
 		AccountID:      options.AccountID,
 		Version:        coderd.CurrentVersion,
 		Features: coderd.Features{
-			UserLimit: options.UserLimit,
-			AuditLog:  auditLog,
-			SCIM:      scim,
+			UserLimit:        options.UserLimit,
+			AuditLog:         auditLog,
+			SCIM:             scim,
+			HighAvailability: highAvailability,
+			TemplateRBAC:     rbacEnabled,
+			WorkspaceQuota:   workspaceQuota,
 		},
 	}
 	tok := jwt.NewWithClaims(jwt.SigningMethodEdDSA, c)
@@ -6,9 +6,13 @@ import (
 	"net/http"
 	"testing"
 
+	"github.com/stretchr/testify/require"
+
 	"github.com/coder/coder/coderd/coderdtest"
 	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/codersdk"
 	"github.com/coder/coder/enterprise/coderd/coderdenttest"
+	"github.com/coder/coder/testutil"
 )
 
 func TestNew(t *testing.T) {
@@ -24,10 +28,23 @@ func TestAuthorizeAllEndpoints(t *testing.T) {
 			IncludeProvisionerDaemon: true,
 		},
 	})
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
 	admin := coderdtest.CreateFirstUser(t, client)
-	license := coderdenttest.AddLicense(t, client, coderdenttest.LicenseOptions{})
-	a := coderdtest.NewAuthTester(context.Background(), t, client, api.AGPL, admin)
+	license := coderdenttest.AddLicense(t, client, coderdenttest.LicenseOptions{
+		TemplateRBAC:   true,
+		WorkspaceQuota: true,
+	})
+	group, err := client.CreateGroup(ctx, admin.OrganizationID, codersdk.CreateGroupRequest{
+		Name: "testgroup",
+	})
+	require.NoError(t, err)
+
+	groupObj := rbac.ResourceGroup.InOrg(admin.OrganizationID)
+	a := coderdtest.NewAuthTester(ctx, t, client, api.AGPL, admin)
 	a.URLParams["licenses/{id}"] = fmt.Sprintf("licenses/%d", license.ID)
+	a.URLParams["groups/{group}"] = fmt.Sprintf("groups/%s", group.ID.String())
+	a.URLParams["{groupName}"] = group.Name
 
 	skipRoutes, assertRoute := coderdtest.AGPLRoutes(a)
 	assertRoute["GET:/api/v2/entitlements"] = coderdtest.RouteCheck{
@@ -46,6 +63,47 @@ func TestAuthorizeAllEndpoints(t *testing.T) {
 		AssertAction: rbac.ActionDelete,
 		AssertObject: rbac.ResourceLicense,
 	}
+	assertRoute["GET:/api/v2/replicas"] = coderdtest.RouteCheck{
+		AssertAction: rbac.ActionRead,
+		AssertObject: rbac.ResourceReplicas,
+	}
+	assertRoute["GET:/api/v2/templates/{template}/acl"] = coderdtest.RouteCheck{
+		AssertAction: rbac.ActionRead,
+		AssertObject: rbac.ResourceTemplate,
+	}
+	assertRoute["PATCH:/api/v2/templates/{template}/acl"] = coderdtest.RouteCheck{
+		AssertAction: rbac.ActionUpdate,
+		AssertObject: rbac.ResourceTemplate,
+	}
+	assertRoute["GET:/api/v2/organizations/{organization}/groups"] = coderdtest.RouteCheck{
+		StatusCode:   http.StatusOK,
+		AssertAction: rbac.ActionRead,
+		AssertObject: groupObj,
+	}
+	assertRoute["POST:/api/v2/organizations/{organization}/groups"] = coderdtest.RouteCheck{
+		AssertAction: rbac.ActionCreate,
+		AssertObject: groupObj,
+	}
+	assertRoute["GET:/api/v2/organizations/{organization}/groups/{groupName}"] = coderdtest.RouteCheck{
+		AssertAction: rbac.ActionRead,
+		AssertObject: groupObj,
+	}
+	assertRoute["GET:/api/v2/groups/{group}"] = coderdtest.RouteCheck{
+		AssertAction: rbac.ActionRead,
+		AssertObject: groupObj,
+	}
+	assertRoute["PATCH:/api/v2/groups/{group}"] = coderdtest.RouteCheck{
+		AssertAction: rbac.ActionUpdate,
+		AssertObject: groupObj,
+	}
+	assertRoute["DELETE:/api/v2/groups/{group}"] = coderdtest.RouteCheck{
+		AssertAction: rbac.ActionDelete,
+		AssertObject: groupObj,
+	}
+	assertRoute["GET:/api/v2/workspace-quota/{user}"] = coderdtest.RouteCheck{
+		AssertAction: rbac.ActionRead,
+		AssertObject: rbac.ResourceUser,
+	}
 
-	a.Test(context.Background(), assertRoute, skipRoutes)
+	a.Test(ctx, assertRoute, skipRoutes)
 }
@@ -0,0 +1,380 @@
+package coderd
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/coderd"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/codersdk"
+)
+
+func (api *API) postGroupByOrganization(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx = r.Context()
+		org = httpmw.OrganizationParam(r)
+	)
+
+	if !api.AGPL.Authorize(r, rbac.ActionCreate, rbac.ResourceGroup.InOrg(org.ID)) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	var req codersdk.CreateGroupRequest
+	if !httpapi.Read(rw, r, &req) {
+		return
+	}
+
+	if req.Name == database.AllUsersGroup {
+		httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("%q is a reserved keyword and cannot be used for a group name.", database.AllUsersGroup),
+		})
+		return
+	}
+	if req.QuotaAllowance < 0 {
+		httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Quota allowance cannot be negative.",
+		})
+		return
+	}
+
+	group, err := api.Database.InsertGroup(ctx, database.InsertGroupParams{
+		ID:             uuid.New(),
+		Name:           req.Name,
+		OrganizationID: org.ID,
+		QuotaAllowance: int32(req.QuotaAllowance),
+	})
+	if database.IsUniqueViolation(err) {
+		httpapi.Write(rw, http.StatusConflict, codersdk.Response{
+			Message: fmt.Sprintf("Group with name %q already exists.", req.Name),
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error creating group.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(rw, http.StatusCreated, convertGroup(group, nil))
+}
+
+func (api *API) patchGroup(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx   = r.Context()
+		group = httpmw.GroupParam(r)
+	)
+
+	if !api.AGPL.Authorize(r, rbac.ActionUpdate, group) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	var req codersdk.PatchGroupRequest
+	if !httpapi.Read(rw, r, &req) {
+		return
+	}
+
+	if req.Name != "" && req.Name != group.Name && group.ID == group.OrganizationID {
+		httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Cannot rename the %q group.", database.AllUsersGroup),
+		})
+		return
+	}
+	if req.Name == database.AllUsersGroup && group.ID != group.OrganizationID {
+		httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("%q is a reserved group name.", database.AllUsersGroup),
+		})
+		return
+	}
+	if group.ID == group.OrganizationID && (len(req.AddUsers) > 0 || len(req.RemoveUsers) > 0) {
+		httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Members of the %q group are managed through organization membership.", database.AllUsersGroup),
+		})
+		return
+	}
+	if req.QuotaAllowance != nil && *req.QuotaAllowance < 0 {
+		httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Quota allowance cannot be negative.",
+		})
+		return
+	}
+
+	users := make([]string, 0, len(req.AddUsers)+len(req.RemoveUsers))
+	users = append(users, req.AddUsers...)
+	users = append(users, req.RemoveUsers...)
+
+	for _, id := range users {
+		userID, err := uuid.Parse(id)
+		if err != nil {
+			httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+				Message: fmt.Sprintf("ID %q must be a valid user UUID.", id),
+			})
+			return
+		}
+		// Only members of the group's organization can join it.
+		_, err = api.Database.GetOrganizationMemberByUserID(ctx, database.GetOrganizationMemberByUserIDParams{
+			OrganizationID: group.OrganizationID,
+			UserID:         userID,
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+				Message: fmt.Sprintf("User %q must be a member of organization %q.", id, group.OrganizationID),
+			})
+			return
+		}
+		if err != nil {
+			httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching organization member.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+	}
+
+	if req.Name != "" && req.Name != group.Name {
+		_, err := api.Database.GetGroupByOrgAndName(ctx, database.GetGroupByOrgAndNameParams{
+			OrganizationID: group.OrganizationID,
+			Name:           req.Name,
+		})
+		if err == nil {
+			httpapi.Write(rw, http.StatusConflict, codersdk.Response{
+				Message: fmt.Sprintf("A group with name %q already exists.", req.Name),
+			})
+			return
+		}
+	}
+
+	err := api.Database.InTx(func(tx database.Store) error {
+		var err error
+		params := database.UpdateGroupByIDParams{
+			ID:             group.ID,
+			Name:           group.Name,
+			QuotaAllowance: group.QuotaAllowance,
+		}
+		if req.Name != "" {
+			params.Name = req.Name
+		}
+		if req.QuotaAllowance != nil {
+			params.QuotaAllowance = int32(*req.QuotaAllowance)
+		}
+		if params.Name != group.Name || params.QuotaAllowance != group.QuotaAllowance {
+			group, err = tx.UpdateGroupByID(ctx, params)
+			if err != nil {
+				return xerrors.Errorf("update group by ID: %w", err)
+			}
+		}
+		for _, id := range req.AddUsers {
+			err := tx.InsertGroupMember(ctx, database.InsertGroupMemberParams{
+				GroupID: group.ID,
+				UserID:  uuid.MustParse(id),
+			})
+			if err != nil {
+				return xerrors.Errorf("insert group member %q: %w", id, err)
+			}
+		}
+		for _, id := range req.RemoveUsers {
+			err := tx.DeleteGroupMemberFromGroup(ctx, database.DeleteGroupMemberFromGroupParams{
+				UserID:  uuid.MustParse(id),
+				GroupID: group.ID,
+			})
+			if err != nil {
+				return xerrors.Errorf("delete group member %q: %w", id, err)
+			}
+		}
+		return nil
+	})
+	if database.IsUniqueViolation(err) {
+		httpapi.Write(rw, http.StatusPreconditionFailed, codersdk.Response{
+			Message: "Cannot add the same user to a group twice!",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating group.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	members, err := api.Database.GetGroupMembers(ctx, group.ID)
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching group members.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(rw, http.StatusOK, convertGroup(group, members))
+}
+
+func (api *API) deleteGroup(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx   = r.Context()
+		group = httpmw.GroupParam(r)
+	)
+
+	if !api.AGPL.Authorize(r, rbac.ActionDelete, group) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	if group.ID == group.OrganizationID {
+		httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("%q is a reserved group and cannot be deleted!", database.AllUsersGroup),
+		})
+		return
+	}
+
+	err := api.Database.DeleteGroupByID(ctx, group.ID)
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting group.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(rw, http.StatusOK, codersdk.Response{
+		Message: "Successfully deleted group!",
+	})
+}
+
+func (api *API) group(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx   = r.Context()
+		group = httpmw.GroupParam(r)
+	)
+
+	if !api.AGPL.Authorize(r, rbac.ActionRead, group) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	users, err := api.Database.GetGroupMembers(ctx, group.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching group members.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(rw, http.StatusOK, convertGroup(group, users))
+}
+
+func (api *API) groupByOrgAndName(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		org  = httpmw.OrganizationParam(r)
+		name = chi.URLParam(r, "groupName")
+	)
+
+	group, err := api.Database.GetGroupByOrgAndName(ctx, database.GetGroupByOrgAndNameParams{
+		OrganizationID: org.ID,
+		Name:           name,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching group.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	if !api.AGPL.Authorize(r, rbac.ActionRead, group) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	users, err := api.Database.GetGroupMembers(ctx, group.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching group members.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(rw, http.StatusOK, convertGroup(group, users))
+}
+
+func (api *API) groups(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx = r.Context()
+		org = httpmw.OrganizationParam(r)
+	)
+
+	groups, err := api.Database.GetGroupsByOrganizationID(ctx, org.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching groups.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	// Filter groups based on rbac permissions
+	groups, err = coderd.AuthorizeFilter(api.AGPL.HTTPAuth, r, rbac.ActionRead, groups)
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching groups.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	resp := make([]codersdk.Group, 0, len(groups))
+	for _, group := range groups {
+		members, err := api.Database.GetGroupMembers(ctx, group.ID)
+		if err != nil {
+			httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching group members.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+
+		resp = append(resp, convertGroup(group, members))
+	}
+
+	httpapi.Write(rw, http.StatusOK, resp)
+}
+
+func convertGroup(g database.Group, users []database.User) codersdk.Group {
+	return codersdk.Group{
+		ID:             g.ID,
+		Name:           g.Name,
+		OrganizationID: g.OrganizationID,
+		Members:        convertUsers(users),
+		QuotaAllowance: int(g.QuotaAllowance),
+	}
+}
+
+// convertUsers omits organization IDs, since they aren't needed to
+// describe group members.
+func convertUsers(users []database.User) []codersdk.User {
+	converted := make([]codersdk.User, 0, len(users))
+	for _, u := range users {
+		converted = append(converted, coderd.ConvertUser(u, []uuid.UUID{}))
+	}
+	return converted
+}
@@ -0,0 +1,347 @@
+package coderd_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/enterprise/coderd/coderdenttest"
+	"github.com/coder/coder/testutil"
+)
+
+func TestCreateGroup(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdenttest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		_ = coderdenttest.AddLicense(t, client, coderdenttest.LicenseOptions{
+			TemplateRBAC: true,
+		})
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		group, err := client.CreateGroup(ctx, user.OrganizationID, codersdk.CreateGroupRequest{
+			Name: "hi",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "hi", group.Name)
+		require.Equal(t, user.OrganizationID, group.OrganizationID)
+		require.Empty(t, group.Members)
+	})
+
+	t.Run("Conflict", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdenttest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		_ = coderdenttest.AddLicense(t, client, coderdenttest.LicenseOptions{
+			TemplateRBAC: true,
+		})
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		_, err := client.CreateGroup(ctx, user.OrganizationID, codersdk.CreateGroupRequest{
+			Name: "hi",
+		})
+		require.NoError(t, err)
+
+		_, err = client.CreateGroup(ctx, user.OrganizationID, codersdk.CreateGroupRequest{
+			Name: "hi",
+		})
+		require.Error(t, err)
+		var cerr *codersdk.Error
+		require.True(t, errors.As(err, &cerr))
+		require.Equal(t, http.StatusConflict, cerr.StatusCode())
+	})
+
+	t.Run("ReservedName", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdenttest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		_ = coderdenttest.AddLicense(t, client, coderdenttest.LicenseOptions{
+			TemplateRBAC: true,
+		})
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		_, err := client.CreateGroup(ctx, user.OrganizationID, codersdk.CreateGroupRequest{
+			Name: "Everyone",
+		})
+		require.Error(t, err)
+		var cerr *codersdk.Error
+		require.True(t, errors.As(err, &cerr))
+		require.Equal(t, http.StatusBadRequest, cerr.StatusCode())
+	})
+
+	t.Run("NotEntitled", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdenttest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		_, err := client.CreateGroup(ctx, user.OrganizationID, codersdk.CreateGroupRequest{
+			Name: "hi",
+		})
+		require.Error(t, err)
+		var cerr *codersdk.Error
+		require.True(t, errors.As(err, &cerr))
+		require.Equal(t, http.StatusForbidden, cerr.StatusCode())
+	})
+}
+
+func TestPatchGroup(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Name", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdenttest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		_ = coderdenttest.AddLicense(t, client, coderdenttest.LicenseOptions{
+			TemplateRBAC: true,
+		})
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		group, err := client.CreateGroup(ctx, user.OrganizationID, codersdk.CreateGroupRequest{
+			Name: "hi",
+		})
+		require.NoError(t, err)
+
+		group, err = client.PatchGroup(ctx, group.ID, codersdk.PatchGroupRequest{
+			Name: "bye",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "bye", group.Name)
+	})
+
+	t.Run("AddRemoveUsers", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdenttest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		_ = coderdenttest.AddLicense(t, client, coderdenttest.LicenseOptions{
+			TemplateRBAC: true,
+		})
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		_, user2 := coderdtest.CreateAnotherUserWithUser(t, client, user.OrganizationID)
+		_, user3 := coderdtest.CreateAnotherUserWithUser(t, client, user.OrganizationID)
+		_, user4 := coderdtest.CreateAnotherUserWithUser(t, client, user.OrganizationID)
+
+		group, err := client.CreateGroup(ctx, user.OrganizationID, codersdk.CreateGroupRequest{
+			Name: "hi",
+		})
+		require.NoError(t, err)
+
+		// Adding several users at once is how groups are populated in bulk.
+		group, err = client.PatchGroup(ctx, group.ID, codersdk.PatchGroupRequest{
+			AddUsers: []string{user2.ID.String(), user3.ID.String(), user4.ID.String()},
+		})
+		require.NoError(t, err)
+		require.Len(t, group.Members, 3)
+
+		group, err = client.PatchGroup(ctx, group.ID, codersdk.PatchGroupRequest{
+			RemoveUsers: []string{user2.ID.String(), user3.ID.String()},
+		})
+		require.NoError(t, err)
+		require.Len(t, group.Members, 1)
+		require.Equal(t, user4.ID, group.Members[0].ID)
+	})
+
+	t.Run("UserNotInOrg", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdenttest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		_ = coderdenttest.AddLicense(t, client, coderdenttest.LicenseOptions{
+			TemplateRBAC: true,
+		})
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		group, err := client.CreateGroup(ctx, user.OrganizationID, codersdk.CreateGroupRequest{
+			Name: "hi",
+		})
+		require.NoError(t, err)
+
+		_, err = client.PatchGroup(ctx, group.ID, codersdk.PatchGroupRequest{
+			AddUsers: []string{uuid.NewString()},
+		})
+		require.Error(t, err)
+		var cerr *codersdk.Error
+		require.True(t, errors.As(err, &cerr))
+		require.Equal(t, http.StatusBadRequest, cerr.StatusCode())
+	})
+
+	t.Run("AddDuplicateUser", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdenttest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		_ = coderdenttest.AddLicense(t, client, coderdenttest.LicenseOptions{
+			TemplateRBAC: true,
+		})
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		_, user2 := coderdtest.CreateAnotherUserWithUser(t, client, user.OrganizationID)
+		group, err := client.CreateGroup(ctx, user.OrganizationID, codersdk.CreateGroupRequest{
+			Name: "hi",
+		})
+		require.NoError(t, err)
+
+		_, err = client.PatchGroup(ctx, group.ID, codersdk.PatchGroupRequest{
+			AddUsers: []string{user2.ID.String(), user2.ID.String()},
+		})
+		require.Error(t, err)
+		var cerr *codersdk.Error
+		require.True(t, errors.As(err, &cerr))
+		require.Equal(t, http.StatusPreconditionFailed, cerr.StatusCode())
+	})
+}
+
+func TestGroup(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdenttest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		_ = coderdenttest.AddLicense(t, client, coderdenttest.LicenseOptions{
+			TemplateRBAC: true,
+		})
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		_, user2 := coderdtest.CreateAnotherUserWithUser(t, client, user.OrganizationID)
+		group, err := client.CreateGroup(ctx, user.OrganizationID, codersdk.CreateGroupRequest{
+			Name: "hi",
+		})
+		require.NoError(t, err)
+		group, err = client.PatchGroup(ctx, group.ID, codersdk.PatchGroupRequest{
+			AddUsers: []string{user2.ID.String()},
+		})
+		require.NoError(t, err)
+
+		fetched, err := client.Group(ctx, group.ID)
+		require.NoError(t, err)
+		require.Equal(t, group, fetched)
+
+		fetched, err = client.GroupByOrgAndName(ctx, user.OrganizationID, group.Name)
+		require.NoError(t, err)
+		require.Equal(t, group, fetched)
+	})
+
+	t.Run("RegularUserReadGroup", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdenttest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		_ = coderdenttest.AddLicense(t, client, coderdenttest.LicenseOptions{
+			TemplateRBAC: true,
+		})
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		group, err := client.CreateGroup(ctx, user.OrganizationID, codersdk.CreateGroupRequest{
+			Name: "hi",
+		})
+		require.NoError(t, err)
+
+		// Organization members can read groups so they can be shown
+		// alongside template permissions.
+		client1 := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		fetched, err := client1.Group(ctx, group.ID)
+		require.NoError(t, err)
+		require.Equal(t, group.ID, fetched.ID)
+	})
+}
+
+func TestGroups(t *testing.T) {
+	t.Parallel()
+
+	client := coderdenttest.New(t, nil)
+	user := coderdtest.CreateFirstUser(t, client)
+	_ = coderdenttest.AddLicense(t, client, coderdenttest.LicenseOptions{
+		TemplateRBAC: true,
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	group1, err := client.CreateGroup(ctx, user.OrganizationID, codersdk.CreateGroupRequest{
+		Name: "hi",
+	})
+	require.NoError(t, err)
+	group2, err := client.CreateGroup(ctx, user.OrganizationID, codersdk.CreateGroupRequest{
+		Name: "hey",
+	})
+	require.NoError(t, err)
+
+	// The "Everyone" group is implicit and not listed.
+	groups, err := client.GroupsByOrganization(ctx, user.OrganizationID)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []codersdk.Group{group1, group2}, groups)
+}
+
+func TestDeleteGroup(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdenttest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		_ = coderdenttest.AddLicense(t, client, coderdenttest.LicenseOptions{
+			TemplateRBAC: true,
+		})
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		group, err := client.CreateGroup(ctx, user.OrganizationID, codersdk.CreateGroupRequest{
+			Name: "hi",
+		})
+		require.NoError(t, err)
+
+		err = client.DeleteGroup(ctx, group.ID)
+		require.NoError(t, err)
+
+		_, err = client.Group(ctx, group.ID)
+		require.Error(t, err)
+		var cerr *codersdk.Error
+		require.True(t, errors.As(err, &cerr))
+		require.Equal(t, http.StatusNotFound, cerr.StatusCode())
+	})
+
+	t.Run("AllUsers", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdenttest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		_ = coderdenttest.AddLicense(t, client, coderdenttest.LicenseOptions{
+			TemplateRBAC: true,
+		})
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		err := client.DeleteGroup(ctx, user.OrganizationID)
+		require.Error(t, err)
+		var cerr *codersdk.Error
+		require.True(t, errors.As(err, &cerr))
+		require.Equal(t, http.StatusBadRequest, cerr.StatusCode())
+	})
+}
@@ -45,9 +45,12 @@ var key20220812 []byte
 var Keys = map[string]ed25519.PublicKey{"2022-08-12": ed25519.PublicKey(key20220812)}
 
 type Features struct {
-	UserLimit int64 `json:"user_limit"`
-	AuditLog  int64 `json:"audit_log"`
-	SCIM      int64 `json:"scim"`
+	UserLimit        int64 `json:"user_limit"`
+	AuditLog         int64 `json:"audit_log"`
+	SCIM             int64 `json:"scim"`
+	HighAvailability int64 `json:"high_availability"`
+	TemplateRBAC     int64 `json:"template_rbac"`
+	WorkspaceQuota   int64 `json:"workspace_quota"`
 }
 
 type Claims struct {
@@ -96,16 +96,22 @@ func TestGetLicense(t *testing.T) {
 		assert.Equal(t, int32(1), licenses[0].ID)
 		assert.Equal(t, "testing", licenses[0].Claims["account_id"])
 		assert.Equal(t, map[string]interface{}{
-			codersdk.FeatureUserLimit: json.Number("0"),
-			codersdk.FeatureAuditLog:  json.Number("1"),
-			codersdk.FeatureSCIM:      json.Number("1"),
+			codersdk.FeatureUserLimit:        json.Number("0"),
+			codersdk.FeatureAuditLog:         json.Number("1"),
+			codersdk.FeatureSCIM:             json.Number("1"),
+			codersdk.FeatureHighAvailability: json.Number("0"),
+			codersdk.FeatureTemplateRBAC:     json.Number("0"),
+			codersdk.FeatureWorkspaceQuota:   json.Number("0"),
 		}, licenses[0].Claims["features"])
 		assert.Equal(t, int32(2), licenses[1].ID)
 		assert.Equal(t, "testing2", licenses[1].Claims["account_id"])
 		assert.Equal(t, map[string]interface{}{
-			codersdk.FeatureUserLimit: json.Number("200"),
-			codersdk.FeatureAuditLog:  json.Number("1"),
-			codersdk.FeatureSCIM:      json.Number("1"),
+			codersdk.FeatureUserLimit:        json.Number("200"),
+			codersdk.FeatureAuditLog:         json.Number("1"),
+			codersdk.FeatureSCIM:             json.Number("1"),
+			codersdk.FeatureHighAvailability: json.Number("0"),
+			codersdk.FeatureTemplateRBAC:     json.Number("0"),
+			codersdk.FeatureWorkspaceQuota:   json.Number("0"),
 		}, licenses[1].Claims["features"])
 	})
 }
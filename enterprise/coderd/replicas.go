@@ -0,0 +1,36 @@
+package coderd
+
+import (
+	"net/http"
+
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/codersdk"
+)
+
+// replicas returns every replica that is active in the deployment.
+func (api *API) replicas(rw http.ResponseWriter, r *http.Request) {
+	if !api.AGPL.Authorize(r, rbac.ActionRead, rbac.ResourceReplicas) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	replicas := api.replicaManager.All()
+	res := make([]codersdk.Replica, 0, len(replicas))
+	for _, replica := range replicas {
+		res = append(res, convertReplica(replica))
+	}
+	httpapi.Write(rw, http.StatusOK, res)
+}
+
+func convertReplica(replica database.Replica) codersdk.Replica {
+	return codersdk.Replica{
+		ID:              replica.ID,
+		Hostname:        replica.Hostname,
+		CreatedAt:       replica.CreatedAt,
+		RelayAddress:    replica.RelayAddress,
+		Error:           replica.Error,
+		DatabaseLatency: replica.DatabaseLatency,
+	}
+}
@@ -0,0 +1,84 @@
+package coderd_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/databasefake"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/enterprise/coderd/coderdenttest"
+	"github.com/coder/coder/testutil"
+)
+
+func TestReplicas(t *testing.T) {
+	t.Parallel()
+	t.Run("WarningsWithoutLicense", func(t *testing.T) {
+		t.Parallel()
+		db := databasefake.New()
+		pubsub := database.NewPubsubInMemory()
+		firstClient := coderdenttest.New(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{
+				Database: db,
+				Pubsub:   pubsub,
+			},
+		})
+		_ = coderdtest.CreateFirstUser(t, firstClient)
+		secondClient := coderdenttest.New(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{
+				Database: db,
+				Pubsub:   pubsub,
+			},
+		})
+		secondClient.SessionToken = firstClient.SessionToken
+		ents, err := secondClient.Entitlements(context.Background())
+		require.NoError(t, err)
+		require.Len(t, ents.Warnings, 1)
+		replicas, err := secondClient.Replicas(context.Background())
+		require.NoError(t, err)
+		require.Len(t, replicas, 2)
+	})
+	t.Run("ConnectAcrossMultiple", func(t *testing.T) {
+		t.Parallel()
+		db := databasefake.New()
+		pubsub := database.NewPubsubInMemory()
+		firstClient := coderdenttest.New(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{
+				Database: db,
+				Pubsub:   pubsub,
+			},
+		})
+		_ = coderdtest.CreateFirstUser(t, firstClient)
+		coderdenttest.AddLicense(t, firstClient, coderdenttest.LicenseOptions{
+			HighAvailability: true,
+		})
+		secondClient := coderdenttest.New(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{
+				Database: db,
+				Pubsub:   pubsub,
+			},
+		})
+		secondClient.SessionToken = firstClient.SessionToken
+		ents, err := secondClient.Entitlements(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, codersdk.EntitlementEntitled, ents.Features[codersdk.FeatureHighAvailability].Entitlement)
+		require.Empty(t, ents.Warnings)
+
+		// Each replica must be able to reach the relay of the other.
+		require.Eventually(t, func() bool {
+			replicas, err := firstClient.Replicas(context.Background())
+			if err != nil || len(replicas) != 2 {
+				return false
+			}
+			for _, replica := range replicas {
+				if replica.Error != "" {
+					return false
+				}
+			}
+			return true
+		}, testutil.WaitLong, testutil.IntervalFast)
+	})
+}
@@ -0,0 +1,249 @@
+package coderd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/codersdk"
+)
+
+func (api *API) templateACL(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx      = r.Context()
+		template = httpmw.TemplateParam(r)
+	)
+	if !api.AGPL.Authorize(r, rbac.ActionRead, template) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	userIDs := make([]uuid.UUID, 0, len(template.UserACL))
+	for id := range template.UserACL {
+		// An invalid ID can't be stored, since it's validated when
+		// the ACL is updated.
+		userIDs = append(userIDs, uuid.MustParse(id))
+	}
+	users, err := api.Database.GetUsersByIDs(ctx, database.GetUsersByIDsParams{
+		IDs: userIDs,
+	})
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching users.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	templateUsers := make([]codersdk.TemplateUser, 0, len(users))
+	for _, user := range users {
+		templateUsers = append(templateUsers, codersdk.TemplateUser{
+			User: convertUsers([]database.User{user})[0],
+			Role: convertToTemplateRole(template.UserACL[user.ID.String()]),
+		})
+	}
+
+	templateGroups := make([]codersdk.TemplateGroup, 0, len(template.GroupACL))
+	for id, actions := range template.GroupACL {
+		group, err := api.Database.GetGroupByID(ctx, uuid.MustParse(id))
+		if err != nil {
+			httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching group.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		members, err := api.Database.GetGroupMembers(ctx, group.ID)
+		if err != nil {
+			httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching group members.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		templateGroups = append(templateGroups, codersdk.TemplateGroup{
+			Group: convertGroup(group, members),
+			Role:  convertToTemplateRole(actions),
+		})
+	}
+
+	httpapi.Write(rw, http.StatusOK, codersdk.TemplateACL{
+		Users:  templateUsers,
+		Groups: templateGroups,
+	})
+}
+
+func (api *API) patchTemplateACL(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx      = r.Context()
+		template = httpmw.TemplateParam(r)
+	)
+
+	// Only users who are able to update the template, such as template
+	// admins, are able to control permissions.
+	if !api.AGPL.Authorize(r, rbac.ActionUpdate, template) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	var req codersdk.UpdateTemplateACL
+	if !httpapi.Read(rw, r, &req) {
+		return
+	}
+
+	validErrs := validateTemplateACLPerms(ctx, api.Database, req.UserPerms, "user_perms", true)
+	validErrs = append(validErrs,
+		validateTemplateACLPerms(ctx, api.Database, req.GroupPerms, "group_perms", false)...)
+	if len(validErrs) > 0 {
+		httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid request to update template ACL.",
+			Validations: validErrs,
+		})
+		return
+	}
+
+	err := api.Database.InTx(func(tx database.Store) error {
+		// Refetch the template to avoid overwriting a concurrent update.
+		template, err := tx.GetTemplateByID(ctx, template.ID)
+		if err != nil {
+			return xerrors.Errorf("get template by ID: %w", err)
+		}
+		userACL := template.UserACL
+		if userACL == nil {
+			userACL = database.TemplateACL{}
+		}
+		groupACL := template.GroupACL
+		if groupACL == nil {
+			groupACL = database.TemplateACL{}
+		}
+
+		for id, role := range req.UserPerms {
+			if role == codersdk.TemplateRoleDeleted {
+				delete(userACL, id)
+				continue
+			}
+			userACL[id] = convertSDKTemplateRole(role)
+		}
+		for id, role := range req.GroupPerms {
+			if role == codersdk.TemplateRoleDeleted {
+				delete(groupACL, id)
+				continue
+			}
+			groupACL[id] = convertSDKTemplateRole(role)
+		}
+
+		_, err = tx.UpdateTemplateACLByID(ctx, database.UpdateTemplateACLByIDParams{
+			ID:       template.ID,
+			UserACL:  userACL,
+			GroupACL: groupACL,
+		})
+		if err != nil {
+			return xerrors.Errorf("update template ACL by ID: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating template ACL.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(rw, http.StatusOK, codersdk.Response{
+		Message: "Successfully updated template ACL list.",
+	})
+}
+
+// validateTemplateACLPerms ensures every ID refers to an existing user or
+// group and every role is one we know how to grant.
+func validateTemplateACLPerms(ctx context.Context, db database.Store, perms map[string]codersdk.TemplateRole, field string, isUser bool) []codersdk.ValidationError {
+	var validErrs []codersdk.ValidationError
+	for k, v := range perms {
+		if err := validateTemplateRole(v); err != nil {
+			validErrs = append(validErrs, codersdk.ValidationError{Field: field, Detail: err.Error()})
+			continue
+		}
+
+		id, err := uuid.Parse(k)
+		if err != nil {
+			validErrs = append(validErrs, codersdk.ValidationError{Field: field, Detail: fmt.Sprintf("%q is not a valid UUID.", k)})
+			continue
+		}
+
+		// Removing access doesn't require the user or group to
+		// still exist.
+		if v == codersdk.TemplateRoleDeleted {
+			continue
+		}
+
+		if isUser {
+			_, err = db.GetUserByID(ctx, id)
+		} else {
+			_, err = db.GetGroupByID(ctx, id)
+		}
+		if err != nil {
+			validErrs = append(validErrs, codersdk.ValidationError{Field: field, Detail: fmt.Sprintf("Failed to find resource with ID %q: %v", k, err.Error())})
+			continue
+		}
+	}
+
+	return validErrs
+}
+
+func validateTemplateRole(role codersdk.TemplateRole) error {
+	switch role {
+	case codersdk.TemplateRoleAdmin, codersdk.TemplateRoleUse, codersdk.TemplateRoleDeleted:
+		return nil
+	default:
+		return xerrors.Errorf("role %q is not a valid template role", role)
+	}
+}
+
+func convertToTemplateRole(actions []rbac.Action) codersdk.TemplateRole {
+	switch {
+	case len(actions) == 1 && actions[0] == rbac.WildcardSymbol:
+		return codersdk.TemplateRoleAdmin
+	case len(actions) == 1 && actions[0] == rbac.ActionRead:
+		return codersdk.TemplateRoleUse
+	}
+
+	return ""
+}
+
+func convertSDKTemplateRole(role codersdk.TemplateRole) []rbac.Action {
+	switch role {
+	case codersdk.TemplateRoleAdmin:
+		return []rbac.Action{rbac.WildcardSymbol}
+	case codersdk.TemplateRoleUse:
+		return []rbac.Action{rbac.ActionRead}
+	}
+
+	return nil
+}
+
+// templateRBACEnabledMW rejects requests for group and template ACL
+// endpoints when the deployment isn't licensed for template RBAC.
+func (api *API) templateRBACEnabledMW(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		api.entitlementsMu.RLock()
+		rbac := api.entitlements.templateRBAC
+		api.entitlementsMu.RUnlock()
+
+		if rbac == codersdk.EntitlementNotEntitled {
+			httpapi.Write(rw, http.StatusForbidden, codersdk.Response{
+				Message: "Template RBAC is an Enterprise feature. Contact sales!",
+			})
+			return
+		}
+
+		next.ServeHTTP(rw, r)
+	})
+}
@@ -0,0 +1,149 @@
+package coderd
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/coderd"
+	"github.com/coder/coder/coderd/audit"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/codersdk"
+)
+
+// quotaCommitter enforces the quota allowances granted to users
+// directly and through their groups.
+type quotaCommitter struct{}
+
+var _ coderd.QuotaCommitter = quotaCommitter{}
+
+func (quotaCommitter) CommitQuota(ctx context.Context, db database.Store, ownerID uuid.UUID, dailyCost int32) (coderd.QuotaCommitResult, error) {
+	consumed, budget, err := quotaForUser(ctx, db, ownerID)
+	if err != nil {
+		return coderd.QuotaCommitResult{}, err
+	}
+	return coderd.QuotaCommitResult{
+		Permitted:       consumed+int64(dailyCost) <= budget,
+		CreditsConsumed: consumed,
+		Budget:          budget,
+	}, nil
+}
+
+func quotaForUser(ctx context.Context, db database.Store, userID uuid.UUID) (consumed int64, budget int64, err error) {
+	consumed, err = db.GetQuotaConsumedForUser(ctx, userID)
+	if err != nil {
+		return 0, 0, xerrors.Errorf("get consumed quota: %w", err)
+	}
+	budget, err = db.GetQuotaAllowanceForUser(ctx, userID)
+	if err != nil {
+		return 0, 0, xerrors.Errorf("get quota allowance: %w", err)
+	}
+	return consumed, budget, nil
+}
+
+func (api *API) workspaceQuota(rw http.ResponseWriter, r *http.Request) {
+	user := httpmw.UserParam(r)
+
+	if !api.AGPL.Authorize(r, rbac.ActionRead, rbac.ResourceUser.WithOwner(user.ID.String())) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	consumed, budget, err := quotaForUser(r.Context(), api.Database, user.ID)
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace quota.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(rw, http.StatusOK, codersdk.WorkspaceQuota{
+		CreditsConsumed: int(consumed),
+		Budget:          int(budget),
+	})
+}
+
+func (api *API) patchWorkspaceQuota(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		user              = httpmw.UserParam(r)
+		auditor           = *api.AGPL.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.User](rw, &audit.RequestParams{
+			Audit:   auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionWrite,
+		})
+	)
+	defer commitAudit()
+	aReq.Old = user
+
+	// Users own themselves, so the allowance is only managed by those
+	// allowed to update any user.
+	if !api.AGPL.Authorize(r, rbac.ActionUpdate, rbac.ResourceUser) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	var req codersdk.UpdateWorkspaceQuotaRequest
+	if !httpapi.Read(rw, r, &req) {
+		return
+	}
+	if req.QuotaAllowance < 0 {
+		httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Quota allowance cannot be negative.",
+		})
+		return
+	}
+
+	updated, err := api.Database.UpdateUserQuotaAllowance(ctx, database.UpdateUserQuotaAllowanceParams{
+		ID:             user.ID,
+		QuotaAllowance: int32(req.QuotaAllowance),
+		UpdatedAt:      database.Now(),
+	})
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating user's quota allowance.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = updated
+
+	consumed, budget, err := quotaForUser(ctx, api.Database, user.ID)
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace quota.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(rw, http.StatusOK, codersdk.WorkspaceQuota{
+		CreditsConsumed: int(consumed),
+		Budget:          int(budget),
+	})
+}
+
+func (api *API) workspaceQuotaEnabledMW(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		api.entitlementsMu.RLock()
+		quota := api.entitlements.workspaceQuota
+		api.entitlementsMu.RUnlock()
+
+		if quota == codersdk.EntitlementNotEntitled {
+			httpapi.Write(rw, http.StatusForbidden, codersdk.Response{
+				Message: "Workspace quotas are an Enterprise feature. Contact sales!",
+			})
+			return
+		}
+
+		next.ServeHTTP(rw, r)
+	})
+}
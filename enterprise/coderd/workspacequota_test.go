@@ -0,0 +1,214 @@
+package coderd_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/enterprise/coderd/coderdenttest"
+	"github.com/coder/coder/provisioner/echo"
+	"github.com/coder/coder/provisionersdk/proto"
+	"github.com/coder/coder/testutil"
+)
+
+func TestWorkspaceQuota(t *testing.T) {
+	t.Parallel()
+
+	t.Run("NotEntitled", func(t *testing.T) {
+		t.Parallel()
+		client := coderdenttest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		_, err := client.WorkspaceQuota(ctx, codersdk.Me)
+		require.Error(t, err)
+		var cerr *codersdk.Error
+		require.True(t, errors.As(err, &cerr))
+		require.Equal(t, http.StatusForbidden, cerr.StatusCode())
+	})
+
+	t.Run("Enforced", func(t *testing.T) {
+		t.Parallel()
+		client := coderdenttest.New(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{
+				IncludeProvisionerDaemon: true,
+			},
+		})
+		user := coderdtest.CreateFirstUser(t, client)
+		_ = coderdenttest.AddLicense(t, client, coderdenttest.LicenseOptions{
+			TemplateRBAC:   true,
+			WorkspaceQuota: true,
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		// Every member of the organization is granted the allowance of
+		// the "Everyone" group.
+		everyone, err := client.GroupByOrgAndName(ctx, user.OrganizationID, database.AllUsersGroup)
+		require.NoError(t, err)
+		allowance := 4
+		_, err = client.PatchGroup(ctx, everyone.ID, codersdk.PatchGroupRequest{
+			QuotaAllowance: &allowance,
+		})
+		require.NoError(t, err)
+
+		quota, err := client.WorkspaceQuota(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Equal(t, 0, quota.CreditsConsumed)
+		require.Equal(t, 4, quota.Budget)
+
+		// The cost detected when importing the template version is checked
+		// before workspaces are provisioned.
+		provision := []*proto.Provision_Response{{
+			Type: &proto.Provision_Response_Complete{
+				Complete: &proto.Provision_Complete{
+					Resources: []*proto.Resource{{
+						Name:      "example",
+						Type:      "aws_instance",
+						DailyCost: 2,
+					}},
+				},
+			},
+		}}
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+			Parse:           echo.ParseComplete,
+			ProvisionDryRun: provision,
+			Provision:       provision,
+		})
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+
+		// The first two workspaces fit within the allowance.
+		for i := 0; i < 2; i++ {
+			workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+			build := coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+			require.Equal(t, codersdk.ProvisionerJobSucceeded, build.Job.Status)
+			require.Equal(t, int32(2), build.DailyCost)
+		}
+
+		quota, err = client.WorkspaceQuota(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Equal(t, 4, quota.CreditsConsumed)
+		require.Equal(t, 4, quota.Budget)
+
+		// The third would exceed it, so the build fails without
+		// provisioning any resources or consuming quota.
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		build := coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+		require.Equal(t, codersdk.ProvisionerJobFailed, build.Job.Status)
+		require.Contains(t, build.Job.Error, "Insufficient quota")
+		require.Empty(t, build.Resources)
+		require.Equal(t, int32(0), build.DailyCost)
+
+		quota, err = client.WorkspaceQuota(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Equal(t, 4, quota.CreditsConsumed)
+
+		// Allowances granted to the user directly add to their budget.
+		quota, err = client.UpdateWorkspaceQuota(ctx, codersdk.Me, codersdk.UpdateWorkspaceQuotaRequest{
+			QuotaAllowance: 2,
+		})
+		require.NoError(t, err)
+		require.Equal(t, 6, quota.Budget)
+
+		workspace = coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		build = coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+		require.Equal(t, codersdk.ProvisionerJobSucceeded, build.Job.Status)
+	})
+
+	t.Run("FailedBuildReleasesQuota", func(t *testing.T) {
+		t.Parallel()
+		client := coderdenttest.New(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{
+				IncludeProvisionerDaemon: true,
+			},
+		})
+		user := coderdtest.CreateFirstUser(t, client)
+		_ = coderdenttest.AddLicense(t, client, coderdenttest.LicenseOptions{
+			WorkspaceQuota: true,
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		_, err := client.UpdateWorkspaceQuota(ctx, codersdk.Me, codersdk.UpdateWorkspaceQuotaRequest{
+			QuotaAllowance: 4,
+		})
+		require.NoError(t, err)
+
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+			Parse: echo.ParseComplete,
+			ProvisionDryRun: []*proto.Provision_Response{{
+				Type: &proto.Provision_Response_Complete{
+					Complete: &proto.Provision_Complete{
+						Resources: []*proto.Resource{{
+							Name:      "example",
+							Type:      "aws_instance",
+							DailyCost: 2,
+						}},
+					},
+				},
+			}},
+			Provision: []*proto.Provision_Response{{
+				Type: &proto.Provision_Response_Complete{
+					Complete: &proto.Provision_Complete{
+						Error: "failed to provision",
+					},
+				},
+			}},
+		})
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+
+		// The cost reserved when the build was acquired is released when
+		// it fails.
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		build := coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+		require.Equal(t, codersdk.ProvisionerJobFailed, build.Job.Status)
+		require.Equal(t, int32(0), build.DailyCost)
+
+		quota, err := client.WorkspaceQuota(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Equal(t, 0, quota.CreditsConsumed)
+		require.Equal(t, 4, quota.Budget)
+	})
+
+	t.Run("Member", func(t *testing.T) {
+		t.Parallel()
+		client := coderdenttest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		_ = coderdenttest.AddLicense(t, client, coderdenttest.LicenseOptions{
+			WorkspaceQuota: true,
+		})
+		member := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		memberUser, err := member.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+		_, err = client.UpdateWorkspaceQuota(ctx, memberUser.ID.String(), codersdk.UpdateWorkspaceQuotaRequest{
+			QuotaAllowance: 3,
+		})
+		require.NoError(t, err)
+
+		// Members can view their own quota, but can't raise it.
+		quota, err := member.WorkspaceQuota(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Equal(t, 3, quota.Budget)
+
+		_, err = member.UpdateWorkspaceQuota(ctx, codersdk.Me, codersdk.UpdateWorkspaceQuotaRequest{
+			QuotaAllowance: 10,
+		})
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,144 @@
+package derpmesh_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"tailscale.com/derp"
+	"tailscale.com/derp/derphttp"
+	"tailscale.com/types/key"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/enterprise/derpmesh"
+	"github.com/coder/coder/tailnet"
+	"github.com/coder/coder/testutil"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+func TestDERPMesh(t *testing.T) {
+	t.Parallel()
+	t.Run("ExchangeMessages", func(t *testing.T) {
+		// This tests messages passing through multiple DERP servers.
+		t.Parallel()
+		firstServer, firstServerURL := startDERP(t)
+		secondServer, secondServerURL := startDERP(t)
+		firstMesh := derpmesh.New(slogtest.Make(t, nil).Named("first").Leveled(slog.LevelDebug), firstServer, nil)
+		firstMesh.SetAddresses([]string{secondServerURL})
+		secondMesh := derpmesh.New(slogtest.Make(t, nil).Named("second").Leveled(slog.LevelDebug), secondServer, nil)
+		secondMesh.SetAddresses([]string{firstServerURL})
+		defer firstMesh.Close()
+		defer secondMesh.Close()
+
+		first := key.NewNode()
+		second := key.NewNode()
+		firstClient, err := derphttp.NewClient(first, secondServerURL+"/derp", tailnet.Logger(slogtest.Make(t, nil)))
+		require.NoError(t, err)
+		defer firstClient.Close()
+		secondClient, err := derphttp.NewClient(second, firstServerURL+"/derp", tailnet.Logger(slogtest.Make(t, nil)))
+		require.NoError(t, err)
+		defer secondClient.Close()
+		err = secondClient.Connect(context.Background())
+		require.NoError(t, err)
+
+		sent := []byte("hello world")
+		received := make(chan struct{})
+		go func() {
+			defer close(received)
+			for {
+				msg, err := secondClient.Recv()
+				if errors.Is(err, io.EOF) {
+					return
+				}
+				if !assert.NoError(t, err) {
+					return
+				}
+				if packet, ok := msg.(derp.ReceivedPacket); ok {
+					assert.Equal(t, sent, packet.Data)
+					return
+				}
+			}
+		}()
+		// Packets are dropped until the meshes have registered the
+		// second client as a peer, so keep sending until one arrives.
+		require.Eventually(t, func() bool {
+			err := firstClient.Send(second.Public(), sent)
+			if !assert.NoError(t, err) {
+				return false
+			}
+			select {
+			case <-received:
+				return true
+			default:
+				return false
+			}
+		}, testutil.WaitLong, testutil.IntervalFast)
+	})
+	t.Run("RemoveAddress", func(t *testing.T) {
+		// Removing a peer must not break delivery on the local server.
+		t.Parallel()
+		server, serverURL := startDERP(t)
+		mesh := derpmesh.New(slogtest.Make(t, nil).Named("first").Leveled(slog.LevelDebug), server, nil)
+		mesh.SetAddresses([]string{"http://fake.com"})
+		// This should trigger a removal...
+		mesh.SetAddresses([]string{})
+		defer mesh.Close()
+
+		first := key.NewNode()
+		second := key.NewNode()
+		firstClient, err := derphttp.NewClient(first, serverURL+"/derp", tailnet.Logger(slogtest.Make(t, nil)))
+		require.NoError(t, err)
+		defer firstClient.Close()
+		secondClient, err := derphttp.NewClient(second, serverURL+"/derp", tailnet.Logger(slogtest.Make(t, nil)))
+		require.NoError(t, err)
+		defer secondClient.Close()
+		err = secondClient.Connect(context.Background())
+		require.NoError(t, err)
+
+		sent := []byte("hello world")
+		err = firstClient.Send(second.Public(), sent)
+		require.NoError(t, err)
+
+		got := recvData(t, secondClient)
+		require.Equal(t, sent, got)
+	})
+}
+
+func recvData(t *testing.T, client *derphttp.Client) []byte {
+	for {
+		msg, err := client.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		assert.NoError(t, err)
+		t.Logf("derp: %T", msg)
+		switch msg := msg.(type) {
+		case derp.ReceivedPacket:
+			return msg.Data
+		default:
+			// Drop all others!
+		}
+	}
+}
+
+func startDERP(t *testing.T) (*derp.Server, string) {
+	logf := tailnet.Logger(slogtest.Make(t, nil))
+	d := derp.NewServer(key.NewNode(), logf)
+	d.SetMeshKey("some-key")
+	server := httptest.NewUnstartedServer(derphttp.Handler(d))
+	server.Start()
+	t.Cleanup(func() {
+		_ = d.Close()
+	})
+	t.Cleanup(server.Close)
+	return d, server.URL
+}
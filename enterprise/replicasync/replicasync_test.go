@@ -0,0 +1,165 @@
+package replicasync_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/databasefake"
+	"github.com/coder/coder/enterprise/replicasync"
+	"github.com/coder/coder/testutil"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+func TestReplica(t *testing.T) {
+	t.Parallel()
+	t.Run("CreateOnNew", func(t *testing.T) {
+		// This ensures that a new replica is created on New.
+		t.Parallel()
+		db := databasefake.New()
+		pubsub := database.NewPubsubInMemory()
+		closeChan := make(chan struct{}, 1)
+		cancel, err := pubsub.Subscribe(replicasync.PubsubEvent, func(ctx context.Context, message []byte) {
+			closeChan <- struct{}{}
+		})
+		require.NoError(t, err)
+		defer cancel()
+		server, err := replicasync.New(context.Background(), slogtest.Make(t, nil), db, pubsub, nil)
+		require.NoError(t, err)
+		<-closeChan
+		_, err = db.GetReplicaByID(context.Background(), server.Self().ID)
+		require.NoError(t, err)
+		_ = server.Close()
+	})
+	t.Run("ConnectsToPeerReplica", func(t *testing.T) {
+		// Ensures that the replica reports a successful status for
+		// accessing all of its peers.
+		t.Parallel()
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer srv.Close()
+		db := databasefake.New()
+		pubsub := database.NewPubsubInMemory()
+		peer, err := db.InsertReplica(context.Background(), database.InsertReplicaParams{
+			ID:           uuid.New(),
+			CreatedAt:    database.Now(),
+			StartedAt:    database.Now(),
+			UpdatedAt:    database.Now(),
+			Hostname:     "something",
+			RelayAddress: srv.URL,
+		})
+		require.NoError(t, err)
+		server, err := replicasync.New(context.Background(), slogtest.Make(t, nil), db, pubsub, &replicasync.Options{
+			RelayAddress: "http://169.254.169.254",
+		})
+		require.NoError(t, err)
+		require.Len(t, server.Peers(), 1)
+		require.Equal(t, peer.ID, server.Peers()[0].ID)
+		require.Empty(t, server.Self().Error)
+		_ = server.Close()
+	})
+	t.Run("ConnectsToFakePeerWithError", func(t *testing.T) {
+		t.Parallel()
+		db := databasefake.New()
+		pubsub := database.NewPubsubInMemory()
+		var err error
+		_, err = db.InsertReplica(context.Background(), database.InsertReplicaParams{
+			ID:        uuid.New(),
+			CreatedAt: database.Now(),
+			StartedAt: database.Now(),
+			UpdatedAt: database.Now(),
+			Hostname:  "something",
+			// Fake address to dial!
+			RelayAddress: "http://127.0.0.1:1",
+		})
+		require.NoError(t, err)
+		server, err := replicasync.New(context.Background(), slogtest.Make(t, nil), db, pubsub, &replicasync.Options{
+			PeerTimeout:  1 * time.Millisecond,
+			RelayAddress: "http://127.0.0.1:1",
+		})
+		require.NoError(t, err)
+		require.Len(t, server.Peers(), 1)
+		require.Contains(t, server.Self().Error, "Failed to dial peers")
+		_ = server.Close()
+	})
+	t.Run("RefreshOnPublish", func(t *testing.T) {
+		// Refresh when a new replica appears!
+		t.Parallel()
+		db := databasefake.New()
+		pubsub := database.NewPubsubInMemory()
+		server, err := replicasync.New(context.Background(), slogtest.Make(t, nil), db, pubsub, nil)
+		require.NoError(t, err)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer srv.Close()
+		peer, err := db.InsertReplica(context.Background(), database.InsertReplicaParams{
+			ID:           uuid.New(),
+			RelayAddress: srv.URL,
+			UpdatedAt:    database.Now(),
+		})
+		require.NoError(t, err)
+		// Publish multiple times to ensure it can handle that case.
+		err = pubsub.Publish(replicasync.PubsubEvent, []byte(peer.ID.String()))
+		require.NoError(t, err)
+		err = pubsub.Publish(replicasync.PubsubEvent, []byte(peer.ID.String()))
+		require.NoError(t, err)
+		require.Eventually(t, func() bool {
+			return len(server.Peers()) == 1
+		}, testutil.WaitShort, testutil.IntervalFast)
+		_ = server.Close()
+	})
+	t.Run("DeletesOld", func(t *testing.T) {
+		t.Parallel()
+		db := databasefake.New()
+		pubsub := database.NewPubsubInMemory()
+		old, err := db.InsertReplica(context.Background(), database.InsertReplicaParams{
+			ID:        uuid.New(),
+			UpdatedAt: database.Now().Add(-time.Hour),
+		})
+		require.NoError(t, err)
+		server, err := replicasync.New(context.Background(), slogtest.Make(t, nil), db, pubsub, &replicasync.Options{
+			RelayAddress:    "google.com",
+			CleanupInterval: time.Millisecond,
+		})
+		require.NoError(t, err)
+		defer server.Close()
+		require.Eventually(t, func() bool {
+			_, err := db.GetReplicaByID(context.Background(), old.ID)
+			return errors.Is(err, sql.ErrNoRows)
+		}, testutil.WaitShort, testutil.IntervalFast)
+	})
+	t.Run("RefreshOnPeerStop", func(t *testing.T) {
+		// Closing a replica notifies its peers so they stop meshing.
+		t.Parallel()
+		db := databasefake.New()
+		pubsub := database.NewPubsubInMemory()
+		first, err := replicasync.New(context.Background(), slogtest.Make(t, nil), db, pubsub, nil)
+		require.NoError(t, err)
+		defer first.Close()
+		second, err := replicasync.New(context.Background(), slogtest.Make(t, nil), db, pubsub, nil)
+		require.NoError(t, err)
+		require.Eventually(t, func() bool {
+			return len(first.Peers()) == 1
+		}, testutil.WaitShort, testutil.IntervalFast)
+		err = second.Close()
+		require.NoError(t, err)
+		require.Eventually(t, func() bool {
+			return len(first.Peers()) == 0
+		}, testutil.WaitShort, testutil.IntervalFast)
+	})
+}
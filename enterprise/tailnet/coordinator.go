@@ -0,0 +1,397 @@
+package tailnet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"sync"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/coderd/database"
+	agpl "github.com/coder/coder/tailnet"
+)
+
+// PubsubEvent is the pubsub channel replicas exchange node updates on.
+const PubsubEvent = "tailnet_coordinator"
+
+type messageType string
+
+const (
+	// messageTypeClientNodes sends client nodes to the replica the agent is
+	// connected to.
+	messageTypeClientNodes messageType = "client_nodes"
+	// messageTypeAgentNode sends an agent node to the replicas its clients are
+	// connected to.
+	messageTypeAgentNode messageType = "agent_node"
+	// messageTypeAgentHello asks replicas with clients of a newly connected
+	// agent to send their client nodes.
+	messageTypeAgentHello messageType = "agent_hello"
+	// messageTypeClientHello asks the replica an agent is connected to for the
+	// agent's node.
+	messageTypeClientHello messageType = "client_hello"
+)
+
+// message is published to other replicas to exchange nodes.
+type message struct {
+	CoordinatorID uuid.UUID    `json:"coordinator_id"`
+	Type          messageType  `json:"type"`
+	AgentID       uuid.UUID    `json:"agent_id"`
+	Nodes         []*agpl.Node `json:"nodes,omitempty"`
+}
+
+// NewCoordinator creates a coordinator that shares node updates with the
+// coordinators of other replicas over pubsub. Agents and the clients
+// connecting to them can be served by different replicas.
+func NewCoordinator(logger slog.Logger, pubsub database.Pubsub) (agpl.Coordinator, error) {
+	coord := &haCoordinator{
+		id:                       uuid.New(),
+		log:                      logger,
+		pubsub:                   pubsub,
+		nodes:                    map[uuid.UUID]*agpl.Node{},
+		agentSockets:             map[uuid.UUID]net.Conn{},
+		agentToConnectionSockets: map[uuid.UUID]map[uuid.UUID]net.Conn{},
+	}
+	cancel, err := pubsub.Subscribe(PubsubEvent, coord.handlePubsubMessage)
+	if err != nil {
+		return nil, xerrors.Errorf("subscribe to %q: %w", PubsubEvent, err)
+	}
+	coord.cancelSubscription = cancel
+	return coord, nil
+}
+
+type haCoordinator struct {
+	id                 uuid.UUID
+	log                slog.Logger
+	pubsub             database.Pubsub
+	cancelSubscription func()
+
+	mutex  sync.Mutex
+	closed bool
+	// Maps agent and connection IDs to a node. Nodes of agents on other
+	// replicas are only kept while a local connection is using them.
+	nodes map[uuid.UUID]*agpl.Node
+	// Maps agent ID to an open socket on this replica.
+	agentSockets map[uuid.UUID]net.Conn
+	// Maps agent ID to the connection sockets on this replica.
+	agentToConnectionSockets map[uuid.UUID]map[uuid.UUID]net.Conn
+}
+
+// Node returns a node by ID.
+func (c *haCoordinator) Node(id uuid.UUID) *agpl.Node {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.nodes[id]
+}
+
+// ServeClient accepts a WebSocket connection that wants to connect to an agent
+// with the specified ID.
+func (c *haCoordinator) ServeClient(conn net.Conn, id uuid.UUID, agent uuid.UUID) error {
+	c.mutex.Lock()
+	if c.closed {
+		c.mutex.Unlock()
+		return xerrors.New("coordinator is closed")
+	}
+	// When a new connection is requested, we update it with the latest
+	// node of the agent. This allows the connection to establish.
+	node, ok := c.nodes[agent]
+	if ok {
+		err := writeNodes(conn, []*agpl.Node{node})
+		if err != nil {
+			c.mutex.Unlock()
+			return xerrors.Errorf("write nodes: %w", err)
+		}
+	}
+	connectionSockets, ok := c.agentToConnectionSockets[agent]
+	if !ok {
+		connectionSockets = map[uuid.UUID]net.Conn{}
+		c.agentToConnectionSockets[agent] = connectionSockets
+	}
+	connectionSockets[id] = conn
+	_, agentIsLocal := c.agentSockets[agent]
+	c.mutex.Unlock()
+	defer func() {
+		c.mutex.Lock()
+		defer c.mutex.Unlock()
+		delete(c.nodes, id)
+		connectionSockets, ok := c.agentToConnectionSockets[agent]
+		if !ok {
+			return
+		}
+		delete(connectionSockets, id)
+		if len(connectionSockets) != 0 {
+			return
+		}
+		delete(c.agentToConnectionSockets, agent)
+		if _, ok := c.agentSockets[agent]; !ok {
+			// Nothing on this replica needs the remote agent anymore.
+			delete(c.nodes, agent)
+		}
+	}()
+
+	if !agentIsLocal {
+		// The agent may be connected to another replica. Ask for its
+		// node, since this replica may have never seen it.
+		c.publish(message{
+			Type:    messageTypeClientHello,
+			AgentID: agent,
+		})
+	}
+
+	decoder := json.NewDecoder(conn)
+	for {
+		var node agpl.Node
+		err := decoder.Decode(&node)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return xerrors.Errorf("read json: %w", err)
+		}
+		c.mutex.Lock()
+		c.nodes[id] = &node
+		agentSocket, ok := c.agentSockets[agent]
+		if !ok {
+			c.mutex.Unlock()
+			// The agent isn't connected to this replica, so
+			// forward the node to whichever replica it is on.
+			c.publish(message{
+				Type:    messageTypeClientNodes,
+				AgentID: agent,
+				Nodes:   []*agpl.Node{&node},
+			})
+			continue
+		}
+		err = writeNodes(agentSocket, []*agpl.Node{&node})
+		c.mutex.Unlock()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return xerrors.Errorf("write json: %w", err)
+		}
+	}
+}
+
+// ServeAgent accepts a WebSocket connection to an agent that listens to
+// incoming connections and publishes node updates.
+func (c *haCoordinator) ServeAgent(conn net.Conn, id uuid.UUID) error {
+	c.mutex.Lock()
+	if c.closed {
+		c.mutex.Unlock()
+		return xerrors.New("coordinator is closed")
+	}
+	// Publish all nodes on this replica that want to connect to the
+	// agent. Other replicas are asked for theirs below.
+	nodes := c.connectionNodes(id)
+	if len(nodes) > 0 {
+		err := writeNodes(conn, nodes)
+		if err != nil {
+			c.mutex.Unlock()
+			return xerrors.Errorf("write nodes: %w", err)
+		}
+	}
+
+	// If an old agent socket is connected, we close it
+	// to avoid any leaks. This shouldn't ever occur because
+	// we expect one agent to be running.
+	oldAgentSocket, ok := c.agentSockets[id]
+	if ok {
+		_ = oldAgentSocket.Close()
+	}
+	c.agentSockets[id] = conn
+	c.mutex.Unlock()
+	defer func() {
+		c.mutex.Lock()
+		defer c.mutex.Unlock()
+		// The socket may have been replaced by a newer connection.
+		if c.agentSockets[id] == conn {
+			delete(c.agentSockets, id)
+			delete(c.nodes, id)
+		}
+	}()
+
+	c.publish(message{
+		Type:    messageTypeAgentHello,
+		AgentID: id,
+	})
+
+	decoder := json.NewDecoder(conn)
+	for {
+		var node agpl.Node
+		err := decoder.Decode(&node)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return xerrors.Errorf("read json: %w", err)
+		}
+		c.mutex.Lock()
+		c.nodes[id] = &node
+		c.writeToConnections(id, []*agpl.Node{&node})
+		c.mutex.Unlock()
+
+		c.publish(message{
+			Type:    messageTypeAgentNode,
+			AgentID: id,
+			Nodes:   []*agpl.Node{&node},
+		})
+	}
+}
+
+// Close closes all of the open connections in the coordinator and stops
+// listening for updates from other replicas.
+func (c *haCoordinator) Close() error {
+	c.mutex.Lock()
+	if c.closed {
+		c.mutex.Unlock()
+		return nil
+	}
+	c.closed = true
+	for _, socket := range c.agentSockets {
+		_ = socket.Close()
+	}
+	for _, connMap := range c.agentToConnectionSockets {
+		for _, socket := range connMap {
+			_ = socket.Close()
+		}
+	}
+	c.mutex.Unlock()
+
+	c.cancelSubscription()
+	return nil
+}
+
+// handlePubsubMessage handles node updates published by other replicas.
+func (c *haCoordinator) handlePubsubMessage(ctx context.Context, data []byte) {
+	var msg message
+	err := json.Unmarshal(data, &msg)
+	if err != nil {
+		c.log.Error(ctx, "unmarshal coordinator message", slog.Error(err))
+		return
+	}
+	if msg.CoordinatorID == c.id {
+		return
+	}
+
+	switch msg.Type {
+	case messageTypeClientNodes:
+		c.mutex.Lock()
+		defer c.mutex.Unlock()
+		agentSocket, ok := c.agentSockets[msg.AgentID]
+		if !ok {
+			return
+		}
+		err := writeNodes(agentSocket, msg.Nodes)
+		if err != nil {
+			c.log.Debug(ctx, "write nodes to agent", slog.F("agent_id", msg.AgentID), slog.Error(err))
+		}
+	case messageTypeAgentNode:
+		if len(msg.Nodes) == 0 {
+			return
+		}
+		c.mutex.Lock()
+		defer c.mutex.Unlock()
+		if _, ok := c.agentToConnectionSockets[msg.AgentID]; !ok {
+			return
+		}
+		c.nodes[msg.AgentID] = msg.Nodes[0]
+		c.writeToConnections(msg.AgentID, msg.Nodes)
+	case messageTypeAgentHello:
+		c.mutex.Lock()
+		nodes := c.connectionNodes(msg.AgentID)
+		c.mutex.Unlock()
+		if len(nodes) == 0 {
+			return
+		}
+		c.publish(message{
+			Type:    messageTypeClientNodes,
+			AgentID: msg.AgentID,
+			Nodes:   nodes,
+		})
+	case messageTypeClientHello:
+		c.mutex.Lock()
+		_, ok := c.agentSockets[msg.AgentID]
+		node := c.nodes[msg.AgentID]
+		c.mutex.Unlock()
+		if !ok || node == nil {
+			return
+		}
+		c.publish(message{
+			Type:    messageTypeAgentNode,
+			AgentID: msg.AgentID,
+			Nodes:   []*agpl.Node{node},
+		})
+	default:
+		c.log.Warn(ctx, "unknown coordinator message type", slog.F("type", msg.Type))
+	}
+}
+
+// publish sends a message to the coordinators of other replicas. It must not
+// be called while holding the mutex, since pubsub implementations may deliver
+// messages synchronously.
+func (c *haCoordinator) publish(msg message) {
+	msg.CoordinatorID = c.id
+	data, err := json.Marshal(msg)
+	if err != nil {
+		c.log.Error(context.Background(), "marshal coordinator message", slog.Error(err))
+		return
+	}
+	err = c.pubsub.Publish(PubsubEvent, data)
+	if err != nil {
+		c.log.Error(context.Background(), "publish coordinator message",
+			slog.F("type", msg.Type), slog.F("agent_id", msg.AgentID), slog.Error(err))
+	}
+}
+
+// connectionNodes returns the nodes of connections on this replica that want
+// to connect to the agent. The mutex must be held.
+func (c *haCoordinator) connectionNodes(agent uuid.UUID) []*agpl.Node {
+	sockets := c.agentToConnectionSockets[agent]
+	nodes := make([]*agpl.Node, 0, len(sockets))
+	for targetID := range sockets {
+		node, ok := c.nodes[targetID]
+		if !ok {
+			continue
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+// writeToConnections publishes nodes to every connection on this replica that
+// wants to connect to the agent. The mutex must be held.
+func (c *haCoordinator) writeToConnections(agent uuid.UUID, nodes []*agpl.Node) {
+	connectionSockets, ok := c.agentToConnectionSockets[agent]
+	if !ok {
+		return
+	}
+	data, err := json.Marshal(nodes)
+	if err != nil {
+		c.log.Error(context.Background(), "marshal nodes", slog.Error(err))
+		return
+	}
+	var wg sync.WaitGroup
+	wg.Add(len(connectionSockets))
+	for _, connectionSocket := range connectionSockets {
+		connectionSocket := connectionSocket
+		go func() {
+			_, _ = connectionSocket.Write(data)
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+}
+
+func writeNodes(conn net.Conn, nodes []*agpl.Node) error {
+	data, err := json.Marshal(nodes)
+	if err != nil {
+		return xerrors.Errorf("marshal nodes: %w", err)
+	}
+	_, err = conn.Write(data)
+	return err
+}
@@ -13,14 +13,38 @@ import (
 
 // A mapping of attributes on the "coder_agent" resource.
 type agentAttributes struct {
-	Auth            string            `mapstructure:"auth"`
-	OperatingSystem string            `mapstructure:"os"`
-	Architecture    string            `mapstructure:"arch"`
-	Directory       string            `mapstructure:"dir"`
-	ID              string            `mapstructure:"id"`
-	Token           string            `mapstructure:"token"`
-	Env             map[string]string `mapstructure:"env"`
-	StartupScript   string            `mapstructure:"startup_script"`
+	Auth                  string            `mapstructure:"auth"`
+	OperatingSystem       string            `mapstructure:"os"`
+	Architecture          string            `mapstructure:"arch"`
+	Directory             string            `mapstructure:"dir"`
+	ID                    string            `mapstructure:"id"`
+	Token                 string            `mapstructure:"token"`
+	Env                   map[string]string `mapstructure:"env"`
+	StartupScript         string            `mapstructure:"startup_script"`
+	StartupScriptTimeout  int32             `mapstructure:"startup_script_timeout"`
+	ShutdownScript        string            `mapstructure:"shutdown_script"`
+	ShutdownScriptTimeout int32             `mapstructure:"shutdown_script_timeout"`
+	Metadata              []agentMetadata   `mapstructure:"metadata"`
+	// SessionLimits is a list, as blocks are represented as lists in the
+	// Terraform state, but at most one is allowed.
+	SessionLimits []agentSessionLimits `mapstructure:"session_limits"`
+}
+
+// A mapping of the "metadata" blocks on the "coder_agent" resource.
+type agentMetadata struct {
+	Key         string `mapstructure:"key"`
+	DisplayName string `mapstructure:"display_name"`
+	Script      string `mapstructure:"script"`
+	Interval    int64  `mapstructure:"interval"`
+	Timeout     int64  `mapstructure:"timeout"`
+}
+
+// A mapping of the "session_limits" block on the "coder_agent" resource.
+type agentSessionLimits struct {
+	CPUs float64 `mapstructure:"cpus"`
+	// Memory is in megabytes.
+	Memory int64 `mapstructure:"memory"`
+	Pids   int64 `mapstructure:"pids"`
 }
 
 // A mapping of attributes on the "coder_app" resource.
@@ -31,6 +55,16 @@ type agentAppAttributes struct {
 	URL          string `mapstructure:"url"`
 	Command      string `mapstructure:"command"`
 	RelativePath bool   `mapstructure:"relative_path"`
+	// Healthcheck is a list, as blocks are represented as lists in the
+	// Terraform state, but at most one is allowed.
+	Healthcheck []appHealthcheckAttributes `mapstructure:"healthcheck"`
+}
+
+// A mapping of the "healthcheck" block on the "coder_app" resource.
+type appHealthcheckAttributes struct {
+	URL       string `mapstructure:"url"`
+	Interval  int32  `mapstructure:"interval"`
+	Threshold int32  `mapstructure:"threshold"`
 }
 
 // A mapping of attributes on the "coder_metadata" resource.
@@ -38,6 +72,7 @@ type metadataAttributes struct {
 	ResourceID string         `mapstructure:"resource_id"`
 	Hide       bool           `mapstructure:"hide"`
 	Icon       string         `mapstructure:"icon"`
+	DailyCost  int32          `mapstructure:"daily_cost"`
 	Items      []metadataItem `mapstructure:"item"`
 }
 
@@ -110,6 +145,27 @@ func ConvertResources(module *tfjson.StateModule, rawGraph string) ([]*proto.Res
 			OperatingSystem: attrs.OperatingSystem,
 			Architecture:    attrs.Architecture,
 			Directory:       attrs.Directory,
+
+			StartupScriptTimeoutSeconds:  attrs.StartupScriptTimeout,
+			ShutdownScript:               attrs.ShutdownScript,
+			ShutdownScriptTimeoutSeconds: attrs.ShutdownScriptTimeout,
+		}
+		for _, item := range attrs.Metadata {
+			agent.Metadata = append(agent.Metadata, &proto.Agent_Metadata{
+				Key:         item.Key,
+				DisplayName: item.DisplayName,
+				Script:      item.Script,
+				Interval:    item.Interval,
+				Timeout:     item.Timeout,
+			})
+		}
+		if len(attrs.SessionLimits) > 0 {
+			limits := attrs.SessionLimits[0]
+			agent.SessionLimits = &proto.Agent_SessionLimits{
+				Cpus:     limits.CPUs,
+				MemoryMb: limits.Memory,
+				Pids:     limits.Pids,
+			}
 		}
 		switch attrs.Auth {
 		case "token":
@@ -224,12 +280,21 @@ func ConvertResources(module *tfjson.StateModule, rawGraph string) ([]*proto.Res
 				if agent.Id != attrs.AgentID {
 					continue
 				}
+				var healthcheck *proto.Healthcheck
+				if len(attrs.Healthcheck) != 0 {
+					healthcheck = &proto.Healthcheck{
+						Url:       attrs.Healthcheck[0].URL,
+						Interval:  attrs.Healthcheck[0].Interval,
+						Threshold: attrs.Healthcheck[0].Threshold,
+					}
+				}
 				agent.Apps = append(agent.Apps, &proto.App{
 					Name:         attrs.Name,
 					Command:      attrs.Command,
 					Url:          attrs.URL,
 					Icon:         attrs.Icon,
 					RelativePath: attrs.RelativePath,
+					Healthcheck:  healthcheck,
 				})
 			}
 		}
@@ -239,6 +304,7 @@ func ConvertResources(module *tfjson.StateModule, rawGraph string) ([]*proto.Res
 	resourceMetadata := map[string][]*proto.Resource_Metadata{}
 	resourceHidden := map[string]bool{}
 	resourceIcon := map[string]string{}
+	resourceCost := map[string]int32{}
 	for _, resource := range tfResourceByLabel {
 		if resource.Type != "coder_metadata" {
 			continue
@@ -298,6 +364,7 @@ func ConvertResources(module *tfjson.StateModule, rawGraph string) ([]*proto.Res
 
 		resourceHidden[targetLabel] = attrs.Hide
 		resourceIcon[targetLabel] = attrs.Icon
+		resourceCost[targetLabel] = attrs.DailyCost
 		for _, item := range attrs.Items {
 			resourceMetadata[targetLabel] = append(resourceMetadata[targetLabel],
 				&proto.Resource_Metadata{
@@ -324,12 +391,13 @@ func ConvertResources(module *tfjson.StateModule, rawGraph string) ([]*proto.Res
 		}
 
 		resources = append(resources, &proto.Resource{
-			Name:     resource.Name,
-			Type:     resource.Type,
-			Agents:   agents,
-			Hide:     resourceHidden[label],
-			Icon:     resourceIcon[label],
-			Metadata: resourceMetadata[label],
+			Name:      resource.Name,
+			Type:      resource.Type,
+			Agents:    agents,
+			Hide:      resourceHidden[label],
+			Icon:      resourceIcon[label],
+			DailyCost: resourceCost[label],
+			Metadata:  resourceMetadata[label],
 		})
 	}
 
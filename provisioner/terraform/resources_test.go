@@ -88,6 +88,11 @@ func TestConvertResources(t *testing.T) {
 				OperatingSystem: "linux",
 				Architecture:    "amd64",
 				Auth:            &proto.Agent_Token{},
+				SessionLimits: &proto.Agent_SessionLimits{
+					Cpus:     1.5,
+					MemoryMb: 2048,
+					Pids:     1024,
+				},
 			}, {
 				Name:            "dev2",
 				OperatingSystem: "darwin",
@@ -110,6 +115,11 @@ func TestConvertResources(t *testing.T) {
 				Architecture:    "amd64",
 				Apps: []*proto.App{{
 					Name: "app1",
+					Healthcheck: &proto.Healthcheck{
+						Url:       "http://localhost:13337/healthz",
+						Interval:  5,
+						Threshold: 6,
+					},
 				}, {
 					Name: "app2",
 				}},
@@ -118,10 +128,11 @@ func TestConvertResources(t *testing.T) {
 		}},
 		// Tests fetching metadata about workspace resources.
 		"resource-metadata": {{
-			Name: "about",
-			Type: "null_resource",
-			Hide: true,
-			Icon: "/icon/server.svg",
+			Name:      "about",
+			Type:      "null_resource",
+			Hide:      true,
+			Icon:      "/icon/server.svg",
+			DailyCost: 29,
 			Metadata: []*proto.Resource_Metadata{{
 				Key:   "hello",
 				Value: "world",
@@ -2,7 +2,7 @@ terraform {
   required_providers {
     coder = {
       source  = "coder/coder"
-      version = "0.4.11"
+      version = "0.5.0"
     }
   }
 }
@@ -10,6 +10,11 @@ terraform {
 resource "coder_agent" "dev1" {
   os   = "linux"
   arch = "amd64"
+  session_limits {
+    cpus   = 1.5
+    memory = 2048
+    pids   = 1024
+  }
 }
 
 resource "coder_agent" "dev2" {
@@ -451,6 +451,63 @@ export const putWorkspaceExtension = async (
   await axios.put(`/api/v2/workspaces/${workspaceId}/extend`, { deadline: newDeadline })
 }
 
+export const getWorkspacePortShares = async (
+  workspaceId: string,
+): Promise<TypesGen.WorkspacePortShare[]> => {
+  const response = await axios.get<TypesGen.WorkspacePortShare[]>(
+    `/api/v2/workspaces/${workspaceId}/port-share`,
+  )
+  return response.data
+}
+
+export const upsertWorkspacePortShare = async (
+  workspaceId: string,
+  req: TypesGen.UpsertWorkspacePortShareRequest,
+): Promise<TypesGen.WorkspacePortShare> => {
+  const response = await axios.post<TypesGen.WorkspacePortShare>(
+    `/api/v2/workspaces/${workspaceId}/port-share`,
+    req,
+  )
+  return response.data
+}
+
+export const deleteWorkspacePortShare = async (
+  workspaceId: string,
+  req: TypesGen.DeleteWorkspacePortShareRequest,
+): Promise<void> => {
+  await axios.delete(`/api/v2/workspaces/${workspaceId}/port-share`, { data: req })
+}
+
+export const getWorkspaceAgentFiles = async (
+  agentId: string,
+  path: string,
+): Promise<TypesGen.WorkspaceAgentListFilesResponse> => {
+  const response = await axios.get<TypesGen.WorkspaceAgentListFilesResponse>(
+    `/api/v2/workspaceagents/${agentId}/files`,
+    { params: { path } },
+  )
+  return response.data
+}
+
+// uploadWorkspaceAgentFile writes a file to the workspace, e.g. when a file
+// is dropped onto the terminal. Relative paths are relative to the home
+// directory of the workspace user.
+export const uploadWorkspaceAgentFile = async (
+  agentId: string,
+  path: string,
+  file: File,
+): Promise<TypesGen.WorkspaceAgentFile> => {
+  const response = await axios.post<TypesGen.WorkspaceAgentFile>(
+    `/api/v2/workspaceagents/${agentId}/files/write`,
+    file,
+    {
+      params: { path },
+      headers: { "Content-Type": "application/octet-stream" },
+    },
+  )
+  return response.data
+}
+
 export const getEntitlements = async (): Promise<TypesGen.Entitlements> => {
   try {
     const response = await axios.get("/api/v2/entitlements")
@@ -498,3 +555,17 @@ export const getTemplateDAUs = async (
   const response = await axios.get(`/api/v2/templates/${templateId}/daus`)
   return response.data
 }
+
+export const getTemplateUsage = async (
+  templateId: string,
+): Promise<TypesGen.AgentUsageResponse> => {
+  const response = await axios.get(`/api/v2/templates/${templateId}/usage`)
+  return response.data
+}
+
+export const getUserUsage = async (
+  userId = "me",
+): Promise<TypesGen.AgentUsageResponse> => {
+  const response = await axios.get(`/api/v2/users/${userId}/usage`)
+  return response.data
+}
@@ -10,6 +10,8 @@ export interface APIKey {
   readonly updated_at: string
   readonly login_type: LoginType
   readonly lifetime_seconds: number
+  readonly token_name: string
+  readonly scope: APIKeyScope
 }
 
 // From codersdk/workspaceagents.go
@@ -34,6 +36,24 @@ export interface AgentStatsReportResponse {
   readonly num_comms: number
   readonly rx_bytes: number
   readonly tx_bytes: number
+  readonly session_count: Record<string, number>
+  readonly session_seconds: number
+}
+
+// From codersdk/templates.go
+export interface AgentUsageEntry {
+  readonly date: string
+  readonly connection_count: number
+  readonly rx_bytes: number
+  readonly tx_bytes: number
+  readonly session_count: Record<string, number>
+  readonly session_duration_seconds: number
+  readonly average_latency_ms: number
+}
+
+// From codersdk/templates.go
+export interface AgentUsageResponse {
+  readonly entries: AgentUsageEntry[]
 }
 
 // From codersdk/roles.go
@@ -86,6 +106,12 @@ export interface AuditLogCountResponse {
   readonly count: number
 }
 
+// From codersdk/audit.go
+export interface AuditLogExportRequest {
+  readonly q?: string
+  readonly format?: AuditLogExportFormat
+}
+
 // From codersdk/audit.go
 export interface AuditLogResponse {
   readonly audit_logs: AuditLog[]
@@ -115,6 +141,32 @@ export interface BuildInfoResponse {
   readonly version: string
 }
 
+// From codersdk/workspaces.go
+export interface BulkWorkspaceBuildRequest {
+  readonly q: string
+  readonly transition: WorkspaceTransition
+  readonly active_version?: boolean
+  readonly include_stopped?: boolean
+  readonly dry_run?: boolean
+  readonly workspace_ids?: string[]
+}
+
+// From codersdk/workspaces.go
+export interface BulkWorkspaceBuildResponse {
+  readonly dry_run: boolean
+  readonly results: BulkWorkspaceBuildResult[]
+}
+
+// From codersdk/workspaces.go
+export interface BulkWorkspaceBuildResult {
+  readonly workspace_id: string
+  readonly workspace_name: string
+  readonly owner_name: string
+  readonly build_id?: string
+  readonly skipped?: string
+  readonly error?: string
+}
+
 // From codersdk/parameters.go
 export interface ComputedParameter extends Parameter {
   readonly source_value: string
@@ -136,6 +188,12 @@ export interface CreateFirstUserResponse {
   readonly organization_id: string
 }
 
+// From codersdk/groups.go
+export interface CreateGroupRequest {
+  readonly name: string
+  readonly quota_allowance?: number
+}
+
 // From codersdk/users.go
 export interface CreateOrganizationRequest {
   readonly name: string
@@ -159,6 +217,7 @@ export interface CreateTemplateRequest {
   readonly parameter_values?: CreateParameterRequest[]
   readonly max_ttl_ms?: number
   readonly min_autostart_interval_ms?: number
+  readonly require_active_version?: boolean
 }
 
 // From codersdk/templateversions.go
@@ -174,6 +233,8 @@ export interface CreateTemplateVersionRequest {
   readonly storage_source: string
   readonly provisioner: ProvisionerType
   readonly parameter_values?: CreateParameterRequest[]
+  readonly provisioner_tags?: Record<string, string>
+  readonly message?: string
 }
 
 // From codersdk/audit.go
@@ -183,6 +244,13 @@ export interface CreateTestAuditLogRequest {
   readonly resource_id?: string
 }
 
+// From codersdk/users.go
+export interface CreateTokenRequest {
+  readonly token_name: string
+  readonly lifetime_seconds?: number
+  readonly scope?: APIKeyScope
+}
+
 // From codersdk/users.go
 export interface CreateUserRequest {
   readonly email: string
@@ -208,6 +276,7 @@ export interface CreateWorkspaceRequest {
   readonly autostart_schedule?: string
   readonly ttl_ms?: number
   readonly parameter_values?: CreateParameterRequest[]
+  readonly automatic_updates?: AutomaticUpdates
 }
 
 // From codersdk/templates.go
@@ -222,6 +291,12 @@ export interface DERPRegion {
   readonly latency_ms: number
 }
 
+// From codersdk/workspaceportshares.go
+export interface DeleteWorkspacePortShareRequest {
+  readonly agent_name: string
+  readonly port: number
+}
+
 // From codersdk/features.go
 export interface Entitlements {
   readonly features: Record<string, Feature>
@@ -255,6 +330,22 @@ export interface GoogleInstanceIdentityToken {
   readonly json_web_token: string
 }
 
+// From codersdk/groups.go
+export interface Group {
+  readonly id: string
+  readonly name: string
+  readonly organization_id: string
+  readonly members: User[]
+  readonly quota_allowance: number
+}
+
+// From codersdk/workspaceapps.go
+export interface Healthcheck {
+  readonly url: string
+  readonly interval: number
+  readonly threshold: number
+}
+
 // From codersdk/licenses.go
 export interface License {
   readonly id: number
@@ -331,11 +422,39 @@ export interface ParameterSchema {
   readonly validation_contains?: string[]
 }
 
+// From codersdk/groups.go
+export interface PatchGroupRequest {
+  readonly add_users: string[]
+  readonly remove_users: string[]
+  readonly name: string
+  readonly quota_allowance?: number
+}
+
+// From codersdk/workspaceagents.go
+export interface PatchWorkspaceAgentStartupLogs {
+  readonly logs: StartupLog[]
+}
+
+// From codersdk/workspaceagents.go
+export interface PostWorkspaceAgentHealthRequest {
+  readonly warnings: string[]
+}
+
+// From codersdk/workspaceagents.go
+export interface PostWorkspaceAgentLifecycleRequest {
+  readonly state: WorkspaceAgentLifecycle
+}
+
 // From codersdk/workspaceagents.go
 export interface PostWorkspaceAgentVersionRequest {
   readonly version: string
 }
 
+// From codersdk/workspaceapps.go
+export interface PostWorkspaceAppHealthsRequest {
+  readonly healths: Record<string, WorkspaceAppHealth>
+}
+
 // From codersdk/provisionerdaemons.go
 export interface ProvisionerDaemon {
   readonly id: string
@@ -343,6 +462,7 @@ export interface ProvisionerDaemon {
   readonly updated_at?: string
   readonly name: string
   readonly provisioners: ProvisionerType[]
+  readonly tags: Record<string, string>
 }
 
 // From codersdk/provisionerdaemons.go
@@ -355,6 +475,7 @@ export interface ProvisionerJob {
   readonly status: ProvisionerJobStatus
   readonly worker_id?: string
   readonly storage_source: string
+  readonly tags: Record<string, string>
 }
 
 // From codersdk/provisionerdaemons.go
@@ -372,6 +493,16 @@ export interface PutExtendWorkspaceRequest {
   readonly deadline: string
 }
 
+// From codersdk/replicas.go
+export interface Replica {
+  readonly id: string
+  readonly hostname: string
+  readonly created_at: string
+  readonly relay_address: string
+  readonly error: string
+  readonly database_latency: number
+}
+
 // From codersdk/error.go
 export interface Response {
   readonly message: string
@@ -392,6 +523,12 @@ export interface ServerSentEvent {
   readonly data: any
 }
 
+// From codersdk/workspaceagents.go
+export interface StartupLog {
+  readonly created_at: string
+  readonly output: string
+}
+
 // From codersdk/templates.go
 export interface Template {
   readonly id: string
@@ -409,6 +546,13 @@ export interface Template {
   readonly min_autostart_interval_ms: number
   readonly created_by_id: string
   readonly created_by_name: string
+  readonly require_active_version: boolean
+}
+
+// From codersdk/templates.go
+export interface TemplateACL {
+  readonly users: TemplateUser[]
+  readonly group: TemplateGroup[]
 }
 
 // From codersdk/templates.go
@@ -416,6 +560,16 @@ export interface TemplateDAUsResponse {
   readonly entries: DAUEntry[]
 }
 
+// From codersdk/templates.go
+export interface TemplateGroup extends Group {
+  readonly role: TemplateRole
+}
+
+// From codersdk/templates.go
+export interface TemplateUser extends User {
+  readonly role: TemplateRole
+}
+
 // From codersdk/templateversions.go
 export interface TemplateVersion {
   readonly id: string
@@ -428,11 +582,14 @@ export interface TemplateVersion {
   readonly readme: string
   readonly created_by_id: string
   readonly created_by_name: string
+  readonly message: string
+  readonly archived: boolean
 }
 
 // From codersdk/templates.go
 export interface TemplateVersionsByTemplateRequest extends Pagination {
   readonly template_id: string
+  readonly include_archived: boolean
 }
 
 // From codersdk/templates.go
@@ -445,6 +602,12 @@ export interface UpdateRoles {
   readonly roles: string[]
 }
 
+// From codersdk/templates.go
+export interface UpdateTemplateACL {
+  readonly user_perms?: Record<string, TemplateRole>
+  readonly group_perms?: Record<string, TemplateRole>
+}
+
 // From codersdk/templates.go
 export interface UpdateTemplateMeta {
   readonly name?: string
@@ -452,6 +615,7 @@ export interface UpdateTemplateMeta {
   readonly icon?: string
   readonly max_ttl_ms?: number
   readonly min_autostart_interval_ms?: number
+  readonly require_active_version?: boolean
 }
 
 // From codersdk/users.go
@@ -465,11 +629,21 @@ export interface UpdateUserProfileRequest {
   readonly username: string
 }
 
+// From codersdk/workspaces.go
+export interface UpdateWorkspaceAutomaticUpdatesRequest {
+  readonly automatic_updates: AutomaticUpdates
+}
+
 // From codersdk/workspaces.go
 export interface UpdateWorkspaceAutostartRequest {
   readonly schedule?: string
 }
 
+// From codersdk/workspacequota.go
+export interface UpdateWorkspaceQuotaRequest {
+  readonly quota_allowance: number
+}
+
 // From codersdk/workspaces.go
 export interface UpdateWorkspaceRequest {
   readonly name?: string
@@ -485,6 +659,13 @@ export interface UploadResponse {
   readonly hash: string
 }
 
+// From codersdk/workspaceportshares.go
+export interface UpsertWorkspacePortShareRequest {
+  readonly agent_name: string
+  readonly port: number
+  readonly share_level: WorkspacePortShareLevel
+}
+
 // From codersdk/users.go
 export interface User {
   readonly id: string
@@ -552,6 +733,7 @@ export interface Workspace {
   readonly autostart_schedule?: string
   readonly ttl_ms?: number
   readonly last_used_at: string
+  readonly automatic_updates: AutomaticUpdates
 }
 
 // From codersdk/workspaceresources.go
@@ -573,6 +755,14 @@ export interface WorkspaceAgent {
   readonly directory?: string
   readonly version: string
   readonly apps: WorkspaceApp[]
+  readonly lifecycle_state: WorkspaceAgentLifecycle
+  readonly startup_script_timeout_seconds: number
+  readonly startup_logs_length: number
+  readonly startup_logs_overflowed: boolean
+  readonly shutdown_script?: string
+  readonly shutdown_script_timeout_seconds: number
+  readonly metadata?: WorkspaceAgentMetadata[]
+  readonly health_warnings: string[]
   readonly latency?: Record<string, DERPRegion>
 }
 
@@ -601,6 +791,56 @@ export interface WorkspaceAgentInstanceMetadata {
   readonly vnc: boolean
 }
 
+// From codersdk/workspaceagentfiles.go
+export interface WorkspaceAgentFile {
+  readonly name: string
+  readonly size: number
+  readonly mode: string
+  readonly mod_time: string
+  readonly is_dir: boolean
+}
+
+// From codersdk/workspaceagentfiles.go
+export interface WorkspaceAgentListFilesResponse {
+  readonly path: string
+  readonly files: WorkspaceAgentFile[]
+}
+
+// From codersdk/workspaceagents.go
+export interface WorkspaceAgentListeningPort {
+  readonly process_name: string
+  readonly network: string
+  readonly port: number
+}
+
+// From codersdk/workspaceagents.go
+export interface WorkspaceAgentListeningPortsResponse {
+  readonly ports: WorkspaceAgentListeningPort[]
+}
+
+// From codersdk/workspaceagents.go
+export interface WorkspaceAgentMetadata {
+  readonly description: WorkspaceAgentMetadataDescription
+  readonly result: WorkspaceAgentMetadataResult
+}
+
+// From codersdk/workspaceagents.go
+export interface WorkspaceAgentMetadataDescription {
+  readonly display_name: string
+  readonly key: string
+  readonly script: string
+  readonly interval: number
+  readonly timeout: number
+}
+
+// From codersdk/workspaceagents.go
+export interface WorkspaceAgentMetadataResult {
+  readonly collected_at: string
+  readonly age: number
+  readonly value: string
+  readonly error: string
+}
+
 // From codersdk/workspaceresources.go
 export interface WorkspaceAgentResourceMetadata {
   readonly memory_total: number
@@ -610,12 +850,21 @@ export interface WorkspaceAgentResourceMetadata {
   readonly cpu_mhz: number
 }
 
+// From codersdk/workspaceagents.go
+export interface WorkspaceAgentStartupLog {
+  readonly id: number
+  readonly created_at: string
+  readonly output: string
+}
+
 // From codersdk/workspaceapps.go
 export interface WorkspaceApp {
   readonly id: string
   readonly name: string
   readonly command?: string
   readonly icon?: string
+  readonly healthcheck: Healthcheck
+  readonly health: WorkspaceAppHealth
 }
 
 // From codersdk/workspacebuilds.go
@@ -636,6 +885,7 @@ export interface WorkspaceBuild {
   readonly reason: BuildReason
   readonly resources: WorkspaceResource[]
   readonly deadline?: string
+  readonly daily_cost: number
 }
 
 // From codersdk/workspaces.go
@@ -653,6 +903,21 @@ export interface WorkspaceOptions {
   readonly include_deleted?: boolean
 }
 
+// From codersdk/workspaceportshares.go
+export interface WorkspacePortShare {
+  readonly workspace_id: string
+  readonly agent_name: string
+  readonly port: number
+  readonly share_level: WorkspacePortShareLevel
+  readonly url: string
+}
+
+// From codersdk/workspacequota.go
+export interface WorkspaceQuota {
+  readonly credits_consumed: number
+  readonly budget: number
+}
+
 // From codersdk/workspaceresources.go
 export interface WorkspaceResource {
   readonly id: string
@@ -665,6 +930,7 @@ export interface WorkspaceResource {
   readonly icon: string
   readonly agents?: WorkspaceAgent[]
   readonly metadata?: WorkspaceResourceMetadata[]
+  readonly daily_cost: number
 }
 
 // From codersdk/workspaceresources.go
@@ -674,8 +940,32 @@ export interface WorkspaceResourceMetadata {
   readonly sensitive: boolean
 }
 
+// From codersdk/users.go
+export type APIKeyScope =
+  | "agent_connect"
+  | "all"
+  | "application_connect"
+  | "read_only"
+  | "template_push"
+  | "workspace_lifecycle"
+
+// From codersdk/audit.go
+export type AuditAction =
+  | "connect"
+  | "create"
+  | "delete"
+  | "disconnect"
+  | "login"
+  | "logout"
+  | "start"
+  | "stop"
+  | "write"
+
 // From codersdk/audit.go
-export type AuditAction = "create" | "delete" | "write"
+export type AuditLogExportFormat = "csv" | "json"
+
+// From codersdk/workspaces.go
+export type AutomaticUpdates = "always" | "never"
 
 // From codersdk/workspacebuilds.go
 export type BuildReason = "autostart" | "autostop" | "initiator"
@@ -690,7 +980,7 @@ export type LogLevel = "debug" | "error" | "info" | "trace" | "warn"
 export type LogSource = "provisioner" | "provisioner_daemon"
 
 // From codersdk/users.go
-export type LoginType = "github" | "oidc" | "password"
+export type LoginType = "github" | "oidc" | "password" | "token"
 
 // From codersdk/parameters.go
 export type ParameterDestinationScheme = "environment_variable" | "none" | "provisioner_variable"
@@ -732,11 +1022,36 @@ export type ResourceType =
 // From codersdk/sse.go
 export type ServerSentEventType = "data" | "error" | "ping"
 
+// From codersdk/templates.go
+export type TemplateRole = "" | "admin" | "use"
+
 // From codersdk/users.go
 export type UserStatus = "active" | "suspended"
 
+// From codersdk/workspaceresources.go
+export type WorkspaceAgentLifecycle =
+  | "created"
+  | "off"
+  | "ready"
+  | "shutdown_error"
+  | "shutdown_timeout"
+  | "shutting_down"
+  | "start_error"
+  | "start_timeout"
+  | "starting"
+
 // From codersdk/workspaceresources.go
 export type WorkspaceAgentStatus = "connected" | "connecting" | "disconnected"
 
+// From codersdk/workspaceapps.go
+export type WorkspaceAppHealth =
+  | "disabled"
+  | "healthy"
+  | "initializing"
+  | "unhealthy"
+
+// From codersdk/workspaceportshares.go
+export type WorkspacePortShareLevel = "authenticated" | "owner" | "public"
+
 // From codersdk/workspacebuilds.go
 export type WorkspaceTransition = "delete" | "start" | "stop"
@@ -33,6 +33,7 @@ const Language = {
   statusLabel: "status: ",
   versionLabel: "version: ",
   osLabel: "os: ",
+  warningLabel: "warning: ",
 }
 
 interface ResourcesProps {
@@ -140,6 +141,12 @@ export const Resources: FC<React.PropsWithChildren<ResourcesProps>> = ({
                               <span className={styles.agentVersion}>{displayVersion}</span>
                               <AgentOutdatedTooltip outdated={outdated} />
                             </div>
+                            {agent.health_warnings.map((warning) => (
+                              <div key={warning} className={styles.dataRow}>
+                                <strong>{Language.warningLabel}</strong>
+                                <span className={styles.warning}>{warning}</span>
+                              </div>
+                            ))}
                             <div className={styles.dataRow}>
                               <ResourceAgentLatency latency={agent.latency} />
                             </div>
@@ -250,6 +257,11 @@ const useStyles = makeStyles((theme) => ({
     display: "block",
   },
 
+  warning: {
+    color: theme.palette.warning.light,
+    whiteSpace: "normal",
+  },
+
   accessLinks: {
     display: "flex",
     gap: theme.spacing(0.5),
@@ -1,6 +1,7 @@
 import { makeStyles } from "@material-ui/core/styles"
 import { useMachine } from "@xstate/react"
-import { FC, useEffect, useRef, useState } from "react"
+import { uploadWorkspaceAgentFile } from "api/api"
+import { DragEvent, FC, useEffect, useRef, useState } from "react"
 import { Helmet } from "react-helmet-async"
 import { useLocation, useNavigate, useParams, useSearchParams } from "react-router-dom"
 import { colors } from "theme/colors"
@@ -17,6 +18,7 @@ export const Language = {
   workspaceErrorMessagePrefix: "Unable to fetch workspace: ",
   workspaceAgentErrorMessagePrefix: "Unable to fetch workspace agent: ",
   websocketErrorMessagePrefix: "WebSocket failed: ",
+  uploadErrorMessagePrefix: "Unable to upload file: ",
 }
 
 const TerminalPage: FC<
@@ -190,6 +192,30 @@ const TerminalPage: FC<
     sendEvent,
   ])
 
+  // Files dropped onto the terminal are uploaded to the home directory of
+  // the workspace user, and their names are typed into the terminal.
+  const onDrop = async (event: DragEvent<HTMLDivElement>) => {
+    event.preventDefault()
+    if (!workspaceAgent || !isConnected) {
+      return
+    }
+    for (const file of Array.from(event.dataTransfer.files)) {
+      try {
+        await uploadWorkspaceAgentFile(workspaceAgent.id, file.name, file)
+        sendEvent({
+          type: "WRITE",
+          request: {
+            data: `'${file.name.replace(/'/g, `'\\''`)}' `,
+          },
+        })
+      } catch (error) {
+        terminal?.writeln(
+          Language.uploadErrorMessagePrefix + (error instanceof Error ? error.message : String(error)),
+        )
+      }
+    }
+  }
+
   return (
     <>
       <Helmet>
@@ -206,7 +232,13 @@ const TerminalPage: FC<
       <div className={`${styles.overlay} ${isDisconnected ? "" : "connected"}`}>
         <span className={styles.overlayText}>Disconnected</span>
       </div>
-      <div className={styles.terminal} ref={xtermRef} data-testid="terminal" />
+      <div
+        className={styles.terminal}
+        ref={xtermRef}
+        data-testid="terminal"
+        onDragOver={(event) => event.preventDefault()}
+        onDrop={onDrop}
+      />
     </>
   )
 }
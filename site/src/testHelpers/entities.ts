@@ -118,6 +118,7 @@ export const MockProvisioner: TypesGen.ProvisionerDaemon = {
   id: "test-provisioner",
   name: "Test Provisioner",
   provisioners: ["echo"],
+  tags: {},
 }
 
 export const MockProvisionerJob: TypesGen.ProvisionerJob = {
@@ -126,6 +127,7 @@ export const MockProvisionerJob: TypesGen.ProvisionerJob = {
   status: "succeeded",
   storage_source: "asdf",
   completed_at: "2022-05-17T17:39:01.382927298Z",
+  tags: {},
 }
 
 export const MockFailedProvisionerJob: TypesGen.ProvisionerJob = {
@@ -165,6 +167,8 @@ You can add instructions here
 [Some link info](https://coder.com)`,
   created_by_id: "test-creator-id",
   created_by_name: "test_creator",
+  message: "",
+  archived: false,
 }
 
 export const MockTemplate: TypesGen.Template = {
@@ -183,6 +187,7 @@ export const MockTemplate: TypesGen.Template = {
   created_by_id: "test-creator-id",
   created_by_name: "test_creator",
   icon: "/icon/code.svg",
+  require_active_version: false,
 }
 
 export const MockWorkspaceAutostartDisabled: TypesGen.UpdateWorkspaceAutostartRequest = {
@@ -264,6 +269,7 @@ export const MockWorkspace: TypesGen.Workspace = {
   ttl_ms: 2 * 60 * 60 * 1000, // 2 hours as milliseconds
   latest_build: MockWorkspaceBuild,
   last_used_at: "",
+  automatic_updates: "never",
 }
 
 export const MockStoppedWorkspace: TypesGen.Workspace = {
@@ -338,6 +344,12 @@ export const MockWorkspaceAgent: TypesGen.WorkspaceAgent = {
   status: "connected",
   updated_at: "",
   version: MockBuildInfo.version,
+  lifecycle_state: "ready",
+  startup_script_timeout_seconds: 0,
+  shutdown_script_timeout_seconds: 0,
+  startup_logs_length: 0,
+  startup_logs_overflowed: false,
+  health_warnings: [],
   latency: {
     "Coder Embedded DERP": {
       latency_ms: 32.55,
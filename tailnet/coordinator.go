@@ -14,6 +14,24 @@ import (
 	"tailscale.com/types/key"
 )
 
+// Coordinator exchanges nodes with agents to establish connections.
+// ┌──────────────────┐   ┌────────────────────┐   ┌───────────────────┐   ┌──────────────────┐
+// │tailnet.Coordinate├──►│tailnet.AcceptClient│◄─►│tailnet.AcceptAgent│◄──┤tailnet.Coordinate│
+// └──────────────────┘   └────────────────────┘   └───────────────────┘   └──────────────────┘
+// Coordinators have different guarantees for HA support.
+type Coordinator interface {
+	// Node returns an in-memory node by ID.
+	Node(id uuid.UUID) *Node
+	// ServeClient accepts a WebSocket connection that wants to connect to an agent
+	// with the specified ID.
+	ServeClient(conn net.Conn, id uuid.UUID, agent uuid.UUID) error
+	// ServeAgent accepts a WebSocket connection to an agent that listens to
+	// incoming connections and publishes node updates.
+	ServeAgent(conn net.Conn, id uuid.UUID) error
+	// Close closes the coordinator and all of its connections.
+	Close() error
+}
+
 // Node represents a node in the network.
 type Node struct {
 	ID            tailcfg.NodeID     `json:"id"`
@@ -64,23 +82,27 @@ func ServeCoordinator(conn net.Conn, updateNodes func(node []*Node) error) (func
 	}, errChan
 }
 
-// NewCoordinator constructs a new in-memory connection coordinator.
-func NewCoordinator() *Coordinator {
-	return &Coordinator{
+// NewCoordinator constructs a new in-memory connection coordinator. This
+// coordinator is incompatible with multiple Coder replicas as all node data is
+// in-memory.
+func NewCoordinator() Coordinator {
+	return &coordinator{
 		nodes:                    map[uuid.UUID]*Node{},
 		agentSockets:             map[uuid.UUID]net.Conn{},
 		agentToConnectionSockets: map[uuid.UUID]map[uuid.UUID]net.Conn{},
 	}
 }
 
-// Coordinator exchanges nodes with agents to establish connections.
+// coordinator exchanges nodes with agents to establish connections entirely in-memory.
+// The Enterprise implementation provides this for high-availability.
 // ┌──────────────────┐   ┌────────────────────┐   ┌───────────────────┐   ┌──────────────────┐
 // │tailnet.Coordinate├──►│tailnet.AcceptClient│◄─►│tailnet.AcceptAgent│◄──┤tailnet.Coordinate│
 // └──────────────────┘   └────────────────────┘   └───────────────────┘   └──────────────────┘
 // This coordinator is incompatible with multiple Coder
 // replicas as all node data is in-memory.
-type Coordinator struct {
-	mutex sync.Mutex
+type coordinator struct {
+	mutex  sync.Mutex
+	closed bool
 
 	// Maps agent and connection IDs to a node.
 	nodes map[uuid.UUID]*Node
@@ -92,7 +114,7 @@ type Coordinator struct {
 }
 
 // Node returns an in-memory node by ID.
-func (c *Coordinator) Node(id uuid.UUID) *Node {
+func (c *coordinator) Node(id uuid.UUID) *Node {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 	node := c.nodes[id]
@@ -101,8 +123,13 @@ func (c *Coordinator) Node(id uuid.UUID) *Node {
 
 // ServeClient accepts a WebSocket connection that wants to
 // connect to an agent with the specified ID.
-func (c *Coordinator) ServeClient(conn net.Conn, id uuid.UUID, agent uuid.UUID) error {
+func (c *coordinator) ServeClient(conn net.Conn, id uuid.UUID, agent uuid.UUID) error {
 	c.mutex.Lock()
+	if c.closed {
+		c.mutex.Unlock()
+		return xerrors.New("coordinator is closed")
+	}
+
 	// When a new connection is requested, we update it with the latest
 	// node of the agent. This allows the connection to establish.
 	node, ok := c.nodes[agent]
@@ -186,8 +213,13 @@ func (c *Coordinator) ServeClient(conn net.Conn, id uuid.UUID, agent uuid.UUID)
 
 // ServeAgent accepts a WebSocket connection to an agent that
 // listens to incoming connections and publishes node updates.
-func (c *Coordinator) ServeAgent(conn net.Conn, id uuid.UUID) error {
+func (c *coordinator) ServeAgent(conn net.Conn, id uuid.UUID) error {
 	c.mutex.Lock()
+	if c.closed {
+		c.mutex.Unlock()
+		return xerrors.New("coordinator is closed")
+	}
+
 	sockets, ok := c.agentToConnectionSockets[id]
 	if ok {
 		// Publish all nodes that want to connect to the
@@ -263,3 +295,24 @@ func (c *Coordinator) ServeAgent(conn net.Conn, id uuid.UUID) error {
 		c.mutex.Unlock()
 	}
 }
+
+// Close closes all of the open connections in the coordinator and stops the
+// coordinator from accepting new connections.
+func (c *coordinator) Close() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+
+	for _, socket := range c.agentSockets {
+		_ = socket.Close()
+	}
+	for _, connMap := range c.agentToConnectionSockets {
+		for _, socket := range connMap {
+			_ = socket.Close()
+		}
+	}
+	return nil
+}